@@ -0,0 +1,25 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/idoyudha/eshop-warehouse/config"
+	"github.com/idoyudha/eshop-warehouse/internal/app"
+)
+
+// exits with status 1 when a warehouse product disagrees with its ledger, so it can run as a scheduled check
+func main() {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	discrepancies, err := app.Reconcile(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if discrepancies > 0 {
+		os.Exit(1)
+	}
+}
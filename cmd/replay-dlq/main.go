@@ -0,0 +1,24 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/idoyudha/eshop-warehouse/config"
+	"github.com/idoyudha/eshop-warehouse/internal/app"
+)
+
+// replays the dead letters of every consumed topic, or only of the topic given by -topic
+func main() {
+	topic := flag.String("topic", "", "consumed topic whose dead letters are replayed, empty replays all topics")
+	flag.Parse()
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := app.ReplayDeadLetters(cfg, *topic); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -1,15 +1,28 @@
 package config
 
-import "github.com/ilyakaznacheev/cleanenv"
+import (
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
 
 type (
 	Config struct {
-		App  `yaml:"app"`
-		HTTP `yaml:"http"`
-		Log  `yaml:"log"`
+		App            `yaml:"app"`
+		HTTP           `yaml:"http"`
+		Log            `yaml:"log"`
+		Reservation    `yaml:"reservation"`
+		Outbox         `yaml:"outbox"`
+		Geo            `yaml:"geo"`
+		Allocation     `yaml:"allocation"`
+		StockThreshold `yaml:"stock_threshold"`
+		Rebalance      `yaml:"rebalance"`
+		Forecast       `yaml:"forecast"`
+		StockSnapshot  `yaml:"stock_snapshot"`
 		PostgreSQL
 		AuthService
 		Kafka
+		KafkaConsumer `yaml:"kafka_consumer"`
 	}
 
 	App struct {
@@ -39,6 +52,64 @@ type (
 	Kafka struct {
 		Broker string `env-required:"true" env:"KAFKA_BROKER"`
 	}
+
+	// a message still failing after max attempts is published to the dead letter topic of its topic.
+	// Messages of the same key are handled by the same worker, max in flight bounds the messages read ahead
+	KafkaConsumer struct {
+		MaxAttempts  int           `env-required:"true" yaml:"max_attempts" env:"KAFKA_CONSUMER_MAX_ATTEMPTS"`
+		RetryBackoff time.Duration `env-required:"true" yaml:"retry_backoff" env:"KAFKA_CONSUMER_RETRY_BACKOFF"`
+		Workers      int           `env-required:"true" yaml:"workers" env:"KAFKA_CONSUMER_WORKERS"`
+		MaxInFlight  int           `env-required:"true" yaml:"max_in_flight" env:"KAFKA_CONSUMER_MAX_IN_FLIGHT"`
+	}
+
+	Reservation struct {
+		TTL             time.Duration `env-required:"true" yaml:"ttl" env:"RESERVATION_TTL"`
+		ReleaseInterval time.Duration `env-required:"true" yaml:"release_interval" env:"RESERVATION_RELEASE_INTERVAL"`
+	}
+
+	Geo struct {
+		PostalCodesPath string `env-required:"true" yaml:"postal_codes_path" env:"GEO_POSTAL_CODES_PATH"`
+	}
+
+	// one of nearest-first, minimize-warehouses, single-warehouse, balance-stock, minimize-shipments
+	Allocation struct {
+		Strategy string `env-required:"true" yaml:"strategy" env:"ALLOCATION_STRATEGY"`
+	}
+
+	StockThreshold struct {
+		EvaluateInterval time.Duration `env-required:"true" yaml:"evaluate_interval" env:"STOCK_THRESHOLD_EVALUATE_INTERVAL"`
+	}
+
+	// transfers are only created by the job when auto create is enabled, otherwise the planned transfers are logged
+	Rebalance struct {
+		Interval               time.Duration `env-required:"true" yaml:"interval" env:"REBALANCE_INTERVAL"`
+		Lookback               time.Duration `env-required:"true" yaml:"lookback" env:"REBALANCE_LOOKBACK"`
+		Coverage               time.Duration `env-required:"true" yaml:"coverage" env:"REBALANCE_COVERAGE"`
+		MaxTransfers           int           `env-required:"true" yaml:"max_transfers" env:"REBALANCE_MAX_TRANSFERS"`
+		MaxQuantityPerTransfer int64         `env-required:"true" yaml:"max_quantity_per_transfer" env:"REBALANCE_MAX_QUANTITY_PER_TRANSFER"`
+		MinMainQuantity        int64         `env-default:"0" yaml:"min_main_quantity" env:"REBALANCE_MIN_MAIN_QUANTITY"`
+		AutoCreate             bool          `env-default:"false" yaml:"auto_create" env:"REBALANCE_AUTO_CREATE"`
+	}
+
+	// defaults of the forecast parameters, requests can override them
+	Forecast struct {
+		Method      string  `env-required:"true" yaml:"method" env:"FORECAST_METHOD"`
+		HistoryDays int     `env-required:"true" yaml:"history_days" env:"FORECAST_HISTORY_DAYS"`
+		Window      int     `env-required:"true" yaml:"window" env:"FORECAST_WINDOW"`
+		Alpha       float64 `env-required:"true" yaml:"alpha" env:"FORECAST_ALPHA"`
+	}
+
+	// the snapshot of a day is replaced by every capture during that day, so the last capture is kept
+	StockSnapshot struct {
+		CaptureInterval time.Duration `env-required:"true" yaml:"capture_interval" env:"STOCK_SNAPSHOT_CAPTURE_INTERVAL"`
+	}
+
+	Outbox struct {
+		RelayInterval time.Duration `env-required:"true" yaml:"relay_interval" env:"OUTBOX_RELAY_INTERVAL"`
+		BatchSize     int           `env-required:"true" yaml:"batch_size" env:"OUTBOX_BATCH_SIZE"`
+		MaxAttempts   int           `env-required:"true" yaml:"max_attempts" env:"OUTBOX_MAX_ATTEMPTS"`
+		RetryBackoff  time.Duration `env-required:"true" yaml:"retry_backoff" env:"OUTBOX_RETRY_BACKOFF"`
+	}
 )
 
 func NewConfig() (*Config, error) {
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
@@ -9,12 +10,15 @@ import (
 	"github.com/idoyudha/eshop-warehouse/config"
 	v1Http "github.com/idoyudha/eshop-warehouse/internal/controller/http/v1"
 	kafkaEvent "github.com/idoyudha/eshop-warehouse/internal/controller/kafka"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
 	"github.com/idoyudha/eshop-warehouse/internal/usecase"
 	"github.com/idoyudha/eshop-warehouse/internal/usecase/repo"
+	"github.com/idoyudha/eshop-warehouse/internal/utils"
 	"github.com/idoyudha/eshop-warehouse/pkg/httpserver"
 	"github.com/idoyudha/eshop-warehouse/pkg/kafka"
 	"github.com/idoyudha/eshop-warehouse/pkg/logger"
 	"github.com/idoyudha/eshop-warehouse/pkg/postgresql"
+	"github.com/idoyudha/eshop-warehouse/pkg/scheduler"
 )
 
 func Run(cfg *config.Config) {
@@ -37,12 +41,25 @@ func Run(cfg *config.Config) {
 		l.Fatal("app - Run - postgresql.NewPostgres: ", err)
 	}
 
+	postalCodes, err := utils.LoadPostalCodesFile(cfg.Geo.PostalCodesPath)
+	if err != nil {
+		l.Fatal("app - Run - utils.LoadPostalCodesFile: ", err)
+	}
+	distanceCalculator := utils.NewHaversineDistanceCalculator(postalCodes)
+
+	allocationStrategy, err := utils.NewAllocationStrategy(cfg.Allocation.Strategy, distanceCalculator)
+	if err != nil {
+		l.Fatal("app - Run - utils.NewAllocationStrategy: ", err)
+	}
+
 	warehouseUseCase := usecase.NewWarehouseUseCase(
 		repo.NewWarehousePostgreRepo(postgreSQL),
+		distanceCalculator,
 	)
 
 	warehouseProductUseCase := usecase.NewWarehouseProductUseCase(
 		repo.NewWarehouseProductPostgreRepo(postgreSQL),
+		distanceCalculator,
 	)
 
 	stockMovementUseCase := usecase.NewStockMovementUseCase(
@@ -52,20 +69,153 @@ func Run(cfg *config.Config) {
 	transactionProductUseCase := usecase.NewTransactionProductUseCase(
 		repo.NewTransactionProductPostgreRepo(postgreSQL),
 		repo.NewWarehouseProductPostgreRepo(postgreSQL),
+		distanceCalculator,
+		allocationStrategy,
+	)
+
+	reservationUseCase := usecase.NewReservationUseCase(
+		repo.NewReservationPostgreRepo(postgreSQL),
+		repo.NewWarehouseProductPostgreRepo(postgreSQL),
+		distanceCalculator,
+		cfg.Reservation.TTL,
+	)
+
+	inventoryCountUseCase := usecase.NewInventoryCountUseCase(
+		repo.NewInventoryCountPostgreRepo(postgreSQL),
+		repo.NewWarehouseProductPostgreRepo(postgreSQL),
+	)
+
+	inboundReceiptUseCase := usecase.NewInboundReceiptUseCase(
+		repo.NewInboundReceiptPostgreRepo(postgreSQL),
+	)
+
+	transferOrderUseCase := usecase.NewTransferOrderUseCase(
+		repo.NewTransferOrderPostgreRepo(postgreSQL),
+	)
+
+	stockThresholdUseCase := usecase.NewStockThresholdUseCase(
+		repo.NewStockThresholdPostgreRepo(postgreSQL),
+		repo.NewWarehousePostgreRepo(postgreSQL),
+		repo.NewWarehouseProductPostgreRepo(postgreSQL),
+	)
+
+	rebalanceUseCase := usecase.NewRebalanceUseCase(
+		repo.NewStockMovementPostgreRepo(postgreSQL),
+		repo.NewWarehousePostgreRepo(postgreSQL),
+		repo.NewWarehouseProductPostgreRepo(postgreSQL),
+		repo.NewTransactionProductPostgreRepo(postgreSQL),
+		entity.RebalanceLimits{
+			Lookback:               cfg.Rebalance.Lookback,
+			Coverage:               cfg.Rebalance.Coverage,
+			MaxTransfers:           cfg.Rebalance.MaxTransfers,
+			MaxQuantityPerTransfer: cfg.Rebalance.MaxQuantityPerTransfer,
+			MinMainQuantity:        cfg.Rebalance.MinMainQuantity,
+		},
+	)
+
+	forecastParams := entity.ForecastParams{
+		Method:      cfg.Forecast.Method,
+		HistoryDays: cfg.Forecast.HistoryDays,
+		Window:      cfg.Forecast.Window,
+		Alpha:       cfg.Forecast.Alpha,
+	}
+	if err = forecastParams.Validate(); err != nil {
+		l.Fatal("app - Run - forecastParams.Validate: ", err)
+	}
+	forecastUseCase := usecase.NewForecastUseCase(
+		repo.NewStockMovementPostgreRepo(postgreSQL),
+		forecastParams,
+	)
+
+	stockHistoryUseCase := usecase.NewStockHistoryUseCase(
+		repo.NewStockHistoryPostgreRepo(postgreSQL),
+	)
+
+	stockSnapshotUseCase := usecase.NewStockSnapshotUseCase(
+		repo.NewStockSnapshotPostgreRepo(postgreSQL),
+	)
+
+	valuationUseCase := usecase.NewValuationUseCase(
+		repo.NewCostLayerPostgreRepo(postgreSQL),
+	)
+
+	outboxUseCase := usecase.NewOutboxUseCase(
+		repo.NewOutboxEventPostgreRepo(postgreSQL),
 		kafkaProducer,
+		cfg.Outbox.BatchSize,
+		cfg.Outbox.MaxAttempts,
+		cfg.Outbox.RetryBackoff,
 	)
 
+	// Scheduler
+	jobScheduler := scheduler.New(l)
+	jobScheduler.Every("relay outbox events", cfg.Outbox.RelayInterval, func(ctx context.Context) error {
+		_, err := outboxUseCase.RelayPendingEvents(ctx)
+		return err
+	})
+	jobScheduler.Every("release expired reservations", cfg.Reservation.ReleaseInterval, func(ctx context.Context) error {
+		released, err := reservationUseCase.ReleaseExpiredReservations(ctx)
+		if err != nil {
+			return err
+		}
+		if released > 0 {
+			l.Info("app - Run - released %d expired reservations", released)
+		}
+		return nil
+	})
+	jobScheduler.Every("evaluate low stocks", cfg.StockThreshold.EvaluateInterval, func(ctx context.Context) error {
+		lowStocks, err := stockThresholdUseCase.EvaluateLowStocks(ctx)
+		if err != nil {
+			return err
+		}
+		if lowStocks > 0 {
+			l.Info("app - Run - %d products became low on stock", lowStocks)
+		}
+		return nil
+	})
+	jobScheduler.Every("capture stock snapshots", cfg.StockSnapshot.CaptureInterval, func(ctx context.Context) error {
+		_, err := stockSnapshotUseCase.CaptureStockSnapshot(ctx)
+		return err
+	})
+	jobScheduler.Every("rebalance stock", cfg.Rebalance.Interval, func(ctx context.Context) error {
+		if !cfg.Rebalance.AutoCreate {
+			report, err := rebalanceUseCase.PlanRebalance(ctx)
+			if err != nil {
+				return err
+			}
+			for _, line := range report.Lines {
+				l.Info("app - Run - rebalance suggests moving %d of product %s from warehouse %s to warehouse %s",
+					line.Quantity, line.ProductID, line.FromWarehouseID, line.ToWarehouseID)
+			}
+			return nil
+		}
+
+		report, err := rebalanceUseCase.Rebalance(ctx)
+		if err != nil {
+			return err
+		}
+		for _, line := range report.Lines {
+			if line.Error != "" {
+				l.Info("app - Run - rebalance failed to move product %s to warehouse %s: %s", line.ProductID, line.ToWarehouseID, line.Error)
+			}
+		}
+		if len(report.Lines) > 0 {
+			l.Info("app - Run - rebalance %s planned %d transfers", report.ID, len(report.Lines))
+		}
+		return nil
+	})
+
 	// HTTP Server
 	handler := gin.Default()
-	v1Http.NewRouter(handler, warehouseUseCase, warehouseProductUseCase, stockMovementUseCase, transactionProductUseCase, l, cfg.AuthService)
+	v1Http.NewRouter(handler, warehouseUseCase, warehouseProductUseCase, stockMovementUseCase, transactionProductUseCase, reservationUseCase, inventoryCountUseCase, inboundReceiptUseCase, transferOrderUseCase, stockThresholdUseCase, rebalanceUseCase, forecastUseCase, stockHistoryUseCase, stockSnapshotUseCase, valuationUseCase, l, cfg.AuthService)
 	httpServer := httpserver.New(handler, httpserver.Port(cfg.HTTP.Port))
 
 	// Kafka Consumer
-	kafkaErrChan := make(chan error, 1)
+	kafkaCtx, kafkaCancel := context.WithCancel(context.Background())
+	kafkaDone := make(chan struct{})
 	go func() {
-		if err := kafkaEvent.KafkaNewRouter(warehouseUseCase, warehouseProductUseCase, l, kafkaConsumer); err != nil {
-			kafkaErrChan <- err
-		}
+		defer close(kafkaDone)
+		kafkaEvent.KafkaNewRouter(kafkaCtx, warehouseUseCase, warehouseProductUseCase, transactionProductUseCase, kafkaProducer, cfg.KafkaConsumer, l, kafkaConsumer)
 	}()
 
 	interrupt := make(chan os.Signal, 1)
@@ -79,8 +229,14 @@ func Run(cfg *config.Config) {
 	}
 
 	// Shutdown
+	// the consumer stops and its workers return before the deferred kafka clients are closed
+	kafkaCancel()
+	<-kafkaDone
+
 	err = httpServer.Shutdown()
 	if err != nil {
 		l.Info("app - Run - httpServer.Shutdown: %s", err)
 	}
+
+	jobScheduler.Shutdown()
 }
@@ -0,0 +1,42 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/idoyudha/eshop-warehouse/config"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase/repo"
+	"github.com/idoyudha/eshop-warehouse/pkg/logger"
+	"github.com/idoyudha/eshop-warehouse/pkg/postgresql"
+)
+
+// Reconcile compares every warehouse product against its movement ledger and logs the discrepancies.
+// It returns the number of discrepancies found.
+func Reconcile(cfg *config.Config) (int, error) {
+	l := logger.New(cfg.Log.Level)
+
+	postgreSQL, err := postgresql.NewPostgres(cfg.PostgreSQL)
+	if err != nil {
+		return 0, fmt.Errorf("app - Reconcile - postgresql.NewPostgres: %w", err)
+	}
+	defer postgreSQL.Conn.Close()
+
+	stockHistoryUseCase := usecase.NewStockHistoryUseCase(
+		repo.NewStockHistoryPostgreRepo(postgreSQL),
+	)
+
+	discrepancies, err := stockHistoryUseCase.ReconcileStock(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("app - Reconcile - stockHistoryUseCase.ReconcileStock: %w", err)
+	}
+
+	for _, discrepancy := range discrepancies {
+		l.Info("app - Reconcile - product %s (%s) in warehouse %s has quantity %d but ledger quantity %d, difference %d",
+			discrepancy.ProductID, discrepancy.ProductName, discrepancy.WarehouseID,
+			discrepancy.Quantity, discrepancy.LedgerQuantity, discrepancy.Difference())
+	}
+	l.Info("app - Reconcile - found %d discrepancies", len(discrepancies))
+
+	return len(discrepancies), nil
+}
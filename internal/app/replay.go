@@ -0,0 +1,89 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	confluentKafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/idoyudha/eshop-warehouse/config"
+	"github.com/idoyudha/eshop-warehouse/pkg/kafka"
+	"github.com/idoyudha/eshop-warehouse/pkg/logger"
+)
+
+// the replay stops once no dead letter arrives within this timeout
+const replayIdleTimeout = 10 * time.Second
+
+// ReplayDeadLetters publishes the dead lettered payloads back to their original topics, or only to the given topic.
+// It returns the number of replayed messages.
+func ReplayDeadLetters(cfg *config.Config, topic string) (int, error) {
+	l := logger.New(cfg.Log.Level)
+
+	topics := kafka.ConsumedTopics()
+	if topic != "" {
+		if !slices.Contains(topics, topic) {
+			return 0, fmt.Errorf("app - ReplayDeadLetters - unknown topic %s", topic)
+		}
+		topics = []string{topic}
+	}
+
+	kafkaProducer, err := kafka.NewKafkaProducer(cfg.Kafka)
+	if err != nil {
+		return 0, fmt.Errorf("app - ReplayDeadLetters - kafka.NewKafkaProducer: %w", err)
+	}
+	defer kafkaProducer.Close()
+
+	deadLetterConsumer, err := kafka.NewKafkaDeadLetterConsumer(cfg.Kafka, topics)
+	if err != nil {
+		return 0, fmt.Errorf("app - ReplayDeadLetters - kafka.NewKafkaDeadLetterConsumer: %w", err)
+	}
+	defer deadLetterConsumer.Close()
+
+	return replayDeadLetters(deadLetterConsumer.Consumer, kafkaProducer, l, replayIdleTimeout)
+}
+
+// deadLetterReader is implemented by *confluentKafka.Consumer
+type deadLetterReader interface {
+	ReadMessage(timeout time.Duration) (*confluentKafka.Message, error)
+	CommitMessage(m *confluentKafka.Message) ([]confluentKafka.TopicPartition, error)
+}
+
+// rawProducer is implemented by *kafka.ProducerServer
+type rawProducer interface {
+	ProduceRawSync(topic string, key []byte, value []byte) error
+}
+
+func replayDeadLetters(consumer deadLetterReader, producer rawProducer, l logger.Interface, idleTimeout time.Duration) (int, error) {
+	replayed := 0
+	for {
+		ev, err := consumer.ReadMessage(idleTimeout)
+		if err != nil {
+			if kerr, ok := err.(confluentKafka.Error); ok && kerr.Code() == confluentKafka.ErrTimedOut {
+				break
+			}
+			return replayed, fmt.Errorf("app - ReplayDeadLetters - ReadMessage: %w", err)
+		}
+
+		var deadLetter kafka.DeadLetter
+		if err := json.Unmarshal(ev.Value, &deadLetter); err != nil {
+			return replayed, fmt.Errorf("app - ReplayDeadLetters - json.Unmarshal: %w", err)
+		}
+
+		// the offset is committed after the payload is back on its topic, so a failed replay is retried by the next run
+		err = producer.ProduceRawSync(deadLetter.Topic, []byte(deadLetter.Key), []byte(deadLetter.Payload))
+		if err != nil {
+			return replayed, fmt.Errorf("app - ReplayDeadLetters - kafkaProducer.ProduceRawSync: %w", err)
+		}
+		if _, err := consumer.CommitMessage(ev); err != nil {
+			return replayed, fmt.Errorf("app - ReplayDeadLetters - CommitMessage: %w", err)
+		}
+
+		l.Info("app - ReplayDeadLetters - replayed message of %s offset %d, failed after %d attempts: %s",
+			deadLetter.Topic, deadLetter.Offset, deadLetter.Attempts, deadLetter.Error)
+		replayed++
+	}
+	l.Info("app - ReplayDeadLetters - replayed %d messages", replayed)
+
+	return replayed, nil
+}
@@ -0,0 +1,142 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	confluentKafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/idoyudha/eshop-warehouse/pkg/kafka"
+	"github.com/idoyudha/eshop-warehouse/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDeadLetterReader returns the messages in order, then times out like an idle topic
+type fakeDeadLetterReader struct {
+	messages  []*confluentKafka.Message
+	commitErr error
+	committed []*confluentKafka.Message
+}
+
+func (r *fakeDeadLetterReader) ReadMessage(time.Duration) (*confluentKafka.Message, error) {
+	if len(r.messages) == 0 {
+		return nil, confluentKafka.NewError(confluentKafka.ErrTimedOut, "timed out", false)
+	}
+	msg := r.messages[0]
+	r.messages = r.messages[1:]
+	return msg, nil
+}
+
+func (r *fakeDeadLetterReader) CommitMessage(m *confluentKafka.Message) ([]confluentKafka.TopicPartition, error) {
+	if r.commitErr != nil {
+		return nil, r.commitErr
+	}
+	r.committed = append(r.committed, m)
+	return []confluentKafka.TopicPartition{m.TopicPartition}, nil
+}
+
+type mockRawProducer struct {
+	mock.Mock
+}
+
+func (m *mockRawProducer) ProduceRawSync(topic string, key []byte, value []byte) error {
+	args := m.Called(topic, key, value)
+	return args.Error(0)
+}
+
+func newDeadLetterMessage(t *testing.T, offset confluentKafka.Offset, key string, payload string) *confluentKafka.Message {
+	topic := kafka.DeadLetterTopic(kafka.OrderCreatedTopic)
+	value, err := json.Marshal(kafka.DeadLetter{
+		Topic:    kafka.OrderCreatedTopic,
+		Key:      key,
+		Payload:  payload,
+		Error:    "database error",
+		Attempts: 3,
+	})
+	require.NoError(t, err)
+	return &confluentKafka.Message{
+		TopicPartition: confluentKafka.TopicPartition{Topic: &topic, Offset: offset},
+		Value:          value,
+	}
+}
+
+func TestReplayDeadLetters(t *testing.T) {
+	tests := []struct {
+		name          string
+		messages      int
+		mockBehavior  func(*mockRawProducer)
+		commitErr     error
+		wantReplayed  int
+		wantCommitted []confluentKafka.Offset
+		wantErr       bool
+	}{
+		{
+			name:     "replays every dead letter",
+			messages: 2,
+			mockBehavior: func(m *mockRawProducer) {
+				m.On("ProduceRawSync", kafka.OrderCreatedTopic, []byte("order-0"), []byte(`{"order_id": 0}`)).Return(nil).Once()
+				m.On("ProduceRawSync", kafka.OrderCreatedTopic, []byte("order-1"), []byte(`{"order_id": 1}`)).Return(nil).Once()
+			},
+			wantReplayed:  2,
+			wantCommitted: []confluentKafka.Offset{0, 1},
+		},
+		{
+			name:          "nothing to replay",
+			wantCommitted: []confluentKafka.Offset{},
+		},
+		{
+			name:     "not committed when the produce fails",
+			messages: 2,
+			mockBehavior: func(m *mockRawProducer) {
+				m.On("ProduceRawSync", kafka.OrderCreatedTopic, []byte("order-0"), mock.Anything).Return(nil).Once()
+				m.On("ProduceRawSync", kafka.OrderCreatedTopic, []byte("order-1"), mock.Anything).Return(errors.New("broker unavailable")).Once()
+			},
+			wantReplayed:  1,
+			wantCommitted: []confluentKafka.Offset{0},
+			wantErr:       true,
+		},
+		{
+			name:     "commit error stops the replay",
+			messages: 2,
+			mockBehavior: func(m *mockRawProducer) {
+				m.On("ProduceRawSync", kafka.OrderCreatedTopic, []byte("order-0"), mock.Anything).Return(nil).Once()
+			},
+			commitErr:     errors.New("coordinator unavailable"),
+			wantCommitted: []confluentKafka.Offset{},
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &fakeDeadLetterReader{commitErr: tt.commitErr}
+			for i := range tt.messages {
+				reader.messages = append(reader.messages,
+					newDeadLetterMessage(t, confluentKafka.Offset(i), fmt.Sprintf("order-%d", i), fmt.Sprintf(`{"order_id": %d}`, i)))
+			}
+			producer := new(mockRawProducer)
+			if tt.mockBehavior != nil {
+				tt.mockBehavior(producer)
+			}
+
+			replayed, err := replayDeadLetters(reader, producer, logger.New("error"), time.Millisecond)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantReplayed, replayed)
+			committed := []confluentKafka.Offset{}
+			for _, msg := range reader.committed {
+				committed = append(committed, msg.TopicPartition.Offset)
+			}
+			assert.Equal(t, tt.wantCommitted, committed)
+			producer.AssertExpectations(t)
+		})
+	}
+}
@@ -2,6 +2,8 @@ package v1
 
 import (
 	"net/http"
+
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
 )
 
 type restError struct {
@@ -14,6 +16,16 @@ type errorMessage struct {
 	Causes  error  `json:"causes"`
 }
 
+type insufficientStockRestError struct {
+	Code  int                    `json:"code"`
+	Error insufficientStockCause `json:"error"`
+}
+
+type insufficientStockCause struct {
+	Message string                         `json:"message"`
+	Items   []entity.InsufficientStockItem `json:"items"`
+}
+
 func newBadRequestError(message string) *restError {
 	return &restError{
 		Code: http.StatusBadRequest,
@@ -32,6 +44,24 @@ func newNotFoundError(message string) *restError {
 	}
 }
 
+func newConflictError(message string) *restError {
+	return &restError{
+		Code: http.StatusConflict,
+		Error: errorMessage{
+			Message: message,
+		},
+	}
+}
+
+func newUnprocessableEntityError(message string) *restError {
+	return &restError{
+		Code: http.StatusUnprocessableEntity,
+		Error: errorMessage{
+			Message: message,
+		},
+	}
+}
+
 func newInternalServerError(message string) *restError {
 	return &restError{
 		Code: http.StatusInternalServerError,
@@ -49,3 +79,13 @@ func newUnauthorizedError(message string) *restError {
 		},
 	}
 }
+
+func newInsufficientStockError(err *entity.InsufficientStockError) *insufficientStockRestError {
+	return &insufficientStockRestError{
+		Code: http.StatusConflict,
+		Error: insufficientStockCause{
+			Message: err.Error(),
+			Items:   err.Items,
+		},
+	}
+}
@@ -0,0 +1,58 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/idoyudha/eshop-warehouse/pkg/logger"
+)
+
+type forecastRoutes struct {
+	uc usecase.Forecast
+	l  logger.Interface
+}
+
+func newForecastRoutes(handler *gin.RouterGroup, uc usecase.Forecast, l logger.Interface, authMid gin.HandlerFunc) {
+	r := &forecastRoutes{uc: uc, l: l}
+
+	h := handler.Group("/forecasts").Use(authMid)
+	{
+		h.GET("", r.forecastDemand)
+	}
+}
+
+// parameters which are not set use the configured defaults
+type forecastDemandQuery struct {
+	Method      string  `form:"method" binding:"omitempty,oneof=moving-average exponential-smoothing"`
+	HistoryDays int     `form:"history_days" binding:"omitempty,gt=0"`
+	Window      int     `form:"window" binding:"omitempty,gt=0"`
+	Alpha       float64 `form:"alpha" binding:"omitempty,gt=0,lte=1"`
+	WarehouseID string  `form:"warehouse_id" binding:"omitempty,uuid"`
+	ProductID   string  `form:"product_id" binding:"omitempty,uuid"`
+}
+
+func (r *forecastRoutes) forecastDemand(ctx *gin.Context) {
+	var query forecastDemandQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		r.l.Error(err, "http - v1 - forecastRoutes - forecastDemand")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	forecasts, err := r.uc.ForecastDemand(context.Background(), forecastDemandQueryToForecastParams(query))
+	if err != nil {
+		r.l.Error(err, "http - v1 - forecastRoutes - forecastDemand")
+		if errors.Is(err, entity.ErrInvalidForecast) {
+			ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newGetSuccess(forecasts))
+}
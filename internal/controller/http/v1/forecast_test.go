@@ -0,0 +1,124 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockForecastUsecase struct {
+	mock.Mock
+}
+
+func (m *mockForecastUsecase) ForecastDemand(ctx context.Context, params entity.ForecastParams) ([]*entity.DemandForecast, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.DemandForecast), args.Error(1)
+}
+
+var _ usecase.Forecast = (*mockForecastUsecase)(nil)
+
+func newForecastTestRouter(uc *mockForecastUsecase, l *MockLogger) *gin.Engine {
+	router := gin.New()
+	handler := router.Group("/api/v1")
+	newForecastRoutes(
+		handler,
+		uc,
+		l,
+		func(c *gin.Context) {
+			c.Next()
+		},
+	)
+	return router
+}
+
+func TestForecastDemand(t *testing.T) {
+	// t.Parallell()
+	productID := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		mockBehavior   func(*mockForecastUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			query:          fmt.Sprintf("?method=moving-average&window=7&product_id=%s", productID),
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockForecastUsecase, l *MockLogger) {
+				m.On("ForecastDemand",
+					mock.Anything,
+					entity.ForecastParams{Method: entity.ForecastMethodMovingAverage, Window: 7, ProductID: productID},
+				).Return([]*entity.DemandForecast{{ProductID: productID, DailyDemand: 2.5}}, nil)
+			},
+		},
+		{
+			name:           "Unknown Method",
+			query:          "?method=median",
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockForecastUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Invalid Warehouse ID",
+			query:          "?warehouse_id=invalid",
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockForecastUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Invalid Forecast",
+			query:          "?window=60",
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockForecastUsecase, l *MockLogger) {
+				m.On("ForecastDemand", mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf("window must be between 1 and 28 days: %w", entity.ErrInvalidForecast))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Usecase Error",
+			query:          "",
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockForecastUsecase, l *MockLogger) {
+				m.On("ForecastDemand", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("database error"))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockForecastUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := newForecastTestRouter(mockUC, mockLogger)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/forecasts"+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/idoyudha/eshop-warehouse/pkg/logger"
+)
+
+type inboundReceiptRoutes struct {
+	uc usecase.InboundReceipt
+	l  logger.Interface
+}
+
+func newInboundReceiptRoutes(handler *gin.RouterGroup, uc usecase.InboundReceipt, l logger.Interface, authMid gin.HandlerFunc) {
+	r := &inboundReceiptRoutes{uc: uc, l: l}
+
+	h := handler.Group("/inbound-receipts").Use(authMid)
+	{
+		h.POST("", r.createInboundReceipt)
+		h.GET("/:id", r.getInboundReceiptByID)
+		h.POST("/:id/receive", r.receiveInboundReceipt)
+		h.POST("/:id/close", r.closeInboundReceipt)
+	}
+}
+
+type createInboundReceiptRequest struct {
+	WarehouseID     uuid.UUID                             `json:"warehouse_id" binding:"required"`
+	Type            string                                `json:"type" binding:"required,oneof=purchase-order asn"`
+	ReferenceNumber string                                `json:"reference_number" binding:"required"`
+	Lines           []itemCreateInboundReceiptLineRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+type itemCreateInboundReceiptLineRequest struct {
+	ProductID        uuid.UUID `json:"product_id" binding:"required"`
+	ProductName      string    `json:"product_name" binding:"required"`
+	ExpectedQuantity int64     `json:"expected_quantity" binding:"required,gt=0"`
+	UnitCost         float64   `json:"unit_cost" binding:"gte=0"`
+}
+
+func (r *inboundReceiptRoutes) createInboundReceipt(ctx *gin.Context) {
+	var req createInboundReceiptRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - inboundReceiptRoutes - createInboundReceipt")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	userID, exist := ctx.Get(UserIDKey)
+	if !exist {
+		r.l.Error("not exist", "http - v1 - inboundReceiptRoutes - createInboundReceipt")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError("user id not exist"))
+		return
+	}
+
+	inboundReceipt := createInboundReceiptRequestToInboundReceiptEntity(req, userID.(uuid.UUID))
+	err := r.uc.CreateInboundReceipt(context.Background(), &inboundReceipt)
+	if err != nil {
+		r.l.Error(err, "http - v1 - inboundReceiptRoutes - createInboundReceipt")
+		r.handleInboundReceiptError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newCreateSuccess(inboundReceipt))
+}
+
+func (r *inboundReceiptRoutes) getInboundReceiptByID(ctx *gin.Context) {
+	inboundReceiptID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - inboundReceiptRoutes - getInboundReceiptByID")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	inboundReceipt, err := r.uc.GetInboundReceiptByID(context.Background(), inboundReceiptID)
+	if err != nil {
+		r.l.Error(err, "http - v1 - inboundReceiptRoutes - getInboundReceiptByID")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newGetSuccess(inboundReceipt))
+}
+
+type receiveInboundReceiptRequest struct {
+	Lines []itemReceiveInboundReceiptLineRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+type itemReceiveInboundReceiptLineRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	Quantity  int64     `json:"quantity" binding:"required,gt=0"`
+}
+
+func (r *inboundReceiptRoutes) receiveInboundReceipt(ctx *gin.Context) {
+	inboundReceiptID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - inboundReceiptRoutes - receiveInboundReceipt")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	var req receiveInboundReceiptRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - inboundReceiptRoutes - receiveInboundReceipt")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	userID, exist := ctx.Get(UserIDKey)
+	if !exist {
+		r.l.Error("not exist", "http - v1 - inboundReceiptRoutes - receiveInboundReceipt")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError("user id not exist"))
+		return
+	}
+
+	idempotencyKey, ok := getIdempotencyKey(ctx, entity.IdempotencyOperationInboundReceiptReceive)
+	if !ok {
+		r.l.Error("idempotency key too long", "http - v1 - inboundReceiptRoutes - receiveInboundReceipt")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError("idempotency key too long"))
+		return
+	}
+
+	inboundReceipt := receiveInboundReceiptRequestToInboundReceiptEntity(req, inboundReceiptID, userID.(uuid.UUID))
+	err = r.uc.ReceiveInboundReceipt(context.Background(), &inboundReceipt, idempotencyKey)
+	if err != nil {
+		r.l.Error(err, "http - v1 - inboundReceiptRoutes - receiveInboundReceipt")
+		r.handleInboundReceiptError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUpdateSuccess(inboundReceipt))
+}
+
+func (r *inboundReceiptRoutes) closeInboundReceipt(ctx *gin.Context) {
+	inboundReceiptID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - inboundReceiptRoutes - closeInboundReceipt")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	inboundReceipt := entity.InboundReceipt{ID: inboundReceiptID}
+	err = r.uc.CloseInboundReceipt(context.Background(), &inboundReceipt)
+	if err != nil {
+		r.l.Error(err, "http - v1 - inboundReceiptRoutes - closeInboundReceipt")
+		r.handleInboundReceiptError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUpdateSuccess(inboundReceipt))
+}
+
+// closed receipt, unknown products and invalid lines are client errors
+func (r *inboundReceiptRoutes) handleInboundReceiptError(ctx *gin.Context, err error) {
+	if errors.Is(err, entity.ErrIdempotencyKeyReused) {
+		ctx.JSON(http.StatusUnprocessableEntity, newUnprocessableEntityError(err.Error()))
+		return
+	}
+	if errors.Is(err, entity.ErrInvalidInboundReceipt) {
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+}
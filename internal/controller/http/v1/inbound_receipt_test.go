@@ -0,0 +1,244 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockInboundReceiptUsecase struct {
+	mock.Mock
+}
+
+func (m *mockInboundReceiptUsecase) CreateInboundReceipt(ctx context.Context, inboundReceipt *entity.InboundReceipt) error {
+	args := m.Called(ctx, inboundReceipt)
+	return args.Error(0)
+}
+
+func (m *mockInboundReceiptUsecase) GetInboundReceiptByID(ctx context.Context, id uuid.UUID) (*entity.InboundReceipt, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.InboundReceipt), args.Error(1)
+}
+
+func (m *mockInboundReceiptUsecase) ReceiveInboundReceipt(ctx context.Context, inboundReceipt *entity.InboundReceipt, idempotencyKey string) error {
+	args := m.Called(ctx, inboundReceipt, idempotencyKey)
+	return args.Error(0)
+}
+
+func (m *mockInboundReceiptUsecase) CloseInboundReceipt(ctx context.Context, inboundReceipt *entity.InboundReceipt) error {
+	args := m.Called(ctx, inboundReceipt)
+	return args.Error(0)
+}
+
+var _ usecase.InboundReceipt = (*mockInboundReceiptUsecase)(nil)
+
+func newInboundReceiptTestRouter(uc *mockInboundReceiptUsecase, l *MockLogger, userID uuid.UUID) *gin.Engine {
+	router := gin.New()
+	handler := router.Group("/api/v1")
+	newInboundReceiptRoutes(
+		handler,
+		uc,
+		l,
+		func(c *gin.Context) {
+			c.Set(UserIDKey, userID)
+			c.Next()
+		},
+	)
+	return router
+}
+
+func TestCreateInboundReceipt(t *testing.T) {
+	// t.Parallell()
+	userID := uuid.New()
+	warehouseID := uuid.New()
+	productID := uuid.New()
+
+	tests := []struct {
+		name           string
+		inputJSON      string
+		expectedStatus int
+		mockBehavior   func(*mockInboundReceiptUsecase, *MockLogger)
+	}{
+		{
+			name: "Success",
+			inputJSON: fmt.Sprintf(`{"warehouse_id": "%s", "type": "purchase-order", "reference_number": "PO-001",
+				"lines": [{"product_id": "%s", "product_name": "Product A", "expected_quantity": 10}]}`, warehouseID, productID),
+			expectedStatus: http.StatusCreated,
+			mockBehavior: func(m *mockInboundReceiptUsecase, l *MockLogger) {
+				m.On("CreateInboundReceipt",
+					mock.Anything,
+					mock.MatchedBy(func(ir *entity.InboundReceipt) bool {
+						return ir.WarehouseID == warehouseID &&
+							ir.Type == entity.InboundReceiptTypePurchaseOrder &&
+							ir.ReferenceNumber == "PO-001" &&
+							ir.CreatedBy == userID &&
+							len(ir.Lines) == 1 &&
+							ir.Lines[0].ExpectedQuantity == 10
+					}),
+				).Return(nil)
+			},
+		},
+		{
+			name: "Invalid Type",
+			inputJSON: fmt.Sprintf(`{"warehouse_id": "%s", "type": "invoice", "reference_number": "PO-001",
+				"lines": [{"product_id": "%s", "product_name": "Product A", "expected_quantity": 10}]}`, warehouseID, productID),
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockInboundReceiptUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Missing Lines",
+			inputJSON:      fmt.Sprintf(`{"warehouse_id": "%s", "type": "asn", "reference_number": "ASN-001", "lines": []}`, warehouseID),
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockInboundReceiptUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name: "Duplicated Product",
+			inputJSON: fmt.Sprintf(`{"warehouse_id": "%s", "type": "asn", "reference_number": "ASN-001",
+				"lines": [{"product_id": "%s", "product_name": "Product A", "expected_quantity": 1},
+				{"product_id": "%s", "product_name": "Product A", "expected_quantity": 2}]}`, warehouseID, productID, productID),
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockInboundReceiptUsecase, l *MockLogger) {
+				m.On("CreateInboundReceipt", mock.Anything, mock.Anything).
+					Return(fmt.Errorf("product %s is duplicated: %w", productID, entity.ErrInvalidInboundReceipt))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockInboundReceiptUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := newInboundReceiptTestRouter(mockUC, mockLogger, userID)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/inbound-receipts", bytes.NewBufferString(tt.inputJSON))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReceiveInboundReceipt(t *testing.T) {
+	// t.Parallell()
+	userID := uuid.New()
+	inboundReceiptID := uuid.New()
+	productID := uuid.New()
+	idempotencyKey := entity.ScopedIdempotencyKey(entity.IdempotencyOperationInboundReceiptReceive, userID, "receive-1")
+
+	tests := []struct {
+		name           string
+		id             string
+		inputJSON      string
+		expectedStatus int
+		mockBehavior   func(*mockInboundReceiptUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			id:             inboundReceiptID.String(),
+			inputJSON:      fmt.Sprintf(`{"lines": [{"product_id": "%s", "quantity": 4}]}`, productID),
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockInboundReceiptUsecase, l *MockLogger) {
+				m.On("ReceiveInboundReceipt",
+					mock.Anything,
+					mock.MatchedBy(func(ir *entity.InboundReceipt) bool {
+						return ir.ID == inboundReceiptID &&
+							ir.ReceivedBy == userID &&
+							len(ir.Lines) == 1 &&
+							ir.Lines[0].ProductID == productID &&
+							ir.Lines[0].ReceivedQuantity == 4
+					}),
+					idempotencyKey,
+				).Return(nil)
+			},
+		},
+		{
+			name:           "Invalid ID",
+			id:             "invalid-uuid",
+			inputJSON:      fmt.Sprintf(`{"lines": [{"product_id": "%s", "quantity": 4}]}`, productID),
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockInboundReceiptUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Zero Quantity",
+			id:             inboundReceiptID.String(),
+			inputJSON:      fmt.Sprintf(`{"lines": [{"product_id": "%s", "quantity": 0}]}`, productID),
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockInboundReceiptUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Receipt Closed",
+			id:             inboundReceiptID.String(),
+			inputJSON:      fmt.Sprintf(`{"lines": [{"product_id": "%s", "quantity": 4}]}`, productID),
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockInboundReceiptUsecase, l *MockLogger) {
+				m.On("ReceiveInboundReceipt", mock.Anything, mock.Anything, idempotencyKey).
+					Return(fmt.Errorf("failed to receive inbound receipt: %w", entity.ErrInvalidInboundReceipt))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Internal Error",
+			id:             inboundReceiptID.String(),
+			inputJSON:      fmt.Sprintf(`{"lines": [{"product_id": "%s", "quantity": 4}]}`, productID),
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockInboundReceiptUsecase, l *MockLogger) {
+				m.On("ReceiveInboundReceipt", mock.Anything, mock.Anything, idempotencyKey).Return(fmt.Errorf("database error"))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockInboundReceiptUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := newInboundReceiptTestRouter(mockUC, mockLogger, userID)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/inbound-receipts/"+tt.id+"/receive", bytes.NewBufferString(tt.inputJSON))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(IdempotencyKeyHeader, "receive-1")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
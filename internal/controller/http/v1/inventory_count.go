@@ -0,0 +1,171 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/idoyudha/eshop-warehouse/pkg/logger"
+)
+
+type inventoryCountRoutes struct {
+	uc usecase.InventoryCount
+	l  logger.Interface
+}
+
+func newInventoryCountRoutes(handler *gin.RouterGroup, uc usecase.InventoryCount, l logger.Interface, authMid gin.HandlerFunc) {
+	r := &inventoryCountRoutes{uc: uc, l: l}
+
+	h := handler.Group("/inventory-counts").Use(authMid)
+	{
+		h.POST("", r.openInventoryCount)
+		h.GET("/:id", r.getInventoryCountByID)
+		h.PUT("/:id/lines", r.submitInventoryCountLines)
+		h.POST("/:id/post", r.postInventoryCount)
+		h.DELETE("/:id", r.cancelInventoryCount)
+	}
+}
+
+type openInventoryCountRequest struct {
+	WarehouseID uuid.UUID `json:"warehouse_id" binding:"required"`
+}
+
+func (r *inventoryCountRoutes) openInventoryCount(ctx *gin.Context) {
+	var req openInventoryCountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - inventoryCountRoutes - openInventoryCount")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	userID, exist := ctx.Get(UserIDKey)
+	if !exist {
+		r.l.Error("not exist", "http - v1 - inventoryCountRoutes - openInventoryCount")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError("user id not exist"))
+		return
+	}
+
+	inventoryCount := openInventoryCountRequestToInventoryCountEntity(req, userID.(uuid.UUID))
+	err := r.uc.OpenInventoryCount(context.Background(), &inventoryCount)
+	if err != nil {
+		r.l.Error(err, "http - v1 - inventoryCountRoutes - openInventoryCount")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newCreateSuccess(inventoryCount))
+}
+
+func (r *inventoryCountRoutes) getInventoryCountByID(ctx *gin.Context) {
+	inventoryCountID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - inventoryCountRoutes - getInventoryCountByID")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	inventoryCount, err := r.uc.GetInventoryCountByID(context.Background(), inventoryCountID)
+	if err != nil {
+		r.l.Error(err, "http - v1 - inventoryCountRoutes - getInventoryCountByID")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newGetSuccess(inventoryCount))
+}
+
+type submitInventoryCountLinesRequest struct {
+	Lines []itemInventoryCountLineRequest `json:"lines" binding:"required,dive"`
+}
+
+type itemInventoryCountLineRequest struct {
+	ProductID       uuid.UUID `json:"product_id" binding:"required"`
+	CountedQuantity *int64    `json:"counted_quantity" binding:"required,gte=0"`
+}
+
+func (r *inventoryCountRoutes) submitInventoryCountLines(ctx *gin.Context) {
+	inventoryCountID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - inventoryCountRoutes - submitInventoryCountLines")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	var req submitInventoryCountLinesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - inventoryCountRoutes - submitInventoryCountLines")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	inventoryCount := submitInventoryCountLinesRequestToInventoryCountEntity(req, inventoryCountID)
+	err = r.uc.SubmitInventoryCountLines(context.Background(), &inventoryCount)
+	if err != nil {
+		r.l.Error(err, "http - v1 - inventoryCountRoutes - submitInventoryCountLines")
+		r.handleInventoryCountError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUpdateSuccess(inventoryCount))
+}
+
+func (r *inventoryCountRoutes) postInventoryCount(ctx *gin.Context) {
+	inventoryCountID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - inventoryCountRoutes - postInventoryCount")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	userID, exist := ctx.Get(UserIDKey)
+	if !exist {
+		r.l.Error("not exist", "http - v1 - inventoryCountRoutes - postInventoryCount")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError("user id not exist"))
+		return
+	}
+
+	inventoryCount := entity.InventoryCount{
+		ID:       inventoryCountID,
+		PostedBy: userID.(uuid.UUID),
+	}
+	err = r.uc.PostInventoryCount(context.Background(), &inventoryCount)
+	if err != nil {
+		r.l.Error(err, "http - v1 - inventoryCountRoutes - postInventoryCount")
+		r.handleInventoryCountError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUpdateSuccess(inventoryCount))
+}
+
+func (r *inventoryCountRoutes) cancelInventoryCount(ctx *gin.Context) {
+	inventoryCountID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - inventoryCountRoutes - cancelInventoryCount")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	inventoryCount := entity.InventoryCount{ID: inventoryCountID}
+	err = r.uc.CancelInventoryCount(context.Background(), &inventoryCount)
+	if err != nil {
+		r.l.Error(err, "http - v1 - inventoryCountRoutes - cancelInventoryCount")
+		r.handleInventoryCountError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newDeleteSuccess())
+}
+
+// inventory count which is not open, unknown products and variances making the quantity negative are client errors
+func (r *inventoryCountRoutes) handleInventoryCountError(ctx *gin.Context, err error) {
+	if errors.Is(err, entity.ErrInvalidInventoryCount) || errors.Is(err, entity.ErrInvalidAdjustment) {
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+}
@@ -0,0 +1,351 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockInventoryCountUsecase struct {
+	mock.Mock
+}
+
+func (m *mockInventoryCountUsecase) OpenInventoryCount(ctx context.Context, inventoryCount *entity.InventoryCount) error {
+	args := m.Called(ctx, inventoryCount)
+	return args.Error(0)
+}
+
+func (m *mockInventoryCountUsecase) GetInventoryCountByID(ctx context.Context, id uuid.UUID) (*entity.InventoryCount, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.InventoryCount), args.Error(1)
+}
+
+func (m *mockInventoryCountUsecase) SubmitInventoryCountLines(ctx context.Context, inventoryCount *entity.InventoryCount) error {
+	args := m.Called(ctx, inventoryCount)
+	return args.Error(0)
+}
+
+func (m *mockInventoryCountUsecase) PostInventoryCount(ctx context.Context, inventoryCount *entity.InventoryCount) error {
+	args := m.Called(ctx, inventoryCount)
+	return args.Error(0)
+}
+
+func (m *mockInventoryCountUsecase) CancelInventoryCount(ctx context.Context, inventoryCount *entity.InventoryCount) error {
+	args := m.Called(ctx, inventoryCount)
+	return args.Error(0)
+}
+
+var _ usecase.InventoryCount = (*mockInventoryCountUsecase)(nil)
+
+func newInventoryCountTestRouter(uc *mockInventoryCountUsecase, l *MockLogger, userID *uuid.UUID) *gin.Engine {
+	router := gin.New()
+	handler := router.Group("/api/v1")
+	newInventoryCountRoutes(
+		handler,
+		uc,
+		l,
+		func(c *gin.Context) {
+			if userID != nil {
+				c.Set(UserIDKey, *userID)
+			}
+			c.Next()
+		},
+	)
+	return router
+}
+
+func TestOpenInventoryCount(t *testing.T) {
+	// t.Parallell()
+	userID := uuid.New()
+	warehouseID := uuid.New()
+
+	tests := []struct {
+		name           string
+		inputJSON      string
+		withUserID     bool
+		expectedStatus int
+		mockBehavior   func(*mockInventoryCountUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			inputJSON:      fmt.Sprintf(`{"warehouse_id": "%s"}`, warehouseID),
+			withUserID:     true,
+			expectedStatus: http.StatusCreated,
+			mockBehavior: func(m *mockInventoryCountUsecase, l *MockLogger) {
+				m.On("OpenInventoryCount",
+					mock.Anything,
+					mock.MatchedBy(func(ic *entity.InventoryCount) bool {
+						return ic.WarehouseID == warehouseID && ic.CreatedBy == userID
+					}),
+				).Return(nil)
+			},
+		},
+		{
+			name:           "Missing Warehouse ID",
+			inputJSON:      `{}`,
+			withUserID:     true,
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockInventoryCountUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "User ID Not Exist",
+			inputJSON:      fmt.Sprintf(`{"warehouse_id": "%s"}`, warehouseID),
+			withUserID:     false,
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockInventoryCountUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Usecase Error",
+			inputJSON:      fmt.Sprintf(`{"warehouse_id": "%s"}`, warehouseID),
+			withUserID:     true,
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockInventoryCountUsecase, l *MockLogger) {
+				m.On("OpenInventoryCount", mock.Anything, mock.Anything).Return(fmt.Errorf("database error"))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockInventoryCountUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			var user *uuid.UUID
+			if tt.withUserID {
+				user = &userID
+			}
+			router := newInventoryCountTestRouter(mockUC, mockLogger, user)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/inventory-counts", bytes.NewBufferString(tt.inputJSON))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSubmitInventoryCountLines(t *testing.T) {
+	// t.Parallell()
+	inventoryCountID := uuid.New()
+	productID := uuid.New()
+
+	tests := []struct {
+		name           string
+		id             string
+		inputJSON      string
+		expectedStatus int
+		mockBehavior   func(*mockInventoryCountUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			id:             inventoryCountID.String(),
+			inputJSON:      fmt.Sprintf(`{"lines": [{"product_id": "%s", "counted_quantity": 0}]}`, productID),
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockInventoryCountUsecase, l *MockLogger) {
+				m.On("SubmitInventoryCountLines",
+					mock.Anything,
+					mock.MatchedBy(func(ic *entity.InventoryCount) bool {
+						return ic.ID == inventoryCountID &&
+							len(ic.Lines) == 1 &&
+							ic.Lines[0].ProductID == productID &&
+							*ic.Lines[0].CountedQuantity == 0
+					}),
+				).Return(nil)
+			},
+		},
+		{
+			name:           "Invalid ID",
+			id:             "invalid-uuid",
+			inputJSON:      fmt.Sprintf(`{"lines": [{"product_id": "%s", "counted_quantity": 1}]}`, productID),
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockInventoryCountUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Missing Counted Quantity",
+			id:             inventoryCountID.String(),
+			inputJSON:      fmt.Sprintf(`{"lines": [{"product_id": "%s"}]}`, productID),
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockInventoryCountUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Inventory Count Not Open",
+			id:             inventoryCountID.String(),
+			inputJSON:      fmt.Sprintf(`{"lines": [{"product_id": "%s", "counted_quantity": 1}]}`, productID),
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockInventoryCountUsecase, l *MockLogger) {
+				m.On("SubmitInventoryCountLines", mock.Anything, mock.Anything).
+					Return(fmt.Errorf("failed to submit inventory count lines: %w", entity.ErrInvalidInventoryCount))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockInventoryCountUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := newInventoryCountTestRouter(mockUC, mockLogger, nil)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPut, "/api/v1/inventory-counts/"+tt.id+"/lines", bytes.NewBufferString(tt.inputJSON))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPostInventoryCount(t *testing.T) {
+	// t.Parallell()
+	userID := uuid.New()
+	inventoryCountID := uuid.New()
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockBehavior   func(*mockInventoryCountUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockInventoryCountUsecase, l *MockLogger) {
+				m.On("PostInventoryCount",
+					mock.Anything,
+					mock.MatchedBy(func(ic *entity.InventoryCount) bool {
+						return ic.ID == inventoryCountID && ic.PostedBy == userID
+					}),
+				).Return(nil)
+			},
+		},
+		{
+			name:           "Negative Quantity After Adjustment",
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockInventoryCountUsecase, l *MockLogger) {
+				m.On("PostInventoryCount", mock.Anything, mock.Anything).
+					Return(fmt.Errorf("failed to post inventory count: %w", entity.ErrInvalidAdjustment))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Internal Error",
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockInventoryCountUsecase, l *MockLogger) {
+				m.On("PostInventoryCount", mock.Anything, mock.Anything).Return(fmt.Errorf("database error"))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockInventoryCountUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := newInventoryCountTestRouter(mockUC, mockLogger, &userID)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/inventory-counts/"+inventoryCountID.String()+"/post", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCancelInventoryCount(t *testing.T) {
+	// t.Parallell()
+	inventoryCountID := uuid.New()
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockBehavior   func(*mockInventoryCountUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockInventoryCountUsecase, l *MockLogger) {
+				m.On("CancelInventoryCount",
+					mock.Anything,
+					mock.MatchedBy(func(ic *entity.InventoryCount) bool {
+						return ic.ID == inventoryCountID
+					}),
+				).Return(nil)
+			},
+		},
+		{
+			name:           "Already Posted",
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockInventoryCountUsecase, l *MockLogger) {
+				m.On("CancelInventoryCount", mock.Anything, mock.Anything).
+					Return(fmt.Errorf("failed to cancel inventory count: %w", entity.ErrInvalidInventoryCount))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockInventoryCountUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := newInventoryCountTestRouter(mockUC, mockLogger, nil)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodDelete, "/api/v1/inventory-counts/"+inventoryCountID.String(), nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
@@ -14,6 +14,8 @@ func createWarehouseRequestToWarehouseEntity(req createWarehouseRequest) entity.
 		City:            req.City,
 		State:           req.State,
 		ZipCode:         req.ZipCode,
+		Latitude:        req.Latitude,
+		Longitude:       req.Longitude,
 		IsMainWarehouse: false,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
@@ -28,6 +30,8 @@ func warehouseEntityToCreateWarehouseResponse(warehouse entity.Warehouse) create
 		City:            warehouse.City,
 		State:           warehouse.State,
 		ZipCode:         warehouse.ZipCode,
+		Latitude:        warehouse.Latitude,
+		Longitude:       warehouse.Longitude,
 		IsMainWarehouse: warehouse.IsMainWarehouse,
 	}
 }
@@ -45,6 +49,8 @@ func updateWarehouseRequestToWarehouseEntity(req updateWarehouseRequest, warehou
 		ID:        warehouseID,
 		Name:      req.Name,
 		Street:    req.Street,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
 		UpdatedAt: time.Now(),
 	}
 }
@@ -57,21 +63,39 @@ func warehouseEntityToUpdateWarehouseResponse(warehouse entity.Warehouse) update
 		City:            warehouse.City,
 		State:           warehouse.State,
 		ZipCode:         warehouse.ZipCode,
+		Latitude:        warehouse.Latitude,
+		Longitude:       warehouse.Longitude,
 		IsMainWarehouse: warehouse.IsMainWarehouse,
 	}
 }
 
-func createStockMovementInRequestToStockMovementEntity(req CreateStockMovementIn) entity.StockMovement {
+func createStockMovementInRequestToStockMovementEntity(req CreateStockMovementIn, idempotencyKey string) entity.StockMovement {
 	return entity.StockMovement{
 		ProductID:       req.ProductID,
 		ProductName:     req.ProductName,
 		Quantity:        req.Quantity,
 		FromWarehouseID: req.FromWarehouseID,
 		ToWarehouseID:   req.ToWarehouseID,
+		Reason:          req.Reason,
+		ReferenceID:     req.ReferenceID,
+		IdempotencyKey:  idempotencyKey,
 		CreatedAt:       time.Now(),
 	}
 }
 
+func createStockMovementReturnRequestToStockMovementEntity(req createStockMovementReturn, idempotencyKey string) entity.StockMovement {
+	return entity.StockMovement{
+		OriginalMovementID: req.OriginalMovementID,
+		Quantity:           req.Quantity,
+		ToWarehouseID:      req.ToWarehouseID,
+		Disposition:        req.Disposition,
+		Reason:             req.Reason,
+		ReferenceID:        req.ReferenceID,
+		IdempotencyKey:     idempotencyKey,
+		CreatedAt:          time.Now(),
+	}
+}
+
 func warehouseEntityToGetWarehouseResponse(warehouse entity.Warehouse) getWarehouseResponse {
 	return getWarehouseResponse{
 		ID:              warehouse.ID,
@@ -80,20 +104,215 @@ func warehouseEntityToGetWarehouseResponse(warehouse entity.Warehouse) getWareho
 		City:            warehouse.City,
 		State:           warehouse.State,
 		ZipCode:         warehouse.ZipCode,
+		Latitude:        warehouse.Latitude,
+		Longitude:       warehouse.Longitude,
 		IsMainWarehouse: warehouse.IsMainWarehouse,
 	}
 }
 
-func createStockMovementOutRequestToStockMovementEntity(req createStockMovementOut, userID uuid.UUID) []*entity.StockMovement {
+func createStockMovementOutRequestToStockMovementEntity(req createStockMovementOut, userID uuid.UUID, idempotencyKey string) []*entity.StockMovement {
 	var stockMovements []*entity.StockMovement
 	for _, stockMovement := range req.Items {
 		stockMovements = append(stockMovements, &entity.StockMovement{
-			ProductID: stockMovement.ProductID,
-			Quantity:  stockMovement.Quantity,
-			ToUserID:  userID,
-			CreatedAt: time.Now(),
+			ProductID:      stockMovement.ProductID,
+			Quantity:       stockMovement.Quantity,
+			ToUserID:       userID,
+			Reason:         req.Reason,
+			ReferenceID:    req.ReferenceID,
+			IdempotencyKey: idempotencyKey,
+			CreatedAt:      time.Now(),
 		})
 	}
 
 	return stockMovements
 }
+
+func getStockMovementsQueryToStockMovementFilter(query getStockMovementsQuery) entity.StockMovementFilter {
+	return entity.StockMovementFilter{
+		MovementType: query.MovementType,
+		ReferenceID:  query.ReferenceID,
+	}
+}
+
+func adjustWarehouseProductQuantityRequestToStockAdjustmentEntity(req adjustWarehouseProductQuantityRequest, productID, warehouseID, userID uuid.UUID) entity.StockAdjustment {
+	return entity.StockAdjustment{
+		WarehouseID:     warehouseID,
+		ProductID:       productID,
+		Delta:           req.Delta,
+		CountedQuantity: req.CountedQuantity,
+		Reason:          req.Reason,
+		UserID:          userID,
+		CreatedAt:       time.Now(),
+	}
+}
+
+func openInventoryCountRequestToInventoryCountEntity(req openInventoryCountRequest, userID uuid.UUID) entity.InventoryCount {
+	return entity.InventoryCount{
+		WarehouseID: req.WarehouseID,
+		CreatedBy:   userID,
+		CreatedAt:   time.Now(),
+	}
+}
+
+func submitInventoryCountLinesRequestToInventoryCountEntity(req submitInventoryCountLinesRequest, inventoryCountID uuid.UUID) entity.InventoryCount {
+	var lines []*entity.InventoryCountLine
+	for _, line := range req.Lines {
+		lines = append(lines, &entity.InventoryCountLine{
+			InventoryCountID: inventoryCountID,
+			ProductID:        line.ProductID,
+			CountedQuantity:  line.CountedQuantity,
+		})
+	}
+
+	return entity.InventoryCount{
+		ID:    inventoryCountID,
+		Lines: lines,
+	}
+}
+
+func createInboundReceiptRequestToInboundReceiptEntity(req createInboundReceiptRequest, userID uuid.UUID) entity.InboundReceipt {
+	var lines []*entity.InboundReceiptLine
+	for _, line := range req.Lines {
+		lines = append(lines, &entity.InboundReceiptLine{
+			ProductID:        line.ProductID,
+			ProductName:      line.ProductName,
+			ExpectedQuantity: line.ExpectedQuantity,
+			UnitCost:         line.UnitCost,
+		})
+	}
+
+	return entity.InboundReceipt{
+		WarehouseID:     req.WarehouseID,
+		Type:            req.Type,
+		ReferenceNumber: req.ReferenceNumber,
+		Lines:           lines,
+		CreatedBy:       userID,
+		CreatedAt:       time.Now(),
+	}
+}
+
+func receiveInboundReceiptRequestToInboundReceiptEntity(req receiveInboundReceiptRequest, inboundReceiptID, userID uuid.UUID) entity.InboundReceipt {
+	var lines []*entity.InboundReceiptLine
+	for _, line := range req.Lines {
+		lines = append(lines, &entity.InboundReceiptLine{
+			InboundReceiptID: inboundReceiptID,
+			ProductID:        line.ProductID,
+			ReceivedQuantity: line.Quantity,
+		})
+	}
+
+	return entity.InboundReceipt{
+		ID:         inboundReceiptID,
+		Lines:      lines,
+		ReceivedBy: userID,
+	}
+}
+
+func createTransferOrderRequestToTransferOrderEntity(req createTransferOrderRequest, userID uuid.UUID) entity.TransferOrder {
+	var lines []*entity.TransferOrderLine
+	for _, line := range req.Lines {
+		lines = append(lines, &entity.TransferOrderLine{
+			ProductID: line.ProductID,
+			Quantity:  line.Quantity,
+		})
+	}
+
+	return entity.TransferOrder{
+		FromWarehouseID: req.FromWarehouseID,
+		ToWarehouseID:   req.ToWarehouseID,
+		Reason:          req.Reason,
+		Lines:           lines,
+		CreatedBy:       userID,
+		CreatedAt:       time.Now(),
+	}
+}
+
+func receiveTransferOrderRequestToTransferOrderDeliveryEntity(req receiveTransferOrderRequest, idempotencyKey string) entity.TransferOrderDelivery {
+	var lines []*entity.TransferOrderDeliveryLine
+	for _, line := range req.Lines {
+		lines = append(lines, &entity.TransferOrderDeliveryLine{
+			ProductID: line.ProductID,
+			Quantity:  line.Quantity,
+		})
+	}
+
+	return entity.TransferOrderDelivery{
+		Lines:          lines,
+		Close:          req.Close,
+		IdempotencyKey: idempotencyKey,
+	}
+}
+
+func setStockThresholdRequestToStockThresholdEntity(req setStockThresholdRequest, warehouseID, productID uuid.UUID) entity.StockThreshold {
+	return entity.StockThreshold{
+		WarehouseID:  warehouseID,
+		ProductID:    productID,
+		ReorderPoint: req.ReorderPoint,
+		TargetLevel:  req.TargetLevel,
+		CreatedAt:    time.Now(),
+	}
+}
+
+// warehouse and product id are validated by the query binding, an empty id stays nil
+func forecastDemandQueryToForecastParams(query forecastDemandQuery) entity.ForecastParams {
+	params := entity.ForecastParams{
+		Method:      query.Method,
+		HistoryDays: query.HistoryDays,
+		Window:      query.Window,
+		Alpha:       query.Alpha,
+	}
+	if query.WarehouseID != "" {
+		params.WarehouseID = uuid.MustParse(query.WarehouseID)
+	}
+	if query.ProductID != "" {
+		params.ProductID = uuid.MustParse(query.ProductID)
+	}
+
+	return params
+}
+
+// warehouse and product id are validated by the query binding, an empty id stays nil
+func stockSnapshotQueryToStockSnapshotFilter(query stockSnapshotQuery) entity.StockSnapshotFilter {
+	filter := entity.StockSnapshotFilter{
+		From: query.From,
+		To:   query.To,
+	}
+	if query.WarehouseID != "" {
+		filter.WarehouseID = uuid.MustParse(query.WarehouseID)
+	}
+	if query.ProductID != "" {
+		filter.ProductID = uuid.MustParse(query.ProductID)
+	}
+
+	return filter
+}
+
+func costOfGoodsSoldQueryToCostOfGoodsSoldPeriod(query costOfGoodsSoldQuery) entity.CostOfGoodsSoldPeriod {
+	period := entity.CostOfGoodsSoldPeriod{
+		Method: query.Method,
+		From:   query.From,
+		To:     query.To,
+	}
+	if query.WarehouseID != "" {
+		period.WarehouseID = uuid.MustParse(query.WarehouseID)
+	}
+
+	return period
+}
+
+func createReservationRequestToReservationEntity(req createReservationRequest, userID uuid.UUID) entity.Reservation {
+	var items []*entity.ReservationItem
+	for _, item := range req.Items {
+		items = append(items, &entity.ReservationItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	return entity.Reservation{
+		UserID:    userID,
+		ZipCode:   req.ZipCode,
+		Items:     items,
+		CreatedAt: time.Now(),
+	}
+}
@@ -111,15 +111,20 @@ func TestCreateStockMovementInRequestToStockMovementEntity(t *testing.T) {
 		Quantity:        10,
 		FromWarehouseID: uuid.New(),
 		ToWarehouseID:   uuid.New(),
+		Reason:          "restock regional warehouse",
+		ReferenceID:     "TR-001",
 	}
 
-	result := createStockMovementInRequestToStockMovementEntity(req)
+	result := createStockMovementInRequestToStockMovementEntity(req, "movein-1")
 
 	assert.Equal(t, req.ProductID, result.ProductID)
 	assert.Equal(t, req.ProductName, result.ProductName)
 	assert.Equal(t, req.Quantity, result.Quantity)
 	assert.Equal(t, req.FromWarehouseID, result.FromWarehouseID)
 	assert.Equal(t, req.ToWarehouseID, result.ToWarehouseID)
+	assert.Equal(t, req.Reason, result.Reason)
+	assert.Equal(t, req.ReferenceID, result.ReferenceID)
+	assert.Equal(t, "movein-1", result.IdempotencyKey)
 	assert.WithinDuration(t, time.Now(), result.CreatedAt, time.Second)
 }
 
@@ -136,19 +141,34 @@ func TestCreateStockMovementOutRequestToStockMovementEntity(t *testing.T) {
 				Quantity:  10,
 			},
 		},
+		ReferenceID: "order-1",
 	}
 
-	result := createStockMovementOutRequestToStockMovementEntity(req, userID)
+	result := createStockMovementOutRequestToStockMovementEntity(req, userID, "moveout-1")
 
 	assert.Len(t, result, len(req.Items))
 	for i, movement := range result {
 		assert.Equal(t, req.Items[i].ProductID, movement.ProductID)
 		assert.Equal(t, req.Items[i].Quantity, movement.Quantity)
 		assert.Equal(t, userID, movement.ToUserID)
+		assert.Equal(t, "order-1", movement.ReferenceID)
+		assert.Equal(t, "moveout-1", movement.IdempotencyKey)
 		assert.WithinDuration(t, time.Now(), movement.CreatedAt, time.Second)
 	}
 }
 
+func TestGetStockMovementsQueryToStockMovementFilter(t *testing.T) {
+	query := getStockMovementsQuery{
+		MovementType: entity.MovementTypeSale,
+		ReferenceID:  "order-1",
+	}
+
+	result := getStockMovementsQueryToStockMovementFilter(query)
+
+	assert.Equal(t, entity.MovementTypeSale, result.MovementType)
+	assert.Equal(t, "order-1", result.ReferenceID)
+}
+
 func TestWarehouseEntityToUpdateWarehouseResponse(t *testing.T) {
 	warehouse := entity.Warehouse{
 		ID:              uuid.New(),
@@ -0,0 +1,48 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/idoyudha/eshop-warehouse/pkg/logger"
+)
+
+type rebalanceRoutes struct {
+	uc usecase.Rebalance
+	l  logger.Interface
+}
+
+func newRebalanceRoutes(handler *gin.RouterGroup, uc usecase.Rebalance, l logger.Interface, authMid gin.HandlerFunc) {
+	r := &rebalanceRoutes{uc: uc, l: l}
+
+	h := handler.Group("/rebalance").Use(authMid)
+	{
+		h.GET("/report", r.planRebalance)
+		h.POST("", r.rebalance)
+	}
+}
+
+// dry run, the report lists the transfers without creating them
+func (r *rebalanceRoutes) planRebalance(ctx *gin.Context) {
+	report, err := r.uc.PlanRebalance(context.Background())
+	if err != nil {
+		r.l.Error(err, "http - v1 - rebalanceRoutes - planRebalance")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newGetSuccess(report))
+}
+
+func (r *rebalanceRoutes) rebalance(ctx *gin.Context) {
+	report, err := r.uc.Rebalance(context.Background())
+	if err != nil {
+		r.l.Error(err, "http - v1 - rebalanceRoutes - rebalance")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newCreateSuccess(report))
+}
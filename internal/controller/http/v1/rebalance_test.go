@@ -0,0 +1,128 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockRebalanceUsecase struct {
+	mock.Mock
+}
+
+func (m *mockRebalanceUsecase) PlanRebalance(ctx context.Context) (*entity.RebalanceReport, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.RebalanceReport), args.Error(1)
+}
+
+func (m *mockRebalanceUsecase) Rebalance(ctx context.Context) (*entity.RebalanceReport, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.RebalanceReport), args.Error(1)
+}
+
+var _ usecase.Rebalance = (*mockRebalanceUsecase)(nil)
+
+func newRebalanceTestRouter(uc *mockRebalanceUsecase, l *MockLogger) *gin.Engine {
+	router := gin.New()
+	handler := router.Group("/api/v1")
+	newRebalanceRoutes(
+		handler,
+		uc,
+		l,
+		func(c *gin.Context) {
+			c.Next()
+		},
+	)
+	return router
+}
+
+func TestRebalanceRoutes(t *testing.T) {
+	// t.Parallell()
+	report := &entity.RebalanceReport{
+		ID:    uuid.New(),
+		Lines: []*entity.RebalanceLine{{ProductID: uuid.New(), Quantity: 5}},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		expectedStatus int
+		mockBehavior   func(*mockRebalanceUsecase, *MockLogger)
+	}{
+		{
+			name:           "Dry Run Report",
+			method:         http.MethodGet,
+			url:            "/api/v1/rebalance/report",
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockRebalanceUsecase, l *MockLogger) {
+				m.On("PlanRebalance", mock.Anything).Return(report, nil)
+			},
+		},
+		{
+			name:           "Dry Run Usecase Error",
+			method:         http.MethodGet,
+			url:            "/api/v1/rebalance/report",
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockRebalanceUsecase, l *MockLogger) {
+				m.On("PlanRebalance", mock.Anything).Return(nil, fmt.Errorf("database error"))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Rebalance",
+			method:         http.MethodPost,
+			url:            "/api/v1/rebalance",
+			expectedStatus: http.StatusCreated,
+			mockBehavior: func(m *mockRebalanceUsecase, l *MockLogger) {
+				m.On("Rebalance", mock.Anything).Return(report, nil)
+			},
+		},
+		{
+			name:           "Rebalance Usecase Error",
+			method:         http.MethodPost,
+			url:            "/api/v1/rebalance",
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockRebalanceUsecase, l *MockLogger) {
+				m.On("Rebalance", mock.Anything).Return(nil, fmt.Errorf("database error"))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockRebalanceUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := newRebalanceTestRouter(mockUC, mockLogger)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.url, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
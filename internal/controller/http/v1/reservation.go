@@ -0,0 +1,119 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/idoyudha/eshop-warehouse/pkg/logger"
+)
+
+type reservationRoutes struct {
+	uc usecase.Reservation
+	l  logger.Interface
+}
+
+func newReservationRoutes(handler *gin.RouterGroup, uc usecase.Reservation, l logger.Interface, authMid gin.HandlerFunc) {
+	r := &reservationRoutes{uc: uc, l: l}
+
+	h := handler.Group("/reservations").Use(authMid)
+	{
+		h.POST("", r.createReservation)
+		h.GET("/:id", r.getReservationByID)
+		h.DELETE("/:id", r.releaseReservation)
+	}
+}
+
+type createReservationRequest struct {
+	Items   []itemReservationRequest `json:"items" binding:"required,min=1,dive"`
+	ZipCode string                   `json:"zipcode" binding:"required"`
+}
+
+type itemReservationRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	Quantity  int64     `json:"quantity" binding:"required,gt=0"`
+}
+
+func (r *reservationRoutes) createReservation(ctx *gin.Context) {
+	var req createReservationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - reservationRoutes - createReservation")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	userID, exist := ctx.Get(UserIDKey)
+	if !exist {
+		r.l.Error("not exist", "http - v1 - reservationRoutes - createReservation")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError("user id not exist"))
+		return
+	}
+
+	reservation := createReservationRequestToReservationEntity(req, userID.(uuid.UUID))
+
+	err := r.uc.CreateReservation(context.Background(), &reservation)
+	if err != nil {
+		r.l.Error(err, "http - v1 - reservationRoutes - createReservation")
+		handleReservationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newCreateSuccess(reservation))
+}
+
+func (r *reservationRoutes) getReservationByID(ctx *gin.Context) {
+	reservationID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - reservationRoutes - getReservationByID")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	reservation, err := r.uc.GetReservationByID(context.Background(), reservationID)
+	if err != nil {
+		r.l.Error(err, "http - v1 - reservationRoutes - getReservationByID")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newGetSuccess(reservation))
+}
+
+func (r *reservationRoutes) releaseReservation(ctx *gin.Context) {
+	reservationID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - reservationRoutes - releaseReservation")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	err = r.uc.ReleaseReservation(context.Background(), reservationID)
+	if err != nil {
+		r.l.Error(err, "http - v1 - reservationRoutes - releaseReservation")
+		handleReservationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newDeleteSuccess())
+}
+
+// shared with the commit of a reservation in the stock movement routes
+func handleReservationError(ctx *gin.Context, err error) {
+	var insufficientStockErr *entity.InsufficientStockError
+	switch {
+	case errors.Is(err, entity.ErrInvalidReservation):
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+	case errors.Is(err, entity.ErrReservationNotFound):
+		ctx.JSON(http.StatusNotFound, newNotFoundError(err.Error()))
+	case errors.Is(err, entity.ErrReservationNotHolding):
+		ctx.JSON(http.StatusConflict, newConflictError(err.Error()))
+	case errors.As(err, &insufficientStockErr):
+		ctx.JSON(http.StatusConflict, newInsufficientStockError(insufficientStockErr))
+	default:
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+	}
+}
@@ -0,0 +1,236 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockReservationUsecase struct {
+	mock.Mock
+}
+
+func (m *mockReservationUsecase) CreateReservation(ctx context.Context, reservation *entity.Reservation) error {
+	args := m.Called(ctx, reservation)
+	return args.Error(0)
+}
+
+func (m *mockReservationUsecase) GetReservationByID(ctx context.Context, id uuid.UUID) (*entity.Reservation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Reservation), args.Error(1)
+}
+
+func (m *mockReservationUsecase) ReleaseReservation(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockReservationUsecase) ReleaseExpiredReservations(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+var _ usecase.Reservation = (*mockReservationUsecase)(nil)
+
+func TestCreateReservation(t *testing.T) {
+	// t.Parallell()
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		inputJSON      string
+		withUserID     bool
+		expectedStatus int
+		mockBehavior   func(*mockReservationUsecase, *MockLogger)
+	}{
+		{
+			name: "Success",
+			inputJSON: `{
+                "items": [{"product_id": "019444a2-e318-79b5-8fe4-b32716306083", "quantity": 2}],
+                "zipcode": "12345"
+            }`,
+			withUserID:     true,
+			expectedStatus: http.StatusCreated,
+			mockBehavior: func(m *mockReservationUsecase, l *MockLogger) {
+				m.On("CreateReservation",
+					mock.Anything,
+					mock.MatchedBy(func(r *entity.Reservation) bool {
+						return r.UserID == userID &&
+							r.ZipCode == "12345" &&
+							len(r.Items) == 1 &&
+							r.Items[0].ProductID.String() == "019444a2-e318-79b5-8fe4-b32716306083" &&
+							r.Items[0].Quantity == 2
+					}),
+				).Return(nil)
+			},
+		},
+		{
+			name:           "Invalid JSON",
+			inputJSON:      `{"items": "invalid"}`,
+			withUserID:     true,
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockReservationUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name: "User ID Not Exist",
+			inputJSON: `{
+                "items": [{"product_id": "019444a2-e318-79b5-8fe4-b32716306083", "quantity": 2}],
+                "zipcode": "12345"
+            }`,
+			withUserID:     false,
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockReservationUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name: "Not Enough Quantity Error",
+			inputJSON: `{
+                "items": [{"product_id": "019444a2-e318-79b5-8fe4-b32716306083", "quantity": 2}],
+                "zipcode": "12345"
+            }`,
+			withUserID:     true,
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockReservationUsecase, l *MockLogger) {
+				m.On("CreateReservation", mock.Anything, mock.Anything).Return(fmt.Errorf("product quantity is not enough"))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockReservationUsecase)
+			mockLogger := NewMockLogger(t)
+
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := gin.New()
+			handler := router.Group("/api/v1")
+			newReservationRoutes(
+				handler,
+				mockUC,
+				mockLogger,
+				func(c *gin.Context) {
+					if tt.withUserID {
+						c.Set(UserIDKey, userID)
+					}
+					c.Next()
+				},
+			)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				"/api/v1/reservations",
+				bytes.NewBufferString(tt.inputJSON),
+			)
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReleaseReservation(t *testing.T) {
+	// t.Parallell()
+	reservationID := uuid.New()
+
+	tests := []struct {
+		name           string
+		reservationID  string
+		expectedStatus int
+		mockBehavior   func(*mockReservationUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			reservationID:  reservationID.String(),
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockReservationUsecase, l *MockLogger) {
+				m.On("ReleaseReservation", mock.Anything, reservationID).Return(nil)
+			},
+		},
+		{
+			name:           "Invalid ID",
+			reservationID:  "invalid-uuid",
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockReservationUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Not Active",
+			reservationID:  reservationID.String(),
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockReservationUsecase, l *MockLogger) {
+				m.On("ReleaseReservation", mock.Anything, reservationID).Return(fmt.Errorf("reservation is not active"))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Not Holding",
+			reservationID:  reservationID.String(),
+			expectedStatus: http.StatusConflict,
+			mockBehavior: func(m *mockReservationUsecase, l *MockLogger) {
+				m.On("ReleaseReservation", mock.Anything, reservationID).Return(entity.ErrReservationNotHolding)
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockReservationUsecase)
+			mockLogger := NewMockLogger(t)
+
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := gin.New()
+			handler := router.Group("/api/v1")
+			newReservationRoutes(
+				handler,
+				mockUC,
+				mockLogger,
+				func(c *gin.Context) { c.Next() },
+			)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(
+				http.MethodDelete,
+				fmt.Sprintf("/api/v1/reservations/%s", tt.reservationID),
+				nil,
+			)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
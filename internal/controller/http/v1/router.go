@@ -16,13 +16,23 @@ func NewRouter(
 	ucwp usecase.WarehouseProduct,
 	ucsm usecase.StockMovement,
 	uct usecase.TransactionProduct,
+	ucr usecase.Reservation,
+	ucic usecase.InventoryCount,
+	ucir usecase.InboundReceipt,
+	ucto usecase.TransferOrder,
+	ucst usecase.StockThreshold,
+	ucrb usecase.Rebalance,
+	ucf usecase.Forecast,
+	ucsh usecase.StockHistory,
+	ucss usecase.StockSnapshot,
+	ucv usecase.Valuation,
 	l logger.Interface,
 	auth config.AuthService,
 ) {
 	handler.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "http://localhost:3001"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", IdempotencyKeyHeader},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge:           12 * 3600,
@@ -38,5 +48,15 @@ func NewRouter(
 		newWarehouseRoutes(h, ucw, l, authMid)
 		newWarehouseProductRoutes(h, ucwp, l, authMid)
 		newStockMovementRoutes(h, ucsm, uct, l, authMid)
+		newReservationRoutes(h, ucr, l, authMid)
+		newInventoryCountRoutes(h, ucic, l, authMid)
+		newInboundReceiptRoutes(h, ucir, l, authMid)
+		newTransferOrderRoutes(h, ucto, l, authMid)
+		newStockThresholdRoutes(h, ucst, l, authMid)
+		newRebalanceRoutes(h, ucrb, l, authMid)
+		newForecastRoutes(h, ucf, l, authMid)
+		newStockHistoryRoutes(h, ucsh, l, authMid)
+		newStockSnapshotRoutes(h, ucss, l, authMid)
+		newValuationRoutes(h, ucv, l, authMid)
 	}
 }
@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/idoyudha/eshop-warehouse/pkg/logger"
+)
+
+type stockHistoryRoutes struct {
+	uc usecase.StockHistory
+	l  logger.Interface
+}
+
+func newStockHistoryRoutes(handler *gin.RouterGroup, uc usecase.StockHistory, l logger.Interface, authMid gin.HandlerFunc) {
+	r := &stockHistoryRoutes{uc: uc, l: l}
+
+	h := handler.Group("/stock-history").Use(authMid)
+	{
+		h.GET("/warehouse/:warehouse_id/product/:product_id", r.getOnHandQuantity)
+		h.GET("/discrepancies", r.reconcileStock)
+	}
+}
+
+// the current quantity is returned when at is not set
+type getOnHandQuantityQuery struct {
+	At time.Time `form:"at"` // RFC 3339
+}
+
+func (r *stockHistoryRoutes) getOnHandQuantity(ctx *gin.Context) {
+	warehouseID, err := uuid.Parse(ctx.Param("warehouse_id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockHistoryRoutes - getOnHandQuantity")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	productID, err := uuid.Parse(ctx.Param("product_id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockHistoryRoutes - getOnHandQuantity")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	var query getOnHandQuantityQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		r.l.Error(err, "http - v1 - stockHistoryRoutes - getOnHandQuantity")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+	// movements are saved in local time
+	at := query.At.Local()
+	if query.At.IsZero() {
+		at = time.Now()
+	}
+
+	stockHistory, err := r.uc.GetOnHandQuantity(context.Background(), warehouseID, productID, at)
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockHistoryRoutes - getOnHandQuantity")
+		if errors.Is(err, entity.ErrInvalidStockHistory) {
+			ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newGetSuccess(stockHistory))
+}
+
+func (r *stockHistoryRoutes) reconcileStock(ctx *gin.Context) {
+	discrepancies, err := r.uc.ReconcileStock(context.Background())
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockHistoryRoutes - reconcileStock")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newGetSuccess(discrepancies))
+}
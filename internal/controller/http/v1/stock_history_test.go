@@ -0,0 +1,193 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockStockHistoryUsecase struct {
+	mock.Mock
+}
+
+func (m *mockStockHistoryUsecase) GetOnHandQuantity(ctx context.Context, warehouseID, productID uuid.UUID, at time.Time) (*entity.StockHistory, error) {
+	args := m.Called(ctx, warehouseID, productID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.StockHistory), args.Error(1)
+}
+
+func (m *mockStockHistoryUsecase) ReconcileStock(ctx context.Context) ([]*entity.StockDiscrepancy, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.StockDiscrepancy), args.Error(1)
+}
+
+var _ usecase.StockHistory = (*mockStockHistoryUsecase)(nil)
+
+func newStockHistoryTestRouter(uc *mockStockHistoryUsecase, l *MockLogger) *gin.Engine {
+	router := gin.New()
+	handler := router.Group("/api/v1")
+	newStockHistoryRoutes(
+		handler,
+		uc,
+		l,
+		func(c *gin.Context) {
+			c.Next()
+		},
+	)
+	return router
+}
+
+func TestGetOnHandQuantity(t *testing.T) {
+	// t.Parallell()
+	warehouseID := uuid.New()
+	productID := uuid.New()
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		warehouseID    string
+		query          string
+		expectedStatus int
+		mockBehavior   func(*mockStockHistoryUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			warehouseID:    warehouseID.String(),
+			query:          "?at=2024-03-01T10:00:00Z",
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockStockHistoryUsecase, l *MockLogger) {
+				m.On("GetOnHandQuantity",
+					mock.Anything,
+					warehouseID,
+					productID,
+					mock.MatchedBy(func(t time.Time) bool {
+						return t.Equal(at)
+					}),
+				).Return(&entity.StockHistory{Quantity: 12}, nil)
+			},
+		},
+		{
+			name:           "Current Quantity",
+			warehouseID:    warehouseID.String(),
+			query:          "",
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockStockHistoryUsecase, l *MockLogger) {
+				m.On("GetOnHandQuantity", mock.Anything, warehouseID, productID, mock.Anything).
+					Return(&entity.StockHistory{Quantity: 12}, nil)
+			},
+		},
+		{
+			name:           "Invalid Warehouse ID",
+			warehouseID:    "invalid",
+			query:          "",
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockStockHistoryUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Invalid Time",
+			warehouseID:    warehouseID.String(),
+			query:          "?at=yesterday",
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockStockHistoryUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Before Opening Balance",
+			warehouseID:    warehouseID.String(),
+			query:          "?at=2020-01-01T00:00:00Z",
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockStockHistoryUsecase, l *MockLogger) {
+				m.On("GetOnHandQuantity", mock.Anything, warehouseID, productID, mock.Anything).
+					Return(nil, fmt.Errorf("stock history starts at 2024-01-01T00:00:00Z: %w", entity.ErrInvalidStockHistory))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockStockHistoryUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := newStockHistoryTestRouter(mockUC, mockLogger)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/stock-history/warehouse/%s/product/%s%s", tt.warehouseID, productID, tt.query)
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReconcileStock(t *testing.T) {
+	// t.Parallell()
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockBehavior   func(*mockStockHistoryUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockStockHistoryUsecase, l *MockLogger) {
+				m.On("ReconcileStock", mock.Anything).
+					Return([]*entity.StockDiscrepancy{{ProductID: uuid.New(), Quantity: 4, LedgerQuantity: 6}}, nil)
+			},
+		},
+		{
+			name:           "Usecase Error",
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockStockHistoryUsecase, l *MockLogger) {
+				m.On("ReconcileStock", mock.Anything).Return(nil, fmt.Errorf("database error"))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockStockHistoryUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := newStockHistoryTestRouter(mockUC, mockLogger)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/stock-history/discrepancies", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
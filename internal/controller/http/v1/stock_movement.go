@@ -2,10 +2,12 @@ package v1
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
 	"github.com/idoyudha/eshop-warehouse/internal/usecase"
 	"github.com/idoyudha/eshop-warehouse/pkg/logger"
 )
@@ -33,6 +35,10 @@ func newStockMovementRoutes(
 	{
 		h.POST("/movein", r.createStockMovementIn)
 		h.POST("/moveout", r.createStockMovementOut)
+		h.POST("/moveout/preview", r.previewStockMovementOut)
+		h.POST("/moveout/reservations/:reservation_id", r.createStockMovementOutByReservation)
+		h.POST("/moveout/compensate", r.compensateStockMovementOut)
+		h.POST("/return", r.createStockMovementReturn)
 		h.GET("", r.getAllStockMovements)
 		h.GET("/product/:product_id", r.getStockMovementByProductID)
 		h.GET("/source/:source_id", r.getStockMovementBySourceID)
@@ -41,12 +47,34 @@ func newStockMovementRoutes(
 	}
 }
 
+// retried requests with the same key return the original stock movements
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+const maxIdempotencyKeyLength = 255
+
+// getIdempotencyKey scopes the key by the operation and the caller, so the same key sent to another endpoint
+// or by another user is a different request. It is not ok when the key is too long
+func getIdempotencyKey(ctx *gin.Context, operation string) (string, bool) {
+	key := ctx.GetHeader(IdempotencyKeyHeader)
+	if len(key) > maxIdempotencyKeyLength {
+		return "", false
+	}
+
+	var callerID uuid.UUID
+	if userID, exist := ctx.Get(UserIDKey); exist {
+		callerID, _ = userID.(uuid.UUID)
+	}
+	return entity.ScopedIdempotencyKey(operation, callerID, key), true
+}
+
 type CreateStockMovementIn struct {
 	ProductID       uuid.UUID `json:"product_id"`
 	ProductName     string    `json:"product_name"`
 	Quantity        int64     `json:"quantity"`
 	FromWarehouseID uuid.UUID `json:"from_warehouse_id"`
 	ToWarehouseID   uuid.UUID `json:"to_warehouse_id"`
+	Reason          string    `json:"reason"`
+	ReferenceID     string    `json:"reference_id"`
 }
 
 func (r *stockMovementRoutes) createStockMovementIn(ctx *gin.Context) {
@@ -57,11 +85,27 @@ func (r *stockMovementRoutes) createStockMovementIn(ctx *gin.Context) {
 		return
 	}
 
-	stockMovement := createStockMovementInRequestToStockMovementEntity(req)
+	idempotencyKey, ok := getIdempotencyKey(ctx, entity.IdempotencyOperationMoveIn)
+	if !ok {
+		r.l.Error("idempotency key too long", "http - v1 - stockMovementRoutes - createStockMovementIn")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError("idempotency key too long"))
+		return
+	}
+
+	stockMovement := createStockMovementInRequestToStockMovementEntity(req, idempotencyKey)
 
 	err := r.uct.MoveIn(context.Background(), &stockMovement)
 	if err != nil {
 		r.l.Error(err, "http - v1 - stockMovementRoutes - createStockMovementIn")
+		if errors.Is(err, entity.ErrIdempotencyKeyReused) {
+			ctx.JSON(http.StatusUnprocessableEntity, newUnprocessableEntityError(err.Error()))
+			return
+		}
+		var insufficientStockErr *entity.InsufficientStockError
+		if errors.As(err, &insufficientStockErr) {
+			ctx.JSON(http.StatusConflict, newInsufficientStockError(insufficientStockErr))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
 		return
 	}
@@ -70,13 +114,22 @@ func (r *stockMovementRoutes) createStockMovementIn(ctx *gin.Context) {
 }
 
 type createStockMovementOut struct {
-	Items   []ItemStockMovementOut `json:"items" binding:"required"`
-	ZipCode string                 `json:"zipcode" binding:"required"`
+	Items    []ItemStockMovementOut `json:"items" binding:"required,min=1,dive"`
+	ZipCode  string                 `json:"zipcode" binding:"required"`
+	Strategy string                 `json:"strategy" binding:"omitempty,oneof=nearest-first minimize-warehouses single-warehouse balance-stock minimize-shipments"`
+	// order id of the moved out items
+	ReferenceID string `json:"reference_id"`
+	Reason      string `json:"reason"`
+}
+
+type createStockMovementOutResponse struct {
+	StockMovements []*entity.StockMovement `json:"stock_movements"`
+	Allocation     *entity.AllocationPlan  `json:"allocation,omitempty"`
 }
 
 type ItemStockMovementOut struct {
 	ProductID uuid.UUID `json:"product_id" binding:"required"`
-	Quantity  int64     `json:"quantity" binding:"required"`
+	Quantity  int64     `json:"quantity" binding:"required,gt=0"`
 }
 
 func (r *stockMovementRoutes) createStockMovementOut(ctx *gin.Context) {
@@ -94,19 +147,165 @@ func (r *stockMovementRoutes) createStockMovementOut(ctx *gin.Context) {
 		return
 	}
 
-	stockMovements := createStockMovementOutRequestToStockMovementEntity(req, userID.(uuid.UUID))
-	err := r.uct.MoveOut(context.Background(), stockMovements, req.ZipCode)
+	idempotencyKey, ok := getIdempotencyKey(ctx, entity.IdempotencyOperationMoveOut)
+	if !ok {
+		r.l.Error("idempotency key too long", "http - v1 - stockMovementRoutes - createStockMovementOut")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError("idempotency key too long"))
+		return
+	}
+
+	stockMovementsReq := createStockMovementOutRequestToStockMovementEntity(req, userID.(uuid.UUID), idempotencyKey)
+	stockMovements, allocationPlan, err := r.uct.MoveOut(context.Background(), stockMovementsReq, req.ZipCode, req.Strategy)
 	if err != nil {
 		r.l.Error(err, "http - v1 - stockMovementRoutes - createStockMovementOut")
+		if errors.Is(err, entity.ErrIdempotencyKeyReused) {
+			ctx.JSON(http.StatusUnprocessableEntity, newUnprocessableEntityError(err.Error()))
+			return
+		}
+		if errors.Is(err, entity.ErrReferenceCancelled) {
+			ctx.JSON(http.StatusConflict, newConflictError(err.Error()))
+			return
+		}
+		var insufficientStockErr *entity.InsufficientStockError
+		if errors.As(err, &insufficientStockErr) {
+			ctx.JSON(http.StatusConflict, newInsufficientStockError(insufficientStockErr))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
 		return
 	}
 
+	ctx.JSON(http.StatusCreated, newCreateSuccess(createStockMovementOutResponse{
+		StockMovements: stockMovements,
+		Allocation:     allocationPlan,
+	}))
+}
+
+// dry run of moveout, returns the allocation without moving any stock
+func (r *stockMovementRoutes) previewStockMovementOut(ctx *gin.Context) {
+	var req createStockMovementOut
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - stockMovementRoutes - previewStockMovementOut")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	stockMovementsReq := createStockMovementOutRequestToStockMovementEntity(req, uuid.Nil, "")
+	allocationPlan, err := r.uct.PreviewMoveOut(context.Background(), stockMovementsReq, req.ZipCode, req.Strategy)
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockMovementRoutes - previewStockMovementOut")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newGetSuccess(allocationPlan))
+}
+
+func (r *stockMovementRoutes) createStockMovementOutByReservation(ctx *gin.Context) {
+	reservationID, err := uuid.Parse(ctx.Param("reservation_id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockMovementRoutes - createStockMovementOutByReservation")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	stockMovements, err := r.uct.CommitReservation(context.Background(), reservationID)
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockMovementRoutes - createStockMovementOutByReservation")
+		handleReservationError(ctx, err)
+		return
+	}
+
 	ctx.JSON(http.StatusCreated, newCreateSuccess(stockMovements))
 }
 
+type compensateStockMovementOut struct {
+	// order id of the moved out items
+	ReferenceID string `json:"reference_id" binding:"required"`
+	Reason      string `json:"reason"`
+}
+
+// put the stock moved out for the reference back to its source warehouses
+func (r *stockMovementRoutes) compensateStockMovementOut(ctx *gin.Context) {
+	var req compensateStockMovementOut
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - stockMovementRoutes - compensateStockMovementOut")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	reversals, err := r.uct.CompensateMoveOut(context.Background(), req.ReferenceID, req.Reason)
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockMovementRoutes - compensateStockMovementOut")
+		if errors.Is(err, entity.ErrReversalNotAllowed) {
+			ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newCreateSuccess(reversals))
+}
+
+type createStockMovementReturn struct {
+	OriginalMovementID uuid.UUID `json:"original_movement_id" binding:"required"`
+	Quantity           int64     `json:"quantity" binding:"required,gt=0"`
+	ToWarehouseID      uuid.UUID `json:"to_warehouse_id" binding:"required"`
+	Disposition        string    `json:"disposition" binding:"omitempty,oneof=restock quarantine"`
+	Reason             string    `json:"reason"`
+	ReferenceID        string    `json:"reference_id"`
+}
+
+func (r *stockMovementRoutes) createStockMovementReturn(ctx *gin.Context) {
+	var req createStockMovementReturn
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - stockMovementRoutes - createStockMovementReturn")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	idempotencyKey, ok := getIdempotencyKey(ctx, entity.IdempotencyOperationMoveReturn)
+	if !ok {
+		r.l.Error("idempotency key too long", "http - v1 - stockMovementRoutes - createStockMovementReturn")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError("idempotency key too long"))
+		return
+	}
+
+	stockMovement := createStockMovementReturnRequestToStockMovementEntity(req, idempotencyKey)
+	err := r.uct.MoveReturn(context.Background(), &stockMovement)
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockMovementRoutes - createStockMovementReturn")
+		if errors.Is(err, entity.ErrIdempotencyKeyReused) {
+			ctx.JSON(http.StatusUnprocessableEntity, newUnprocessableEntityError(err.Error()))
+			return
+		}
+		if errors.Is(err, entity.ErrReturnNotAllowed) {
+			ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newCreateSuccess(stockMovement))
+}
+
+// optional filters of the stock movement query endpoints
+type getStockMovementsQuery struct {
+	MovementType string `form:"type" binding:"omitempty,oneof=transfer sale return adjustment receipt write-off transfer-shipment transfer-receipt reversal"`
+	ReferenceID  string `form:"reference_id"`
+}
+
 func (r *stockMovementRoutes) getAllStockMovements(ctx *gin.Context) {
-	stockMovements, err := r.ucs.GetAllStockMovements(context.Background())
+	var query getStockMovementsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		r.l.Error(err, "http - v1 - stockMovementRoutes - getAllStockMovements")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	stockMovements, err := r.ucs.GetAllStockMovements(context.Background(), getStockMovementsQueryToStockMovementFilter(query))
 	if err != nil {
 		r.l.Error(err, "http - v1 - stockMovementRoutes - getAllStockMovements")
 		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
@@ -124,7 +323,14 @@ func (r *stockMovementRoutes) getStockMovementByProductID(ctx *gin.Context) {
 		return
 	}
 
-	stockMovements, err := r.ucs.GetStockMovementsByProductID(context.Background(), productID)
+	var query getStockMovementsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		r.l.Error(err, "http - v1 - stockMovementRoutes - getStockMovementByProductID")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	stockMovements, err := r.ucs.GetStockMovementsByProductID(context.Background(), productID, getStockMovementsQueryToStockMovementFilter(query))
 	if err != nil {
 		r.l.Error(err, "http - v1 - stockMovementRoutes - getStockMovementByProductID")
 		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
@@ -142,7 +348,14 @@ func (r *stockMovementRoutes) getStockMovementBySourceID(ctx *gin.Context) {
 		return
 	}
 
-	stockMovements, err := r.ucs.GetStockMovementsBySourceID(context.Background(), sourceID)
+	var query getStockMovementsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		r.l.Error(err, "http - v1 - stockMovementRoutes - getStockMovementBySourceID")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	stockMovements, err := r.ucs.GetStockMovementsBySourceID(context.Background(), sourceID, getStockMovementsQueryToStockMovementFilter(query))
 	if err != nil {
 		r.l.Error(err, "http - v1 - stockMovementRoutes - getStockMovementBySourceID")
 		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
@@ -160,7 +373,14 @@ func (r *stockMovementRoutes) getStockMovementByDestinationID(ctx *gin.Context)
 		return
 	}
 
-	stockMovements, err := r.ucs.GetStockMovementsByDestinationID(context.Background(), destinationID)
+	var query getStockMovementsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		r.l.Error(err, "http - v1 - stockMovementRoutes - getStockMovementByDestinationID")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	stockMovements, err := r.ucs.GetStockMovementsByDestinationID(context.Background(), destinationID, getStockMovementsQueryToStockMovementFilter(query))
 	if err != nil {
 		r.l.Error(err, "http - v1 - stockMovementRoutes - getStockMovementByDestinationID")
 		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
@@ -3,6 +3,7 @@ package v1
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -14,14 +15,15 @@ import (
 	"github.com/idoyudha/eshop-warehouse/internal/usecase"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type mockStockMovementUsecase struct {
 	mock.Mock
 }
 
-func (m *mockStockMovementUsecase) GetAllStockMovements(ctx context.Context) ([]*entity.StockMovement, error) {
-	args := m.Called(ctx)
+func (m *mockStockMovementUsecase) GetAllStockMovements(ctx context.Context, filter entity.StockMovementFilter) ([]*entity.StockMovement, error) {
+	args := m.Called(ctx, filter)
 
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -30,8 +32,8 @@ func (m *mockStockMovementUsecase) GetAllStockMovements(ctx context.Context) ([]
 	return args.Get(0).([]*entity.StockMovement), args.Error(1)
 }
 
-func (m *mockStockMovementUsecase) GetStockMovementsByProductID(ctx context.Context, productID uuid.UUID) ([]*entity.StockMovement, error) {
-	args := m.Called(ctx, productID)
+func (m *mockStockMovementUsecase) GetStockMovementsByProductID(ctx context.Context, productID uuid.UUID, filter entity.StockMovementFilter) ([]*entity.StockMovement, error) {
+	args := m.Called(ctx, productID, filter)
 
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -40,8 +42,8 @@ func (m *mockStockMovementUsecase) GetStockMovementsByProductID(ctx context.Cont
 	return args.Get(0).([]*entity.StockMovement), args.Error(1)
 }
 
-func (m *mockStockMovementUsecase) GetStockMovementsBySourceID(ctx context.Context, sourceID uuid.UUID) ([]*entity.StockMovement, error) {
-	args := m.Called(ctx, sourceID)
+func (m *mockStockMovementUsecase) GetStockMovementsBySourceID(ctx context.Context, sourceID uuid.UUID, filter entity.StockMovementFilter) ([]*entity.StockMovement, error) {
+	args := m.Called(ctx, sourceID, filter)
 
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -50,8 +52,8 @@ func (m *mockStockMovementUsecase) GetStockMovementsBySourceID(ctx context.Conte
 	return args.Get(0).([]*entity.StockMovement), args.Error(1)
 }
 
-func (m *mockStockMovementUsecase) GetStockMovementsByDestinationID(ctx context.Context, destinationID uuid.UUID) ([]*entity.StockMovement, error) {
-	args := m.Called(ctx, destinationID)
+func (m *mockStockMovementUsecase) GetStockMovementsByDestinationID(ctx context.Context, destinationID uuid.UUID, filter entity.StockMovementFilter) ([]*entity.StockMovement, error) {
+	args := m.Called(ctx, destinationID, filter)
 
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -72,11 +74,43 @@ func (m *mockTransactionProductUsecase) MoveIn(ctx context.Context, stockMovemen
 	return args.Error(0)
 }
 
-func (m *mockTransactionProductUsecase) MoveOut(ctx context.Context, stockMovements []*entity.StockMovement, zipCode string) error {
-	args := m.Called(ctx, stockMovements, zipCode)
+func (m *mockTransactionProductUsecase) MoveOut(ctx context.Context, stockMovements []*entity.StockMovement, zipCode string, strategy string) ([]*entity.StockMovement, *entity.AllocationPlan, error) {
+	args := m.Called(ctx, stockMovements, zipCode, strategy)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]*entity.StockMovement), args.Get(1).(*entity.AllocationPlan), args.Error(2)
+}
+
+func (m *mockTransactionProductUsecase) PreviewMoveOut(ctx context.Context, stockMovements []*entity.StockMovement, zipCode string, strategy string) (*entity.AllocationPlan, error) {
+	args := m.Called(ctx, stockMovements, zipCode, strategy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.AllocationPlan), args.Error(1)
+}
+
+func (m *mockTransactionProductUsecase) MoveReturn(ctx context.Context, stockMovement *entity.StockMovement) error {
+	args := m.Called(ctx, stockMovement)
 	return args.Error(0)
 }
 
+func (m *mockTransactionProductUsecase) CommitReservation(ctx context.Context, reservationID uuid.UUID) ([]*entity.StockMovement, error) {
+	args := m.Called(ctx, reservationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.StockMovement), args.Error(1)
+}
+
+func (m *mockTransactionProductUsecase) CompensateMoveOut(ctx context.Context, referenceID string, reason string) ([]*entity.StockMovement, error) {
+	args := m.Called(ctx, referenceID, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.StockMovement), args.Error(1)
+}
+
 type testStockMovement struct {
 	name             string
 	inputJSON        string
@@ -201,3 +235,759 @@ func TestCreateStockMovementIn(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateStockMovementOut(t *testing.T) {
+	// t.Parallell()
+
+	userID := uuid.MustParse("019444a4-1b2c-7d3e-8f4a-5b6c7d8e9f00")
+	productID := uuid.MustParse("019444a2-e318-79b5-8fe4-b32716306083")
+
+	tests := []struct {
+		name           string
+		inputJSON      string
+		idempotencyKey string
+		expectedStatus int
+		expectedKey    string // idempotency key of the returned movements
+		mockBehavior   func(*mockTransactionProductUsecase, *MockLogger)
+	}{
+		{
+			name: "Success",
+			inputJSON: `{
+                "items": [{"product_id": "019444a2-e318-79b5-8fe4-b32716306083", "quantity": 5}],
+                "zipcode": "12345"
+            }`,
+			expectedStatus: http.StatusCreated,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				m.On("MoveOut",
+					mock.Anything,
+					mock.MatchedBy(func(sms []*entity.StockMovement) bool {
+						return len(sms) == 1 &&
+							sms[0].ProductID == productID &&
+							sms[0].Quantity == 5 &&
+							sms[0].ToUserID == userID
+					}),
+					"12345",
+					"",
+				).Return([]*entity.StockMovement{{ProductID: productID, Quantity: 5, ToUserID: userID}}, &entity.AllocationPlan{Strategy: "nearest-first"}, nil)
+			},
+		},
+		{
+			name: "Idempotency Key",
+			inputJSON: `{
+                "items": [{"product_id": "019444a2-e318-79b5-8fe4-b32716306083", "quantity": 5}],
+                "zipcode": "12345"
+            }`,
+			idempotencyKey: "order-019444a5",
+			expectedStatus: http.StatusCreated,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				m.On("MoveOut",
+					mock.Anything,
+					mock.MatchedBy(func(sms []*entity.StockMovement) bool {
+						return len(sms) == 1 && sms[0].IdempotencyKey == "move-out:"+userID.String()+":order-019444a5"
+					}),
+					"12345",
+					"",
+				).Return([]*entity.StockMovement{{ProductID: productID, Quantity: 5, IdempotencyKey: "move-out:" + userID.String() + ":order-019444a5"}}, (*entity.AllocationPlan)(nil), nil)
+			},
+			expectedKey: "order-019444a5",
+		},
+		{
+			name: "Idempotency Key Reused",
+			inputJSON: `{
+                "items": [{"product_id": "019444a2-e318-79b5-8fe4-b32716306083", "quantity": 5}],
+                "zipcode": "12345"
+            }`,
+			idempotencyKey: "order-019444a5",
+			expectedStatus: http.StatusUnprocessableEntity,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				m.On("MoveOut", mock.Anything, mock.Anything, "12345", "").
+					Return(nil, (*entity.AllocationPlan)(nil), entity.ErrIdempotencyKeyReused)
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name: "Reference Cancelled",
+			inputJSON: `{
+                "items": [{"product_id": "019444a2-e318-79b5-8fe4-b32716306083", "quantity": 5}],
+                "zipcode": "12345"
+            }`,
+			expectedStatus: http.StatusConflict,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				m.On("MoveOut", mock.Anything, mock.Anything, "12345", "").
+					Return(nil, (*entity.AllocationPlan)(nil), entity.ErrReferenceCancelled)
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name: "Negative Quantity",
+			inputJSON: `{
+                "items": [{"product_id": "019444a2-e318-79b5-8fe4-b32716306083", "quantity": -5}],
+                "zipcode": "12345"
+            }`,
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name: "Empty Items",
+			inputJSON: `{
+                "items": [],
+                "zipcode": "12345"
+            }`,
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name: "Allocation Strategy",
+			inputJSON: `{
+                "items": [{"product_id": "019444a2-e318-79b5-8fe4-b32716306083", "quantity": 5}],
+                "zipcode": "12345",
+                "strategy": "single-warehouse"
+            }`,
+			expectedStatus: http.StatusCreated,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				m.On("MoveOut",
+					mock.Anything,
+					mock.Anything,
+					"12345",
+					"single-warehouse",
+				).Return([]*entity.StockMovement{{ProductID: productID, Quantity: 5}}, &entity.AllocationPlan{Strategy: "single-warehouse"}, nil)
+			},
+		},
+		{
+			name: "Unknown Allocation Strategy",
+			inputJSON: `{
+                "items": [{"product_id": "019444a2-e318-79b5-8fe4-b32716306083", "quantity": 5}],
+                "zipcode": "12345",
+                "strategy": "random"
+            }`,
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				l.On("Error",
+					mock.Anything,
+					mock.Anything,
+				).Return()
+			},
+		},
+		{
+			name: "Insufficient Stock Error",
+			inputJSON: `{
+                "items": [{"product_id": "019444a2-e318-79b5-8fe4-b32716306083", "quantity": 5}],
+                "zipcode": "12345"
+            }`,
+			expectedStatus: http.StatusConflict,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				expectedError := &entity.InsufficientStockError{
+					Items: []entity.InsufficientStockItem{{ProductID: productID, Requested: 5, Available: 2}},
+				}
+				m.On("MoveOut",
+					mock.Anything,
+					mock.Anything,
+					"12345",
+					"",
+				).Return(nil, nil, expectedError)
+
+				l.On("Error",
+					mock.Anything,
+					mock.Anything,
+				).Return()
+			},
+		},
+		{
+			name: "Internal Error",
+			inputJSON: `{
+                "items": [{"product_id": "019444a2-e318-79b5-8fe4-b32716306083", "quantity": 5}],
+                "zipcode": "12345"
+            }`,
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				m.On("MoveOut",
+					mock.Anything,
+					mock.Anything,
+					"12345",
+					"",
+				).Return(nil, nil, fmt.Errorf("failed to begin transaction"))
+
+				l.On("Error",
+					mock.Anything,
+					mock.Anything,
+				).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			// initialize mocks
+			mockTxUsecase := new(mockTransactionProductUsecase)
+			mockStockUsecase := new(mockStockMovementUsecase)
+			mockLogger := NewMockLogger(t)
+
+			tt.mockBehavior(mockTxUsecase, mockLogger)
+
+			// setup router
+			router := gin.New()
+			handler := router.Group("/api/v1")
+			newStockMovementRoutes(
+				handler,
+				mockStockUsecase,
+				mockTxUsecase,
+				mockLogger,
+				func(c *gin.Context) {
+					c.Set(UserIDKey, userID)
+					c.Next()
+				},
+			)
+
+			// create request
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				"/api/v1/stock-movements/moveout",
+				bytes.NewBufferString(tt.inputJSON),
+			)
+			req.Header.Set("Content-Type", "application/json")
+			if tt.idempotencyKey != "" {
+				req.Header.Set(IdempotencyKeyHeader, tt.idempotencyKey)
+			}
+
+			// serve request
+			router.ServeHTTP(w, req)
+
+			// assert status code
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedKey != "" {
+				var response struct {
+					Data createStockMovementOutResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				require.Len(t, response.Data.StockMovements, 1)
+				assert.Equal(t, tt.expectedKey, response.Data.StockMovements[0].IdempotencyKey)
+			}
+
+			mockTxUsecase.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPreviewStockMovementOut(t *testing.T) {
+	// t.Parallell()
+
+	productID := uuid.MustParse("019444a2-e318-79b5-8fe4-b32716306083")
+	warehouseID := uuid.MustParse("019444a3-6a3f-7249-b694-f6f071d8eb79")
+
+	tests := []struct {
+		name           string
+		inputJSON      string
+		expectedStatus int
+		mockBehavior   func(*mockTransactionProductUsecase, *MockLogger)
+	}{
+		{
+			name: "Success",
+			inputJSON: `{
+                "items": [{"product_id": "019444a2-e318-79b5-8fe4-b32716306083", "quantity": 5}],
+                "zipcode": "12345",
+                "strategy": "minimize-shipments"
+            }`,
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				m.On("PreviewMoveOut",
+					mock.Anything,
+					mock.MatchedBy(func(sms []*entity.StockMovement) bool {
+						return len(sms) == 1 && sms[0].ProductID == productID && sms[0].Quantity == 5
+					}),
+					"12345",
+					"minimize-shipments",
+				).Return(&entity.AllocationPlan{
+					Strategy: "minimize-shipments",
+					Allocations: []entity.AllocationLine{
+						{ProductID: productID, WarehouseID: warehouseID, Quantity: 5},
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "Invalid JSON",
+			inputJSON: `{
+                "items": "invalid"
+            }`,
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				l.On("Error",
+					mock.Anything,
+					mock.Anything,
+				).Return()
+			},
+		},
+		{
+			name: "Internal Error",
+			inputJSON: `{
+                "items": [{"product_id": "019444a2-e318-79b5-8fe4-b32716306083", "quantity": 5}],
+                "zipcode": "12345"
+            }`,
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				m.On("PreviewMoveOut",
+					mock.Anything,
+					mock.Anything,
+					"12345",
+					"",
+				).Return(nil, fmt.Errorf("failed to get warehouse id and zip code by product id"))
+
+				l.On("Error",
+					mock.Anything,
+					mock.Anything,
+				).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			// initialize mocks
+			mockTxUsecase := new(mockTransactionProductUsecase)
+			mockStockUsecase := new(mockStockMovementUsecase)
+			mockLogger := NewMockLogger(t)
+
+			tt.mockBehavior(mockTxUsecase, mockLogger)
+
+			// setup router
+			router := gin.New()
+			handler := router.Group("/api/v1")
+			newStockMovementRoutes(
+				handler,
+				mockStockUsecase,
+				mockTxUsecase,
+				mockLogger,
+				func(c *gin.Context) { c.Next() },
+			)
+
+			// create request
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				"/api/v1/stock-movements/moveout/preview",
+				bytes.NewBufferString(tt.inputJSON),
+			)
+			req.Header.Set("Content-Type", "application/json")
+
+			// serve request
+			router.ServeHTTP(w, req)
+
+			// assert status code
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			mockTxUsecase.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateStockMovementReturn(t *testing.T) {
+	// t.Parallell()
+
+	tests := []struct {
+		name           string
+		inputJSON      string
+		expectedStatus int
+		mockBehavior   func(*mockTransactionProductUsecase, *MockLogger)
+	}{
+		{
+			name: "Success",
+			inputJSON: `{
+                "original_movement_id": "019444a2-e318-79b5-8fe4-b32716306083",
+                "quantity": 2,
+                "to_warehouse_id": "019444a3-6a3f-7249-b694-f6f071d8eb79",
+                "disposition": "quarantine"
+            }`,
+			expectedStatus: http.StatusCreated,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				m.On("MoveReturn",
+					mock.Anything,
+					mock.MatchedBy(func(sm *entity.StockMovement) bool {
+						return sm.OriginalMovementID.String() == "019444a2-e318-79b5-8fe4-b32716306083" &&
+							sm.Quantity == 2 &&
+							sm.ToWarehouseID.String() == "019444a3-6a3f-7249-b694-f6f071d8eb79" &&
+							sm.Disposition == entity.DispositionQuarantine
+					}),
+				).Return(nil)
+			},
+		},
+		{
+			name: "Invalid Disposition",
+			inputJSON: `{
+                "original_movement_id": "019444a2-e318-79b5-8fe4-b32716306083",
+                "quantity": 2,
+                "to_warehouse_id": "019444a3-6a3f-7249-b694-f6f071d8eb79",
+                "disposition": "lost"
+            }`,
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				l.On("Error",
+					mock.Anything,
+					mock.Anything,
+				).Return()
+			},
+		},
+		{
+			name: "Return Not Allowed",
+			inputJSON: `{
+                "original_movement_id": "019444a2-e318-79b5-8fe4-b32716306083",
+                "quantity": 20,
+                "to_warehouse_id": "019444a3-6a3f-7249-b694-f6f071d8eb79"
+            }`,
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				m.On("MoveReturn",
+					mock.Anything,
+					mock.AnythingOfType("*entity.StockMovement"),
+				).Return(fmt.Errorf("return quantity 20 exceeds remaining quantity 2: %w", entity.ErrReturnNotAllowed))
+
+				l.On("Error",
+					mock.Anything,
+					mock.Anything,
+				).Return()
+			},
+		},
+		{
+			name: "Internal Error",
+			inputJSON: `{
+                "original_movement_id": "019444a2-e318-79b5-8fe4-b32716306083",
+                "quantity": 2,
+                "to_warehouse_id": "019444a3-6a3f-7249-b694-f6f071d8eb79"
+            }`,
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				m.On("MoveReturn",
+					mock.Anything,
+					mock.AnythingOfType("*entity.StockMovement"),
+				).Return(fmt.Errorf("failed to begin transaction"))
+
+				l.On("Error",
+					mock.Anything,
+					mock.Anything,
+				).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			// initialize mocks
+			mockTxUsecase := new(mockTransactionProductUsecase)
+			mockStockUsecase := new(mockStockMovementUsecase)
+			mockLogger := NewMockLogger(t)
+
+			tt.mockBehavior(mockTxUsecase, mockLogger)
+
+			// setup router
+			router := gin.New()
+			handler := router.Group("/api/v1")
+			newStockMovementRoutes(
+				handler,
+				mockStockUsecase,
+				mockTxUsecase,
+				mockLogger,
+				func(c *gin.Context) { c.Next() },
+			)
+
+			// create request
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				"/api/v1/stock-movements/return",
+				bytes.NewBufferString(tt.inputJSON),
+			)
+			req.Header.Set("Content-Type", "application/json")
+
+			// serve request
+			router.ServeHTTP(w, req)
+
+			// assert status code
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			mockTxUsecase.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCompensateStockMovementOut(t *testing.T) {
+	// t.Parallell()
+
+	tests := []struct {
+		name           string
+		inputJSON      string
+		expectedStatus int
+		mockBehavior   func(*mockTransactionProductUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			inputJSON:      `{"reference_id": "order-1", "reason": "payment failed"}`,
+			expectedStatus: http.StatusCreated,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				m.On("CompensateMoveOut", mock.Anything, "order-1", "payment failed").
+					Return([]*entity.StockMovement{{MovementType: entity.MovementTypeReversal, Quantity: 2}}, nil)
+			},
+		},
+		{
+			name:           "Missing Reference ID",
+			inputJSON:      `{"reason": "payment failed"}`,
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Internal Error",
+			inputJSON:      `{"reference_id": "order-1"}`,
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				m.On("CompensateMoveOut", mock.Anything, "order-1", "").
+					Return(nil, fmt.Errorf("failed to begin transaction"))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockTxUsecase := new(mockTransactionProductUsecase)
+			mockStockUsecase := new(mockStockMovementUsecase)
+			mockLogger := NewMockLogger(t)
+
+			tt.mockBehavior(mockTxUsecase, mockLogger)
+
+			router := gin.New()
+			handler := router.Group("/api/v1")
+			newStockMovementRoutes(
+				handler,
+				mockStockUsecase,
+				mockTxUsecase,
+				mockLogger,
+				func(c *gin.Context) { c.Next() },
+			)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				"/api/v1/stock-movements/moveout/compensate",
+				bytes.NewBufferString(tt.inputJSON),
+			)
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			mockTxUsecase.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateStockMovementOutByReservation(t *testing.T) {
+	// t.Parallell()
+	reservationID := uuid.New()
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockBehavior   func(*mockTransactionProductUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			expectedStatus: http.StatusCreated,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				m.On("CommitReservation", mock.Anything, reservationID).
+					Return([]*entity.StockMovement{{MovementType: entity.MovementTypeSale, Quantity: 2}}, nil)
+			},
+		},
+		{
+			name:           "Not Found",
+			expectedStatus: http.StatusNotFound,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				m.On("CommitReservation", mock.Anything, reservationID).
+					Return(nil, fmt.Errorf("failed to commit reservation: %w", entity.ErrReservationNotFound))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Expired Or Committed",
+			expectedStatus: http.StatusConflict,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				m.On("CommitReservation", mock.Anything, reservationID).
+					Return(nil, fmt.Errorf("failed to commit reservation: %w", entity.ErrReservationNotHolding))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Internal Error",
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockTransactionProductUsecase, l *MockLogger) {
+				m.On("CommitReservation", mock.Anything, reservationID).
+					Return(nil, fmt.Errorf("failed to begin transaction"))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockTxUsecase := new(mockTransactionProductUsecase)
+			mockStockUsecase := new(mockStockMovementUsecase)
+			mockLogger := NewMockLogger(t)
+
+			tt.mockBehavior(mockTxUsecase, mockLogger)
+
+			router := gin.New()
+			handler := router.Group("/api/v1")
+			newStockMovementRoutes(
+				handler,
+				mockStockUsecase,
+				mockTxUsecase,
+				mockLogger,
+				func(c *gin.Context) { c.Next() },
+			)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				fmt.Sprintf("/api/v1/stock-movements/moveout/reservations/%s", reservationID),
+				nil,
+			)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			mockTxUsecase.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetAllStockMovements(t *testing.T) {
+	// t.Parallell()
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		mockBehavior   func(*mockStockMovementUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success Without Filter",
+			query:          "",
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockStockMovementUsecase, l *MockLogger) {
+				m.On("GetAllStockMovements",
+					mock.Anything,
+					entity.StockMovementFilter{},
+				).Return([]*entity.StockMovement{}, nil)
+			},
+		},
+		{
+			name:           "Success With Filter",
+			query:          "?type=sale&reference_id=order-1",
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockStockMovementUsecase, l *MockLogger) {
+				m.On("GetAllStockMovements",
+					mock.Anything,
+					entity.StockMovementFilter{
+						MovementType: entity.MovementTypeSale,
+						ReferenceID:  "order-1",
+					},
+				).Return([]*entity.StockMovement{}, nil)
+			},
+		},
+		{
+			name:           "Invalid Movement Type",
+			query:          "?type=refund",
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockStockMovementUsecase, l *MockLogger) {
+				l.On("Error",
+					mock.Anything,
+					mock.Anything,
+				).Return()
+			},
+		},
+		{
+			name:           "Internal Error",
+			query:          "?type=write-off",
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockStockMovementUsecase, l *MockLogger) {
+				m.On("GetAllStockMovements",
+					mock.Anything,
+					entity.StockMovementFilter{MovementType: entity.MovementTypeWriteOff},
+				).Return(nil, fmt.Errorf("database error"))
+
+				l.On("Error",
+					mock.Anything,
+					mock.Anything,
+				).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			// initialize mocks
+			mockTxUsecase := new(mockTransactionProductUsecase)
+			mockStockUsecase := new(mockStockMovementUsecase)
+			mockLogger := NewMockLogger(t)
+
+			tt.mockBehavior(mockStockUsecase, mockLogger)
+
+			// setup router
+			router := gin.New()
+			handler := router.Group("/api/v1")
+			newStockMovementRoutes(
+				handler,
+				mockStockUsecase,
+				mockTxUsecase,
+				mockLogger,
+				func(c *gin.Context) { c.Next() },
+			)
+
+			// create request
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				"/api/v1/stock-movements"+tt.query,
+				nil,
+			)
+
+			// serve request
+			router.ServeHTTP(w, req)
+
+			// assert status code
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			mockStockUsecase.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
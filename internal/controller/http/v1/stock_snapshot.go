@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/idoyudha/eshop-warehouse/pkg/logger"
+)
+
+type stockSnapshotRoutes struct {
+	uc usecase.StockSnapshot
+	l  logger.Interface
+}
+
+func newStockSnapshotRoutes(handler *gin.RouterGroup, uc usecase.StockSnapshot, l logger.Interface, authMid gin.HandlerFunc) {
+	r := &stockSnapshotRoutes{uc: uc, l: l}
+
+	h := handler.Group("/stock-snapshots").Use(authMid)
+	{
+		h.GET("/trend", r.getStockTrend)
+		h.GET("/compare", r.compareStockSnapshots)
+	}
+}
+
+type stockSnapshotQuery struct {
+	From        time.Time `form:"from" time_format:"2006-01-02" binding:"required"`
+	To          time.Time `form:"to" time_format:"2006-01-02" binding:"required"`
+	WarehouseID string    `form:"warehouse_id" binding:"omitempty,uuid"`
+	ProductID   string    `form:"product_id" binding:"omitempty,uuid"`
+}
+
+func (r *stockSnapshotRoutes) getStockTrend(ctx *gin.Context) {
+	var query stockSnapshotQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		r.l.Error(err, "http - v1 - stockSnapshotRoutes - getStockTrend")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	trend, err := r.uc.GetStockTrend(context.Background(), stockSnapshotQueryToStockSnapshotFilter(query))
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockSnapshotRoutes - getStockTrend")
+		r.handleStockSnapshotError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newGetSuccess(trend))
+}
+
+func (r *stockSnapshotRoutes) compareStockSnapshots(ctx *gin.Context) {
+	var query stockSnapshotQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		r.l.Error(err, "http - v1 - stockSnapshotRoutes - compareStockSnapshots")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	comparison, err := r.uc.CompareStockSnapshots(context.Background(), stockSnapshotQueryToStockSnapshotFilter(query))
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockSnapshotRoutes - compareStockSnapshots")
+		r.handleStockSnapshotError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newGetSuccess(comparison))
+}
+
+func (r *stockSnapshotRoutes) handleStockSnapshotError(ctx *gin.Context, err error) {
+	if errors.Is(err, entity.ErrInvalidStockSnapshot) {
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+}
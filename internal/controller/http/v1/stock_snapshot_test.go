@@ -0,0 +1,180 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockStockSnapshotUsecase struct {
+	mock.Mock
+}
+
+func (m *mockStockSnapshotUsecase) CaptureStockSnapshot(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockStockSnapshotUsecase) GetStockTrend(ctx context.Context, filter entity.StockSnapshotFilter) ([]*entity.StockTrendPoint, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.StockTrendPoint), args.Error(1)
+}
+
+func (m *mockStockSnapshotUsecase) CompareStockSnapshots(ctx context.Context, filter entity.StockSnapshotFilter) (*entity.StockComparison, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.StockComparison), args.Error(1)
+}
+
+var _ usecase.StockSnapshot = (*mockStockSnapshotUsecase)(nil)
+
+func newStockSnapshotTestRouter(uc *mockStockSnapshotUsecase, l *MockLogger) *gin.Engine {
+	router := gin.New()
+	handler := router.Group("/api/v1")
+	newStockSnapshotRoutes(
+		handler,
+		uc,
+		l,
+		func(c *gin.Context) {
+			c.Next()
+		},
+	)
+	return router
+}
+
+func TestGetStockTrend(t *testing.T) {
+	// t.Parallell()
+	warehouseID := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		mockBehavior   func(*mockStockSnapshotUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			query:          fmt.Sprintf("?from=2024-03-01&to=2024-03-31&warehouse_id=%s", warehouseID),
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockStockSnapshotUsecase, l *MockLogger) {
+				m.On("GetStockTrend",
+					mock.Anything,
+					mock.MatchedBy(func(f entity.StockSnapshotFilter) bool {
+						return f.From.Day() == 1 && f.To.Day() == 31 && f.WarehouseID == warehouseID && f.ProductID == uuid.Nil
+					}),
+				).Return([]*entity.StockTrendPoint{{Quantity: 10, Value: 120}}, nil)
+			},
+		},
+		{
+			name:           "Missing To Date",
+			query:          "?from=2024-03-01",
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockStockSnapshotUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "From After To",
+			query:          "?from=2024-03-31&to=2024-03-01",
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockStockSnapshotUsecase, l *MockLogger) {
+				m.On("GetStockTrend", mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf("from date must not be after to date: %w", entity.ErrInvalidStockSnapshot))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockStockSnapshotUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := newStockSnapshotTestRouter(mockUC, mockLogger)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/stock-snapshots/trend"+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCompareStockSnapshots(t *testing.T) {
+	// t.Parallell()
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		mockBehavior   func(*mockStockSnapshotUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			query:          "?from=2024-03-01&to=2024-03-08",
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockStockSnapshotUsecase, l *MockLogger) {
+				m.On("CompareStockSnapshots", mock.Anything, mock.Anything).
+					Return(&entity.StockComparison{QuantityChange: -3}, nil)
+			},
+		},
+		{
+			name:           "Invalid Product ID",
+			query:          "?from=2024-03-01&to=2024-03-08&product_id=invalid",
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockStockSnapshotUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Usecase Error",
+			query:          "?from=2024-03-01&to=2024-03-08",
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockStockSnapshotUsecase, l *MockLogger) {
+				m.On("CompareStockSnapshots", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("database error"))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockStockSnapshotUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := newStockSnapshotTestRouter(mockUC, mockLogger)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/stock-snapshots/compare"+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/idoyudha/eshop-warehouse/pkg/logger"
+)
+
+type stockThresholdRoutes struct {
+	uc usecase.StockThreshold
+	l  logger.Interface
+}
+
+func newStockThresholdRoutes(handler *gin.RouterGroup, uc usecase.StockThreshold, l logger.Interface, authMid gin.HandlerFunc) {
+	r := &stockThresholdRoutes{uc: uc, l: l}
+
+	h := handler.Group("/stock-thresholds").Use(authMid)
+	{
+		h.GET("/warehouse/:warehouse_id", r.getStockThresholdsByWarehouseID)
+		h.PUT("/warehouse/:warehouse_id/product/:product_id", r.setStockThreshold)
+		h.DELETE("/warehouse/:warehouse_id/product/:product_id", r.deleteStockThreshold)
+		h.GET("/low-stocks", r.getLowStocks)
+		h.GET("/replenishments", r.suggestReplenishments)
+	}
+}
+
+type setStockThresholdRequest struct {
+	ReorderPoint int64 `json:"reorder_point" binding:"gte=0"`
+	TargetLevel  int64 `json:"target_level" binding:"required,gt=0"`
+}
+
+func (r *stockThresholdRoutes) setStockThreshold(ctx *gin.Context) {
+	warehouseID, err := uuid.Parse(ctx.Param("warehouse_id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockThresholdRoutes - setStockThreshold")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	productID, err := uuid.Parse(ctx.Param("product_id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockThresholdRoutes - setStockThreshold")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	var req setStockThresholdRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - stockThresholdRoutes - setStockThreshold")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	stockThreshold := setStockThresholdRequestToStockThresholdEntity(req, warehouseID, productID)
+	err = r.uc.SetStockThreshold(context.Background(), &stockThreshold)
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockThresholdRoutes - setStockThreshold")
+		r.handleStockThresholdError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUpdateSuccess(stockThreshold))
+}
+
+func (r *stockThresholdRoutes) getStockThresholdsByWarehouseID(ctx *gin.Context) {
+	warehouseID, err := uuid.Parse(ctx.Param("warehouse_id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockThresholdRoutes - getStockThresholdsByWarehouseID")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	stockThresholds, err := r.uc.GetStockThresholdsByWarehouseID(context.Background(), warehouseID)
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockThresholdRoutes - getStockThresholdsByWarehouseID")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newGetSuccess(stockThresholds))
+}
+
+func (r *stockThresholdRoutes) deleteStockThreshold(ctx *gin.Context) {
+	warehouseID, err := uuid.Parse(ctx.Param("warehouse_id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockThresholdRoutes - deleteStockThreshold")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	productID, err := uuid.Parse(ctx.Param("product_id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockThresholdRoutes - deleteStockThreshold")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	err = r.uc.DeleteStockThreshold(context.Background(), warehouseID, productID)
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockThresholdRoutes - deleteStockThreshold")
+		r.handleStockThresholdError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newDeleteSuccess())
+}
+
+func (r *stockThresholdRoutes) getLowStocks(ctx *gin.Context) {
+	lowStocks, err := r.uc.GetLowStocks(context.Background())
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockThresholdRoutes - getLowStocks")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newGetSuccess(lowStocks))
+}
+
+func (r *stockThresholdRoutes) suggestReplenishments(ctx *gin.Context) {
+	suggestions, err := r.uc.SuggestReplenishments(context.Background())
+	if err != nil {
+		r.l.Error(err, "http - v1 - stockThresholdRoutes - suggestReplenishments")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newGetSuccess(suggestions))
+}
+
+func (r *stockThresholdRoutes) handleStockThresholdError(ctx *gin.Context, err error) {
+	if errors.Is(err, entity.ErrInvalidStockThreshold) {
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+}
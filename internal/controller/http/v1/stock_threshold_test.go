@@ -0,0 +1,194 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockStockThresholdUsecase struct {
+	mock.Mock
+}
+
+func (m *mockStockThresholdUsecase) SetStockThreshold(ctx context.Context, stockThreshold *entity.StockThreshold) error {
+	args := m.Called(ctx, stockThreshold)
+	return args.Error(0)
+}
+
+func (m *mockStockThresholdUsecase) GetStockThresholdsByWarehouseID(ctx context.Context, warehouseID uuid.UUID) ([]*entity.StockThreshold, error) {
+	args := m.Called(ctx, warehouseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.StockThreshold), args.Error(1)
+}
+
+func (m *mockStockThresholdUsecase) DeleteStockThreshold(ctx context.Context, warehouseID, productID uuid.UUID) error {
+	args := m.Called(ctx, warehouseID, productID)
+	return args.Error(0)
+}
+
+func (m *mockStockThresholdUsecase) GetLowStocks(ctx context.Context) ([]*entity.StockLevel, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.StockLevel), args.Error(1)
+}
+
+func (m *mockStockThresholdUsecase) EvaluateLowStocks(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockStockThresholdUsecase) SuggestReplenishments(ctx context.Context) ([]*entity.ReplenishmentSuggestion, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.ReplenishmentSuggestion), args.Error(1)
+}
+
+var _ usecase.StockThreshold = (*mockStockThresholdUsecase)(nil)
+
+func newStockThresholdTestRouter(uc *mockStockThresholdUsecase, l *MockLogger) *gin.Engine {
+	router := gin.New()
+	handler := router.Group("/api/v1")
+	newStockThresholdRoutes(
+		handler,
+		uc,
+		l,
+		func(c *gin.Context) {
+			c.Next()
+		},
+	)
+	return router
+}
+
+func TestSetStockThreshold(t *testing.T) {
+	// t.Parallell()
+	warehouseID := uuid.New()
+	productID := uuid.New()
+
+	tests := []struct {
+		name           string
+		inputJSON      string
+		expectedStatus int
+		mockBehavior   func(*mockStockThresholdUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			inputJSON:      `{"reorder_point": 5, "target_level": 20}`,
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockStockThresholdUsecase, l *MockLogger) {
+				m.On("SetStockThreshold",
+					mock.Anything,
+					mock.MatchedBy(func(st *entity.StockThreshold) bool {
+						return st.WarehouseID == warehouseID &&
+							st.ProductID == productID &&
+							st.ReorderPoint == 5 &&
+							st.TargetLevel == 20
+					}),
+				).Return(nil)
+			},
+		},
+		{
+			name:           "Missing Target Level",
+			inputJSON:      `{"reorder_point": 5}`,
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockStockThresholdUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Target Level Below Reorder Point",
+			inputJSON:      `{"reorder_point": 5, "target_level": 3}`,
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockStockThresholdUsecase, l *MockLogger) {
+				m.On("SetStockThreshold", mock.Anything, mock.Anything).
+					Return(fmt.Errorf("target level must be greater than reorder point: %w", entity.ErrInvalidStockThreshold))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockStockThresholdUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := newStockThresholdTestRouter(mockUC, mockLogger)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/stock-thresholds/warehouse/%s/product/%s", warehouseID, productID)
+			req, _ := http.NewRequest(http.MethodPut, url, bytes.NewBufferString(tt.inputJSON))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSuggestReplenishments(t *testing.T) {
+	// t.Parallell()
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockBehavior   func(*mockStockThresholdUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockStockThresholdUsecase, l *MockLogger) {
+				m.On("SuggestReplenishments", mock.Anything).
+					Return([]*entity.ReplenishmentSuggestion{{ProductID: uuid.New(), Quantity: 4}}, nil)
+			},
+		},
+		{
+			name:           "Usecase Error",
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockStockThresholdUsecase, l *MockLogger) {
+				m.On("SuggestReplenishments", mock.Anything).Return(nil, fmt.Errorf("database error"))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockStockThresholdUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := newStockThresholdTestRouter(mockUC, mockLogger)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/stock-thresholds/replenishments", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/idoyudha/eshop-warehouse/pkg/logger"
+)
+
+type transferOrderRoutes struct {
+	uc usecase.TransferOrder
+	l  logger.Interface
+}
+
+func newTransferOrderRoutes(handler *gin.RouterGroup, uc usecase.TransferOrder, l logger.Interface, authMid gin.HandlerFunc) {
+	r := &transferOrderRoutes{uc: uc, l: l}
+
+	h := handler.Group("/transfer-orders").Use(authMid)
+	{
+		h.POST("", r.createTransferOrder)
+		h.GET("/:id", r.getTransferOrderByID)
+		h.POST("/:id/pick", r.pickTransferOrder)
+		h.POST("/:id/ship", r.shipTransferOrder)
+		h.POST("/:id/receive", r.receiveTransferOrder)
+		h.DELETE("/:id", r.cancelTransferOrder)
+		h.GET("/in-transit/product/:product_id", r.getInTransitByProductID)
+	}
+}
+
+type createTransferOrderRequest struct {
+	FromWarehouseID uuid.UUID                        `json:"from_warehouse_id" binding:"required"`
+	ToWarehouseID   uuid.UUID                        `json:"to_warehouse_id" binding:"required"`
+	Reason          string                           `json:"reason"`
+	Lines           []itemCreateTransferOrderRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+type itemCreateTransferOrderRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	Quantity  int64     `json:"quantity" binding:"required,gt=0"`
+}
+
+func (r *transferOrderRoutes) createTransferOrder(ctx *gin.Context) {
+	var req createTransferOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - transferOrderRoutes - createTransferOrder")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	userID, exist := ctx.Get(UserIDKey)
+	if !exist {
+		r.l.Error("not exist", "http - v1 - transferOrderRoutes - createTransferOrder")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError("user id not exist"))
+		return
+	}
+
+	transferOrder := createTransferOrderRequestToTransferOrderEntity(req, userID.(uuid.UUID))
+	err := r.uc.CreateTransferOrder(context.Background(), &transferOrder)
+	if err != nil {
+		r.l.Error(err, "http - v1 - transferOrderRoutes - createTransferOrder")
+		r.handleTransferOrderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newCreateSuccess(transferOrder))
+}
+
+func (r *transferOrderRoutes) getTransferOrderByID(ctx *gin.Context) {
+	transferOrderID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - transferOrderRoutes - getTransferOrderByID")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	transferOrder, err := r.uc.GetTransferOrderByID(context.Background(), transferOrderID)
+	if err != nil {
+		r.l.Error(err, "http - v1 - transferOrderRoutes - getTransferOrderByID")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newGetSuccess(transferOrder))
+}
+
+func (r *transferOrderRoutes) pickTransferOrder(ctx *gin.Context) {
+	transferOrder, ok := r.transferOrderFromPath(ctx, "pickTransferOrder")
+	if !ok {
+		return
+	}
+
+	err := r.uc.PickTransferOrder(context.Background(), &transferOrder)
+	if err != nil {
+		r.l.Error(err, "http - v1 - transferOrderRoutes - pickTransferOrder")
+		r.handleTransferOrderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUpdateSuccess(transferOrder))
+}
+
+func (r *transferOrderRoutes) shipTransferOrder(ctx *gin.Context) {
+	transferOrder, ok := r.transferOrderFromPath(ctx, "shipTransferOrder")
+	if !ok {
+		return
+	}
+
+	err := r.uc.ShipTransferOrder(context.Background(), &transferOrder)
+	if err != nil {
+		r.l.Error(err, "http - v1 - transferOrderRoutes - shipTransferOrder")
+		r.handleTransferOrderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUpdateSuccess(transferOrder))
+}
+
+type receiveTransferOrderRequest struct {
+	Lines []itemReceiveTransferOrderRequest `json:"lines" binding:"dive"`
+	Close bool                              `json:"close"`
+}
+
+type itemReceiveTransferOrderRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	Quantity  int64     `json:"quantity" binding:"required,gt=0"`
+}
+
+func (r *transferOrderRoutes) receiveTransferOrder(ctx *gin.Context) {
+	transferOrder, ok := r.transferOrderFromPath(ctx, "receiveTransferOrder")
+	if !ok {
+		return
+	}
+
+	var req receiveTransferOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - transferOrderRoutes - receiveTransferOrder")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	idempotencyKey, ok := getIdempotencyKey(ctx, entity.IdempotencyOperationTransferOrderReceive)
+	if !ok {
+		r.l.Error("idempotency key too long", "http - v1 - transferOrderRoutes - receiveTransferOrder")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError("idempotency key too long"))
+		return
+	}
+
+	delivery := receiveTransferOrderRequestToTransferOrderDeliveryEntity(req, idempotencyKey)
+	err := r.uc.ReceiveTransferOrder(context.Background(), &transferOrder, &delivery)
+	if err != nil {
+		r.l.Error(err, "http - v1 - transferOrderRoutes - receiveTransferOrder")
+		r.handleTransferOrderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUpdateSuccess(transferOrder))
+}
+
+func (r *transferOrderRoutes) cancelTransferOrder(ctx *gin.Context) {
+	transferOrder, ok := r.transferOrderFromPath(ctx, "cancelTransferOrder")
+	if !ok {
+		return
+	}
+
+	err := r.uc.CancelTransferOrder(context.Background(), &transferOrder)
+	if err != nil {
+		r.l.Error(err, "http - v1 - transferOrderRoutes - cancelTransferOrder")
+		r.handleTransferOrderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newDeleteSuccess())
+}
+
+func (r *transferOrderRoutes) getInTransitByProductID(ctx *gin.Context) {
+	productID, err := uuid.Parse(ctx.Param("product_id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - transferOrderRoutes - getInTransitByProductID")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	productInTransit, err := r.uc.GetInTransitByProductID(context.Background(), productID)
+	if err != nil {
+		r.l.Error(err, "http - v1 - transferOrderRoutes - getInTransitByProductID")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newGetSuccess(productInTransit))
+}
+
+// transfer order of the id path parameter, updated by the current user
+func (r *transferOrderRoutes) transferOrderFromPath(ctx *gin.Context, method string) (entity.TransferOrder, bool) {
+	transferOrderID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - transferOrderRoutes - "+method)
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return entity.TransferOrder{}, false
+	}
+
+	userID, exist := ctx.Get(UserIDKey)
+	if !exist {
+		r.l.Error("not exist", "http - v1 - transferOrderRoutes - "+method)
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError("user id not exist"))
+		return entity.TransferOrder{}, false
+	}
+
+	return entity.TransferOrder{
+		ID:        transferOrderID,
+		UpdatedBy: userID.(uuid.UUID),
+	}, true
+}
+
+// invalid status transitions and deliveries are client errors, shipping without enough stock is a conflict
+func (r *transferOrderRoutes) handleTransferOrderError(ctx *gin.Context, err error) {
+	if errors.Is(err, entity.ErrIdempotencyKeyReused) {
+		ctx.JSON(http.StatusUnprocessableEntity, newUnprocessableEntityError(err.Error()))
+		return
+	}
+	var insufficientStockErr *entity.InsufficientStockError
+	if errors.As(err, &insufficientStockErr) {
+		ctx.JSON(http.StatusConflict, newInsufficientStockError(insufficientStockErr))
+		return
+	}
+	if errors.Is(err, entity.ErrInvalidTransferOrder) {
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+}
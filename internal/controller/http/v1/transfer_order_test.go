@@ -0,0 +1,329 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockTransferOrderUsecase struct {
+	mock.Mock
+}
+
+func (m *mockTransferOrderUsecase) CreateTransferOrder(ctx context.Context, transferOrder *entity.TransferOrder) error {
+	args := m.Called(ctx, transferOrder)
+	return args.Error(0)
+}
+
+func (m *mockTransferOrderUsecase) GetTransferOrderByID(ctx context.Context, id uuid.UUID) (*entity.TransferOrder, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.TransferOrder), args.Error(1)
+}
+
+func (m *mockTransferOrderUsecase) PickTransferOrder(ctx context.Context, transferOrder *entity.TransferOrder) error {
+	args := m.Called(ctx, transferOrder)
+	return args.Error(0)
+}
+
+func (m *mockTransferOrderUsecase) ShipTransferOrder(ctx context.Context, transferOrder *entity.TransferOrder) error {
+	args := m.Called(ctx, transferOrder)
+	return args.Error(0)
+}
+
+func (m *mockTransferOrderUsecase) ReceiveTransferOrder(ctx context.Context, transferOrder *entity.TransferOrder, delivery *entity.TransferOrderDelivery) error {
+	args := m.Called(ctx, transferOrder, delivery)
+	return args.Error(0)
+}
+
+func (m *mockTransferOrderUsecase) CancelTransferOrder(ctx context.Context, transferOrder *entity.TransferOrder) error {
+	args := m.Called(ctx, transferOrder)
+	return args.Error(0)
+}
+
+func (m *mockTransferOrderUsecase) GetInTransitByProductID(ctx context.Context, productID uuid.UUID) (*entity.ProductInTransit, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ProductInTransit), args.Error(1)
+}
+
+var _ usecase.TransferOrder = (*mockTransferOrderUsecase)(nil)
+
+func newTransferOrderTestRouter(uc *mockTransferOrderUsecase, l *MockLogger, userID uuid.UUID) *gin.Engine {
+	router := gin.New()
+	handler := router.Group("/api/v1")
+	newTransferOrderRoutes(
+		handler,
+		uc,
+		l,
+		func(c *gin.Context) {
+			c.Set(UserIDKey, userID)
+			c.Next()
+		},
+	)
+	return router
+}
+
+func TestCreateTransferOrder(t *testing.T) {
+	// t.Parallell()
+	userID := uuid.New()
+	fromWarehouseID := uuid.New()
+	toWarehouseID := uuid.New()
+	productID := uuid.New()
+
+	tests := []struct {
+		name           string
+		inputJSON      string
+		expectedStatus int
+		mockBehavior   func(*mockTransferOrderUsecase, *MockLogger)
+	}{
+		{
+			name: "Success",
+			inputJSON: fmt.Sprintf(`{"from_warehouse_id": "%s", "to_warehouse_id": "%s",
+				"lines": [{"product_id": "%s", "quantity": 5}]}`, fromWarehouseID, toWarehouseID, productID),
+			expectedStatus: http.StatusCreated,
+			mockBehavior: func(m *mockTransferOrderUsecase, l *MockLogger) {
+				m.On("CreateTransferOrder",
+					mock.Anything,
+					mock.MatchedBy(func(to *entity.TransferOrder) bool {
+						return to.FromWarehouseID == fromWarehouseID &&
+							to.ToWarehouseID == toWarehouseID &&
+							to.CreatedBy == userID &&
+							len(to.Lines) == 1 &&
+							to.Lines[0].Quantity == 5
+					}),
+				).Return(nil)
+			},
+		},
+		{
+			name: "Zero Quantity",
+			inputJSON: fmt.Sprintf(`{"from_warehouse_id": "%s", "to_warehouse_id": "%s",
+				"lines": [{"product_id": "%s", "quantity": 0}]}`, fromWarehouseID, toWarehouseID, productID),
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockTransferOrderUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name: "Same Warehouse",
+			inputJSON: fmt.Sprintf(`{"from_warehouse_id": "%s", "to_warehouse_id": "%s",
+				"lines": [{"product_id": "%s", "quantity": 5}]}`, fromWarehouseID, fromWarehouseID, productID),
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockTransferOrderUsecase, l *MockLogger) {
+				m.On("CreateTransferOrder", mock.Anything, mock.Anything).Return(entity.ErrInvalidTransferOrder)
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockTransferOrderUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := newTransferOrderTestRouter(mockUC, mockLogger, userID)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/transfer-orders", bytes.NewBufferString(tt.inputJSON))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestShipTransferOrder(t *testing.T) {
+	// t.Parallell()
+	userID := uuid.New()
+	transferOrderID := uuid.New()
+
+	tests := []struct {
+		name           string
+		id             string
+		expectedStatus int
+		mockBehavior   func(*mockTransferOrderUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			id:             transferOrderID.String(),
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockTransferOrderUsecase, l *MockLogger) {
+				m.On("ShipTransferOrder",
+					mock.Anything,
+					mock.MatchedBy(func(to *entity.TransferOrder) bool {
+						return to.ID == transferOrderID && to.UpdatedBy == userID
+					}),
+				).Return(nil)
+			},
+		},
+		{
+			name:           "Invalid ID",
+			id:             "invalid-uuid",
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockTransferOrderUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Not Picked",
+			id:             transferOrderID.String(),
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockTransferOrderUsecase, l *MockLogger) {
+				m.On("ShipTransferOrder", mock.Anything, mock.Anything).
+					Return(fmt.Errorf("failed to ship transfer order: %w", entity.ErrInvalidTransferOrder))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Insufficient Stock",
+			id:             transferOrderID.String(),
+			expectedStatus: http.StatusConflict,
+			mockBehavior: func(m *mockTransferOrderUsecase, l *MockLogger) {
+				m.On("ShipTransferOrder", mock.Anything, mock.Anything).
+					Return(fmt.Errorf("failed to ship transfer order: %w", &entity.InsufficientStockError{
+						Items: []entity.InsufficientStockItem{{ProductID: uuid.New(), Requested: 5, Available: 2}},
+					}))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockTransferOrderUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := newTransferOrderTestRouter(mockUC, mockLogger, userID)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/transfer-orders/"+tt.id+"/ship", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReceiveTransferOrder(t *testing.T) {
+	// t.Parallell()
+	userID := uuid.New()
+	transferOrderID := uuid.New()
+	productID := uuid.New()
+
+	tests := []struct {
+		name           string
+		inputJSON      string
+		expectedStatus int
+		mockBehavior   func(*mockTransferOrderUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			inputJSON:      fmt.Sprintf(`{"lines": [{"product_id": "%s", "quantity": 2}], "close": true}`, productID),
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockTransferOrderUsecase, l *MockLogger) {
+				m.On("ReceiveTransferOrder",
+					mock.Anything,
+					mock.MatchedBy(func(to *entity.TransferOrder) bool {
+						return to.ID == transferOrderID && to.UpdatedBy == userID
+					}),
+					mock.MatchedBy(func(d *entity.TransferOrderDelivery) bool {
+						return d.Close &&
+							d.IdempotencyKey == entity.ScopedIdempotencyKey(entity.IdempotencyOperationTransferOrderReceive, userID, "receive-1") &&
+							len(d.Lines) == 1 &&
+							d.Lines[0].ProductID == productID &&
+							d.Lines[0].Quantity == 2
+					}),
+				).Return(nil)
+			},
+		},
+		{
+			name:           "Negative Quantity",
+			inputJSON:      fmt.Sprintf(`{"lines": [{"product_id": "%s", "quantity": -2}]}`, productID),
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockTransferOrderUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "More Than In Transit",
+			inputJSON:      fmt.Sprintf(`{"lines": [{"product_id": "%s", "quantity": 20}]}`, productID),
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockTransferOrderUsecase, l *MockLogger) {
+				m.On("ReceiveTransferOrder", mock.Anything, mock.Anything, mock.Anything).
+					Return(fmt.Errorf("failed to receive transfer order: %w", entity.ErrInvalidTransferOrder))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockTransferOrderUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := newTransferOrderTestRouter(mockUC, mockLogger, userID)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/transfer-orders/"+transferOrderID.String()+"/receive", bytes.NewBufferString(tt.inputJSON))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(IdempotencyKeyHeader, "receive-1")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetInTransitByProductID(t *testing.T) {
+	// t.Parallell()
+	userID := uuid.New()
+	productID := uuid.New()
+
+	mockUC := new(mockTransferOrderUsecase)
+	mockLogger := NewMockLogger(t)
+	mockUC.On("GetInTransitByProductID", mock.Anything, productID).
+		Return(&entity.ProductInTransit{ProductID: productID, Quantity: 3}, nil)
+
+	router := newTransferOrderTestRouter(mockUC, mockLogger, userID)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/transfer-orders/in-transit/product/"+productID.String(), nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"quantity":3`)
+	mockUC.AssertExpectations(t)
+}
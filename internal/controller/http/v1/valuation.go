@@ -0,0 +1,91 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/idoyudha/eshop-warehouse/pkg/logger"
+)
+
+type valuationRoutes struct {
+	uc usecase.Valuation
+	l  logger.Interface
+}
+
+func newValuationRoutes(handler *gin.RouterGroup, uc usecase.Valuation, l logger.Interface, authMid gin.HandlerFunc) {
+	r := &valuationRoutes{uc: uc, l: l}
+
+	h := handler.Group("/valuation").Use(authMid)
+	{
+		h.GET("/warehouse/:warehouse_id", r.getInventoryValuation)
+		h.GET("/cogs", r.getCostOfGoodsSold)
+	}
+}
+
+type inventoryValuationQuery struct {
+	Method string `form:"method" binding:"omitempty,oneof=fifo weighted-average"`
+}
+
+func (r *valuationRoutes) getInventoryValuation(ctx *gin.Context) {
+	warehouseID, err := uuid.Parse(ctx.Param("warehouse_id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - valuationRoutes - getInventoryValuation")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	var query inventoryValuationQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		r.l.Error(err, "http - v1 - valuationRoutes - getInventoryValuation")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	valuation, err := r.uc.GetInventoryValuation(context.Background(), warehouseID, query.Method)
+	if err != nil {
+		r.l.Error(err, "http - v1 - valuationRoutes - getInventoryValuation")
+		r.handleValuationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newGetSuccess(valuation))
+}
+
+type costOfGoodsSoldQuery struct {
+	From        time.Time `form:"from" time_format:"2006-01-02" binding:"required"`
+	To          time.Time `form:"to" time_format:"2006-01-02" binding:"required"`
+	Method      string    `form:"method" binding:"omitempty,oneof=fifo weighted-average"`
+	WarehouseID string    `form:"warehouse_id" binding:"omitempty,uuid"`
+}
+
+func (r *valuationRoutes) getCostOfGoodsSold(ctx *gin.Context) {
+	var query costOfGoodsSoldQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		r.l.Error(err, "http - v1 - valuationRoutes - getCostOfGoodsSold")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	cogs, err := r.uc.GetCostOfGoodsSold(context.Background(), costOfGoodsSoldQueryToCostOfGoodsSoldPeriod(query))
+	if err != nil {
+		r.l.Error(err, "http - v1 - valuationRoutes - getCostOfGoodsSold")
+		r.handleValuationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newGetSuccess(cogs))
+}
+
+func (r *valuationRoutes) handleValuationError(ctx *gin.Context, err error) {
+	if errors.Is(err, entity.ErrInvalidValuation) {
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+}
@@ -0,0 +1,188 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockValuationUsecase struct {
+	mock.Mock
+}
+
+func (m *mockValuationUsecase) GetInventoryValuation(ctx context.Context, warehouseID uuid.UUID, method string) (*entity.InventoryValuation, error) {
+	args := m.Called(ctx, warehouseID, method)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.InventoryValuation), args.Error(1)
+}
+
+func (m *mockValuationUsecase) GetCostOfGoodsSold(ctx context.Context, period entity.CostOfGoodsSoldPeriod) (*entity.CostOfGoodsSold, error) {
+	args := m.Called(ctx, period)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.CostOfGoodsSold), args.Error(1)
+}
+
+var _ usecase.Valuation = (*mockValuationUsecase)(nil)
+
+func newValuationTestRouter(uc *mockValuationUsecase, l *MockLogger) *gin.Engine {
+	router := gin.New()
+	handler := router.Group("/api/v1")
+	newValuationRoutes(
+		handler,
+		uc,
+		l,
+		func(c *gin.Context) {
+			c.Next()
+		},
+	)
+	return router
+}
+
+func TestGetInventoryValuation(t *testing.T) {
+	// t.Parallell()
+	warehouseID := uuid.New()
+
+	tests := []struct {
+		name           string
+		warehouseID    string
+		query          string
+		expectedStatus int
+		mockBehavior   func(*mockValuationUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			warehouseID:    warehouseID.String(),
+			query:          "?method=weighted-average",
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockValuationUsecase, l *MockLogger) {
+				m.On("GetInventoryValuation", mock.Anything, warehouseID, entity.ValuationMethodWeightedAverage).
+					Return(&entity.InventoryValuation{WarehouseID: warehouseID, TotalValue: 225}, nil)
+			},
+		},
+		{
+			name:           "Invalid Warehouse ID",
+			warehouseID:    "invalid",
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockValuationUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Unknown Method",
+			warehouseID:    warehouseID.String(),
+			query:          "?method=lifo",
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockValuationUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "Usecase Error",
+			warehouseID:    warehouseID.String(),
+			expectedStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockValuationUsecase, l *MockLogger) {
+				m.On("GetInventoryValuation", mock.Anything, warehouseID, "").Return(nil, fmt.Errorf("database error"))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockValuationUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := newValuationTestRouter(mockUC, mockLogger)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/valuation/warehouse/"+tt.warehouseID+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetCostOfGoodsSold(t *testing.T) {
+	// t.Parallell()
+	warehouseID := uuid.New()
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		mockBehavior   func(*mockValuationUsecase, *MockLogger)
+	}{
+		{
+			name:           "Success",
+			query:          fmt.Sprintf("?from=2024-03-01&to=2024-03-31&method=fifo&warehouse_id=%s", warehouseID),
+			expectedStatus: http.StatusOK,
+			mockBehavior: func(m *mockValuationUsecase, l *MockLogger) {
+				m.On("GetCostOfGoodsSold",
+					mock.Anything,
+					mock.MatchedBy(func(p entity.CostOfGoodsSoldPeriod) bool {
+						return p.From.Day() == 1 && p.To.Day() == 31 && p.Method == entity.ValuationMethodFIFO && p.WarehouseID == warehouseID
+					}),
+				).Return(&entity.CostOfGoodsSold{TotalCost: 40}, nil)
+			},
+		},
+		{
+			name:           "Missing From Date",
+			query:          "?to=2024-03-31",
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockValuationUsecase, l *MockLogger) {
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+		{
+			name:           "To Before From",
+			query:          "?from=2024-03-31&to=2024-03-01",
+			expectedStatus: http.StatusBadRequest,
+			mockBehavior: func(m *mockValuationUsecase, l *MockLogger) {
+				m.On("GetCostOfGoodsSold", mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf("to date must not be before from date: %w", entity.ErrInvalidValuation))
+				l.On("Error", mock.Anything, mock.Anything).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockValuationUsecase)
+			mockLogger := NewMockLogger(t)
+			tt.mockBehavior(mockUC, mockLogger)
+
+			router := newValuationTestRouter(mockUC, mockLogger)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/valuation/cogs"+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
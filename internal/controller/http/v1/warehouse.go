@@ -29,11 +29,13 @@ func newWarehouseRoutes(handler *gin.RouterGroup, uc usecase.Warehouse, l logger
 }
 
 type createWarehouseRequest struct {
-	Name    string `json:"name" binding:"required"`
-	Street  string `json:"street" binding:"required"`
-	City    string `json:"city" binding:"required"`
-	State   string `json:"state" binding:"required"`
-	ZipCode string `json:"zip_code" binding:"required"`
+	Name      string   `json:"name" binding:"required"`
+	Street    string   `json:"street" binding:"required"`
+	City      string   `json:"city" binding:"required"`
+	State     string   `json:"state" binding:"required"`
+	ZipCode   string   `json:"zip_code" binding:"required"`
+	Latitude  *float64 `json:"latitude" binding:"required_with=Longitude,omitempty,gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude" binding:"required_with=Latitude,omitempty,gte=-180,lte=180"`
 }
 
 type createWarehouseResponse struct {
@@ -43,6 +45,8 @@ type createWarehouseResponse struct {
 	City            string    `json:"city"`
 	State           string    `json:"state"`
 	ZipCode         string    `json:"zip_code"`
+	Latitude        *float64  `json:"latitude"`
+	Longitude       *float64  `json:"longitude"`
 	IsMainWarehouse bool      `json:"is_main_warehouse"`
 }
 
@@ -69,8 +73,10 @@ func (r *warehouseRoutes) createWarehouse(ctx *gin.Context) {
 }
 
 type updateWarehouseRequest struct {
-	Name   string `json:"name" binding:"required"`
-	Street string `json:"street" binding:"required"`
+	Name      string   `json:"name" binding:"required"`
+	Street    string   `json:"street" binding:"required"`
+	Latitude  *float64 `json:"latitude" binding:"required_with=Longitude,omitempty,gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude" binding:"required_with=Latitude,omitempty,gte=-180,lte=180"`
 }
 
 type updateWarehouseResponse struct {
@@ -80,6 +86,8 @@ type updateWarehouseResponse struct {
 	City            string    `json:"city"`
 	State           string    `json:"state"`
 	ZipCode         string    `json:"zip_code"`
+	Latitude        *float64  `json:"latitude"`
+	Longitude       *float64  `json:"longitude"`
 	IsMainWarehouse bool      `json:"is_main_warehouse"`
 }
 
@@ -119,6 +127,8 @@ type getWarehouseResponse struct {
 	City            string    `json:"city"`
 	State           string    `json:"state"`
 	ZipCode         string    `json:"zip_code"`
+	Latitude        *float64  `json:"latitude"`
+	Longitude       *float64  `json:"longitude"`
 	IsMainWarehouse bool      `json:"is_main_warehouse"`
 }
 
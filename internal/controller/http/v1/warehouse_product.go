@@ -2,10 +2,12 @@ package v1
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
 	"github.com/idoyudha/eshop-warehouse/internal/usecase"
 	"github.com/idoyudha/eshop-warehouse/pkg/logger"
 )
@@ -29,6 +31,7 @@ func newWarehouseProductRoutes(
 		h.GET("/product/:product_id", r.getWarehouseProductByProductID)
 		h.GET("/warehouse/:warehouse_id", r.getWarehouseProductByWarehouseID)
 		h.GET("/product/:product_id/warehouse/:warehouse_id", r.getWarehouseProductByProductIDAndWarehouseID)
+		h.POST("/product/:product_id/warehouse/:warehouse_id/adjustments", r.adjustWarehouseProductQuantity)
 		h.POST("/nearest", r.getNearestWarehouseZipCode)
 	}
 }
@@ -105,6 +108,57 @@ func (r *warehouseProductRoutes) getWarehouseProductByProductIDAndWarehouseID(ct
 	ctx.JSON(http.StatusOK, newGetSuccess(products))
 }
 
+// either delta or counted quantity is used
+type adjustWarehouseProductQuantityRequest struct {
+	Delta           int64  `json:"delta"`
+	CountedQuantity *int64 `json:"counted_quantity" binding:"omitempty,gte=0"`
+	Reason          string `json:"reason" binding:"required"`
+}
+
+func (r *warehouseProductRoutes) adjustWarehouseProductQuantity(ctx *gin.Context) {
+	productID, err := uuid.Parse(ctx.Param("product_id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - warehouseProductRoutes - adjustWarehouseProductQuantity")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	warehouseID, err := uuid.Parse(ctx.Param("warehouse_id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - warehouseProductRoutes - adjustWarehouseProductQuantity")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	var req adjustWarehouseProductQuantityRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - warehouseProductRoutes - adjustWarehouseProductQuantity")
+		ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+		return
+	}
+
+	userID, exist := ctx.Get(UserIDKey)
+	if !exist {
+		r.l.Error("not exist", "http - v1 - warehouseProductRoutes - adjustWarehouseProductQuantity")
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError("user id not exist"))
+		return
+	}
+
+	stockAdjustment := adjustWarehouseProductQuantityRequestToStockAdjustmentEntity(req, productID, warehouseID, userID.(uuid.UUID))
+	err = r.uc.AdjustWarehouseProductQuantity(context.Background(), &stockAdjustment)
+	if err != nil {
+		r.l.Error(err, "http - v1 - warehouseProductRoutes - adjustWarehouseProductQuantity")
+		if errors.Is(err, entity.ErrInvalidAdjustment) {
+			ctx.JSON(http.StatusBadRequest, newBadRequestError(err.Error()))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, newInternalServerError(err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, newCreateSuccess(stockAdjustment))
+}
+
 type getNearestWarehouseZipCodeAndProductIDRequest struct {
 	ZipCode   string    `json:"zip_code"`
 	ProductID uuid.UUID `json:"product_id"`
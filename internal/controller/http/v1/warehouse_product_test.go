@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -29,8 +30,8 @@ func (m *mockWarehouseProductUsecase) UpdateWarehouseProduct(ctx context.Context
 	return args.Error(0)
 }
 
-func (m *mockWarehouseProductUsecase) UpdateWarehouseProductQuantity(ctx context.Context, warehouseProduct *entity.WarehouseProduct) error {
-	args := m.Called(ctx, warehouseProduct)
+func (m *mockWarehouseProductUsecase) AdjustWarehouseProductQuantity(ctx context.Context, stockAdjustment *entity.StockAdjustment) error {
+	args := m.Called(ctx, stockAdjustment)
 	return args.Error(0)
 }
 
@@ -198,3 +199,173 @@ func TestGetWarehouseProductByProductIDAndWarehouseID(t *testing.T) {
 		})
 	}
 }
+
+func TestAdjustWarehouseProductQuantity(t *testing.T) {
+	// t.Parallell()
+	productID := uuid.New()
+	warehouseID := uuid.New()
+	userID := uuid.New()
+
+	tests := []struct {
+		name         string
+		productID    string
+		inputJSON    string
+		withUserID   bool
+		expectedCode int
+		setupMock    func(*mockWarehouseProductUsecase, *MockLogger)
+	}{
+		{
+			name:         "success delta",
+			productID:    productID.String(),
+			inputJSON:    `{"delta": -2, "reason": "damaged in storage"}`,
+			withUserID:   true,
+			expectedCode: http.StatusCreated,
+			setupMock: func(m *mockWarehouseProductUsecase, l *MockLogger) {
+				m.On("AdjustWarehouseProductQuantity",
+					mock.Anything,
+					mock.MatchedBy(func(sa *entity.StockAdjustment) bool {
+						return sa.ProductID == productID &&
+							sa.WarehouseID == warehouseID &&
+							sa.UserID == userID &&
+							sa.Delta == -2 &&
+							sa.CountedQuantity == nil &&
+							sa.Reason == "damaged in storage"
+					}),
+				).Return(nil)
+			},
+		},
+		{
+			name:         "success counted quantity",
+			productID:    productID.String(),
+			inputJSON:    `{"counted_quantity": 7, "reason": "shelf count"}`,
+			withUserID:   true,
+			expectedCode: http.StatusCreated,
+			setupMock: func(m *mockWarehouseProductUsecase, l *MockLogger) {
+				m.On("AdjustWarehouseProductQuantity",
+					mock.Anything,
+					mock.MatchedBy(func(sa *entity.StockAdjustment) bool {
+						return sa.CountedQuantity != nil && *sa.CountedQuantity == 7
+					}),
+				).Return(nil)
+			},
+		},
+		{
+			name:         "invalid product id",
+			productID:    "invalid-uuid",
+			inputJSON:    `{"delta": 1, "reason": "found"}`,
+			withUserID:   true,
+			expectedCode: http.StatusBadRequest,
+			setupMock: func(m *mockWarehouseProductUsecase, l *MockLogger) {
+				l.On("Error",
+					mock.Anything,
+					mock.Anything,
+				).Return()
+			},
+		},
+		{
+			name:         "missing reason",
+			productID:    productID.String(),
+			inputJSON:    `{"delta": 1}`,
+			withUserID:   true,
+			expectedCode: http.StatusBadRequest,
+			setupMock: func(m *mockWarehouseProductUsecase, l *MockLogger) {
+				l.On("Error",
+					mock.Anything,
+					mock.Anything,
+				).Return()
+			},
+		},
+		{
+			name:         "user id not exist",
+			productID:    productID.String(),
+			inputJSON:    `{"delta": 1, "reason": "found"}`,
+			withUserID:   false,
+			expectedCode: http.StatusInternalServerError,
+			setupMock: func(m *mockWarehouseProductUsecase, l *MockLogger) {
+				l.On("Error",
+					mock.Anything,
+					mock.Anything,
+				).Return()
+			},
+		},
+		{
+			name:         "invalid adjustment",
+			productID:    productID.String(),
+			inputJSON:    `{"delta": -20, "reason": "lost"}`,
+			withUserID:   true,
+			expectedCode: http.StatusBadRequest,
+			setupMock: func(m *mockWarehouseProductUsecase, l *MockLogger) {
+				m.On("AdjustWarehouseProductQuantity",
+					mock.Anything,
+					mock.AnythingOfType("*entity.StockAdjustment"),
+				).Return(fmt.Errorf("failed to adjust product quantity: %w", entity.ErrInvalidAdjustment))
+
+				l.On("Error",
+					mock.Anything,
+					mock.Anything,
+				).Return()
+			},
+		},
+		{
+			name:         "internal error",
+			productID:    productID.String(),
+			inputJSON:    `{"delta": 1, "reason": "found"}`,
+			withUserID:   true,
+			expectedCode: http.StatusInternalServerError,
+			setupMock: func(m *mockWarehouseProductUsecase, l *MockLogger) {
+				m.On("AdjustWarehouseProductQuantity",
+					mock.Anything,
+					mock.AnythingOfType("*entity.StockAdjustment"),
+				).Return(fmt.Errorf("database error"))
+
+				l.On("Error",
+					mock.Anything,
+					mock.Anything,
+				).Return()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+
+			mockUC := new(mockWarehouseProductUsecase)
+			mockLogger := NewMockLogger(t)
+
+			tt.setupMock(mockUC, mockLogger)
+
+			router := gin.New()
+			handler := router.Group("/api/v1")
+			newWarehouseProductRoutes(
+				handler,
+				mockUC,
+				mockLogger,
+				func(c *gin.Context) {
+					if tt.withUserID {
+						c.Set(UserIDKey, userID)
+					}
+					c.Next()
+				},
+			)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				fmt.Sprintf("/api/v1/warehouse-products/product/%s/warehouse/%s/adjustments",
+					tt.productID,
+					warehouseID,
+				),
+				bytes.NewBufferString(tt.inputJSON),
+			)
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUC.AssertExpectations(t)
+			mockLogger.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	kafkaConSrv "github.com/idoyudha/eshop-warehouse/pkg/kafka"
+)
+
+// handle the message until it succeeds or the attempts run out, then publish it to the dead letter topic.
+// A message that can not be decoded fails the same way every time, so it is not retried.
+// When ctx is done the message is neither retried nor dead lettered, the error of ctx is returned
+func (r *kafkaConsumerRoutes) handleWithRetry(ctx context.Context, msg *kafka.Message, handle messageHandler) error {
+	var err error
+	attempts := 0
+	for {
+		attempts++
+		err = handle(ctx, msg)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if isMalformedMessageError(err) || attempts >= r.cfg.MaxAttempts {
+			break
+		}
+
+		backoff := r.cfg.RetryBackoff * time.Duration(1<<(attempts-1))
+		r.l.Info("http - v1 - kafkaConsumerRoutes - handleWithRetry - attempt %d on topic %s failed, retrying in %v",
+			attempts, *msg.TopicPartition.Topic, backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+
+	return r.publishDeadLetter(msg, err, attempts)
+}
+
+func (r *kafkaConsumerRoutes) publishDeadLetter(msg *kafka.Message, handleErr error, attempts int) error {
+	topic := *msg.TopicPartition.Topic
+	deadLetter := kafkaConSrv.DeadLetter{
+		Topic:     topic,
+		Partition: msg.TopicPartition.Partition,
+		Offset:    int64(msg.TopicPartition.Offset),
+		Key:       string(msg.Key),
+		Payload:   string(msg.Value),
+		Error:     handleErr.Error(),
+		Attempts:  attempts,
+		FailedAt:  time.Now(),
+	}
+
+	err := r.producer.ProduceSync(kafkaConSrv.DeadLetterTopic(topic), msg.Key, deadLetter)
+	if err != nil {
+		return fmt.Errorf("failed to publish dead letter of %s offset %d: %w", topic, deadLetter.Offset, err)
+	}
+
+	r.l.Info("http - v1 - kafkaConsumerRoutes - publishDeadLetter - message of %s offset %d dead lettered after %d attempts: %s",
+		topic, deadLetter.Offset, attempts, deadLetter.Error)
+	return nil
+}
+
+func isMalformedMessageError(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var unmarshalTypeErr *json.UnmarshalTypeError
+	return errors.As(err, &syntaxErr) || errors.As(err, &unmarshalTypeErr)
+}
@@ -0,0 +1,122 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	kafkaConSrv "github.com/idoyudha/eshop-warehouse/pkg/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleWithRetry(t *testing.T) {
+	errDatabase := errors.New("database error")
+
+	tests := []struct {
+		name string
+		// result of each attempt, the last one is repeated
+		results        []error
+		cancelAt       int // ctx is cancelled during this attempt, 0 never
+		produceErr     error
+		wantAttempts   int
+		wantDeadLetter bool
+		wantErr        error
+	}{
+		{
+			name:         "handled at first attempt",
+			results:      []error{nil},
+			wantAttempts: 1,
+		},
+		{
+			name:         "handled after retry",
+			results:      []error{errDatabase, nil},
+			wantAttempts: 2,
+		},
+		{
+			name:           "attempts run out",
+			results:        []error{errDatabase},
+			wantAttempts:   3,
+			wantDeadLetter: true,
+		},
+		{
+			name:           "malformed message is not retried",
+			results:        []error{json.Unmarshal([]byte("{"), &struct{}{})},
+			wantAttempts:   1,
+			wantDeadLetter: true,
+		},
+		{
+			name:           "dead letter not published",
+			results:        []error{errDatabase},
+			produceErr:     errors.New("broker unavailable"),
+			wantAttempts:   3,
+			wantDeadLetter: true,
+			wantErr:        errors.New("broker unavailable"),
+		},
+		{
+			name:         "ctx done is neither retried nor dead lettered",
+			results:      []error{errDatabase},
+			cancelAt:     2,
+			wantAttempts: 2,
+			wantErr:      context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			producer := new(mockKafkaProducer)
+			var deadLetter kafkaConSrv.DeadLetter
+			if tt.wantDeadLetter {
+				producer.On("ProduceSync", kafkaConSrv.DeadLetterTopic(testTopic), []byte("product-1"), mock.Anything).
+					Run(func(args mock.Arguments) {
+						deadLetter = args.Get(2).(kafkaConSrv.DeadLetter)
+					}).
+					Return(tt.produceErr).Once()
+			}
+			routes := newPoolTestRoutes(t, producer)
+			routes.cfg.MaxAttempts = 3
+
+			attempts := 0
+			handle := func(context.Context, *kafka.Message) error {
+				attempts++
+				if attempts == tt.cancelAt {
+					cancel()
+				}
+				return tt.results[min(attempts, len(tt.results))-1]
+			}
+			msg := newPoolTestMessage(7, "product-1", `{"id": 1}`)
+
+			err := routes.handleWithRetry(ctx, msg, handle)
+
+			switch {
+			case tt.wantErr == nil:
+				assert.NoError(t, err)
+			case errors.Is(tt.wantErr, context.Canceled):
+				assert.ErrorIs(t, err, context.Canceled)
+			default:
+				assert.ErrorContains(t, err, tt.wantErr.Error())
+			}
+			assert.Equal(t, tt.wantAttempts, attempts)
+			producer.AssertExpectations(t)
+			if !tt.wantDeadLetter {
+				producer.AssertNotCalled(t, "ProduceSync", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NotZero(t, deadLetter.FailedAt)
+			assert.Equal(t, testTopic, deadLetter.Topic)
+			assert.Equal(t, int32(0), deadLetter.Partition)
+			assert.Equal(t, int64(7), deadLetter.Offset)
+			assert.Equal(t, "product-1", deadLetter.Key)
+			assert.Equal(t, `{"id": 1}`, deadLetter.Payload)
+			assert.Equal(t, tt.results[len(tt.results)-1].Error(), deadLetter.Error)
+			assert.Equal(t, tt.wantAttempts, deadLetter.Attempts)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	kafkaConSrv "github.com/idoyudha/eshop-warehouse/pkg/kafka"
+)
+
+type kafkaOrderCreatedMessage struct {
+	OrderID uuid.UUID                      `json:"order_id"`
+	UserID  uuid.UUID                      `json:"user_id"`
+	ZipCode string                         `json:"zip_code"`
+	Items   []kafkaOrderCreatedItemMessage `json:"items"`
+}
+
+type kafkaOrderCreatedItemMessage struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Quantity  int64     `json:"quantity"`
+}
+
+type kafkaOrderCancelledMessage struct {
+	OrderID uuid.UUID `json:"order_id"`
+	Reason  string    `json:"reason"`
+}
+
+// reply of the order saga, failed reply tells the order service to cancel or retry the order
+type kafkaOrderStockReplyMessage struct {
+	OrderID        uuid.UUID               `json:"order_id"`
+	Event          string                  `json:"event"` // topic of the order event being replied
+	Success        bool                    `json:"success"`
+	Reason         string                  `json:"reason,omitempty"`
+	StockMovements []*entity.StockMovement `json:"stock_movements,omitempty"`
+}
+
+// move the ordered items out of the nearest warehouses. The order id is the idempotency key,
+// so a redelivered event replies with the movements created by the first delivery.
+// An order cancelled before it is handled is replied as failed
+func (r *kafkaConsumerRoutes) handleOrderCreated(ctx context.Context, msg *kafka.Message) error {
+	var message kafkaOrderCreatedMessage
+	if err := json.Unmarshal(msg.Value, &message); err != nil {
+		r.l.Error(err, "http - v1 - kafkaConsumerRoutes - handleOrderCreated")
+		return err
+	}
+
+	reply := kafkaOrderStockReplyMessage{
+		OrderID: message.OrderID,
+		Event:   kafkaConSrv.OrderCreatedTopic,
+	}
+	if reason := message.invalidReason(); reason != "" {
+		reply.Reason = reason
+		return r.replyOrderStock(reply)
+	}
+
+	createdAt := time.Now()
+	stockMovementsReq := make([]*entity.StockMovement, 0, len(message.Items))
+	for _, item := range message.Items {
+		stockMovementsReq = append(stockMovementsReq, &entity.StockMovement{
+			ProductID:      item.ProductID,
+			Quantity:       item.Quantity,
+			ToUserID:       message.UserID,
+			ReferenceID:    message.OrderID.String(),
+			IdempotencyKey: kafkaConSrv.OrderCreatedTopic + ":" + message.OrderID.String(),
+			CreatedAt:      createdAt,
+		})
+	}
+
+	stockMovements, _, err := r.uct.MoveOut(ctx, stockMovementsReq, message.ZipCode, "")
+	if err != nil {
+		r.l.Error(err, "http - v1 - kafkaConsumerRoutes - handleOrderCreated")
+		if !isOrderRejectedError(err) {
+			return err
+		}
+		reply.Reason = err.Error()
+		return r.replyOrderStock(reply)
+	}
+
+	reply.Success = true
+	reply.StockMovements = stockMovements
+	return r.replyOrderStock(reply)
+}
+
+// an order the stock can never be moved out for is replied as failed instead of retried
+func (m kafkaOrderCreatedMessage) invalidReason() string {
+	if m.OrderID == uuid.Nil {
+		return "order id is required"
+	}
+	if len(m.Items) == 0 {
+		return "order has no items"
+	}
+	for _, item := range m.Items {
+		if item.ProductID == uuid.Nil {
+			return "product id is required"
+		}
+		if item.Quantity <= 0 {
+			return fmt.Sprintf("quantity of product %s must be positive", item.ProductID)
+		}
+	}
+	return ""
+}
+
+// the order fails the same way on every attempt, so the saga is replied instead of retrying the message
+func isOrderRejectedError(err error) bool {
+	var insufficientStockErr *entity.InsufficientStockError
+	return errors.As(err, &insufficientStockErr) ||
+		errors.Is(err, entity.ErrInvalidAllocation) ||
+		errors.Is(err, entity.ErrIdempotencyKeyReused) ||
+		errors.Is(err, entity.ErrReferenceCancelled)
+}
+
+// put the stock moved out for the order back to the warehouses it left from,
+// a redelivered event finds nothing left to reverse and replies without movements
+func (r *kafkaConsumerRoutes) handleOrderCancelled(ctx context.Context, msg *kafka.Message) error {
+	var message kafkaOrderCancelledMessage
+	if err := json.Unmarshal(msg.Value, &message); err != nil {
+		r.l.Error(err, "http - v1 - kafkaConsumerRoutes - handleOrderCancelled")
+		return err
+	}
+
+	reply := kafkaOrderStockReplyMessage{
+		OrderID: message.OrderID,
+		Event:   kafkaConSrv.OrderCancelledTopic,
+	}
+	if message.OrderID == uuid.Nil {
+		reply.Reason = "order id is required"
+		return r.replyOrderStock(reply)
+	}
+
+	reversals, err := r.uct.CompensateMoveOut(ctx, message.OrderID.String(), message.Reason)
+	if err != nil {
+		r.l.Error(err, "http - v1 - kafkaConsumerRoutes - handleOrderCancelled")
+		if !errors.Is(err, entity.ErrReversalNotAllowed) {
+			return err
+		}
+		reply.Reason = err.Error()
+		return r.replyOrderStock(reply)
+	}
+
+	reply.Success = true
+	reply.StockMovements = reversals
+	return r.replyOrderStock(reply)
+}
+
+func (r *kafkaConsumerRoutes) replyOrderStock(reply kafkaOrderStockReplyMessage) error {
+	err := r.producer.ProduceSync(kafkaConSrv.OrderStockReplyTopic, []byte(reply.OrderID.String()), reply)
+	if err != nil {
+		return fmt.Errorf("failed to reply order %s: %w", reply.OrderID, err)
+	}
+
+	r.l.Info("Order %s replied, success: %t", reply.OrderID, reply.Success)
+	return nil
+}
@@ -0,0 +1,348 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	kafkaConSrv "github.com/idoyudha/eshop-warehouse/pkg/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockTransactionProductUsecase struct {
+	mock.Mock
+}
+
+func (m *mockTransactionProductUsecase) MoveIn(ctx context.Context, stockMovement *entity.StockMovement) error {
+	args := m.Called(ctx, stockMovement)
+	return args.Error(0)
+}
+
+func (m *mockTransactionProductUsecase) MoveOut(ctx context.Context, stockMovements []*entity.StockMovement, zipCode string, strategy string) ([]*entity.StockMovement, *entity.AllocationPlan, error) {
+	args := m.Called(ctx, stockMovements, zipCode, strategy)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]*entity.StockMovement), args.Get(1).(*entity.AllocationPlan), args.Error(2)
+}
+
+func (m *mockTransactionProductUsecase) PreviewMoveOut(ctx context.Context, stockMovements []*entity.StockMovement, zipCode string, strategy string) (*entity.AllocationPlan, error) {
+	args := m.Called(ctx, stockMovements, zipCode, strategy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.AllocationPlan), args.Error(1)
+}
+
+func (m *mockTransactionProductUsecase) MoveReturn(ctx context.Context, stockMovement *entity.StockMovement) error {
+	args := m.Called(ctx, stockMovement)
+	return args.Error(0)
+}
+
+func (m *mockTransactionProductUsecase) CommitReservation(ctx context.Context, reservationID uuid.UUID) ([]*entity.StockMovement, error) {
+	args := m.Called(ctx, reservationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.StockMovement), args.Error(1)
+}
+
+func (m *mockTransactionProductUsecase) CompensateMoveOut(ctx context.Context, referenceID string, reason string) ([]*entity.StockMovement, error) {
+	args := m.Called(ctx, referenceID, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.StockMovement), args.Error(1)
+}
+
+var _ usecase.TransactionProduct = (*mockTransactionProductUsecase)(nil)
+
+type mockKafkaProducer struct {
+	mock.Mock
+}
+
+func (m *mockKafkaProducer) ProduceSync(topic string, key []byte, message interface{}) error {
+	args := m.Called(topic, key, message)
+	return args.Error(0)
+}
+
+var _ usecase.KafkaProducer = (*mockKafkaProducer)(nil)
+
+func newOrderTestRoutes(t *testing.T, uct *mockTransactionProductUsecase, producer *mockKafkaProducer) *kafkaConsumerRoutes {
+	l := NewMockLogger(t)
+	l.On("Info", mock.Anything, mock.Anything).Maybe().Return()
+	l.On("Error", mock.Anything, mock.Anything).Maybe().Return()
+
+	return &kafkaConsumerRoutes{
+		uct:      uct,
+		producer: producer,
+		l:        l,
+	}
+}
+
+func newOrderTestMessage(t *testing.T, topic string, value interface{}) *kafka.Message {
+	payload, err := json.Marshal(value)
+	assert.NoError(t, err)
+	return &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic},
+		Value:          payload,
+	}
+}
+
+// expects one reply of the order and returns it to be checked
+func expectOrderReply(producer *mockKafkaProducer, orderID uuid.UUID) *kafkaOrderStockReplyMessage {
+	var reply kafkaOrderStockReplyMessage
+	producer.On("ProduceSync", kafkaConSrv.OrderStockReplyTopic, []byte(orderID.String()), mock.Anything).
+		Run(func(args mock.Arguments) {
+			reply = args.Get(2).(kafkaOrderStockReplyMessage)
+		}).
+		Return(nil).Once()
+	return &reply
+}
+
+func TestHandleOrderCreated(t *testing.T) {
+	orderID := uuid.New()
+	userID := uuid.New()
+	productID := uuid.New()
+
+	tests := []struct {
+		name         string
+		message      kafkaOrderCreatedMessage
+		mockBehavior func(*mockTransactionProductUsecase)
+		wantReplied  bool
+		wantSuccess  bool
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			message: kafkaOrderCreatedMessage{
+				OrderID: orderID,
+				UserID:  userID,
+				ZipCode: "12345",
+				Items:   []kafkaOrderCreatedItemMessage{{ProductID: productID, Quantity: 2}},
+			},
+			mockBehavior: func(m *mockTransactionProductUsecase) {
+				m.On("MoveOut",
+					mock.Anything,
+					mock.MatchedBy(func(sms []*entity.StockMovement) bool {
+						return len(sms) == 1 &&
+							sms[0].ProductID == productID &&
+							sms[0].Quantity == 2 &&
+							sms[0].ToUserID == userID &&
+							sms[0].IdempotencyKey == kafkaConSrv.OrderCreatedTopic+":"+orderID.String()
+					}),
+					"12345",
+					"",
+				).Return([]*entity.StockMovement{{ProductID: productID, Quantity: 2}}, &entity.AllocationPlan{}, nil)
+			},
+			wantReplied: true,
+			wantSuccess: true,
+		},
+		{
+			name:        "No Items",
+			message:     kafkaOrderCreatedMessage{OrderID: orderID, ZipCode: "12345"},
+			wantReplied: true,
+		},
+		{
+			name: "Negative Quantity",
+			message: kafkaOrderCreatedMessage{
+				OrderID: orderID,
+				ZipCode: "12345",
+				Items:   []kafkaOrderCreatedItemMessage{{ProductID: productID, Quantity: -2}},
+			},
+			wantReplied: true,
+		},
+		{
+			name: "Missing Product ID",
+			message: kafkaOrderCreatedMessage{
+				OrderID: orderID,
+				ZipCode: "12345",
+				Items:   []kafkaOrderCreatedItemMessage{{Quantity: 2}},
+			},
+			wantReplied: true,
+		},
+		{
+			name: "Missing Order ID",
+			message: kafkaOrderCreatedMessage{
+				ZipCode: "12345",
+				Items:   []kafkaOrderCreatedItemMessage{{ProductID: productID, Quantity: 2}},
+			},
+			wantReplied: true,
+		},
+		{
+			name: "Insufficient Stock",
+			message: kafkaOrderCreatedMessage{
+				OrderID: orderID,
+				ZipCode: "12345",
+				Items:   []kafkaOrderCreatedItemMessage{{ProductID: productID, Quantity: 2}},
+			},
+			mockBehavior: func(m *mockTransactionProductUsecase) {
+				m.On("MoveOut", mock.Anything, mock.Anything, "12345", "").
+					Return(nil, nil, &entity.InsufficientStockError{Items: []entity.InsufficientStockItem{{ProductID: productID, Requested: 2}}})
+			},
+			wantReplied: true,
+		},
+		{
+			name: "Unknown Zip Code",
+			message: kafkaOrderCreatedMessage{
+				OrderID: orderID,
+				ZipCode: "00000",
+				Items:   []kafkaOrderCreatedItemMessage{{ProductID: productID, Quantity: 2}},
+			},
+			mockBehavior: func(m *mockTransactionProductUsecase) {
+				m.On("MoveOut", mock.Anything, mock.Anything, "00000", "").
+					Return(nil, nil, fmt.Errorf("failed to allocate warehouses: %w", entity.ErrInvalidAllocation))
+			},
+			wantReplied: true,
+		},
+		{
+			name: "Cancelled Before Created",
+			message: kafkaOrderCreatedMessage{
+				OrderID: orderID,
+				ZipCode: "12345",
+				Items:   []kafkaOrderCreatedItemMessage{{ProductID: productID, Quantity: 2}},
+			},
+			mockBehavior: func(m *mockTransactionProductUsecase) {
+				m.On("MoveOut",
+					mock.Anything,
+					mock.MatchedBy(func(sms []*entity.StockMovement) bool {
+						return len(sms) == 1 && sms[0].ReferenceID == orderID.String()
+					}),
+					"12345",
+					"",
+				).Return(nil, nil, fmt.Errorf("reference %s: %w", orderID, entity.ErrReferenceCancelled))
+			},
+			wantReplied: true,
+		},
+		{
+			name: "Database Error",
+			message: kafkaOrderCreatedMessage{
+				OrderID: orderID,
+				ZipCode: "12345",
+				Items:   []kafkaOrderCreatedItemMessage{{ProductID: productID, Quantity: 2}},
+			},
+			mockBehavior: func(m *mockTransactionProductUsecase) {
+				m.On("MoveOut", mock.Anything, mock.Anything, "12345", "").
+					Return(nil, nil, fmt.Errorf("database error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(mockTransactionProductUsecase)
+			mockProducer := new(mockKafkaProducer)
+			if tt.mockBehavior != nil {
+				tt.mockBehavior(mockUC)
+			}
+			var reply *kafkaOrderStockReplyMessage
+			if tt.wantReplied {
+				reply = expectOrderReply(mockProducer, tt.message.OrderID)
+			}
+
+			routes := newOrderTestRoutes(t, mockUC, mockProducer)
+			err := routes.handleOrderCreated(context.Background(), newOrderTestMessage(t, kafkaConSrv.OrderCreatedTopic, tt.message))
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.wantReplied {
+				assert.Equal(t, tt.wantSuccess, reply.Success)
+				assert.Equal(t, kafkaConSrv.OrderCreatedTopic, reply.Event)
+				if !tt.wantSuccess {
+					assert.NotEmpty(t, reply.Reason)
+				}
+			}
+			mockUC.AssertExpectations(t)
+			mockProducer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleOrderCancelled(t *testing.T) {
+	orderID := uuid.New()
+
+	tests := []struct {
+		name         string
+		message      kafkaOrderCancelledMessage
+		mockBehavior func(*mockTransactionProductUsecase)
+		wantReplied  bool
+		wantSuccess  bool
+		wantErr      bool
+	}{
+		{
+			name:    "Success",
+			message: kafkaOrderCancelledMessage{OrderID: orderID, Reason: "payment failed"},
+			mockBehavior: func(m *mockTransactionProductUsecase) {
+				m.On("CompensateMoveOut", mock.Anything, orderID.String(), "payment failed").
+					Return([]*entity.StockMovement{{MovementType: entity.MovementTypeReversal}}, nil)
+			},
+			wantReplied: true,
+			wantSuccess: true,
+		},
+		{
+			name:        "Missing Order ID",
+			message:     kafkaOrderCancelledMessage{Reason: "payment failed"},
+			wantReplied: true,
+		},
+		{
+			name:    "Reversal Not Allowed",
+			message: kafkaOrderCancelledMessage{OrderID: orderID, Reason: "payment failed"},
+			mockBehavior: func(m *mockTransactionProductUsecase) {
+				m.On("CompensateMoveOut", mock.Anything, orderID.String(), "payment failed").
+					Return(nil, fmt.Errorf("order already returned: %w", entity.ErrReversalNotAllowed))
+			},
+			wantReplied: true,
+		},
+		{
+			name:    "Database Error",
+			message: kafkaOrderCancelledMessage{OrderID: orderID, Reason: "payment failed"},
+			mockBehavior: func(m *mockTransactionProductUsecase) {
+				m.On("CompensateMoveOut", mock.Anything, orderID.String(), "payment failed").
+					Return(nil, fmt.Errorf("database error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(mockTransactionProductUsecase)
+			mockProducer := new(mockKafkaProducer)
+			if tt.mockBehavior != nil {
+				tt.mockBehavior(mockUC)
+			}
+			var reply *kafkaOrderStockReplyMessage
+			if tt.wantReplied {
+				reply = expectOrderReply(mockProducer, tt.message.OrderID)
+			}
+
+			routes := newOrderTestRoutes(t, mockUC, mockProducer)
+			err := routes.handleOrderCancelled(context.Background(), newOrderTestMessage(t, kafkaConSrv.OrderCancelledTopic, tt.message))
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.wantReplied {
+				assert.Equal(t, tt.wantSuccess, reply.Success)
+				assert.Equal(t, kafkaConSrv.OrderCancelledTopic, reply.Event)
+				if !tt.wantSuccess {
+					assert.NotEmpty(t, reply.Reason)
+				}
+			}
+			mockUC.AssertExpectations(t)
+			mockProducer.AssertExpectations(t)
+		})
+	}
+}
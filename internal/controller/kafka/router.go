@@ -3,14 +3,12 @@ package v1
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
-	"os"
-	"os/signal"
-	"syscall"
-	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/config"
 	"github.com/idoyudha/eshop-warehouse/internal/entity"
 	"github.com/idoyudha/eshop-warehouse/internal/usecase"
 	kafkaConSrv "github.com/idoyudha/eshop-warehouse/pkg/kafka"
@@ -18,68 +16,106 @@ import (
 )
 
 type kafkaConsumerRoutes struct {
-	ucw usecase.Warehouse
-	ucp usecase.WarehouseProduct
-	l   logger.Interface
+	ucw      usecase.Warehouse
+	ucp      usecase.WarehouseProduct
+	uct      usecase.TransactionProduct
+	producer usecase.KafkaProducer
+	cfg      config.KafkaConsumer
+	l        logger.Interface
 }
 
+// a revoke waits for the dispatched messages at most this long, so the consumer is polled again
+// before it is removed from the group. Messages still handled after it are read again by the next owner
+const revokeDrainTimeout = kafkaConSrv.MaxPollInterval / 2
+
+// KafkaNewRouter consumes the messages until ctx is done, the dispatched messages are finished
+// or given up uncommitted before it returns. A partition with a message that can not be handled
+// nor dead lettered is paused, it is read again from that message once it is assigned again
 func KafkaNewRouter(
+	ctx context.Context,
 	ucw usecase.Warehouse,
 	ucp usecase.WarehouseProduct,
+	uct usecase.TransactionProduct,
+	producer usecase.KafkaProducer,
+	cfg config.KafkaConsumer,
 	l logger.Interface,
 	c *kafkaConSrv.ConsumerServer,
-) error {
+) {
 	routes := &kafkaConsumerRoutes{
-		ucw: ucw,
-		ucp: ucp,
-		l:   l,
+		ucw:      ucw,
+		ucp:      ucp,
+		uct:      uct,
+		producer: producer,
+		cfg:      cfg,
+		l:        l,
+	}
+
+	handlers := map[string]messageHandler{
+		kafkaConSrv.ProductCreatedTopic: routes.handleProductCreated,
+		kafkaConSrv.ProductUpdatedTopic: routes.handleProductUpdated,
+		kafkaConSrv.OrderCreatedTopic:   routes.handleOrderCreated,
+		kafkaConSrv.OrderCancelledTopic: routes.handleOrderCancelled,
 	}
 
-	// Set up a channel for handling Ctrl-C, etc
-	sigchan := make(chan os.Signal, 1)
-	signal.Notify(sigchan, syscall.SIGINT, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	offsets := newOffsetTracker(c.Consumer)
+	pool := newWorkerPool(ctx, routes, offsets, cfg.Workers, cfg.MaxInFlight)
+	paused := make(map[partitionKey]bool)
 
 	// Process messages
 	run := true
 	for run {
 		select {
-		case sig := <-sigchan:
-			log.Printf("Caught signal %v: terminating\n", sig)
+		case <-ctx.Done():
+			log.Printf("Kafka consumer stopped: terminating\n")
 			run = false
-			return nil
+		case failure := <-pool.failed():
+			l.Error(failure.err, "http - v1 - kafkaConsumerRoutes - KafkaNewRouter")
+			// the other partitions go on, this one is not committed past the message anyway
+			paused[partitionKey{topic: *failure.tp.Topic, partition: failure.tp.Partition}] = true
+			if err := c.Consumer.Pause([]kafka.TopicPartition{failure.tp}); err != nil {
+				l.Error(err, "http - v1 - kafkaConsumerRoutes - Pause")
+			}
 		default:
-			// l.Debug("Attempting to read message...")
-			ev, err := c.Consumer.ReadMessage(3 * time.Second)
-			if err != nil {
-				// log.Println("CONSUME CART SERVICE!!")
-				// Errors are informational and automatically handled by the consumer
-				if kerr, ok := err.(kafka.Error); ok && kerr.Code() == kafka.ErrTimedOut {
-					// l.Debug("Timeout waiting for message, continuing...")
+			switch ev := c.Consumer.Poll(100).(type) {
+			case *kafka.Message:
+				// fetched before the partition was paused, it is read again with the failed message
+				if paused[partitionKey{topic: *ev.TopicPartition.Topic, partition: ev.TopicPartition.Partition}] {
 					continue
 				}
-				l.Error("Error reading message: ", err)
-				continue
-			}
-
-			switch *ev.TopicPartition.Topic {
-			case kafkaConSrv.ProductCreatedTopic:
-				if err := routes.handleProductCreated(ev); err != nil {
-					l.Error("Failed to handle product creation: %w", err)
+				handle, ok := handlers[*ev.TopicPartition.Topic]
+				if !ok {
+					l.Info("Unknown topic: %s", *ev.TopicPartition.Topic)
+					handle = func(context.Context, *kafka.Message) error { return nil }
+				}
+				// not dispatched only when ctx is done, the loop ends on the next iteration
+				pool.dispatch(ctx, messageJob{msg: ev, handle: handle})
+			case kafka.AssignedPartitions:
+				if err := c.Consumer.Assign(ev.Partitions); err != nil {
+					l.Error(err, "http - v1 - kafkaConsumerRoutes - Assign")
 				}
-			case kafkaConSrv.ProductUpdatedTopic:
-				if err := routes.handleProductUpdated(ev); err != nil {
-					l.Error("Failed to handle product update: %w", err)
+			case kafka.RevokedPartitions:
+				// the handled offsets are committed before the partitions move to another consumer
+				drainCtx, drainCancel := context.WithTimeout(ctx, revokeDrainTimeout)
+				pool.drain(drainCtx)
+				drainCancel()
+				offsets.reset()
+				clear(paused)
+				if err := c.Consumer.Unassign(); err != nil {
+					l.Error(err, "http - v1 - kafkaConsumerRoutes - Unassign")
 				}
-			default:
-				l.Info("Unknown topic: %s", *ev.TopicPartition.Topic)
+			case kafka.Error:
+				// Errors are informational and automatically handled by the consumer
+				l.Error("Error reading message: ", ev)
 			}
-
-			log.Printf("Consumed event from topic %s: key = %-10s value = %s\n",
-				*ev.TopicPartition.Topic, string(ev.Key), string(ev.Value))
 		}
 	}
 
-	return nil
+	// messages still failing are given up uncommitted, the others finish and commit their offsets
+	cancel()
+	pool.close()
 }
 
 type kafkaProductCreatedMessage struct {
@@ -93,7 +129,8 @@ type kafkaProductCreatedMessage struct {
 	CategoryID  uuid.UUID `json:"category_id"`
 }
 
-func (r *kafkaConsumerRoutes) handleProductCreated(msg *kafka.Message) error {
+// the product is only saved once per warehouse, a redelivered event finds it already saved
+func (r *kafkaConsumerRoutes) handleProductCreated(ctx context.Context, msg *kafka.Message) error {
 	var message kafkaProductCreatedMessage
 
 	if err := json.Unmarshal(msg.Value, &message); err != nil {
@@ -101,7 +138,7 @@ func (r *kafkaConsumerRoutes) handleProductCreated(msg *kafka.Message) error {
 		return err
 	}
 
-	warehouseMainID, err := r.ucw.GetMainIDWarehouse(context.Background())
+	warehouseMainID, err := r.ucw.GetMainIDWarehouse(ctx)
 	if err != nil {
 		r.l.Error(err, "http - v1 - kafkaConsumerRoutes - handleProductCreated")
 		return err
@@ -119,7 +156,12 @@ func (r *kafkaConsumerRoutes) handleProductCreated(msg *kafka.Message) error {
 		ProductCategoryID:  message.CategoryID,
 	}
 
-	if err := r.ucp.CreateWarehouseProduct(context.Background(), product); err != nil {
+	err = r.ucp.CreateWarehouseProduct(ctx, product)
+	if errors.Is(err, entity.ErrWarehouseProductExists) {
+		r.l.Info("Product already created", "http - v1 - kafkaConsumerRoutes - handleProductCreated")
+		return nil
+	}
+	if err != nil {
 		r.l.Error(err, "http - v1 - kafkaConsumerRoutes - handleProductCreated")
 		return err
 	}
@@ -138,7 +180,8 @@ type kafkaProductUpdatedMessage struct {
 	ProductCategoryID  uuid.UUID `json:"product_category_id"`
 }
 
-func (r *kafkaConsumerRoutes) handleProductUpdated(msg *kafka.Message) error {
+// the update overwrites the product details, so applying a redelivered event again leaves the same row
+func (r *kafkaConsumerRoutes) handleProductUpdated(ctx context.Context, msg *kafka.Message) error {
 	var message kafkaProductUpdatedMessage
 	if err := json.Unmarshal(msg.Value, &message); err != nil {
 		r.l.Error(err, "http - v1 - kafkaConsumerRoutes - handleProductUpdated")
@@ -154,7 +197,7 @@ func (r *kafkaConsumerRoutes) handleProductUpdated(msg *kafka.Message) error {
 		ProductCategoryID:  message.ProductCategoryID,
 	}
 
-	if err := r.ucp.UpdateWarehouseProduct(context.Background(), product); err != nil {
+	if err := r.ucp.UpdateWarehouseProduct(ctx, product); err != nil {
 		r.l.Error(err, "http - v1 - kafkaConsumerRoutes - handleProductUpdated")
 		return err
 	}
@@ -0,0 +1,233 @@
+package v1
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sync"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+type messageHandler func(context.Context, *kafka.Message) error
+
+type messageJob struct {
+	msg    *kafka.Message
+	handle messageHandler
+}
+
+// messageFailure is a message the pool gave up on, its partition is not committed past it
+type messageFailure struct {
+	tp  kafka.TopicPartition
+	err error
+}
+
+// workerPool handles messages in parallel. Messages with the same key always go to the same worker,
+// so the events of one product or order are handled in the order they were read
+type workerPool struct {
+	routes   *kafkaConsumerRoutes
+	offsets  *offsetTracker
+	queues   []chan messageJob
+	inFlight chan struct{} // bounds the messages read but not handled yet
+	pending  sync.WaitGroup
+	workers  sync.WaitGroup
+	failures chan messageFailure
+}
+
+func newWorkerPool(ctx context.Context, routes *kafkaConsumerRoutes, offsets *offsetTracker, workers, maxInFlight int) *workerPool {
+	workers = max(workers, 1)
+	maxInFlight = max(maxInFlight, workers)
+
+	p := &workerPool{
+		routes:   routes,
+		offsets:  offsets,
+		queues:   make([]chan messageJob, workers),
+		inFlight: make(chan struct{}, maxInFlight),
+		failures: make(chan messageFailure, maxInFlight),
+	}
+	for i := range p.queues {
+		p.queues[i] = make(chan messageJob, maxInFlight)
+		p.workers.Add(1)
+		go p.work(ctx, p.queues[i])
+	}
+
+	return p
+}
+
+// dispatch blocks while the pool is full, which stops the consumer from reading further ahead.
+// It returns false when ctx is done before the message is taken, the message is then read again after restart
+func (p *workerPool) dispatch(ctx context.Context, job messageJob) bool {
+	select {
+	case p.inFlight <- struct{}{}:
+	case <-ctx.Done():
+		return false
+	}
+	p.pending.Add(1)
+	p.offsets.track(job.msg.TopicPartition)
+	p.queues[p.queueIndex(job.msg)] <- job
+	return true
+}
+
+// messages without key keep the order of their partition
+func (p *workerPool) queueIndex(msg *kafka.Message) int {
+	h := fnv.New32a()
+	if len(msg.Key) > 0 {
+		h.Write(msg.Key)
+	} else {
+		h.Write(binary.BigEndian.AppendUint32(nil, uint32(msg.TopicPartition.Partition)))
+	}
+	return int(h.Sum32() % uint32(len(p.queues)))
+}
+
+func (p *workerPool) work(ctx context.Context, queue <-chan messageJob) {
+	defer p.workers.Done()
+
+	for job := range queue {
+		err := p.handle(ctx, job)
+		switch {
+		case err == nil:
+			if err := p.offsets.done(job.msg.TopicPartition); err != nil {
+				p.routes.l.Error(err, "http - v1 - kafkaConsumerRoutes - commitOffsets")
+			}
+			log.Printf("Consumed event from topic %s: key = %-10s value = %s\n",
+				*job.msg.TopicPartition.Topic, string(job.msg.Key), string(job.msg.Value))
+		case ctx.Err() == nil:
+			// given up while the consumer is still running
+			p.fail(messageFailure{tp: job.msg.TopicPartition, err: err})
+		}
+		<-p.inFlight
+		p.pending.Done()
+	}
+}
+
+// handleWithRetry retries the message and dead letters it when the attempts run out.
+// A message which can not be dead lettered either, or is stopped by ctx, is left uncommitted and read again later
+func (p *workerPool) handle(ctx context.Context, job messageJob) error {
+	err := p.routes.handleWithRetry(ctx, job.msg, job.handle)
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return fmt.Errorf("failed to handle message of %s offset %d: %w",
+		*job.msg.TopicPartition.Topic, job.msg.TopicPartition.Offset, err)
+}
+
+// every in-flight message fails at most once, so the buffer never drops a failure
+func (p *workerPool) fail(failure messageFailure) {
+	select {
+	case p.failures <- failure:
+	default:
+	}
+}
+
+// failed receives the messages the pool gave up on, the messages after them in the partition are not committed either
+func (p *workerPool) failed() <-chan messageFailure {
+	return p.failures
+}
+
+// drain waits until every dispatched message is handled or ctx is done
+func (p *workerPool) drain(ctx context.Context) {
+	drained := make(chan struct{})
+	go func() {
+		p.pending.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+	}
+}
+
+// close waits for the workers to finish the dispatched messages, ctx of the workers should be done first
+func (p *workerPool) close() {
+	for _, queue := range p.queues {
+		close(queue)
+	}
+	p.workers.Wait()
+}
+
+type partitionKey struct {
+	topic     string
+	partition int32
+}
+
+type partitionOffsets struct {
+	pending []kafka.Offset // in the order the messages were read
+	done    map[kafka.Offset]bool
+}
+
+// offsetCommitter is implemented by *kafka.Consumer
+type offsetCommitter interface {
+	CommitOffsets([]kafka.TopicPartition) ([]kafka.TopicPartition, error)
+}
+
+// offsetTracker commits the offset of a partition only when every message before it is handled,
+// a message handled ahead of a slower one of the same partition waits to be committed
+type offsetTracker struct {
+	mu         sync.Mutex
+	committer  offsetCommitter
+	partitions map[partitionKey]*partitionOffsets
+}
+
+func newOffsetTracker(committer offsetCommitter) *offsetTracker {
+	return &offsetTracker{
+		committer:  committer,
+		partitions: make(map[partitionKey]*partitionOffsets),
+	}
+}
+
+func (t *offsetTracker) track(tp kafka.TopicPartition) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := partitionKey{topic: *tp.Topic, partition: tp.Partition}
+	offsets, ok := t.partitions[key]
+	if !ok {
+		offsets = &partitionOffsets{done: make(map[kafka.Offset]bool)}
+		t.partitions[key] = offsets
+	}
+	offsets.pending = append(offsets.pending, tp.Offset)
+}
+
+// the commit is made while holding the lock, so a lower offset is never committed after a higher one
+func (t *offsetTracker) done(tp kafka.TopicPartition) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	offsets, ok := t.partitions[partitionKey{topic: *tp.Topic, partition: tp.Partition}]
+	if !ok {
+		return nil
+	}
+	offsets.done[tp.Offset] = true
+
+	committed := kafka.OffsetInvalid
+	for len(offsets.pending) > 0 && offsets.done[offsets.pending[0]] {
+		committed = offsets.pending[0]
+		delete(offsets.done, committed)
+		offsets.pending = offsets.pending[1:]
+	}
+	if committed == kafka.OffsetInvalid {
+		return nil
+	}
+
+	_, err := t.committer.CommitOffsets([]kafka.TopicPartition{{
+		Topic:     tp.Topic,
+		Partition: tp.Partition,
+		Offset:    committed + 1,
+	}})
+	return err
+}
+
+// forget the partitions once they are revoked, the next owner continues from the committed offsets
+func (t *offsetTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.partitions = make(map[partitionKey]*partitionOffsets)
+}
@@ -0,0 +1,324 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/idoyudha/eshop-warehouse/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCommitter struct {
+	mu      sync.Mutex
+	commits []kafka.TopicPartition
+}
+
+func (c *fakeCommitter) CommitOffsets(offsets []kafka.TopicPartition) ([]kafka.TopicPartition, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.commits = append(c.commits, offsets...)
+	return offsets, nil
+}
+
+func (c *fakeCommitter) committed() []kafka.TopicPartition {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]kafka.TopicPartition(nil), c.commits...)
+}
+
+const testTopic = "product-created"
+
+func testTopicPartition(partition int32, offset kafka.Offset) kafka.TopicPartition {
+	topic := testTopic
+	return kafka.TopicPartition{Topic: &topic, Partition: partition, Offset: offset}
+}
+
+func TestOffsetTrackerDone(t *testing.T) {
+	tests := []struct {
+		name    string
+		tracked []kafka.TopicPartition
+		done    []kafka.TopicPartition
+		want    []kafka.TopicPartition
+	}{
+		{
+			name:    "in order",
+			tracked: []kafka.TopicPartition{testTopicPartition(0, 0), testTopicPartition(0, 1), testTopicPartition(0, 2)},
+			done:    []kafka.TopicPartition{testTopicPartition(0, 0), testTopicPartition(0, 1), testTopicPartition(0, 2)},
+			want:    []kafka.TopicPartition{testTopicPartition(0, 1), testTopicPartition(0, 2), testTopicPartition(0, 3)},
+		},
+		{
+			name:    "out of order commits once the first is done",
+			tracked: []kafka.TopicPartition{testTopicPartition(0, 0), testTopicPartition(0, 1), testTopicPartition(0, 2)},
+			done:    []kafka.TopicPartition{testTopicPartition(0, 2), testTopicPartition(0, 1), testTopicPartition(0, 0)},
+			want:    []kafka.TopicPartition{testTopicPartition(0, 3)},
+		},
+		{
+			name:    "gap stops at the first not done",
+			tracked: []kafka.TopicPartition{testTopicPartition(0, 0), testTopicPartition(0, 1), testTopicPartition(0, 2)},
+			done:    []kafka.TopicPartition{testTopicPartition(0, 0), testTopicPartition(0, 2)},
+			want:    []kafka.TopicPartition{testTopicPartition(0, 1)},
+		},
+		{
+			name:    "partitions are committed separately",
+			tracked: []kafka.TopicPartition{testTopicPartition(0, 0), testTopicPartition(1, 5)},
+			done:    []kafka.TopicPartition{testTopicPartition(1, 5)},
+			want:    []kafka.TopicPartition{testTopicPartition(1, 6)},
+		},
+		{
+			name: "untracked partition is ignored",
+			done: []kafka.TopicPartition{testTopicPartition(0, 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			committer := &fakeCommitter{}
+			offsets := newOffsetTracker(committer)
+			for _, tp := range tt.tracked {
+				offsets.track(tp)
+			}
+			for _, tp := range tt.done {
+				assert.NoError(t, offsets.done(tp))
+			}
+
+			assert.Equal(t, tt.want, committer.committed())
+		})
+	}
+}
+
+func newPoolTestRoutes(t *testing.T, producer *mockKafkaProducer) *kafkaConsumerRoutes {
+	l := NewMockLogger(t)
+	l.On("Info", mock.Anything, mock.Anything).Maybe().Return()
+	l.On("Error", mock.Anything, mock.Anything).Maybe().Return()
+
+	return &kafkaConsumerRoutes{
+		producer: producer,
+		cfg: config.KafkaConsumer{
+			MaxAttempts:  2,
+			RetryBackoff: time.Millisecond,
+		},
+		l: l,
+	}
+}
+
+func newPoolTestMessage(offset kafka.Offset, key string, value string) *kafka.Message {
+	tp := testTopicPartition(0, offset)
+	return &kafka.Message{TopicPartition: tp, Key: []byte(key), Value: []byte(value)}
+}
+
+// waits for the signal or fails the test after a second
+func waitFor(t *testing.T, done <-chan struct{}, msg string) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal(msg)
+	}
+}
+
+func TestWorkerPoolKeyOrder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	committer := &fakeCommitter{}
+	pool := newWorkerPool(ctx, newPoolTestRoutes(t, nil), newOffsetTracker(committer), 4, 8)
+
+	var mu sync.Mutex
+	handled := make(map[string][]int)
+	handle := func(_ context.Context, msg *kafka.Message) error {
+		seq, err := strconv.Atoi(string(msg.Value))
+		if err != nil {
+			return err
+		}
+		// later messages of other keys may finish first
+		time.Sleep(time.Duration(seq%3) * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		handled[string(msg.Key)] = append(handled[string(msg.Key)], seq)
+		return nil
+	}
+
+	keys := []string{"product-a", "product-b", "product-c"}
+	const perKey = 10
+	offset := kafka.Offset(0)
+	for seq := range perKey {
+		for _, key := range keys {
+			assert.True(t, pool.dispatch(ctx, messageJob{msg: newPoolTestMessage(offset, key, strconv.Itoa(seq)), handle: handle}))
+			offset++
+		}
+	}
+	pool.drain(ctx)
+	cancel()
+	pool.close()
+
+	for _, key := range keys {
+		want := make([]int, perKey)
+		for i := range want {
+			want[i] = i
+		}
+		assert.Equal(t, want, handled[key], key)
+	}
+	commits := committer.committed()
+	require.NotEmpty(t, commits)
+	assert.Equal(t, offset, commits[len(commits)-1].Offset)
+}
+
+func TestWorkerPoolDrain(t *testing.T) {
+	tests := []struct {
+		name       string
+		cancel     bool // ctx is done while the messages are still handled
+		wantOffset kafka.Offset
+	}{
+		{
+			name:       "waits for dispatched messages",
+			wantOffset: 3,
+		},
+		{
+			name:   "stops when ctx is done",
+			cancel: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			committer := &fakeCommitter{}
+			pool := newWorkerPool(ctx, newPoolTestRoutes(t, nil), newOffsetTracker(committer), 2, 4)
+
+			release := make(chan struct{})
+			handle := func(ctx context.Context, _ *kafka.Message) error {
+				select {
+				case <-release:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			for offset := range kafka.Offset(3) {
+				assert.True(t, pool.dispatch(ctx, messageJob{msg: newPoolTestMessage(offset, fmt.Sprint("key-", offset), ""), handle: handle}))
+			}
+
+			drained := make(chan struct{})
+			go func() {
+				pool.drain(ctx)
+				close(drained)
+			}()
+			select {
+			case <-drained:
+				t.Fatal("drained before the messages are handled")
+			case <-time.After(20 * time.Millisecond):
+			}
+
+			if tt.cancel {
+				cancel()
+			} else {
+				close(release)
+			}
+			waitFor(t, drained, "drain did not return")
+			cancel()
+			pool.close()
+
+			commits := committer.committed()
+			if tt.wantOffset == 0 {
+				assert.Empty(t, commits)
+				return
+			}
+			require.NotEmpty(t, commits)
+			assert.Equal(t, tt.wantOffset, commits[len(commits)-1].Offset)
+		})
+	}
+}
+
+func TestWorkerPoolShutdownWhileSaturated(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	committer := &fakeCommitter{}
+	pool := newWorkerPool(ctx, newPoolTestRoutes(t, nil), newOffsetTracker(committer), 1, 1)
+
+	started := make(chan struct{})
+	handle := func(ctx context.Context, _ *kafka.Message) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	assert.True(t, pool.dispatch(ctx, messageJob{msg: newPoolTestMessage(0, "key", ""), handle: handle}))
+	waitFor(t, started, "message was not handled")
+
+	// the pool is full, so the next message blocks until ctx is done
+	dispatched := make(chan bool)
+	go func() {
+		dispatched <- pool.dispatch(ctx, messageJob{msg: newPoolTestMessage(1, "key", ""), handle: handle})
+	}()
+	select {
+	case <-dispatched:
+		t.Fatal("dispatched while the pool is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case ok := <-dispatched:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("dispatch did not return after ctx is done")
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		pool.close()
+		close(closed)
+	}()
+	waitFor(t, closed, "pool did not close")
+
+	assert.Empty(t, committer.committed())
+	// stopping is not a failure of the message
+	assert.Empty(t, pool.failed())
+}
+
+func TestWorkerPoolGivesUp(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	producer := new(mockKafkaProducer)
+	producer.On("ProduceSync", testTopic+".dlq", mock.Anything, mock.Anything).Return(errors.New("broker unavailable"))
+
+	committer := &fakeCommitter{}
+	pool := newWorkerPool(ctx, newPoolTestRoutes(t, producer), newOffsetTracker(committer), 1, 1)
+
+	var handled int
+	handle := func(context.Context, *kafka.Message) error {
+		handled++
+		return errors.New("database error")
+	}
+	assert.True(t, pool.dispatch(ctx, messageJob{msg: newPoolTestMessage(0, "key", ""), handle: handle}))
+
+	select {
+	case failure := <-pool.failed():
+		assert.True(t, strings.Contains(failure.err.Error(), "broker unavailable"), failure.err.Error())
+		assert.Equal(t, testTopicPartition(0, 0), failure.tp)
+	case <-time.After(time.Second):
+		t.Fatal("pool did not give up the message")
+	}
+	cancel()
+	pool.close()
+
+	assert.Empty(t, committer.committed())
+	// retried up to the max attempts and dead lettered once
+	assert.Equal(t, 2, handled)
+	producer.AssertNumberOfCalls(t, "ProduceSync", 1)
+}
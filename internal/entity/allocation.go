@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidAllocation is returned when the allocation strategy can not allocate the items, e.g. the zip code is unknown
+var ErrInvalidAllocation = errors.New("invalid allocation")
+
+type AllocationItem struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Quantity  int64     `json:"quantity"`
+}
+
+// AllocationLine is the quantity of one product taken from one warehouse,
+// reason explains why the allocation strategy picked the warehouse
+type AllocationLine struct {
+	ProductID   uuid.UUID `json:"product_id"`
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	Quantity    int64     `json:"quantity"`
+	Distance    float64   `json:"distance"`
+	Reason      string    `json:"reason"`
+}
+
+// AllocationPlan is the result of an allocation strategy,
+// items without enough available quantity are listed in shortages and not allocated
+type AllocationPlan struct {
+	Strategy    string           `json:"strategy"`
+	Allocations []AllocationLine `json:"allocations"`
+	Shipments   []Shipment       `json:"shipments"`
+	// sum of the shipments distance
+	TotalDistance float64                 `json:"total_distance"`
+	Shortages     []InsufficientStockItem `json:"shortages,omitempty"`
+}
+
+// Shipment is every allocated item sent from the same source warehouse
+type Shipment struct {
+	WarehouseID uuid.UUID        `json:"warehouse_id"`
+	Distance    float64          `json:"distance"`
+	Items       []AllocationItem `json:"items"`
+}
+
+// group the allocations by source warehouse, in the order the warehouses are first allocated
+func (p *AllocationPlan) GroupShipments() *AllocationPlan {
+	p.Shipments = nil
+	p.TotalDistance = 0
+	index := make(map[uuid.UUID]int)
+	for _, line := range p.Allocations {
+		i, ok := index[line.WarehouseID]
+		if !ok {
+			i = len(p.Shipments)
+			index[line.WarehouseID] = i
+			p.Shipments = append(p.Shipments, Shipment{
+				WarehouseID: line.WarehouseID,
+				Distance:    line.Distance,
+			})
+			p.TotalDistance += line.Distance
+		}
+		p.Shipments[i].Items = append(p.Shipments[i].Items, AllocationItem{
+			ProductID: line.ProductID,
+			Quantity:  line.Quantity,
+		})
+	}
+	return p
+}
@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocationPlanGroupShipments(t *testing.T) {
+	productA := uuid.New()
+	productB := uuid.New()
+	warehouse1 := uuid.New()
+	warehouse2 := uuid.New()
+
+	plan := &AllocationPlan{
+		Allocations: []AllocationLine{
+			{ProductID: productA, WarehouseID: warehouse1, Quantity: 2, Distance: 10},
+			{ProductID: productA, WarehouseID: warehouse2, Quantity: 1, Distance: 50},
+			{ProductID: productB, WarehouseID: warehouse1, Quantity: 4, Distance: 10},
+		},
+	}
+
+	plan.GroupShipments()
+
+	assert.Equal(t, []Shipment{
+		{
+			WarehouseID: warehouse1,
+			Distance:    10,
+			Items: []AllocationItem{
+				{ProductID: productA, Quantity: 2},
+				{ProductID: productB, Quantity: 4},
+			},
+		},
+		{
+			WarehouseID: warehouse2,
+			Distance:    50,
+			Items: []AllocationItem{
+				{ProductID: productA, Quantity: 1},
+			},
+		},
+	}, plan.Shipments)
+	assert.Equal(t, float64(60), plan.TotalDistance)
+}
@@ -0,0 +1,161 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ForecastMethodMovingAverage        = "moving-average"
+	ForecastMethodExponentialSmoothing = "exponential-smoothing"
+)
+
+const day = 24 * time.Hour
+
+// ErrInvalidForecast is returned when the forecast parameters can not produce a forecast
+var ErrInvalidForecast = errors.New("invalid forecast")
+
+// ForecastParams selects the forecasting method and the sales history used by it.
+// A nil warehouse or product id forecasts every warehouse or product.
+type ForecastParams struct {
+	Method      string
+	HistoryDays int     // complete days of sales history before today
+	Window      int     // days averaged by the moving average
+	Alpha       float64 // smoothing factor of the exponential smoothing, between 0 and 1
+	WarehouseID uuid.UUID
+	ProductID   uuid.UUID
+}
+
+// DemandHistory is the daily sales of a product shipped by a warehouse
+type DemandHistory struct {
+	WarehouseID       uuid.UUID
+	ProductID         uuid.UUID
+	ProductName       string
+	AvailableQuantity int64
+	Sales             []*DailySales // ordered by day, days without sales are missing
+}
+
+type DailySales struct {
+	Day      time.Time
+	Quantity int64
+}
+
+type DemandForecast struct {
+	WarehouseID       uuid.UUID `json:"warehouse_id"`
+	ProductID         uuid.UUID `json:"product_id"`
+	ProductName       string    `json:"product_name"`
+	Method            string    `json:"method"`
+	DailyDemand       float64   `json:"daily_demand"`
+	AvailableQuantity int64     `json:"available_quantity"`
+	// days until the available quantity is sold out, nil when there is no demand
+	DaysOfCover  *float64   `json:"days_of_cover"`
+	StockoutDate *time.Time `json:"stockout_date"`
+	ForecastedAt time.Time  `json:"forecasted_at"`
+}
+
+func (fp *ForecastParams) Validate() error {
+	if fp.HistoryDays <= 0 {
+		return fmt.Errorf("history days must be positive: %w", ErrInvalidForecast)
+	}
+
+	switch fp.Method {
+	case ForecastMethodMovingAverage:
+		if fp.Window <= 0 || fp.Window > fp.HistoryDays {
+			return fmt.Errorf("window must be between 1 and %d days: %w", fp.HistoryDays, ErrInvalidForecast)
+		}
+	case ForecastMethodExponentialSmoothing:
+		if fp.Alpha <= 0 || fp.Alpha > 1 {
+			return fmt.Errorf("alpha must be greater than 0 and at most 1: %w", ErrInvalidForecast)
+		}
+	default:
+		return fmt.Errorf("unknown forecast method %q: %w", fp.Method, ErrInvalidForecast)
+	}
+
+	return nil
+}
+
+// history covers the complete days before the day of now, today is excluded because it is not over yet
+func (fp *ForecastParams) HistoryRange(now time.Time) (time.Time, time.Time) {
+	until := now.UTC().Truncate(day)
+	return until.AddDate(0, 0, -fp.HistoryDays), until
+}
+
+// sales quantity of every day from the given day, days without sales are zero
+func (dh *DemandHistory) DailySeries(from time.Time, days int) []int64 {
+	series := make([]int64, days)
+	for _, sales := range dh.Sales {
+		i := int(sales.Day.UTC().Truncate(day).Sub(from) / day)
+		if i >= 0 && i < days {
+			series[i] += sales.Quantity
+		}
+	}
+	return series
+}
+
+// forecast the daily demand from the sales history, and when the available quantity runs out at that demand
+func ForecastDemand(history *DemandHistory, params ForecastParams, now time.Time) *DemandForecast {
+	from, _ := params.HistoryRange(now)
+	series := history.DailySeries(from, params.HistoryDays)
+
+	var dailyDemand float64
+	switch params.Method {
+	case ForecastMethodMovingAverage:
+		dailyDemand = movingAverage(series, params.Window)
+	case ForecastMethodExponentialSmoothing:
+		dailyDemand = exponentialSmoothing(series, params.Alpha)
+	}
+
+	forecast := &DemandForecast{
+		WarehouseID:       history.WarehouseID,
+		ProductID:         history.ProductID,
+		ProductName:       history.ProductName,
+		Method:            params.Method,
+		DailyDemand:       dailyDemand,
+		AvailableQuantity: history.AvailableQuantity,
+		ForecastedAt:      now,
+	}
+	if dailyDemand > 0 {
+		daysOfCover := math.Max(float64(history.AvailableQuantity), 0) / dailyDemand
+		stockoutDate := now.Add(time.Duration(daysOfCover * float64(day)))
+		forecast.DaysOfCover = &daysOfCover
+		forecast.StockoutDate = &stockoutDate
+	}
+
+	return forecast
+}
+
+// forecasted demand over the duration, rounded up so replenishment does not fall short
+func (df *DemandForecast) QuantityFor(duration time.Duration) int64 {
+	return int64(math.Ceil(df.DailyDemand * duration.Hours() / 24))
+}
+
+// average of the last window days
+func movingAverage(series []int64, window int) float64 {
+	if len(series) == 0 || window <= 0 {
+		return 0
+	}
+	window = min(window, len(series))
+
+	var sum int64
+	for _, quantity := range series[len(series)-window:] {
+		sum += quantity
+	}
+	return float64(sum) / float64(window)
+}
+
+// the level starts at the first day and each following day moves it by alpha towards the day's sales
+func exponentialSmoothing(series []int64, alpha float64) float64 {
+	if len(series) == 0 {
+		return 0
+	}
+
+	level := float64(series[0])
+	for _, quantity := range series[1:] {
+		level = alpha*float64(quantity) + (1-alpha)*level
+	}
+	return level
+}
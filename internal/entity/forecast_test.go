@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForecastParamsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  ForecastParams
+		wantErr bool
+	}{
+		{name: "moving average", params: ForecastParams{Method: ForecastMethodMovingAverage, HistoryDays: 28, Window: 7}},
+		{name: "exponential smoothing", params: ForecastParams{Method: ForecastMethodExponentialSmoothing, HistoryDays: 28, Alpha: 0.3}},
+		{name: "window longer than history", params: ForecastParams{Method: ForecastMethodMovingAverage, HistoryDays: 5, Window: 7}, wantErr: true},
+		{name: "alpha above one", params: ForecastParams{Method: ForecastMethodExponentialSmoothing, HistoryDays: 28, Alpha: 1.5}, wantErr: true},
+		{name: "no history", params: ForecastParams{Method: ForecastMethodExponentialSmoothing, Alpha: 0.3}, wantErr: true},
+		{name: "unknown method", params: ForecastParams{Method: "median", HistoryDays: 28}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidForecast)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDemandHistoryDailySeries(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := &DemandHistory{Sales: []*DailySales{
+		{Day: from, Quantity: 2},
+		{Day: from.AddDate(0, 0, 2), Quantity: 5},
+		{Day: from.AddDate(0, 0, 9), Quantity: 8},
+	}}
+
+	// days without sales are zero and days outside of the range are ignored
+	assert.Equal(t, []int64{2, 0, 5, 0}, history.DailySeries(from, 4))
+}
+
+func TestForecastDemand(t *testing.T) {
+	now := time.Date(2024, 1, 11, 12, 0, 0, 0, time.UTC)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var sales []*DailySales
+	for i, quantity := range []int64{0, 0, 0, 0, 0, 0, 10, 10, 10, 10} {
+		sales = append(sales, &DailySales{Day: from.AddDate(0, 0, i), Quantity: quantity})
+	}
+	history := &DemandHistory{WarehouseID: uuid.New(), ProductID: uuid.New(), AvailableQuantity: 25, Sales: sales}
+
+	forecast := ForecastDemand(history, ForecastParams{Method: ForecastMethodMovingAverage, HistoryDays: 10, Window: 5}, now)
+
+	assert.Equal(t, history.ProductID, forecast.ProductID)
+	assert.InDelta(t, 8.0, forecast.DailyDemand, 0.0001)
+	assert.InDelta(t, 3.125, *forecast.DaysOfCover, 0.0001)
+	assert.Equal(t, now.Add(75*time.Hour), *forecast.StockoutDate)
+	assert.Equal(t, int64(24), forecast.QuantityFor(72*time.Hour))
+
+	forecast = ForecastDemand(history, ForecastParams{Method: ForecastMethodExponentialSmoothing, HistoryDays: 10, Alpha: 0.5}, now)
+
+	assert.InDelta(t, 9.375, forecast.DailyDemand, 0.0001)
+
+	// no sales means the stock never runs out
+	forecast = ForecastDemand(&DemandHistory{AvailableQuantity: 25}, ForecastParams{Method: ForecastMethodMovingAverage, HistoryDays: 10, Window: 5}, now)
+
+	assert.Zero(t, forecast.DailyDemand)
+	assert.Nil(t, forecast.DaysOfCover)
+	assert.Nil(t, forecast.StockoutDate)
+}
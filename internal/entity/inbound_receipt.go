@@ -0,0 +1,161 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	InboundReceiptTypePurchaseOrder = "purchase-order"
+	InboundReceiptTypeASN           = "asn" // advance shipping notice
+)
+
+const (
+	InboundReceiptStatusExpected          = "expected"
+	InboundReceiptStatusPartiallyReceived = "partially-received"
+	InboundReceiptStatusReceived          = "received"
+	InboundReceiptStatusClosed            = "closed"
+)
+
+// ErrInvalidInboundReceipt is returned when the inbound receipt is closed or the received product is not expected by it
+var ErrInvalidInboundReceipt = errors.New("invalid inbound receipt")
+
+// InboundReceipt is the stock expected to arrive in a warehouse from a purchase order or shipping notice.
+// It can be received in several deliveries, each delivery increases the product quantity of the warehouse.
+type InboundReceipt struct {
+	ID              uuid.UUID             `json:"id"`
+	WarehouseID     uuid.UUID             `json:"warehouse_id"`
+	Type            string                `json:"type"`
+	ReferenceNumber string                `json:"reference_number"` // purchase order or shipping notice number
+	Status          string                `json:"status"`
+	Lines           []*InboundReceiptLine `json:"lines"`
+	CreatedBy       uuid.UUID             `json:"created_by"`
+	// user and receipt movements of the last delivery
+	ReceivedBy     uuid.UUID        `json:"received_by,omitempty"`
+	StockMovements []*StockMovement `json:"stock_movements,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+type InboundReceiptLine struct {
+	ID               uuid.UUID `json:"id"`
+	InboundReceiptID uuid.UUID `json:"inbound_receipt_id"`
+	ProductID        uuid.UUID `json:"product_id"`
+	ProductName      string    `json:"product_name"`
+	ExpectedQuantity int64     `json:"expected_quantity"`
+	UnitCost         float64   `json:"unit_cost"` // purchase cost of one unit, received quantity is valued at it
+	ReceivedQuantity int64     `json:"received_quantity"`
+	OverQuantity     int64     `json:"over_quantity"`  // received more than expected
+	ShortQuantity    int64     `json:"short_quantity"` // not received yet, or missing when the receipt is closed
+}
+
+func (ir *InboundReceipt) GenerateInboundReceiptID() error {
+	inboundReceiptID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	ir.ID = inboundReceiptID
+	return nil
+}
+
+func (irl *InboundReceiptLine) GenerateInboundReceiptLineID() error {
+	inboundReceiptLineID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	irl.ID = inboundReceiptLineID
+	return nil
+}
+
+// closed receipt does not accept deliveries anymore
+func (ir *InboundReceipt) IsReceivable() bool {
+	return ir.Status != InboundReceiptStatusClosed
+}
+
+// validate new receipt, every line needs a positive expected quantity
+func (ir *InboundReceipt) Validate() error {
+	if ir.Type != InboundReceiptTypePurchaseOrder && ir.Type != InboundReceiptTypeASN {
+		return fmt.Errorf("unknown receipt type %q: %w", ir.Type, ErrInvalidInboundReceipt)
+	}
+	if ir.ReferenceNumber == "" {
+		return fmt.Errorf("reference number is required: %w", ErrInvalidInboundReceipt)
+	}
+	for _, line := range ir.Lines {
+		if line.UnitCost < 0 {
+			return fmt.Errorf("unit cost of product %s must not be negative: %w", line.ProductID, ErrInvalidInboundReceipt)
+		}
+	}
+
+	return validateInboundReceiptLines(ir.Lines, func(line *InboundReceiptLine) int64 {
+		return line.ExpectedQuantity
+	})
+}
+
+// validate delivery of the receipt, received quantity of the lines is the delivered quantity
+func (ir *InboundReceipt) ValidateDelivery() error {
+	return validateInboundReceiptLines(ir.Lines, func(line *InboundReceiptLine) int64 {
+		return line.ReceivedQuantity
+	})
+}
+
+// lines must have positive quantity and each product can only be in one line
+func validateInboundReceiptLines(lines []*InboundReceiptLine, quantity func(*InboundReceiptLine) int64) error {
+	if len(lines) == 0 {
+		return fmt.Errorf("lines are required: %w", ErrInvalidInboundReceipt)
+	}
+
+	products := make(map[uuid.UUID]bool, len(lines))
+	for _, line := range lines {
+		if quantity(line) <= 0 {
+			return fmt.Errorf("quantity of product %s must be positive: %w", line.ProductID, ErrInvalidInboundReceipt)
+		}
+		if products[line.ProductID] {
+			return fmt.Errorf("product %s is duplicated: %w", line.ProductID, ErrInvalidInboundReceipt)
+		}
+		products[line.ProductID] = true
+	}
+
+	return nil
+}
+
+// status based on the received quantity of every line, closed receipt stays closed
+func (ir *InboundReceipt) UpdateStatus() {
+	if ir.Status == InboundReceiptStatusClosed {
+		return
+	}
+
+	received, fullyReceived := false, true
+	for _, line := range ir.Lines {
+		if line.ReceivedQuantity > 0 {
+			received = true
+		}
+		if line.ReceivedQuantity < line.ExpectedQuantity {
+			fullyReceived = false
+		}
+	}
+
+	switch {
+	case received && fullyReceived:
+		ir.Status = InboundReceiptStatusReceived
+	case received:
+		ir.Status = InboundReceiptStatusPartiallyReceived
+	default:
+		ir.Status = InboundReceiptStatusExpected
+	}
+}
+
+func (ir *InboundReceipt) CalculateDiscrepancies() {
+	for _, line := range ir.Lines {
+		line.OverQuantity, line.ShortQuantity = 0, 0
+		if line.ReceivedQuantity > line.ExpectedQuantity {
+			line.OverQuantity = line.ReceivedQuantity - line.ExpectedQuantity
+		} else {
+			line.ShortQuantity = line.ExpectedQuantity - line.ReceivedQuantity
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInboundReceiptValidate(t *testing.T) {
+	productID := uuid.New()
+
+	tests := []struct {
+		name           string
+		inboundReceipt *InboundReceipt
+		wantErr        bool
+	}{
+		{
+			name: "valid purchase order",
+			inboundReceipt: &InboundReceipt{
+				Type:            InboundReceiptTypePurchaseOrder,
+				ReferenceNumber: "PO-001",
+				Lines:           []*InboundReceiptLine{{ProductID: productID, ExpectedQuantity: 10}},
+			},
+		},
+		{
+			name: "unknown type",
+			inboundReceipt: &InboundReceipt{
+				Type:            "invoice",
+				ReferenceNumber: "PO-001",
+				Lines:           []*InboundReceiptLine{{ProductID: productID, ExpectedQuantity: 10}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing reference number",
+			inboundReceipt: &InboundReceipt{
+				Type:  InboundReceiptTypeASN,
+				Lines: []*InboundReceiptLine{{ProductID: productID, ExpectedQuantity: 10}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "no lines",
+			inboundReceipt: &InboundReceipt{
+				Type:            InboundReceiptTypeASN,
+				ReferenceNumber: "ASN-001",
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero expected quantity",
+			inboundReceipt: &InboundReceipt{
+				Type:            InboundReceiptTypeASN,
+				ReferenceNumber: "ASN-001",
+				Lines:           []*InboundReceiptLine{{ProductID: productID}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicated product",
+			inboundReceipt: &InboundReceipt{
+				Type:            InboundReceiptTypeASN,
+				ReferenceNumber: "ASN-001",
+				Lines: []*InboundReceiptLine{
+					{ProductID: productID, ExpectedQuantity: 1},
+					{ProductID: productID, ExpectedQuantity: 2},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative unit cost",
+			inboundReceipt: &InboundReceipt{
+				Type:            InboundReceiptTypePurchaseOrder,
+				ReferenceNumber: "PO-001",
+				Lines:           []*InboundReceiptLine{{ProductID: productID, ExpectedQuantity: 10, UnitCost: -1}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.inboundReceipt.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidInboundReceipt)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestInboundReceiptValidateDelivery(t *testing.T) {
+	inboundReceipt := &InboundReceipt{
+		Lines: []*InboundReceiptLine{{ProductID: uuid.New(), ReceivedQuantity: 3}},
+	}
+	assert.NoError(t, inboundReceipt.ValidateDelivery())
+
+	inboundReceipt.Lines[0].ReceivedQuantity = 0
+	assert.ErrorIs(t, inboundReceipt.ValidateDelivery(), ErrInvalidInboundReceipt)
+}
+
+func TestInboundReceiptUpdateStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		lines  []*InboundReceiptLine
+		want   string
+	}{
+		{
+			name:   "nothing received",
+			status: InboundReceiptStatusExpected,
+			lines:  []*InboundReceiptLine{{ExpectedQuantity: 5}},
+			want:   InboundReceiptStatusExpected,
+		},
+		{
+			name:   "short received",
+			status: InboundReceiptStatusExpected,
+			lines: []*InboundReceiptLine{
+				{ExpectedQuantity: 5, ReceivedQuantity: 5},
+				{ExpectedQuantity: 5, ReceivedQuantity: 2},
+			},
+			want: InboundReceiptStatusPartiallyReceived,
+		},
+		{
+			name:   "over received",
+			status: InboundReceiptStatusPartiallyReceived,
+			lines: []*InboundReceiptLine{
+				{ExpectedQuantity: 5, ReceivedQuantity: 7},
+				{ExpectedQuantity: 5, ReceivedQuantity: 5},
+			},
+			want: InboundReceiptStatusReceived,
+		},
+		{
+			name:   "closed stays closed",
+			status: InboundReceiptStatusClosed,
+			lines:  []*InboundReceiptLine{{ExpectedQuantity: 5, ReceivedQuantity: 5}},
+			want:   InboundReceiptStatusClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inboundReceipt := &InboundReceipt{Status: tt.status, Lines: tt.lines}
+			inboundReceipt.UpdateStatus()
+			assert.Equal(t, tt.want, inboundReceipt.Status)
+		})
+	}
+}
+
+func TestInboundReceiptCalculateDiscrepancies(t *testing.T) {
+	inboundReceipt := &InboundReceipt{
+		Lines: []*InboundReceiptLine{
+			{ExpectedQuantity: 10, ReceivedQuantity: 12},
+			{ExpectedQuantity: 10, ReceivedQuantity: 4},
+			{ExpectedQuantity: 10, ReceivedQuantity: 10},
+		},
+	}
+
+	inboundReceipt.CalculateDiscrepancies()
+
+	assert.Equal(t, int64(2), inboundReceipt.Lines[0].OverQuantity)
+	assert.Equal(t, int64(0), inboundReceipt.Lines[0].ShortQuantity)
+	assert.Equal(t, int64(0), inboundReceipt.Lines[1].OverQuantity)
+	assert.Equal(t, int64(6), inboundReceipt.Lines[1].ShortQuantity)
+	assert.Equal(t, int64(0), inboundReceipt.Lines[2].OverQuantity)
+	assert.Equal(t, int64(0), inboundReceipt.Lines[2].ShortQuantity)
+}
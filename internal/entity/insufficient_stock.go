@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type InsufficientStockItem struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Requested int64     `json:"requested"`
+	Available int64     `json:"available"`
+}
+
+// InsufficientStockError is returned when one or more requested items
+// can not be fulfilled by the available quantity of all warehouses.
+type InsufficientStockError struct {
+	Items []InsufficientStockItem `json:"items"`
+}
+
+func (e *InsufficientStockError) Error() string {
+	items := make([]string, 0, len(e.Items))
+	for _, item := range e.Items {
+		items = append(items, fmt.Sprintf("product %s requested %d available %d", item.ProductID, item.Requested, item.Available))
+	}
+	return fmt.Sprintf("insufficient stock: %s", strings.Join(items, ", "))
+}
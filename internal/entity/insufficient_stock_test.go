@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsufficientStockError(t *testing.T) {
+	productA := uuid.MustParse("019444a2-e318-79b5-8fe4-b32716306083")
+	productB := uuid.MustParse("019444a3-6a3f-7249-b694-f6f071d8eb79")
+
+	err := &InsufficientStockError{
+		Items: []InsufficientStockItem{
+			{ProductID: productA, Requested: 10, Available: 4},
+			{ProductID: productB, Requested: 2, Available: 0},
+		},
+	}
+
+	assert.Equal(t,
+		"insufficient stock: product 019444a2-e318-79b5-8fe4-b32716306083 requested 10 available 4, "+
+			"product 019444a3-6a3f-7249-b694-f6f071d8eb79 requested 2 available 0",
+		err.Error(),
+	)
+
+	var insufficientStockErr *InsufficientStockError
+	wrapped := fmt.Errorf("failed to transfer out: %w", err)
+	assert.True(t, errors.As(wrapped, &insufficientStockErr))
+	assert.Len(t, insufficientStockErr.Items, 2)
+}
@@ -0,0 +1,123 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	InventoryCountStatusOpen      = "open"
+	InventoryCountStatusPosted    = "posted"
+	InventoryCountStatusCancelled = "cancelled"
+)
+
+const InventoryCountAdjustmentReason = "inventory count"
+
+// ErrInvalidInventoryCount is returned when the inventory count is not open or the counted product is not part of it
+var ErrInvalidInventoryCount = errors.New("invalid inventory count")
+
+// InventoryCount is a physical count session of a warehouse. The expected quantity of
+// each product is snapshotted when the session is opened, posting the session adjusts
+// the product quantity by the difference between counted and expected quantity.
+type InventoryCount struct {
+	ID          uuid.UUID             `json:"id"`
+	WarehouseID uuid.UUID             `json:"warehouse_id"`
+	Status      string                `json:"status"`
+	Lines       []*InventoryCountLine `json:"lines"`
+	CreatedBy   uuid.UUID             `json:"created_by"`
+	PostedBy    uuid.UUID             `json:"posted_by"`
+	// adjustments made when the session is posted
+	Adjustments []*StockAdjustment `json:"adjustments,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+type InventoryCountLine struct {
+	ID               uuid.UUID `json:"id"`
+	InventoryCountID uuid.UUID `json:"inventory_count_id"`
+	ProductID        uuid.UUID `json:"product_id"`
+	ProductName      string    `json:"product_name"`
+	ExpectedQuantity int64     `json:"expected_quantity"`
+	CountedQuantity  *int64    `json:"counted_quantity"` // nil until the product is counted
+	Variance         *int64    `json:"variance"`
+}
+
+func (ic *InventoryCount) GenerateInventoryCountID() error {
+	inventoryCountID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	ic.ID = inventoryCountID
+	return nil
+}
+
+func (icl *InventoryCountLine) GenerateInventoryCountLineID() error {
+	inventoryCountLineID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	icl.ID = inventoryCountLineID
+	return nil
+}
+
+func (ic *InventoryCount) IsOpen() bool {
+	return ic.Status == InventoryCountStatusOpen
+}
+
+// create a line for each warehouse product with its current quantity as expected quantity
+func (ic *InventoryCount) Snapshot(warehouseProducts []*WarehouseProduct) error {
+	ic.Lines = make([]*InventoryCountLine, 0, len(warehouseProducts))
+	for _, warehouseProduct := range warehouseProducts {
+		line := &InventoryCountLine{
+			InventoryCountID: ic.ID,
+			ProductID:        warehouseProduct.ProductID,
+			ProductName:      warehouseProduct.ProductName,
+			ExpectedQuantity: warehouseProduct.ProductQuantity,
+		}
+		if err := line.GenerateInventoryCountLineID(); err != nil {
+			return err
+		}
+		ic.Lines = append(ic.Lines, line)
+	}
+
+	return nil
+}
+
+// variance is only known for counted lines
+func (ic *InventoryCount) CalculateVariances() {
+	for _, line := range ic.Lines {
+		line.Variance = nil
+		if line.CountedQuantity != nil {
+			variance := *line.CountedQuantity - line.ExpectedQuantity
+			line.Variance = &variance
+		}
+	}
+}
+
+// adjustment for each counted line with a variance, uncounted lines are not adjusted.
+// the variance is applied as delta so movements made while counting are kept
+func (ic *InventoryCount) ToStockAdjustments(userID uuid.UUID, createdAt time.Time) []*StockAdjustment {
+	ic.CalculateVariances()
+
+	var adjustments []*StockAdjustment
+	for _, line := range ic.Lines {
+		if line.Variance == nil || *line.Variance == 0 {
+			continue
+		}
+		adjustments = append(adjustments, &StockAdjustment{
+			WarehouseID: ic.WarehouseID,
+			ProductID:   line.ProductID,
+			Delta:       *line.Variance,
+			Reason:      InventoryCountAdjustmentReason,
+			ReferenceID: ic.ID.String(),
+			UserID:      userID,
+			CreatedAt:   createdAt,
+		})
+	}
+
+	return adjustments
+}
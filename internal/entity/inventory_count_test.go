@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestInventoryCountSnapshot(t *testing.T) {
+	inventoryCount := &InventoryCount{}
+	assert.NoError(t, inventoryCount.GenerateInventoryCountID())
+
+	warehouseProducts := []*WarehouseProduct{
+		{ProductID: uuid.New(), ProductName: "Product A", ProductQuantity: 10},
+		{ProductID: uuid.New(), ProductName: "Product B", ProductQuantity: 0},
+	}
+
+	err := inventoryCount.Snapshot(warehouseProducts)
+
+	assert.NoError(t, err)
+	assert.Len(t, inventoryCount.Lines, len(warehouseProducts))
+	for i, line := range inventoryCount.Lines {
+		assert.NotEqual(t, uuid.Nil, line.ID)
+		assert.Equal(t, inventoryCount.ID, line.InventoryCountID)
+		assert.Equal(t, warehouseProducts[i].ProductID, line.ProductID)
+		assert.Equal(t, warehouseProducts[i].ProductName, line.ProductName)
+		assert.Equal(t, warehouseProducts[i].ProductQuantity, line.ExpectedQuantity)
+		assert.Nil(t, line.CountedQuantity)
+	}
+}
+
+func TestInventoryCountCalculateVariances(t *testing.T) {
+	inventoryCount := &InventoryCount{
+		Lines: []*InventoryCountLine{
+			{ExpectedQuantity: 10, CountedQuantity: int64Ptr(8)},
+			{ExpectedQuantity: 5, CountedQuantity: int64Ptr(5)},
+			{ExpectedQuantity: 3},
+		},
+	}
+
+	inventoryCount.CalculateVariances()
+
+	assert.Equal(t, int64(-2), *inventoryCount.Lines[0].Variance)
+	assert.Equal(t, int64(0), *inventoryCount.Lines[1].Variance)
+	assert.Nil(t, inventoryCount.Lines[2].Variance)
+}
+
+func TestInventoryCountToStockAdjustments(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now()
+	inventoryCount := &InventoryCount{
+		ID:          uuid.New(),
+		WarehouseID: uuid.New(),
+		Lines: []*InventoryCountLine{
+			{ProductID: uuid.New(), ExpectedQuantity: 10, CountedQuantity: int64Ptr(8)},
+			{ProductID: uuid.New(), ExpectedQuantity: 5, CountedQuantity: int64Ptr(5)},
+			{ProductID: uuid.New(), ExpectedQuantity: 3},
+			{ProductID: uuid.New(), ExpectedQuantity: 0, CountedQuantity: int64Ptr(4)},
+		},
+	}
+
+	adjustments := inventoryCount.ToStockAdjustments(userID, now)
+
+	assert.Len(t, adjustments, 2)
+	assert.Equal(t, inventoryCount.Lines[0].ProductID, adjustments[0].ProductID)
+	assert.Equal(t, int64(-2), adjustments[0].Delta)
+	assert.Equal(t, inventoryCount.Lines[3].ProductID, adjustments[1].ProductID)
+	assert.Equal(t, int64(4), adjustments[1].Delta)
+	for _, adjustment := range adjustments {
+		assert.Equal(t, inventoryCount.WarehouseID, adjustment.WarehouseID)
+		assert.Nil(t, adjustment.CountedQuantity)
+		assert.Equal(t, InventoryCountAdjustmentReason, adjustment.Reason)
+		assert.Equal(t, inventoryCount.ID.String(), adjustment.ReferenceID)
+		assert.Equal(t, userID, adjustment.UserID)
+		assert.Equal(t, now, adjustment.CreatedAt)
+		assert.NoError(t, adjustment.Validate())
+	}
+}
+
+func TestInventoryCountIsOpen(t *testing.T) {
+	assert.True(t, (&InventoryCount{Status: InventoryCountStatusOpen}).IsOpen())
+	assert.False(t, (&InventoryCount{Status: InventoryCountStatusPosted}).IsOpen())
+	assert.False(t, (&InventoryCount{Status: InventoryCountStatusCancelled}).IsOpen())
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	OutboxEventStatusPending = "pending"
+	OutboxEventStatusSent    = "sent"
+	OutboxEventStatusFailed  = "failed"
+)
+
+// OutboxEvent is a kafka message which is saved in the same transaction as the stock change,
+// then published by the relay worker, so only committed stock changes are published.
+type OutboxEvent struct {
+	ID            uuid.UUID `json:"id"`
+	Topic         string    `json:"topic"`
+	Key           string    `json:"key"`
+	Payload       []byte    `json:"payload"`
+	Status        string    `json:"status"`
+	Attempts      int       `json:"attempts"`
+	LastError     string    `json:"last_error"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	SentAt        time.Time `json:"sent_at"`
+}
+
+func (oe *OutboxEvent) GenerateOutboxEventID() error {
+	outboxEventID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	oe.ID = outboxEventID
+	return nil
+}
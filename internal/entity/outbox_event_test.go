@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateOutboxEventID(t *testing.T) {
+	tests := []struct {
+		name        string
+		outboxEvent *OutboxEvent
+		wantPanic   bool
+	}{
+		{
+			name:        "generate id for empty outbox event",
+			outboxEvent: &OutboxEvent{},
+		},
+		{
+			name: "generate id for filled outbox event",
+			outboxEvent: &OutboxEvent{
+				Topic:         "product-quantity-updated",
+				Key:           uuid.NewString(),
+				Payload:       []byte(`{"quantity":10}`),
+				Status:        OutboxEventStatusPending,
+				NextAttemptAt: time.Now(),
+				CreatedAt:     time.Now(),
+			},
+		},
+		{
+			name:        "should panic for nil outbox event",
+			outboxEvent: nil,
+			wantPanic:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.wantPanic {
+				assert.Panics(t, func() {
+					_ = tc.outboxEvent.GenerateOutboxEventID()
+				})
+				return
+			}
+
+			outboxEvent := *tc.outboxEvent
+
+			err := tc.outboxEvent.GenerateOutboxEventID()
+
+			assert.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, tc.outboxEvent.ID)
+			assert.Equal(t, uuid.Version(7), tc.outboxEvent.ID.Version())
+			assert.Equal(t, outboxEvent.Topic, tc.outboxEvent.Topic)
+			assert.Equal(t, outboxEvent.Key, tc.outboxEvent.Key)
+			assert.Equal(t, outboxEvent.Payload, tc.outboxEvent.Payload)
+			assert.Equal(t, outboxEvent.Status, tc.outboxEvent.Status)
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package entity
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const RebalanceReason = "rebalance"
+
+// WarehouseDemand is the recent outbound quantity of a product shipped by a warehouse.
+// Orders are allocated to the warehouses nearest the customers, so the outbound quantity
+// of a regional warehouse is the demand of its region.
+type WarehouseDemand struct {
+	WarehouseID       uuid.UUID `json:"warehouse_id"`
+	ProductID         uuid.UUID `json:"product_id"`
+	ProductName       string    `json:"product_name"`
+	OutboundQuantity  int64     `json:"outbound_quantity"`
+	AvailableQuantity int64     `json:"available_quantity"`
+	InTransitQuantity int64     `json:"in_transit_quantity"`
+}
+
+// RebalanceLimits bounds the transfers created by one rebalancing run
+type RebalanceLimits struct {
+	Lookback               time.Duration // outbound movements newer than this are the demand
+	Coverage               time.Duration // regional warehouses are filled to cover the demand of this duration
+	MaxTransfers           int
+	MaxQuantityPerTransfer int64
+	MinMainQuantity        int64 // quantity always kept in the main warehouse
+}
+
+type RebalanceReport struct {
+	ID     uuid.UUID `json:"id"`
+	DryRun bool      `json:"dry_run"`
+	// outbound movements since this time are the demand
+	Since     time.Time        `json:"since"`
+	Lines     []*RebalanceLine `json:"lines"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+type RebalanceLine struct {
+	ProductID        uuid.UUID `json:"product_id"`
+	ProductName      string    `json:"product_name"`
+	FromWarehouseID  uuid.UUID `json:"from_warehouse_id"`
+	ToWarehouseID    uuid.UUID `json:"to_warehouse_id"`
+	OutboundQuantity int64     `json:"outbound_quantity"`
+	CurrentQuantity  int64     `json:"current_quantity"` // available and in-transit quantity of the regional warehouse
+	TargetQuantity   int64     `json:"target_quantity"`
+	Quantity         int64     `json:"quantity"` // quantity to transfer
+	// transfer movement created when the report is not a dry run
+	StockMovement *StockMovement `json:"stock_movement,omitempty"`
+	Error         string         `json:"error,omitempty"`
+}
+
+func (rr *RebalanceReport) GenerateRebalanceReportID() error {
+	rebalanceReportID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	rr.ID = rebalanceReportID
+	return nil
+}
+
+// quantity the regional warehouse needs to cover the demand for the coverage duration
+func (wd *WarehouseDemand) TargetQuantity(limits RebalanceLimits) int64 {
+	if limits.Lookback <= 0 {
+		return 0
+	}
+	// rounded up, so a small but steady demand still gets stock
+	return (wd.OutboundQuantity*int64(limits.Coverage) + int64(limits.Lookback) - 1) / int64(limits.Lookback)
+}
+
+func (wd *WarehouseDemand) CurrentQuantity() int64 {
+	return wd.AvailableQuantity + wd.InTransitQuantity
+}
+
+// plan transfers from the main warehouse to the regional warehouses below their target quantity.
+// The biggest gap is filled first, the quantity of each transfer is bounded by the limits
+// and the main warehouse keeps at least its minimum quantity of every product.
+func PlanRebalance(
+	mainWarehouseID uuid.UUID,
+	demands []*WarehouseDemand,
+	mainAvailable map[uuid.UUID]int64,
+	limits RebalanceLimits,
+) []*RebalanceLine {
+	type gap struct {
+		demand *WarehouseDemand
+		target int64
+		gap    int64
+	}
+
+	var gaps []gap
+	for _, demand := range demands {
+		if demand.WarehouseID == mainWarehouseID {
+			continue
+		}
+		target := demand.TargetQuantity(limits)
+		if target > demand.CurrentQuantity() {
+			gaps = append(gaps, gap{demand, target, target - demand.CurrentQuantity()})
+		}
+	}
+	sort.SliceStable(gaps, func(i, j int) bool {
+		return gaps[i].gap > gaps[j].gap
+	})
+
+	remaining := make(map[uuid.UUID]int64, len(mainAvailable))
+	for productID, quantity := range mainAvailable {
+		remaining[productID] = quantity - limits.MinMainQuantity
+	}
+
+	var lines []*RebalanceLine
+	for _, g := range gaps {
+		if len(lines) >= limits.MaxTransfers {
+			break
+		}
+
+		quantity := min(g.gap, remaining[g.demand.ProductID])
+		if limits.MaxQuantityPerTransfer > 0 {
+			quantity = min(quantity, limits.MaxQuantityPerTransfer)
+		}
+		if quantity <= 0 {
+			continue
+		}
+		remaining[g.demand.ProductID] -= quantity
+
+		lines = append(lines, &RebalanceLine{
+			ProductID:        g.demand.ProductID,
+			ProductName:      g.demand.ProductName,
+			FromWarehouseID:  mainWarehouseID,
+			ToWarehouseID:    g.demand.WarehouseID,
+			OutboundQuantity: g.demand.OutboundQuantity,
+			CurrentQuantity:  g.demand.CurrentQuantity(),
+			TargetQuantity:   g.target,
+			Quantity:         quantity,
+		})
+	}
+
+	return lines
+}
+
+// transfer movement of the line, linked to the report by the reference id
+func (rl *RebalanceLine) ToStockMovement(reportID uuid.UUID, createdAt time.Time) *StockMovement {
+	return &StockMovement{
+		ProductID:       rl.ProductID,
+		ProductName:     rl.ProductName,
+		Quantity:        rl.Quantity,
+		FromWarehouseID: rl.FromWarehouseID,
+		ToWarehouseID:   rl.ToWarehouseID,
+		MovementType:    MovementTypeTransfer,
+		Reason:          RebalanceReason,
+		ReferenceID:     reportID.String(),
+		CreatedAt:       createdAt,
+	}
+}
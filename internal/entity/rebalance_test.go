@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarehouseDemandTargetQuantity(t *testing.T) {
+	limits := RebalanceLimits{Lookback: 7 * 24 * time.Hour, Coverage: 3 * 24 * time.Hour}
+
+	assert.Equal(t, int64(3), (&WarehouseDemand{OutboundQuantity: 7}).TargetQuantity(limits))
+	// rounded up
+	assert.Equal(t, int64(1), (&WarehouseDemand{OutboundQuantity: 1}).TargetQuantity(limits))
+	assert.Equal(t, int64(0), (&WarehouseDemand{OutboundQuantity: 7}).TargetQuantity(RebalanceLimits{}))
+}
+
+func TestPlanRebalance(t *testing.T) {
+	mainWarehouseID := uuid.New()
+	warehouseA := uuid.New()
+	warehouseB := uuid.New()
+	productID := uuid.New()
+	otherProductID := uuid.New()
+
+	limits := RebalanceLimits{
+		Lookback:               7 * 24 * time.Hour,
+		Coverage:               7 * 24 * time.Hour,
+		MaxTransfers:           10,
+		MaxQuantityPerTransfer: 15,
+		MinMainQuantity:        2,
+	}
+	demands := []*WarehouseDemand{
+		{WarehouseID: warehouseA, ProductID: productID, OutboundQuantity: 10, AvailableQuantity: 4, InTransitQuantity: 2},
+		{WarehouseID: warehouseB, ProductID: productID, OutboundQuantity: 30},
+		{WarehouseID: mainWarehouseID, ProductID: productID, OutboundQuantity: 50},
+		{WarehouseID: warehouseA, ProductID: otherProductID, OutboundQuantity: 5, AvailableQuantity: 9},
+	}
+	mainAvailable := map[uuid.UUID]int64{productID: 20}
+
+	lines := PlanRebalance(mainWarehouseID, demands, mainAvailable, limits)
+
+	// warehouse B has the biggest gap so it is filled first, bounded by the quantity per transfer,
+	// the main warehouse keeps its minimum quantity and covered products are skipped
+	assert.Len(t, lines, 2)
+	assert.Equal(t, warehouseB, lines[0].ToWarehouseID)
+	assert.Equal(t, mainWarehouseID, lines[0].FromWarehouseID)
+	assert.Equal(t, int64(30), lines[0].TargetQuantity)
+	assert.Equal(t, int64(15), lines[0].Quantity)
+	assert.Equal(t, warehouseA, lines[1].ToWarehouseID)
+	assert.Equal(t, int64(6), lines[1].CurrentQuantity)
+	assert.Equal(t, int64(3), lines[1].Quantity)
+	assert.Equal(t, int64(20), mainAvailable[productID])
+
+	limits.MaxTransfers = 1
+	assert.Len(t, PlanRebalance(mainWarehouseID, demands, mainAvailable, limits), 1)
+}
+
+func TestRebalanceLineToStockMovement(t *testing.T) {
+	reportID := uuid.New()
+	line := &RebalanceLine{ProductID: uuid.New(), FromWarehouseID: uuid.New(), ToWarehouseID: uuid.New(), Quantity: 4}
+
+	stockMovement := line.ToStockMovement(reportID, time.Now())
+
+	assert.Equal(t, MovementTypeTransfer, stockMovement.MovementType)
+	assert.Equal(t, RebalanceReason, stockMovement.Reason)
+	assert.Equal(t, reportID.String(), stockMovement.ReferenceID)
+	assert.Equal(t, line.ToWarehouseID, stockMovement.ToWarehouseID)
+	assert.Equal(t, int64(4), stockMovement.Quantity)
+}
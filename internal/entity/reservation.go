@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ReservationStatusActive    = "active"
+	ReservationStatusCommitted = "committed"
+	ReservationStatusReleased  = "released"
+	ReservationStatusExpired   = "expired"
+)
+
+var (
+	ErrInvalidReservation = errors.New("invalid reservation")
+	// the reservation is committed, released or expired, so it can not be committed or released
+	ErrReservationNotHolding = errors.New("reservation is not active or already expired")
+	ErrReservationNotFound   = errors.New("reservation not found")
+)
+
+// Reservation holds product quantity in warehouses for a limited time,
+// e.g. while the customer is paying. Held quantity is not available for other orders.
+type Reservation struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	ZipCode   string             `json:"zip_code"`
+	Status    string             `json:"status"`
+	Items     []*ReservationItem `json:"items"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+type ReservationItem struct {
+	ID            uuid.UUID `json:"id"`
+	ReservationID uuid.UUID `json:"reservation_id"`
+	WarehouseID   uuid.UUID `json:"warehouse_id"`
+	ProductID     uuid.UUID `json:"product_id"`
+	ProductName   string    `json:"product_name"`
+	Quantity      int64     `json:"quantity"`
+}
+
+// validate new reservation, each product is requested once so its availability is checked against the whole quantity
+func (r *Reservation) Validate() error {
+	if len(r.Items) == 0 {
+		return fmt.Errorf("items are required: %w", ErrInvalidReservation)
+	}
+
+	products := make(map[uuid.UUID]bool, len(r.Items))
+	for _, item := range r.Items {
+		if item.Quantity <= 0 {
+			return fmt.Errorf("quantity of product %s must be positive: %w", item.ProductID, ErrInvalidReservation)
+		}
+		if products[item.ProductID] {
+			return fmt.Errorf("product %s is duplicated: %w", item.ProductID, ErrInvalidReservation)
+		}
+		products[item.ProductID] = true
+	}
+
+	return nil
+}
+
+func (r *Reservation) GenerateReservationID() error {
+	reservationID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	r.ID = reservationID
+	return nil
+}
+
+func (ri *ReservationItem) GenerateReservationItemID() error {
+	reservationItemID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	ri.ID = reservationItemID
+	return nil
+}
+
+// reservation is only holding stock when it is active and not yet expired
+func (r *Reservation) IsHolding(now time.Time) bool {
+	return r.Status == ReservationStatusActive && now.Before(r.ExpiresAt)
+}
@@ -0,0 +1,132 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateReservationID(t *testing.T) {
+	tests := []struct {
+		name        string
+		reservation *Reservation
+		wantPanic   bool
+	}{
+		{
+			name:        "generate id for empty reservation",
+			reservation: &Reservation{},
+		},
+		{
+			name: "generate id for filled reservation",
+			reservation: &Reservation{
+				UserID:    uuid.New(),
+				ZipCode:   "12329",
+				Status:    ReservationStatusActive,
+				ExpiresAt: time.Now().Add(15 * time.Minute),
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			},
+		},
+		{
+			name:        "should panic for nil reservation",
+			reservation: nil,
+			wantPanic:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.wantPanic {
+				assert.Panics(t, func() {
+					_ = tc.reservation.GenerateReservationID()
+				})
+				return
+			}
+
+			reservation := *tc.reservation
+
+			err := tc.reservation.GenerateReservationID()
+
+			assert.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, tc.reservation.ID)
+			assert.Equal(t, uuid.Version(7), tc.reservation.ID.Version())
+			assert.Equal(t, reservation.UserID, tc.reservation.UserID)
+			assert.Equal(t, reservation.Status, tc.reservation.Status)
+			assert.Equal(t, reservation.ExpiresAt, tc.reservation.ExpiresAt)
+		})
+	}
+}
+
+func TestReservationIsHolding(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		reservation *Reservation
+		expected    bool
+	}{
+		{
+			name:        "active and not expired",
+			reservation: &Reservation{Status: ReservationStatusActive, ExpiresAt: now.Add(time.Minute)},
+			expected:    true,
+		},
+		{
+			name:        "active but expired",
+			reservation: &Reservation{Status: ReservationStatusActive, ExpiresAt: now.Add(-time.Minute)},
+			expected:    false,
+		},
+		{
+			name:        "committed",
+			reservation: &Reservation{Status: ReservationStatusCommitted, ExpiresAt: now.Add(time.Minute)},
+			expected:    false,
+		},
+		{
+			name:        "released",
+			reservation: &Reservation{Status: ReservationStatusReleased, ExpiresAt: now.Add(time.Minute)},
+			expected:    false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.reservation.IsHolding(now))
+		})
+	}
+}
+
+func TestReservationValidate(t *testing.T) {
+	productID := uuid.New()
+
+	tests := []struct {
+		name    string
+		items   []*ReservationItem
+		wantErr bool
+	}{
+		{
+			name:  "valid",
+			items: []*ReservationItem{{ProductID: productID, Quantity: 2}, {ProductID: uuid.New(), Quantity: 1}},
+		},
+		{name: "no items", wantErr: true},
+		{name: "zero quantity", items: []*ReservationItem{{ProductID: productID, Quantity: 0}}, wantErr: true},
+		{name: "negative quantity", items: []*ReservationItem{{ProductID: productID, Quantity: -1}}, wantErr: true},
+		{
+			name:    "duplicated product",
+			items:   []*ReservationItem{{ProductID: productID, Quantity: 2}, {ProductID: productID, Quantity: 3}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reservation := &Reservation{Items: tc.items}
+			err := reservation.Validate()
+			if tc.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidReservation)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
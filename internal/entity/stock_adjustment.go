@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidAdjustment is returned when the adjustment can not be applied to the product quantity
+var ErrInvalidAdjustment = errors.New("invalid stock adjustment")
+
+// StockAdjustment corrects the product quantity of one warehouse, either by a delta
+// or by the counted quantity. It is recorded as an adjustment stock movement.
+type StockAdjustment struct {
+	WarehouseID     uuid.UUID `json:"warehouse_id"`
+	ProductID       uuid.UUID `json:"product_id"`
+	Delta           int64     `json:"delta"`
+	CountedQuantity *int64    `json:"counted_quantity,omitempty"` // when set, delta is computed from the current quantity
+	Reason          string    `json:"reason"`
+	ReferenceID     string    `json:"reference_id,omitempty"` // e.g. inventory count id
+	UserID          uuid.UUID `json:"user_id"`                // user doing the adjustment
+	// filled after the adjustment is applied
+	PreviousQuantity int64          `json:"previous_quantity"`
+	NewQuantity      int64          `json:"new_quantity"`
+	StockMovement    *StockMovement `json:"stock_movement,omitempty"`
+	CreatedAt        time.Time      `json:"created_at"`
+}
+
+func (sa *StockAdjustment) Validate() error {
+	if sa.Reason == "" {
+		return fmt.Errorf("reason is required: %w", ErrInvalidAdjustment)
+	}
+	if sa.CountedQuantity != nil {
+		if sa.Delta != 0 {
+			return fmt.Errorf("delta and counted quantity can not be used together: %w", ErrInvalidAdjustment)
+		}
+		if *sa.CountedQuantity < 0 {
+			return fmt.Errorf("counted quantity can not be negative: %w", ErrInvalidAdjustment)
+		}
+		return nil
+	}
+	if sa.Delta == 0 {
+		return fmt.Errorf("delta or counted quantity is required: %w", ErrInvalidAdjustment)
+	}
+	return nil
+}
+
+// set the previous and new quantity based on the current product quantity.
+// Lowering the quantity below the quantity held by active reservations would make the available stock negative
+func (sa *StockAdjustment) Apply(currentQuantity, heldQuantity int64) error {
+	sa.PreviousQuantity = currentQuantity
+	if sa.CountedQuantity != nil {
+		sa.Delta = *sa.CountedQuantity - currentQuantity
+	}
+	sa.NewQuantity = currentQuantity + sa.Delta
+	if sa.NewQuantity < 0 {
+		return fmt.Errorf("product quantity can not be negative: %w", ErrInvalidAdjustment)
+	}
+	if sa.Delta < 0 && sa.NewQuantity < heldQuantity {
+		return fmt.Errorf("product quantity can not be lower than the held quantity %d: %w", heldQuantity, ErrInvalidAdjustment)
+	}
+	return nil
+}
+
+// adjustment movement going into the warehouse for positive delta and out of it for negative delta,
+// returns nil when the quantity is unchanged
+func (sa *StockAdjustment) ToStockMovement(productName string) (*StockMovement, error) {
+	if sa.Delta == 0 {
+		return nil, nil
+	}
+
+	movement := &StockMovement{
+		ProductID:    sa.ProductID,
+		ProductName:  productName,
+		Quantity:     sa.Delta,
+		MovementType: MovementTypeAdjustment,
+		Reason:       sa.Reason,
+		ReferenceID:  sa.ReferenceID,
+		CreatedBy:    sa.UserID,
+		CreatedAt:    sa.CreatedAt,
+	}
+	if sa.Delta > 0 {
+		movement.ToWarehouseID = sa.WarehouseID
+	} else {
+		movement.Quantity = -sa.Delta
+		movement.FromWarehouseID = sa.WarehouseID
+	}
+	if err := movement.GenerateStockMovementID(); err != nil {
+		return nil, err
+	}
+
+	return movement, nil
+}
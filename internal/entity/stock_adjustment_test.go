@@ -0,0 +1,191 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockAdjustmentValidate(t *testing.T) {
+	counted := int64(3)
+	negative := int64(-1)
+
+	tests := []struct {
+		name       string
+		adjustment StockAdjustment
+		wantErr    bool
+	}{
+		{
+			name:       "delta",
+			adjustment: StockAdjustment{Delta: -2, Reason: "damaged"},
+			wantErr:    false,
+		},
+		{
+			name:       "counted quantity",
+			adjustment: StockAdjustment{CountedQuantity: &counted, Reason: "count"},
+			wantErr:    false,
+		},
+		{
+			name:       "missing reason",
+			adjustment: StockAdjustment{Delta: 1},
+			wantErr:    true,
+		},
+		{
+			name:       "missing delta and counted quantity",
+			adjustment: StockAdjustment{Reason: "count"},
+			wantErr:    true,
+		},
+		{
+			name:       "delta and counted quantity",
+			adjustment: StockAdjustment{Delta: 1, CountedQuantity: &counted, Reason: "count"},
+			wantErr:    true,
+		},
+		{
+			name:       "negative counted quantity",
+			adjustment: StockAdjustment{CountedQuantity: &negative, Reason: "count"},
+			wantErr:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.adjustment.Validate()
+			if tc.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidAdjustment)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestStockAdjustmentApply(t *testing.T) {
+	counted := int64(4)
+
+	tests := []struct {
+		name       string
+		adjustment StockAdjustment
+		current    int64
+		held       int64
+		wantDelta  int64
+		wantNew    int64
+		wantErr    bool
+	}{
+		{
+			name:       "positive delta",
+			adjustment: StockAdjustment{Delta: 3},
+			current:    10,
+			wantDelta:  3,
+			wantNew:    13,
+		},
+		{
+			name:       "negative delta",
+			adjustment: StockAdjustment{Delta: -3},
+			current:    10,
+			wantDelta:  -3,
+			wantNew:    7,
+		},
+		{
+			name:       "counted quantity",
+			adjustment: StockAdjustment{CountedQuantity: &counted},
+			current:    10,
+			wantDelta:  -6,
+			wantNew:    4,
+		},
+		{
+			name:       "quantity below zero",
+			adjustment: StockAdjustment{Delta: -11},
+			current:    10,
+			wantErr:    true,
+		},
+		{
+			name:       "negative delta down to the held quantity",
+			adjustment: StockAdjustment{Delta: -6},
+			current:    10,
+			held:       4,
+			wantDelta:  -6,
+			wantNew:    4,
+		},
+		{
+			name:       "negative delta below the held quantity",
+			adjustment: StockAdjustment{Delta: -7},
+			current:    10,
+			held:       4,
+			wantErr:    true,
+		},
+		{
+			name:       "counted quantity below the held quantity",
+			adjustment: StockAdjustment{CountedQuantity: &counted},
+			current:    10,
+			held:       5,
+			wantErr:    true,
+		},
+		{
+			name:       "positive delta while below the held quantity",
+			adjustment: StockAdjustment{Delta: 2},
+			current:    3,
+			held:       8,
+			wantDelta:  2,
+			wantNew:    5,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.adjustment.Apply(tc.current, tc.held)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidAdjustment)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.current, tc.adjustment.PreviousQuantity)
+			assert.Equal(t, tc.wantDelta, tc.adjustment.Delta)
+			assert.Equal(t, tc.wantNew, tc.adjustment.NewQuantity)
+		})
+	}
+}
+
+func TestStockAdjustmentToStockMovement(t *testing.T) {
+	warehouseID := uuid.New()
+	userID := uuid.New()
+	now := time.Now()
+
+	t.Run("positive delta moves into the warehouse", func(t *testing.T) {
+		adjustment := StockAdjustment{WarehouseID: warehouseID, ProductID: uuid.New(), Delta: 2, Reason: "found", UserID: userID, CreatedAt: now}
+
+		movement, err := adjustment.ToStockMovement("Product A")
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, movement.ID)
+		assert.Equal(t, int64(2), movement.Quantity)
+		assert.Equal(t, warehouseID, movement.ToWarehouseID)
+		assert.Equal(t, uuid.Nil, movement.FromWarehouseID)
+		assert.Equal(t, MovementTypeAdjustment, movement.MovementType)
+		assert.Equal(t, "found", movement.Reason)
+		assert.Equal(t, userID, movement.CreatedBy)
+		assert.Equal(t, "Product A", movement.ProductName)
+	})
+
+	t.Run("negative delta moves out of the warehouse", func(t *testing.T) {
+		adjustment := StockAdjustment{WarehouseID: warehouseID, Delta: -5, Reason: "damaged", CreatedAt: now}
+
+		movement, err := adjustment.ToStockMovement("Product A")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), movement.Quantity)
+		assert.Equal(t, warehouseID, movement.FromWarehouseID)
+		assert.Equal(t, uuid.Nil, movement.ToWarehouseID)
+	})
+
+	t.Run("unchanged quantity has no movement", func(t *testing.T) {
+		adjustment := StockAdjustment{WarehouseID: warehouseID}
+
+		movement, err := adjustment.ToStockMovement("Product A")
+
+		assert.NoError(t, err)
+		assert.Nil(t, movement)
+	})
+}
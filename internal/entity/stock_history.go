@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidStockHistory is returned when the on-hand quantity can not be reconstructed at the requested time
+var ErrInvalidStockHistory = errors.New("invalid stock history")
+
+// OpeningBalance is the on-hand quantity of a product in a warehouse before the movement ledger starts,
+// movements after it explain every later quantity
+type OpeningBalance struct {
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	ProductID   uuid.UUID `json:"product_id"`
+	Quantity    int64     `json:"quantity"`
+	AsOf        time.Time `json:"as_of"`
+}
+
+// StockHistory is the on-hand quantity reconstructed from the opening balance and the movements until a point in time
+type StockHistory struct {
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	ProductID   uuid.UUID `json:"product_id"`
+	At          time.Time `json:"at"`
+	// nil when the product has no opening balance, the ledger then starts from zero
+	OpeningBalance   *OpeningBalance `json:"opening_balance"`
+	InboundQuantity  int64           `json:"inbound_quantity"`
+	OutboundQuantity int64           `json:"outbound_quantity"`
+	Quantity         int64           `json:"quantity"`
+}
+
+// StockDiscrepancy is a warehouse product whose current quantity disagrees with its ledger
+type StockDiscrepancy struct {
+	WarehouseID    uuid.UUID `json:"warehouse_id"`
+	ProductID      uuid.UUID `json:"product_id"`
+	ProductName    string    `json:"product_name"`
+	Quantity       int64     `json:"quantity"`
+	LedgerQuantity int64     `json:"ledger_quantity"`
+}
+
+// quantity of the warehouse product which is not explained by the ledger, negative when units are missing
+func (sd *StockDiscrepancy) Difference() int64 {
+	return sd.Quantity - sd.LedgerQuantity
+}
+
+// the quantity before the opening balance is unknown, movements before it are not in the ledger
+func (sh *StockHistory) Validate() error {
+	if sh.OpeningBalance != nil && sh.At.Before(sh.OpeningBalance.AsOf) {
+		return fmt.Errorf("stock history starts at %s: %w", sh.OpeningBalance.AsOf.Format(time.RFC3339), ErrInvalidStockHistory)
+	}
+
+	return nil
+}
+
+func (sh *StockHistory) CalculateQuantity() {
+	sh.Quantity = sh.InboundQuantity - sh.OutboundQuantity
+	if sh.OpeningBalance != nil {
+		sh.Quantity += sh.OpeningBalance.Quantity
+	}
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockHistoryCalculateQuantity(t *testing.T) {
+	stockHistory := &StockHistory{InboundQuantity: 12, OutboundQuantity: 5}
+	stockHistory.CalculateQuantity()
+	assert.Equal(t, int64(7), stockHistory.Quantity)
+
+	stockHistory.OpeningBalance = &OpeningBalance{Quantity: 20}
+	stockHistory.CalculateQuantity()
+	assert.Equal(t, int64(27), stockHistory.Quantity)
+}
+
+func TestStockHistoryValidate(t *testing.T) {
+	asOf := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, (&StockHistory{At: asOf.AddDate(0, 0, -1)}).Validate())
+	assert.NoError(t, (&StockHistory{At: asOf, OpeningBalance: &OpeningBalance{AsOf: asOf}}).Validate())
+	assert.ErrorIs(t, (&StockHistory{At: asOf.AddDate(0, 0, -1), OpeningBalance: &OpeningBalance{AsOf: asOf}}).Validate(), ErrInvalidStockHistory)
+}
+
+func TestStockDiscrepancyDifference(t *testing.T) {
+	assert.Equal(t, int64(-3), (&StockDiscrepancy{Quantity: 7, LedgerQuantity: 10}).Difference())
+}
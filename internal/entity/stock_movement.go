@@ -1,11 +1,120 @@
 package entity
 
 import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const (
+	MovementTypeTransfer   = "transfer"
+	MovementTypeSale       = "sale"
+	MovementTypeReturn     = "return"
+	MovementTypeAdjustment = "adjustment"
+	MovementTypeReceipt    = "receipt"
+	MovementTypeWriteOff   = "write-off"
+	// transfer order leaving the source warehouse and arriving at the destination warehouse
+	MovementTypeTransferShipment = "transfer-shipment"
+	MovementTypeTransferReceipt  = "transfer-receipt"
+	// sale put back to its source warehouse when the order is compensated
+	MovementTypeReversal = "reversal"
+)
+
+func IsValidMovementType(movementType string) bool {
+	switch movementType {
+	case MovementTypeTransfer,
+		MovementTypeSale,
+		MovementTypeReturn,
+		MovementTypeAdjustment,
+		MovementTypeReceipt,
+		MovementTypeWriteOff,
+		MovementTypeTransferShipment,
+		MovementTypeTransferReceipt,
+		MovementTypeReversal:
+		return true
+	}
+	return false
+}
+
+// disposition of returned units
+const (
+	DispositionRestock    = "restock"
+	DispositionQuarantine = "quarantine"
+)
+
+// ErrReturnNotAllowed is returned when the original movement can not be returned
+var ErrReturnNotAllowed = errors.New("return not allowed")
+
+// ErrReversalNotAllowed is returned when the movements of a reference can not be reversed
+var ErrReversalNotAllowed = errors.New("reversal not allowed")
+
+// ErrReferenceCancelled is returned when stock is moved out for a reference that is already compensated, e.g. a cancelled order
+var ErrReferenceCancelled = errors.New("reference is cancelled")
+
+// ErrIdempotencyKeyReused is returned when a used idempotency key is sent with a different request
+var ErrIdempotencyKeyReused = errors.New("idempotency key is already used by a different request")
+
+// operations an idempotency key is scoped to
+const (
+	IdempotencyOperationMoveIn                = "move-in"
+	IdempotencyOperationMoveOut               = "move-out"
+	IdempotencyOperationMoveReturn            = "move-return"
+	IdempotencyOperationInboundReceiptReceive = "inbound-receipt-receive"
+	IdempotencyOperationTransferOrderReceive  = "transfer-order-receive"
+)
+
+// ScopedIdempotencyKey is the key of one caller and operation, so the same key sent by another caller
+// or to another operation is a new request instead of a replay. An empty key stays empty
+func ScopedIdempotencyKey(operation string, callerID uuid.UUID, key string) string {
+	if key == "" {
+		return ""
+	}
+	return operation + ":" + callerID.String() + ":" + key
+}
+
+// UnscopedIdempotencyKey is the key as it was sent by the caller, a key which is not scoped is returned as it is
+func UnscopedIdempotencyKey(scopedKey string) string {
+	parts := strings.SplitN(scopedKey, ":", 3)
+	if len(parts) != 3 {
+		return scopedKey
+	}
+	switch parts[0] {
+	case IdempotencyOperationMoveIn,
+		IdempotencyOperationMoveOut,
+		IdempotencyOperationMoveReturn,
+		IdempotencyOperationInboundReceiptReceive,
+		IdempotencyOperationTransferOrderReceive:
+	default:
+		return scopedKey
+	}
+	if _, err := uuid.Parse(parts[1]); err != nil {
+		return scopedKey
+	}
+	return parts[2]
+}
+
+// StockMovementQuantities sums the moved quantity of each product
+func StockMovementQuantities(movements []*StockMovement) map[uuid.UUID]int64 {
+	quantities := make(map[uuid.UUID]int64, len(movements))
+	for _, movement := range movements {
+		quantities[movement.ProductID] += movement.Quantity
+	}
+	return quantities
+}
+
+// MatchIdempotentStockMovements checks the movements created with a key move the requested quantity of each product,
+// otherwise the key is reused by a different request
+func MatchIdempotentStockMovements(existing []*StockMovement, requested map[uuid.UUID]int64) error {
+	if !maps.Equal(StockMovementQuantities(existing), requested) {
+		return ErrIdempotencyKeyReused
+	}
+	return nil
+}
+
 type StockMovement struct {
 	ID              uuid.UUID `json:"id"`
 	ProductID       uuid.UUID `json:"product_id"`
@@ -13,8 +122,30 @@ type StockMovement struct {
 	Quantity        int64     `json:"quantity"`
 	FromWarehouseID uuid.UUID `json:"from_warehouse_id"`
 	ToWarehouseID   uuid.UUID `json:"to_warehouse_id"`
-	ToUserID        uuid.UUID `json:"to_user_id"` // for moving out to user (DELIVERED)
-	CreatedAt       time.Time `json:"created_at"`
+	ToUserID        uuid.UUID `json:"to_user_id"`   // for moving out to user (DELIVERED)
+	FromUserID      uuid.UUID `json:"from_user_id"` // for returning from user to warehouse
+	MovementType    string    `json:"movement_type"`
+	// outbound movement that a return is linked to
+	OriginalMovementID uuid.UUID `json:"original_movement_id"`
+	Disposition        string    `json:"disposition,omitempty"`
+	Reason             string    `json:"reason,omitempty"`
+	ReferenceID        string    `json:"reference_id,omitempty"`    // order id, purchase order number, etc
+	CreatedBy          uuid.UUID `json:"created_by"`                // user who recorded the movement, e.g. for adjustments
+	IdempotencyKey     string    `json:"idempotency_key,omitempty"` // same key returns the original movements instead of moving again
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// the key is stored scoped by the operation and the caller, the caller only sees the key it sent
+func (sm StockMovement) MarshalJSON() ([]byte, error) {
+	type stockMovement StockMovement
+	sm.IdempotencyKey = UnscopedIdempotencyKey(sm.IdempotencyKey)
+	return json.Marshal(stockMovement(sm))
+}
+
+// empty fields are not filtered
+type StockMovementFilter struct {
+	MovementType string
+	ReferenceID  string
 }
 
 func (sm *StockMovement) GenerateStockMovementID() error {
@@ -26,3 +157,31 @@ func (sm *StockMovement) GenerateStockMovementID() error {
 	sm.ID = stockMovementID
 	return nil
 }
+
+// reversal of the sale puts the quantity which is not returned yet back to the warehouse it left from,
+// returns nil when the whole sale is already returned or reversed.
+// A sale is reversed at most once, so its key is derived from the reference and the sale
+func (sm *StockMovement) Reverse(returnedQuantity int64, reason string, reversedAt time.Time) (*StockMovement, error) {
+	if returnedQuantity >= sm.Quantity {
+		return nil, nil
+	}
+
+	reversal := &StockMovement{
+		ProductID:          sm.ProductID,
+		ProductName:        sm.ProductName,
+		Quantity:           sm.Quantity - returnedQuantity,
+		ToWarehouseID:      sm.FromWarehouseID,
+		FromUserID:         sm.ToUserID,
+		MovementType:       MovementTypeReversal,
+		OriginalMovementID: sm.ID,
+		Reason:             reason,
+		ReferenceID:        sm.ReferenceID,
+		IdempotencyKey:     MovementTypeReversal + ":" + sm.ReferenceID + ":" + sm.ID.String(),
+		CreatedAt:          reversedAt,
+	}
+	if err := reversal.GenerateStockMovementID(); err != nil {
+		return nil, err
+	}
+
+	return reversal, nil
+}
@@ -68,3 +68,133 @@ func TestGenerateStockMovementID(t *testing.T) {
 		})
 	}
 }
+
+func TestIsValidMovementType(t *testing.T) {
+	tests := []struct {
+		movementType string
+		want         bool
+	}{
+		{MovementTypeTransfer, true},
+		{MovementTypeSale, true},
+		{MovementTypeReturn, true},
+		{MovementTypeAdjustment, true},
+		{MovementTypeReceipt, true},
+		{MovementTypeWriteOff, true},
+		{MovementTypeTransferShipment, true},
+		{MovementTypeTransferReceipt, true},
+		{MovementTypeReversal, true},
+		{"", false},
+		{"refund", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.movementType, func(t *testing.T) {
+			assert.Equal(t, tc.want, IsValidMovementType(tc.movementType))
+		})
+	}
+}
+
+func TestStockMovementReverse(t *testing.T) {
+	sale := &StockMovement{
+		ID:              uuid.New(),
+		ProductID:       uuid.New(),
+		Quantity:        5,
+		FromWarehouseID: uuid.New(),
+		ToUserID:        uuid.New(),
+		MovementType:    MovementTypeSale,
+		ReferenceID:     "order-1",
+	}
+	reversedAt := time.Now()
+
+	reversal, err := sale.Reverse(2, "payment failed", reversedAt)
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, reversal.ID)
+	assert.Equal(t, int64(3), reversal.Quantity)
+	assert.Equal(t, sale.FromWarehouseID, reversal.ToWarehouseID)
+	assert.Equal(t, sale.ToUserID, reversal.FromUserID)
+	assert.Equal(t, sale.ID, reversal.OriginalMovementID)
+	assert.Equal(t, MovementTypeReversal, reversal.MovementType)
+	assert.Equal(t, "order-1", reversal.ReferenceID)
+	assert.Equal(t, "reversal:order-1:"+sale.ID.String(), reversal.IdempotencyKey)
+
+	// nothing is left when the whole sale is returned
+	reversal, err = sale.Reverse(5, "payment failed", reversedAt)
+
+	assert.NoError(t, err)
+	assert.Nil(t, reversal)
+}
+
+func TestScopedIdempotencyKey(t *testing.T) {
+	callerID := uuid.MustParse("019444a2-e318-79b5-8fe4-b32716306083")
+
+	assert.Equal(t, "", ScopedIdempotencyKey(IdempotencyOperationMoveOut, callerID, ""))
+	assert.Equal(t, "move-out:019444a2-e318-79b5-8fe4-b32716306083:order-1",
+		ScopedIdempotencyKey(IdempotencyOperationMoveOut, callerID, "order-1"))
+	assert.NotEqual(t,
+		ScopedIdempotencyKey(IdempotencyOperationMoveOut, callerID, "order-1"),
+		ScopedIdempotencyKey(IdempotencyOperationMoveIn, callerID, "order-1"))
+	assert.NotEqual(t,
+		ScopedIdempotencyKey(IdempotencyOperationMoveOut, callerID, "order-1"),
+		ScopedIdempotencyKey(IdempotencyOperationMoveOut, uuid.New(), "order-1"))
+}
+
+func TestUnscopedIdempotencyKey(t *testing.T) {
+	callerID := uuid.New()
+
+	tests := []struct {
+		name string
+		key  string
+		want string
+	}{
+		{name: "scoped", key: ScopedIdempotencyKey(IdempotencyOperationMoveOut, callerID, "order-1"), want: "order-1"},
+		{name: "scoped key with colon", key: ScopedIdempotencyKey(IdempotencyOperationMoveIn, callerID, "po:1"), want: "po:1"},
+		{name: "not scoped", key: "order-1", want: "order-1"},
+		{name: "unknown operation", key: "order-created:" + callerID.String() + ":1", want: "order-created:" + callerID.String() + ":1"},
+		{name: "empty", key: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, UnscopedIdempotencyKey(tt.key))
+		})
+	}
+}
+
+func TestMatchIdempotentStockMovements(t *testing.T) {
+	productA := uuid.New()
+	productB := uuid.New()
+	// the first request moved out product A from two warehouses
+	existing := []*StockMovement{
+		{ProductID: productA, Quantity: 3},
+		{ProductID: productA, Quantity: 2},
+		{ProductID: productB, Quantity: 1},
+	}
+
+	tests := []struct {
+		name      string
+		requested map[uuid.UUID]int64
+		err       error
+	}{
+		{
+			name:      "same request",
+			requested: map[uuid.UUID]int64{productA: 5, productB: 1},
+		},
+		{
+			name:      "different quantity",
+			requested: map[uuid.UUID]int64{productA: 4, productB: 1},
+			err:       ErrIdempotencyKeyReused,
+		},
+		{
+			name:      "different products",
+			requested: map[uuid.UUID]int64{productA: 5},
+			err:       ErrIdempotencyKeyReused,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, MatchIdempotentStockMovements(existing, tt.requested), tt.err)
+		})
+	}
+}
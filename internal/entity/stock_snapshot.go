@@ -0,0 +1,127 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidStockSnapshot is returned when the requested snapshot dates are not a valid range
+var ErrInvalidStockSnapshot = errors.New("invalid stock snapshot")
+
+// StockSnapshot is the quantity and value of a product in a warehouse at the end of a day
+type StockSnapshot struct {
+	SnapshotDate time.Time `json:"snapshot_date"`
+	WarehouseID  uuid.UUID `json:"warehouse_id"`
+	ProductID    uuid.UUID `json:"product_id"`
+	ProductName  string    `json:"product_name"`
+	Quantity     int64     `json:"quantity"`
+	ProductPrice float64   `json:"product_price"`
+	Value        float64   `json:"value"` // product price * quantity
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// StockSnapshotFilter selects snapshots between two dates, a nil warehouse or product id matches every warehouse or product
+type StockSnapshotFilter struct {
+	From        time.Time
+	To          time.Time
+	WarehouseID uuid.UUID
+	ProductID   uuid.UUID
+}
+
+// total quantity and value of the filtered snapshots of one day
+type StockTrendPoint struct {
+	SnapshotDate time.Time `json:"snapshot_date"`
+	Quantity     int64     `json:"quantity"`
+	Value        float64   `json:"value"`
+}
+
+type StockComparison struct {
+	From           time.Time              `json:"from"`
+	To             time.Time              `json:"to"`
+	QuantityChange int64                  `json:"quantity_change"`
+	ValueChange    float64                `json:"value_change"`
+	Lines          []*StockComparisonLine `json:"lines"`
+}
+
+type StockComparisonLine struct {
+	WarehouseID    uuid.UUID `json:"warehouse_id"`
+	ProductID      uuid.UUID `json:"product_id"`
+	ProductName    string    `json:"product_name"`
+	FromQuantity   int64     `json:"from_quantity"`
+	ToQuantity     int64     `json:"to_quantity"`
+	FromValue      float64   `json:"from_value"`
+	ToValue        float64   `json:"to_value"`
+	QuantityChange int64     `json:"quantity_change"`
+	ValueChange    float64   `json:"value_change"`
+}
+
+// snapshots are taken per local day, the date is stored without time zone
+func SnapshotDate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func (f *StockSnapshotFilter) Validate() error {
+	if f.From.IsZero() || f.To.IsZero() {
+		return fmt.Errorf("from and to dates are required: %w", ErrInvalidStockSnapshot)
+	}
+	if f.From.After(f.To) {
+		return fmt.Errorf("from date must not be after to date: %w", ErrInvalidStockSnapshot)
+	}
+
+	return nil
+}
+
+// compare the snapshots of two dates per warehouse product. A product missing from one of the dates
+// has zero quantity and value on that date.
+func CompareStockSnapshots(from, to time.Time, fromSnapshots, toSnapshots []*StockSnapshot) *StockComparison {
+	type key struct {
+		warehouseID uuid.UUID
+		productID   uuid.UUID
+	}
+
+	lines := make(map[key]*StockComparisonLine)
+	line := func(snapshot *StockSnapshot) *StockComparisonLine {
+		k := key{snapshot.WarehouseID, snapshot.ProductID}
+		if _, ok := lines[k]; !ok {
+			lines[k] = &StockComparisonLine{
+				WarehouseID: snapshot.WarehouseID,
+				ProductID:   snapshot.ProductID,
+				ProductName: snapshot.ProductName,
+			}
+		}
+		return lines[k]
+	}
+	for _, snapshot := range fromSnapshots {
+		l := line(snapshot)
+		l.FromQuantity = snapshot.Quantity
+		l.FromValue = snapshot.Value
+	}
+	for _, snapshot := range toSnapshots {
+		l := line(snapshot)
+		l.ProductName = snapshot.ProductName
+		l.ToQuantity = snapshot.Quantity
+		l.ToValue = snapshot.Value
+	}
+
+	comparison := &StockComparison{From: from, To: to, Lines: make([]*StockComparisonLine, 0, len(lines))}
+	for _, l := range lines {
+		l.QuantityChange = l.ToQuantity - l.FromQuantity
+		l.ValueChange = l.ToValue - l.FromValue
+		comparison.QuantityChange += l.QuantityChange
+		comparison.ValueChange += l.ValueChange
+		comparison.Lines = append(comparison.Lines, l)
+	}
+	sort.Slice(comparison.Lines, func(i, j int) bool {
+		if comparison.Lines[i].WarehouseID != comparison.Lines[j].WarehouseID {
+			return comparison.Lines[i].WarehouseID.String() < comparison.Lines[j].WarehouseID.String()
+		}
+		return comparison.Lines[i].ProductID.String() < comparison.Lines[j].ProductID.String()
+	})
+
+	return comparison
+}
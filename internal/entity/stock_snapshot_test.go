@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotDate(t *testing.T) {
+	local := time.FixedZone("UTC+7", 7*60*60)
+
+	assert.Equal(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), SnapshotDate(time.Date(2024, 3, 2, 1, 30, 0, 0, local)))
+}
+
+func TestStockSnapshotFilterValidate(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, (&StockSnapshotFilter{From: from, To: from}).Validate())
+	assert.ErrorIs(t, (&StockSnapshotFilter{From: from}).Validate(), ErrInvalidStockSnapshot)
+	assert.ErrorIs(t, (&StockSnapshotFilter{From: from, To: from.AddDate(0, 0, -1)}).Validate(), ErrInvalidStockSnapshot)
+}
+
+func TestCompareStockSnapshots(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	warehouseID := uuid.New()
+	productA := uuid.New()
+	productB := uuid.New()
+	productC := uuid.New()
+
+	fromSnapshots := []*StockSnapshot{
+		{WarehouseID: warehouseID, ProductID: productA, Quantity: 10, Value: 100},
+		{WarehouseID: warehouseID, ProductID: productB, Quantity: 4, Value: 20},
+	}
+	toSnapshots := []*StockSnapshot{
+		{WarehouseID: warehouseID, ProductID: productA, Quantity: 6, Value: 60},
+		{WarehouseID: warehouseID, ProductID: productC, Quantity: 3, Value: 45},
+	}
+
+	comparison := CompareStockSnapshots(from, to, fromSnapshots, toSnapshots)
+
+	// products missing from one date count as zero on that date
+	assert.Len(t, comparison.Lines, 3)
+	assert.Equal(t, int64(-5), comparison.QuantityChange)
+	assert.InDelta(t, -15.0, comparison.ValueChange, 0.0001)
+
+	changes := make(map[uuid.UUID]int64)
+	for _, line := range comparison.Lines {
+		changes[line.ProductID] = line.QuantityChange
+	}
+	assert.Equal(t, map[uuid.UUID]int64{productA: -4, productB: -4, productC: 3}, changes)
+}
@@ -0,0 +1,129 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidStockThreshold is returned when the reorder point or target level of the threshold is not valid
+var ErrInvalidStockThreshold = errors.New("invalid stock threshold")
+
+// StockThreshold is the reorder point and target level of a product in a warehouse.
+// Product is low on stock when its quantity reaches the reorder point, and it is replenished up to the target level.
+type StockThreshold struct {
+	ID           uuid.UUID `json:"id"`
+	WarehouseID  uuid.UUID `json:"warehouse_id"`
+	ProductID    uuid.UUID `json:"product_id"`
+	ReorderPoint int64     `json:"reorder_point"`
+	TargetLevel  int64     `json:"target_level"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// StockLevel is the current stock of a product in a warehouse compared to its threshold
+type StockLevel struct {
+	WarehouseID       uuid.UUID `json:"warehouse_id"`
+	ProductID         uuid.UUID `json:"product_id"`
+	ProductName       string    `json:"product_name"`
+	AvailableQuantity int64     `json:"available_quantity"`  // quantity not held by reservations
+	InTransitQuantity int64     `json:"in_transit_quantity"` // shipped to the warehouse by transfer orders
+	ReorderPoint      int64     `json:"reorder_point"`
+	TargetLevel       int64     `json:"target_level"`
+	// the stock was already low on the previous evaluation, so it is not notified again
+	WasLow bool `json:"-"`
+}
+
+// ReplenishmentSuggestion is a proposed transfer to fill the gap of a low stock
+type ReplenishmentSuggestion struct {
+	ProductID       uuid.UUID `json:"product_id"`
+	ProductName     string    `json:"product_name"`
+	FromWarehouseID uuid.UUID `json:"from_warehouse_id"`
+	ToWarehouseID   uuid.UUID `json:"to_warehouse_id"`
+	Quantity        int64     `json:"quantity"`
+	Shortage        int64     `json:"shortage"` // quantity needed to reach the target level
+}
+
+func (st *StockThreshold) GenerateStockThresholdID() error {
+	stockThresholdID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	st.ID = stockThresholdID
+	return nil
+}
+
+func (st *StockThreshold) Validate() error {
+	if st.ReorderPoint < 0 {
+		return fmt.Errorf("reorder point can not be negative: %w", ErrInvalidStockThreshold)
+	}
+	if st.TargetLevel <= st.ReorderPoint {
+		return fmt.Errorf("target level must be greater than reorder point: %w", ErrInvalidStockThreshold)
+	}
+
+	return nil
+}
+
+// quantity the warehouse will have when the in-transit transfers arrive
+func (sl *StockLevel) Position() int64 {
+	return sl.AvailableQuantity + sl.InTransitQuantity
+}
+
+func (sl *StockLevel) IsLow() bool {
+	return sl.Position() <= sl.ReorderPoint
+}
+
+func (sl *StockLevel) Shortage() int64 {
+	if sl.Position() >= sl.TargetLevel {
+		return 0
+	}
+	return sl.TargetLevel - sl.Position()
+}
+
+// propose transfers from the main warehouse to the low stocks of other warehouses.
+// The biggest shortage is filled first, so the available quantity of the main warehouse
+// goes to the warehouses that need it most. Low stocks which can not be filled at all are skipped.
+func SuggestReplenishments(
+	mainWarehouseID uuid.UUID,
+	lowStocks []*StockLevel,
+	mainAvailable map[uuid.UUID]int64,
+) []*ReplenishmentSuggestion {
+	sorted := make([]*StockLevel, 0, len(lowStocks))
+	for _, lowStock := range lowStocks {
+		if lowStock.WarehouseID != mainWarehouseID && lowStock.Shortage() > 0 {
+			sorted = append(sorted, lowStock)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Shortage() > sorted[j].Shortage()
+	})
+
+	remaining := make(map[uuid.UUID]int64, len(mainAvailable))
+	for productID, quantity := range mainAvailable {
+		remaining[productID] = quantity
+	}
+
+	var suggestions []*ReplenishmentSuggestion
+	for _, lowStock := range sorted {
+		quantity := min(lowStock.Shortage(), remaining[lowStock.ProductID])
+		if quantity <= 0 {
+			continue
+		}
+		remaining[lowStock.ProductID] -= quantity
+
+		suggestions = append(suggestions, &ReplenishmentSuggestion{
+			ProductID:       lowStock.ProductID,
+			ProductName:     lowStock.ProductName,
+			FromWarehouseID: mainWarehouseID,
+			ToWarehouseID:   lowStock.WarehouseID,
+			Quantity:        quantity,
+			Shortage:        lowStock.Shortage(),
+		})
+	}
+
+	return suggestions
+}
@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockThresholdValidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		reorderPoint int64
+		targetLevel  int64
+		wantErr      bool
+	}{
+		{name: "valid", reorderPoint: 5, targetLevel: 20},
+		{name: "zero reorder point", reorderPoint: 0, targetLevel: 1},
+		{name: "negative reorder point", reorderPoint: -1, targetLevel: 20, wantErr: true},
+		{name: "target level equal to reorder point", reorderPoint: 5, targetLevel: 5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stockThreshold := &StockThreshold{ReorderPoint: tt.reorderPoint, TargetLevel: tt.targetLevel}
+			err := stockThreshold.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidStockThreshold)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestStockLevel(t *testing.T) {
+	stockLevel := &StockLevel{AvailableQuantity: 3, InTransitQuantity: 2, ReorderPoint: 5, TargetLevel: 20}
+
+	assert.Equal(t, int64(5), stockLevel.Position())
+	assert.True(t, stockLevel.IsLow())
+	assert.Equal(t, int64(15), stockLevel.Shortage())
+
+	stockLevel.InTransitQuantity = 30
+	assert.False(t, stockLevel.IsLow())
+	assert.Equal(t, int64(0), stockLevel.Shortage())
+}
+
+func TestSuggestReplenishments(t *testing.T) {
+	mainWarehouseID := uuid.New()
+	warehouseA := uuid.New()
+	warehouseB := uuid.New()
+	productID := uuid.New()
+	otherProductID := uuid.New()
+
+	lowStocks := []*StockLevel{
+		{WarehouseID: warehouseA, ProductID: productID, AvailableQuantity: 4, ReorderPoint: 5, TargetLevel: 10},
+		{WarehouseID: warehouseB, ProductID: productID, AvailableQuantity: 0, ReorderPoint: 5, TargetLevel: 10},
+		{WarehouseID: mainWarehouseID, ProductID: productID, AvailableQuantity: 1, ReorderPoint: 5, TargetLevel: 10},
+		{WarehouseID: warehouseA, ProductID: otherProductID, AvailableQuantity: 0, ReorderPoint: 1, TargetLevel: 3},
+	}
+	mainAvailable := map[uuid.UUID]int64{productID: 12}
+
+	suggestions := SuggestReplenishments(mainWarehouseID, lowStocks, mainAvailable)
+
+	// warehouse B has the biggest shortage so it is filled first, product without main stock is skipped
+	assert.Len(t, suggestions, 2)
+	assert.Equal(t, warehouseB, suggestions[0].ToWarehouseID)
+	assert.Equal(t, mainWarehouseID, suggestions[0].FromWarehouseID)
+	assert.Equal(t, int64(10), suggestions[0].Quantity)
+	assert.Equal(t, warehouseA, suggestions[1].ToWarehouseID)
+	assert.Equal(t, int64(2), suggestions[1].Quantity)
+	assert.Equal(t, int64(6), suggestions[1].Shortage)
+	assert.Equal(t, int64(12), mainAvailable[productID])
+}
@@ -0,0 +1,213 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	TransferOrderStatusRequested = "requested"
+	TransferOrderStatusPicked    = "picked"
+	TransferOrderStatusInTransit = "in-transit"
+	TransferOrderStatusReceived  = "received"
+	TransferOrderStatusCancelled = "cancelled"
+)
+
+// allowed next statuses of each transfer order status, received and cancelled are final
+var transferOrderTransitions = map[string][]string{
+	TransferOrderStatusRequested: {TransferOrderStatusPicked, TransferOrderStatusCancelled},
+	TransferOrderStatusPicked:    {TransferOrderStatusInTransit, TransferOrderStatusCancelled},
+	TransferOrderStatusInTransit: {TransferOrderStatusReceived},
+}
+
+// ErrInvalidTransferOrder is returned when the transfer order can not move to the requested status
+// or the delivery does not match the shipped lines
+var ErrInvalidTransferOrder = errors.New("invalid transfer order")
+
+// TransferOrder moves products between warehouses in several steps. Shipping takes the quantity
+// out of the source warehouse into transit, receiving puts it into the destination warehouse.
+type TransferOrder struct {
+	ID              uuid.UUID            `json:"id"`
+	FromWarehouseID uuid.UUID            `json:"from_warehouse_id"`
+	ToWarehouseID   uuid.UUID            `json:"to_warehouse_id"`
+	Status          string               `json:"status"`
+	Reason          string               `json:"reason,omitempty"`
+	Lines           []*TransferOrderLine `json:"lines"`
+	CreatedBy       uuid.UUID            `json:"created_by"`
+	UpdatedBy       uuid.UUID            `json:"updated_by"`
+	// shipment or receipt movements of the last step
+	StockMovements []*StockMovement `json:"stock_movements,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+type TransferOrderLine struct {
+	ID               uuid.UUID `json:"id"`
+	TransferOrderID  uuid.UUID `json:"transfer_order_id"`
+	ProductID        uuid.UUID `json:"product_id"`
+	ProductName      string    `json:"product_name"`
+	Quantity         int64     `json:"quantity"`
+	ShippedQuantity  int64     `json:"shipped_quantity"`
+	ReceivedQuantity int64     `json:"received_quantity"`
+	// shipped but never received, recorded when the receipt is closed
+	DiscrepancyQuantity int64 `json:"discrepancy_quantity"`
+}
+
+// TransferOrderDelivery is the quantity arriving at the destination warehouse
+type TransferOrderDelivery struct {
+	Lines []*TransferOrderDeliveryLine `json:"lines"`
+	// remaining in-transit quantity is recorded as discrepancy and the transfer order is received
+	Close          bool   `json:"close"`
+	IdempotencyKey string `json:"-"`
+}
+
+type TransferOrderDeliveryLine struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Quantity  int64     `json:"quantity"`
+}
+
+// in-transit quantity of a product, in total and per transfer order
+type ProductInTransit struct {
+	ProductID      uuid.UUID                 `json:"product_id"`
+	Quantity       int64                     `json:"quantity"`
+	TransferOrders []*TransferOrderInTransit `json:"transfer_orders"`
+}
+
+type TransferOrderInTransit struct {
+	TransferOrderID uuid.UUID `json:"transfer_order_id"`
+	FromWarehouseID uuid.UUID `json:"from_warehouse_id"`
+	ToWarehouseID   uuid.UUID `json:"to_warehouse_id"`
+	Quantity        int64     `json:"quantity"`
+}
+
+func (to *TransferOrder) GenerateTransferOrderID() error {
+	transferOrderID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	to.ID = transferOrderID
+	return nil
+}
+
+func (tol *TransferOrderLine) GenerateTransferOrderLineID() error {
+	transferOrderLineID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	tol.ID = transferOrderLineID
+	return nil
+}
+
+// quantity which left the source warehouse but is not in the destination warehouse yet
+func (tol *TransferOrderLine) InTransitQuantity() int64 {
+	return tol.ShippedQuantity - tol.ReceivedQuantity - tol.DiscrepancyQuantity
+}
+
+// validate new transfer order, every line needs a positive quantity
+func (to *TransferOrder) Validate() error {
+	if to.FromWarehouseID == to.ToWarehouseID {
+		return fmt.Errorf("source and destination warehouse must be different: %w", ErrInvalidTransferOrder)
+	}
+	if len(to.Lines) == 0 {
+		return fmt.Errorf("lines are required: %w", ErrInvalidTransferOrder)
+	}
+
+	products := make(map[uuid.UUID]bool, len(to.Lines))
+	for _, line := range to.Lines {
+		if line.Quantity <= 0 {
+			return fmt.Errorf("quantity of product %s must be positive: %w", line.ProductID, ErrInvalidTransferOrder)
+		}
+		if products[line.ProductID] {
+			return fmt.Errorf("product %s is duplicated: %w", line.ProductID, ErrInvalidTransferOrder)
+		}
+		products[line.ProductID] = true
+	}
+
+	return nil
+}
+
+func (to *TransferOrder) CanTransitionTo(status string) bool {
+	for _, next := range transferOrderTransitions[to.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// move transfer order to the next status, the status is not changed when the transition is not allowed
+func (to *TransferOrder) TransitionTo(status string) error {
+	if !to.CanTransitionTo(status) {
+		return fmt.Errorf("transfer order can not be %s when it is %s: %w", status, to.Status, ErrInvalidTransferOrder)
+	}
+
+	to.Status = status
+	return nil
+}
+
+// lines must have positive quantity and each product can only be in one line,
+// delivery without lines is only allowed to close the receipt
+func (d *TransferOrderDelivery) Validate() error {
+	if len(d.Lines) == 0 && !d.Close {
+		return fmt.Errorf("lines are required: %w", ErrInvalidTransferOrder)
+	}
+
+	products := make(map[uuid.UUID]bool, len(d.Lines))
+	for _, line := range d.Lines {
+		if line.Quantity <= 0 {
+			return fmt.Errorf("quantity of product %s must be positive: %w", line.ProductID, ErrInvalidTransferOrder)
+		}
+		if products[line.ProductID] {
+			return fmt.Errorf("product %s is duplicated: %w", line.ProductID, ErrInvalidTransferOrder)
+		}
+		products[line.ProductID] = true
+	}
+
+	return nil
+}
+
+// receive the delivery into the lines of the in-transit transfer order. Receiving more than
+// the in-transit quantity is not allowed. The transfer order is received when nothing is in transit anymore,
+// closing the delivery records the remaining in-transit quantity as discrepancy.
+func (to *TransferOrder) Receive(delivery *TransferOrderDelivery) error {
+	if to.Status != TransferOrderStatusInTransit {
+		return fmt.Errorf("transfer order can not be received when it is %s: %w", to.Status, ErrInvalidTransferOrder)
+	}
+
+	lines := make(map[uuid.UUID]*TransferOrderLine, len(to.Lines))
+	for _, line := range to.Lines {
+		lines[line.ProductID] = line
+	}
+
+	for _, delivered := range delivery.Lines {
+		line, ok := lines[delivered.ProductID]
+		if !ok {
+			return fmt.Errorf("product %s is not in the transfer order: %w", delivered.ProductID, ErrInvalidTransferOrder)
+		}
+		if delivered.Quantity > line.InTransitQuantity() {
+			return fmt.Errorf("received quantity of product %s is more than in-transit quantity %d: %w",
+				delivered.ProductID, line.InTransitQuantity(), ErrInvalidTransferOrder)
+		}
+		line.ReceivedQuantity += delivered.Quantity
+	}
+
+	inTransit := false
+	for _, line := range to.Lines {
+		if delivery.Close {
+			line.DiscrepancyQuantity += line.InTransitQuantity()
+		}
+		if line.InTransitQuantity() > 0 {
+			inTransit = true
+		}
+	}
+
+	if !inTransit {
+		to.Status = TransferOrderStatusReceived
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferOrderValidate(t *testing.T) {
+	warehouseID := uuid.New()
+	productID := uuid.New()
+
+	tests := []struct {
+		name          string
+		transferOrder *TransferOrder
+		wantErr       bool
+	}{
+		{
+			name: "valid",
+			transferOrder: &TransferOrder{
+				FromWarehouseID: warehouseID,
+				ToWarehouseID:   uuid.New(),
+				Lines:           []*TransferOrderLine{{ProductID: productID, Quantity: 5}},
+			},
+		},
+		{
+			name: "same warehouse",
+			transferOrder: &TransferOrder{
+				FromWarehouseID: warehouseID,
+				ToWarehouseID:   warehouseID,
+				Lines:           []*TransferOrderLine{{ProductID: productID, Quantity: 5}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "no lines",
+			transferOrder: &TransferOrder{
+				FromWarehouseID: warehouseID,
+				ToWarehouseID:   uuid.New(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicated product",
+			transferOrder: &TransferOrder{
+				FromWarehouseID: warehouseID,
+				ToWarehouseID:   uuid.New(),
+				Lines: []*TransferOrderLine{
+					{ProductID: productID, Quantity: 5},
+					{ProductID: productID, Quantity: 1},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.transferOrder.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidTransferOrder)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTransferOrderTransitionTo(t *testing.T) {
+	tests := []struct {
+		from    string
+		to      string
+		allowed bool
+	}{
+		{TransferOrderStatusRequested, TransferOrderStatusPicked, true},
+		{TransferOrderStatusRequested, TransferOrderStatusInTransit, false},
+		{TransferOrderStatusRequested, TransferOrderStatusCancelled, true},
+		{TransferOrderStatusPicked, TransferOrderStatusInTransit, true},
+		{TransferOrderStatusPicked, TransferOrderStatusCancelled, true},
+		{TransferOrderStatusInTransit, TransferOrderStatusCancelled, false},
+		{TransferOrderStatusInTransit, TransferOrderStatusReceived, true},
+		{TransferOrderStatusReceived, TransferOrderStatusInTransit, false},
+		{TransferOrderStatusCancelled, TransferOrderStatusPicked, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			transferOrder := &TransferOrder{Status: tt.from}
+			err := transferOrder.TransitionTo(tt.to)
+			if !tt.allowed {
+				assert.ErrorIs(t, err, ErrInvalidTransferOrder)
+				assert.Equal(t, tt.from, transferOrder.Status)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.to, transferOrder.Status)
+		})
+	}
+}
+
+func TestTransferOrderDeliveryValidate(t *testing.T) {
+	productID := uuid.New()
+
+	assert.NoError(t, (&TransferOrderDelivery{Lines: []*TransferOrderDeliveryLine{{ProductID: productID, Quantity: 1}}}).Validate())
+	assert.NoError(t, (&TransferOrderDelivery{Close: true}).Validate())
+	assert.ErrorIs(t, (&TransferOrderDelivery{}).Validate(), ErrInvalidTransferOrder)
+	assert.ErrorIs(t, (&TransferOrderDelivery{Lines: []*TransferOrderDeliveryLine{{ProductID: productID}}}).Validate(), ErrInvalidTransferOrder)
+}
+
+func TestTransferOrderReceive(t *testing.T) {
+	productA := uuid.New()
+	productB := uuid.New()
+	newTransferOrder := func() *TransferOrder {
+		return &TransferOrder{
+			Status: TransferOrderStatusInTransit,
+			Lines: []*TransferOrderLine{
+				{ProductID: productA, Quantity: 10, ShippedQuantity: 10},
+				{ProductID: productB, Quantity: 4, ShippedQuantity: 4},
+			},
+		}
+	}
+
+	t.Run("partial receipt stays in transit", func(t *testing.T) {
+		transferOrder := newTransferOrder()
+		err := transferOrder.Receive(&TransferOrderDelivery{
+			Lines: []*TransferOrderDeliveryLine{{ProductID: productA, Quantity: 6}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, TransferOrderStatusInTransit, transferOrder.Status)
+		assert.Equal(t, int64(6), transferOrder.Lines[0].ReceivedQuantity)
+		assert.Equal(t, int64(4), transferOrder.Lines[0].InTransitQuantity())
+		assert.Equal(t, int64(4), transferOrder.Lines[1].InTransitQuantity())
+	})
+
+	t.Run("full receipt", func(t *testing.T) {
+		transferOrder := newTransferOrder()
+		err := transferOrder.Receive(&TransferOrderDelivery{
+			Lines: []*TransferOrderDeliveryLine{
+				{ProductID: productA, Quantity: 10},
+				{ProductID: productB, Quantity: 4},
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, TransferOrderStatusReceived, transferOrder.Status)
+	})
+
+	t.Run("closed receipt records discrepancy", func(t *testing.T) {
+		transferOrder := newTransferOrder()
+		err := transferOrder.Receive(&TransferOrderDelivery{
+			Lines: []*TransferOrderDeliveryLine{{ProductID: productA, Quantity: 9}},
+			Close: true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, TransferOrderStatusReceived, transferOrder.Status)
+		assert.Equal(t, int64(1), transferOrder.Lines[0].DiscrepancyQuantity)
+		assert.Equal(t, int64(4), transferOrder.Lines[1].DiscrepancyQuantity)
+		assert.Equal(t, int64(0), transferOrder.Lines[1].InTransitQuantity())
+	})
+
+	t.Run("more than in transit", func(t *testing.T) {
+		transferOrder := newTransferOrder()
+		err := transferOrder.Receive(&TransferOrderDelivery{
+			Lines: []*TransferOrderDeliveryLine{{ProductID: productB, Quantity: 5}},
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidTransferOrder)
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		transferOrder := newTransferOrder()
+		err := transferOrder.Receive(&TransferOrderDelivery{
+			Lines: []*TransferOrderDeliveryLine{{ProductID: uuid.New(), Quantity: 1}},
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidTransferOrder)
+	})
+
+	t.Run("not shipped", func(t *testing.T) {
+		transferOrder := newTransferOrder()
+		transferOrder.Status = TransferOrderStatusPicked
+		err := transferOrder.Receive(&TransferOrderDelivery{Close: true})
+
+		assert.ErrorIs(t, err, ErrInvalidTransferOrder)
+	})
+}
@@ -0,0 +1,246 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ValuationMethodFIFO            = "fifo"
+	ValuationMethodWeightedAverage = "weighted-average"
+)
+
+// ErrInvalidValuation is returned when the valuation method or period is not valid
+var ErrInvalidValuation = errors.New("invalid valuation")
+
+// CostLayer is a quantity of a product which entered a warehouse at one unit cost.
+// Stock leaving the warehouse consumes the oldest layers first.
+type CostLayer struct {
+	ID                uuid.UUID `json:"id"`
+	WarehouseID       uuid.UUID `json:"warehouse_id"`
+	ProductID         uuid.UUID `json:"product_id"`
+	StockMovementID   uuid.UUID `json:"stock_movement_id"` // nil for the opening layer of a warehouse product
+	Quantity          int64     `json:"quantity"`
+	RemainingQuantity int64     `json:"remaining_quantity"`
+	UnitCost          float64   `json:"unit_cost"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// CostLayerConsumption is the quantity of a cost layer taken by a movement out of the warehouse
+type CostLayerConsumption struct {
+	ID              uuid.UUID `json:"id"`
+	CostLayerID     uuid.UUID `json:"cost_layer_id"` // nil when no layer was left, the unit cost is then the fallback cost
+	StockMovementID uuid.UUID `json:"stock_movement_id"`
+	Quantity        int64     `json:"quantity"`
+	UnitCost        float64   `json:"unit_cost"`
+}
+
+// cost of a warehouse product used by the valuation report
+type ProductCost struct {
+	WarehouseID uuid.UUID
+	ProductID   uuid.UUID
+	ProductName string
+	// quantity and cost of the layers which are not consumed yet
+	RemainingQuantity int64
+	RemainingCost     float64
+	// quantity and cost of every layer received until the valuation time
+	ReceivedQuantity int64
+	ReceivedCost     float64
+}
+
+// sales of a warehouse product in a period used by the cost of goods sold report
+type ProductSalesCost struct {
+	WarehouseID  uuid.UUID
+	ProductID    uuid.UUID
+	ProductName  string
+	SoldQuantity int64
+	FIFOCost     float64 // cost of the layers consumed by the sales
+	ProductCost  ProductCost
+}
+
+type InventoryValuation struct {
+	WarehouseID uuid.UUID                 `json:"warehouse_id"`
+	Method      string                    `json:"method"`
+	TotalValue  float64                   `json:"total_value"`
+	Lines       []*InventoryValuationLine `json:"lines"`
+	ValuedAt    time.Time                 `json:"valued_at"`
+}
+
+type InventoryValuationLine struct {
+	ProductID   uuid.UUID `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	Quantity    int64     `json:"quantity"`
+	UnitCost    float64   `json:"unit_cost"`
+	Value       float64   `json:"value"`
+}
+
+// CostOfGoodsSoldPeriod selects the sale movements between two dates, both dates are included.
+// A nil warehouse id matches every warehouse.
+type CostOfGoodsSoldPeriod struct {
+	WarehouseID uuid.UUID
+	Method      string
+	From        time.Time
+	To          time.Time
+}
+
+type CostOfGoodsSold struct {
+	WarehouseID uuid.UUID              `json:"warehouse_id"`
+	Method      string                 `json:"method"`
+	From        time.Time              `json:"from"`
+	To          time.Time              `json:"to"`
+	TotalCost   float64                `json:"total_cost"`
+	Lines       []*CostOfGoodsSoldLine `json:"lines"`
+}
+
+type CostOfGoodsSoldLine struct {
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	ProductID   uuid.UUID `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	Quantity    int64     `json:"quantity"`
+	Cost        float64   `json:"cost"`
+}
+
+func (cl *CostLayer) GenerateCostLayerID() error {
+	costLayerID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	cl.ID = costLayerID
+	return nil
+}
+
+func (clc *CostLayerConsumption) GenerateCostLayerConsumptionID() error {
+	costLayerConsumptionID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	clc.ID = costLayerConsumptionID
+	return nil
+}
+
+func IsValidValuationMethod(method string) bool {
+	return method == ValuationMethodFIFO || method == ValuationMethodWeightedAverage
+}
+
+func (p *CostOfGoodsSoldPeriod) Validate() error {
+	if !IsValidValuationMethod(p.Method) {
+		return fmt.Errorf("unknown valuation method %q: %w", p.Method, ErrInvalidValuation)
+	}
+	if p.To.Before(p.From) {
+		return fmt.Errorf("to date must not be before from date: %w", ErrInvalidValuation)
+	}
+
+	return nil
+}
+
+// take the quantity from the layers ordered from the oldest. The remaining quantity of the layers is reduced,
+// quantity which is not covered by any layer is taken at the fallback unit cost.
+func ConsumeCostLayers(layers []*CostLayer, quantity int64, fallbackUnitCost float64) []*CostLayerConsumption {
+	var consumptions []*CostLayerConsumption
+	for _, layer := range layers {
+		if quantity == 0 {
+			break
+		}
+
+		taken := min(layer.RemainingQuantity, quantity)
+		if taken <= 0 {
+			continue
+		}
+		layer.RemainingQuantity -= taken
+		quantity -= taken
+
+		consumptions = append(consumptions, &CostLayerConsumption{
+			CostLayerID: layer.ID,
+			Quantity:    taken,
+			UnitCost:    layer.UnitCost,
+		})
+	}
+	if quantity > 0 {
+		consumptions = append(consumptions, &CostLayerConsumption{
+			Quantity: quantity,
+			UnitCost: fallbackUnitCost,
+		})
+	}
+
+	return consumptions
+}
+
+// FIFO unit cost of the remaining layers
+func (pc *ProductCost) FIFOUnitCost() float64 {
+	if pc.RemainingQuantity == 0 {
+		return 0
+	}
+	return pc.RemainingCost / float64(pc.RemainingQuantity)
+}
+
+// average unit cost of every layer received, so each unit on hand or sold has the same cost
+func (pc *ProductCost) WeightedAverageUnitCost() float64 {
+	if pc.ReceivedQuantity == 0 {
+		return 0
+	}
+	return pc.ReceivedCost / float64(pc.ReceivedQuantity)
+}
+
+// value the remaining quantity of every product of the warehouse with the method
+func ValueInventory(warehouseID uuid.UUID, method string, costs []*ProductCost, valuedAt time.Time) *InventoryValuation {
+	valuation := &InventoryValuation{
+		WarehouseID: warehouseID,
+		Method:      method,
+		Lines:       make([]*InventoryValuationLine, 0, len(costs)),
+		ValuedAt:    valuedAt,
+	}
+	for _, cost := range costs {
+		line := &InventoryValuationLine{
+			ProductID:   cost.ProductID,
+			ProductName: cost.ProductName,
+			Quantity:    cost.RemainingQuantity,
+		}
+		switch method {
+		case ValuationMethodFIFO:
+			line.UnitCost = cost.FIFOUnitCost()
+			line.Value = cost.RemainingCost
+		case ValuationMethodWeightedAverage:
+			line.UnitCost = cost.WeightedAverageUnitCost()
+			line.Value = line.UnitCost * float64(line.Quantity)
+		}
+		valuation.TotalValue += line.Value
+		valuation.Lines = append(valuation.Lines, line)
+	}
+
+	return valuation
+}
+
+// cost of the quantity sold in the period. FIFO uses the cost of the consumed layers,
+// weighted average uses the average cost of the layers received until the end of the period.
+func CalculateCostOfGoodsSold(period CostOfGoodsSoldPeriod, sales []*ProductSalesCost) *CostOfGoodsSold {
+	cogs := &CostOfGoodsSold{
+		WarehouseID: period.WarehouseID,
+		Method:      period.Method,
+		From:        period.From,
+		To:          period.To,
+		Lines:       make([]*CostOfGoodsSoldLine, 0, len(sales)),
+	}
+	for _, sale := range sales {
+		line := &CostOfGoodsSoldLine{
+			WarehouseID: sale.WarehouseID,
+			ProductID:   sale.ProductID,
+			ProductName: sale.ProductName,
+			Quantity:    sale.SoldQuantity,
+		}
+		switch period.Method {
+		case ValuationMethodFIFO:
+			line.Cost = sale.FIFOCost
+		case ValuationMethodWeightedAverage:
+			line.Cost = sale.ProductCost.WeightedAverageUnitCost() * float64(sale.SoldQuantity)
+		}
+		cogs.TotalCost += line.Cost
+		cogs.Lines = append(cogs.Lines, line)
+	}
+
+	return cogs
+}
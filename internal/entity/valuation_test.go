@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumeCostLayers(t *testing.T) {
+	oldest := &CostLayer{ID: uuid.New(), Quantity: 5, RemainingQuantity: 2, UnitCost: 10}
+	newest := &CostLayer{ID: uuid.New(), Quantity: 5, RemainingQuantity: 5, UnitCost: 12}
+
+	consumptions := ConsumeCostLayers([]*CostLayer{oldest, newest}, 4, 15)
+
+	assert.Len(t, consumptions, 2)
+	assert.Equal(t, oldest.ID, consumptions[0].CostLayerID)
+	assert.Equal(t, int64(2), consumptions[0].Quantity)
+	assert.Equal(t, 10.0, consumptions[0].UnitCost)
+	assert.Equal(t, newest.ID, consumptions[1].CostLayerID)
+	assert.Equal(t, int64(2), consumptions[1].Quantity)
+	assert.Equal(t, int64(0), oldest.RemainingQuantity)
+	assert.Equal(t, int64(3), newest.RemainingQuantity)
+
+	// quantity not covered by the layers is taken at the fallback cost
+	consumptions = ConsumeCostLayers([]*CostLayer{newest}, 5, 15)
+
+	assert.Len(t, consumptions, 2)
+	assert.Equal(t, int64(3), consumptions[0].Quantity)
+	assert.Equal(t, uuid.Nil, consumptions[1].CostLayerID)
+	assert.Equal(t, int64(2), consumptions[1].Quantity)
+	assert.Equal(t, 15.0, consumptions[1].UnitCost)
+	assert.Equal(t, int64(0), newest.RemainingQuantity)
+}
+
+func TestValueInventory(t *testing.T) {
+	warehouseID := uuid.New()
+	costs := []*ProductCost{
+		// received 10 at 10 and 10 at 20, 5 of the first layer were sold
+		{ProductID: uuid.New(), RemainingQuantity: 15, RemainingCost: 250, ReceivedQuantity: 20, ReceivedCost: 300},
+		{ProductID: uuid.New()},
+	}
+
+	fifo := ValueInventory(warehouseID, ValuationMethodFIFO, costs, time.Now())
+
+	assert.Len(t, fifo.Lines, 2)
+	assert.InDelta(t, 250.0/15, fifo.Lines[0].UnitCost, 0.0001)
+	assert.Equal(t, 250.0, fifo.Lines[0].Value)
+	assert.Equal(t, 0.0, fifo.Lines[1].UnitCost)
+	assert.Equal(t, 250.0, fifo.TotalValue)
+
+	weightedAverage := ValueInventory(warehouseID, ValuationMethodWeightedAverage, costs, time.Now())
+
+	assert.Equal(t, 15.0, weightedAverage.Lines[0].UnitCost)
+	assert.Equal(t, 225.0, weightedAverage.Lines[0].Value)
+	assert.Equal(t, 225.0, weightedAverage.TotalValue)
+}
+
+func TestCostOfGoodsSoldPeriodValidate(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		period  CostOfGoodsSoldPeriod
+		wantErr bool
+	}{
+		{name: "valid", period: CostOfGoodsSoldPeriod{Method: ValuationMethodFIFO, From: from, To: from.AddDate(0, 1, 0)}},
+		{name: "single day", period: CostOfGoodsSoldPeriod{Method: ValuationMethodWeightedAverage, From: from, To: from}},
+		{name: "unknown method", period: CostOfGoodsSoldPeriod{Method: "lifo", From: from, To: from}, wantErr: true},
+		{name: "to before from", period: CostOfGoodsSoldPeriod{Method: ValuationMethodFIFO, From: from, To: from.AddDate(0, 0, -1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.period.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidValuation)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCalculateCostOfGoodsSold(t *testing.T) {
+	sales := []*ProductSalesCost{
+		{ProductID: uuid.New(), SoldQuantity: 4, FIFOCost: 40, ProductCost: ProductCost{ReceivedQuantity: 20, ReceivedCost: 300}},
+		{ProductID: uuid.New(), SoldQuantity: 1, FIFOCost: 7, ProductCost: ProductCost{ReceivedQuantity: 2, ReceivedCost: 16}},
+	}
+
+	fifo := CalculateCostOfGoodsSold(CostOfGoodsSoldPeriod{Method: ValuationMethodFIFO}, sales)
+
+	assert.Len(t, fifo.Lines, 2)
+	assert.Equal(t, 40.0, fifo.Lines[0].Cost)
+	assert.Equal(t, 47.0, fifo.TotalCost)
+
+	weightedAverage := CalculateCostOfGoodsSold(CostOfGoodsSoldPeriod{Method: ValuationMethodWeightedAverage}, sales)
+
+	assert.Equal(t, 60.0, weightedAverage.Lines[0].Cost)
+	assert.Equal(t, 8.0, weightedAverage.Lines[1].Cost)
+	assert.Equal(t, 68.0, weightedAverage.TotalCost)
+}
@@ -13,6 +13,8 @@ type Warehouse struct {
 	City            string
 	State           string
 	ZipCode         string
+	Latitude        *float64 // nil when the coordinate is unknown
+	Longitude       *float64
 	IsMainWarehouse bool
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
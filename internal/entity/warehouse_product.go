@@ -1,11 +1,15 @@
 package entity
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrWarehouseProductExists is returned when the product is already in the warehouse
+var ErrWarehouseProductExists = errors.New("product already exists in the warehouse")
+
 type WarehouseProduct struct {
 	ID                 uuid.UUID `json:"id"`
 	WarehouseID        uuid.UUID `json:"warehouse_id"`
@@ -16,10 +20,12 @@ type WarehouseProduct struct {
 	ProductDescription string    `json:"product_description"`
 	ProductPrice       float64   `json:"product_price"`
 	ProductQuantity    int64     `json:"product_quantity"`
-	ProductCategoryID  uuid.UUID `json:"product_category_id"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
-	DeletedAt          time.Time `json:"deleted_at"`
+	// damaged returned units, not available for moving out
+	QuarantinedQuantity int64     `json:"quarantined_quantity"`
+	ProductCategoryID   uuid.UUID `json:"product_category_id"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+	DeletedAt           time.Time `json:"deleted_at"`
 }
 
 func (wp *WarehouseProduct) GenerateWarehouseProductID() error {
@@ -34,7 +40,10 @@ func (wp *WarehouseProduct) GenerateWarehouseProductID() error {
 
 type WarehouseAddressAndProductQty struct {
 	WarehouseID     uuid.UUID
+	ProductID       uuid.UUID
 	ZipCode         string
+	Latitude        *float64
+	Longitude       *float64
 	ProductName     string
 	ProductQuantity int64
 }
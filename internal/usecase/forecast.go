@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+)
+
+type ForecastUseCase struct {
+	repoMovePostgre StockMovementPostgreRepo
+	defaultParams   entity.ForecastParams
+}
+
+func NewForecastUseCase(repoMovePostgre StockMovementPostgreRepo, defaultParams entity.ForecastParams) *ForecastUseCase {
+	return &ForecastUseCase{
+		repoMovePostgre,
+		defaultParams,
+	}
+}
+
+// forecast the demand of every product in every warehouse which sold it during the history.
+// Method, history days, window and alpha which are not set fall back to the configured defaults.
+func (u *ForecastUseCase) ForecastDemand(ctx context.Context, params entity.ForecastParams) ([]*entity.DemandForecast, error) {
+	if params.Method == "" {
+		params.Method = u.defaultParams.Method
+	}
+	if params.HistoryDays == 0 {
+		params.HistoryDays = u.defaultParams.HistoryDays
+	}
+	if params.Window == 0 {
+		params.Window = u.defaultParams.Window
+	}
+	if params.Alpha == 0 {
+		params.Alpha = u.defaultParams.Alpha
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	from, until := params.HistoryRange(now)
+	histories, err := u.repoMovePostgre.GetDemandHistory(ctx, from, until, params.WarehouseID, params.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get demand history: %w", err)
+	}
+
+	forecasts := make([]*entity.DemandForecast, 0, len(histories))
+	for _, history := range histories {
+		forecasts = append(forecasts, entity.ForecastDemand(history, params, now))
+	}
+
+	return forecasts, nil
+}
@@ -0,0 +1,103 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func forecast(t *testing.T) (*usecase.ForecastUseCase, *MockStockMovementPostgreRepo) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	repoMove := NewMockStockMovementPostgreRepo(mockCtl)
+	forecast := usecase.NewForecastUseCase(repoMove, entity.ForecastParams{
+		Method:      entity.ForecastMethodExponentialSmoothing,
+		HistoryDays: 28,
+		Window:      7,
+		Alpha:       0.3,
+	})
+
+	return forecast, repoMove
+}
+
+func TestForecastDemand(t *testing.T) {
+	// t.Parallell()
+	forecast, repoMove := forecast(t)
+
+	productID := uuid.New()
+
+	tests := []struct {
+		name   string
+		params entity.ForecastParams
+		mock   func()
+		method string
+		err    error
+	}{
+		{
+			name:   "default parameters",
+			params: entity.ForecastParams{ProductID: productID},
+			mock: func() {
+				repoMove.EXPECT().
+					GetDemandHistory(context.Background(), gomock.Any(), gomock.Any(), uuid.Nil, productID).
+					Return([]*entity.DemandHistory{{ProductID: productID, AvailableQuantity: 10}}, nil)
+			},
+			method: entity.ForecastMethodExponentialSmoothing,
+			err:    nil,
+		},
+		{
+			name:   "moving average",
+			params: entity.ForecastParams{Method: entity.ForecastMethodMovingAverage, Window: 14},
+			mock: func() {
+				repoMove.EXPECT().
+					GetDemandHistory(context.Background(), gomock.Any(), gomock.Any(), uuid.Nil, uuid.Nil).
+					Return([]*entity.DemandHistory{{ProductID: productID, AvailableQuantity: 10}}, nil)
+			},
+			method: entity.ForecastMethodMovingAverage,
+			err:    nil,
+		},
+		{
+			name:   "window longer than history",
+			params: entity.ForecastParams{Method: entity.ForecastMethodMovingAverage, Window: 30},
+			mock:   func() {},
+			err:    entity.ErrInvalidForecast,
+		},
+		{
+			name:   "failed to get demand history",
+			params: entity.ForecastParams{},
+			mock: func() {
+				repoMove.EXPECT().
+					GetDemandHistory(context.Background(), gomock.Any(), gomock.Any(), uuid.Nil, uuid.Nil).
+					Return(nil, errInternalServerError)
+			},
+			err: errInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			forecasts, err := forecast.ForecastDemand(context.Background(), tc.params)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, forecasts, 1)
+			assert.Equal(t, tc.method, forecasts[0].Method)
+			assert.Equal(t, productID, forecasts[0].ProductID)
+		})
+	}
+}
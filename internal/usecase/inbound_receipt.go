@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+)
+
+type InboundReceiptUseCase struct {
+	repoInboundReceiptPostgre InboundReceiptPostgreRepo
+}
+
+func NewInboundReceiptUseCase(repoInboundReceiptPostgre InboundReceiptPostgreRepo) *InboundReceiptUseCase {
+	return &InboundReceiptUseCase{
+		repoInboundReceiptPostgre,
+	}
+}
+
+// create inbound receipt with the expected lines, nothing is received yet
+func (u *InboundReceiptUseCase) CreateInboundReceipt(ctx context.Context, inboundReceipt *entity.InboundReceipt) error {
+	if err := inboundReceipt.Validate(); err != nil {
+		return err
+	}
+
+	err := inboundReceipt.GenerateInboundReceiptID()
+	if err != nil {
+		return fmt.Errorf("failed to generate inbound receipt id: %w", err)
+	}
+
+	for _, line := range inboundReceipt.Lines {
+		if err := line.GenerateInboundReceiptLineID(); err != nil {
+			return fmt.Errorf("failed to generate inbound receipt line id: %w", err)
+		}
+		line.InboundReceiptID = inboundReceipt.ID
+		line.ReceivedQuantity = 0
+	}
+
+	inboundReceipt.Status = entity.InboundReceiptStatusExpected
+	inboundReceipt.UpdatedAt = inboundReceipt.CreatedAt
+	inboundReceipt.CalculateDiscrepancies()
+
+	if err := u.repoInboundReceiptPostgre.Save(ctx, inboundReceipt); err != nil {
+		return fmt.Errorf("failed to save inbound receipt: %w", err)
+	}
+
+	return nil
+}
+
+// inbound receipt with the over and short quantity of each line
+func (u *InboundReceiptUseCase) GetInboundReceiptByID(ctx context.Context, id uuid.UUID) (*entity.InboundReceipt, error) {
+	inboundReceipt, err := u.repoInboundReceiptPostgre.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	inboundReceipt.CalculateDiscrepancies()
+	return inboundReceipt, nil
+}
+
+// receive a delivery, received quantity of the lines is the delivered quantity of the products
+func (u *InboundReceiptUseCase) ReceiveInboundReceipt(ctx context.Context, inboundReceipt *entity.InboundReceipt, idempotencyKey string) error {
+	if err := inboundReceipt.ValidateDelivery(); err != nil {
+		return err
+	}
+
+	inboundReceipt.UpdatedAt = time.Now()
+	if err := u.repoInboundReceiptPostgre.Receive(ctx, inboundReceipt, idempotencyKey); err != nil {
+		return fmt.Errorf("failed to receive inbound receipt: %w", err)
+	}
+
+	inboundReceipt.CalculateDiscrepancies()
+	return nil
+}
+
+// close inbound receipt, the remaining short quantity is not expected anymore
+func (u *InboundReceiptUseCase) CloseInboundReceipt(ctx context.Context, inboundReceipt *entity.InboundReceipt) error {
+	inboundReceipt.UpdatedAt = time.Now()
+	if err := u.repoInboundReceiptPostgre.Close(ctx, inboundReceipt); err != nil {
+		return fmt.Errorf("failed to close inbound receipt: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func inboundReceipt(t *testing.T) (*usecase.InboundReceiptUseCase, *MockInboundReceiptPostgreRepo) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	repoInboundReceipt := NewMockInboundReceiptPostgreRepo(mockCtl)
+	inboundReceipt := usecase.NewInboundReceiptUseCase(repoInboundReceipt)
+
+	return inboundReceipt, repoInboundReceipt
+}
+
+func TestCreateInboundReceipt(t *testing.T) {
+	// t.Parallell()
+	inboundReceipt, repoInboundReceipt := inboundReceipt(t)
+
+	tests := []struct {
+		name  string
+		lines []*entity.InboundReceiptLine
+		mock  func()
+		err   error
+	}{
+		{
+			name:  "success",
+			lines: []*entity.InboundReceiptLine{{ProductID: uuid.New(), ProductName: "Product A", ExpectedQuantity: 10}},
+			mock: func() {
+				repoInboundReceipt.EXPECT().
+					Save(context.Background(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, ir *entity.InboundReceipt) error {
+						assert.Equal(t, entity.InboundReceiptStatusExpected, ir.Status)
+						for _, line := range ir.Lines {
+							assert.NotEqual(t, uuid.Nil, line.ID)
+							assert.Equal(t, ir.ID, line.InboundReceiptID)
+						}
+						return nil
+					})
+			},
+			err: nil,
+		},
+		{
+			name:  "invalid lines",
+			lines: []*entity.InboundReceiptLine{{ProductID: uuid.New(), ProductName: "Product A"}},
+			mock:  func() {},
+			err:   entity.ErrInvalidInboundReceipt,
+		},
+		{
+			name:  "failed to save",
+			lines: []*entity.InboundReceiptLine{{ProductID: uuid.New(), ProductName: "Product A", ExpectedQuantity: 10}},
+			mock: func() {
+				repoInboundReceipt.EXPECT().
+					Save(context.Background(), gomock.Any()).
+					Return(errInternalServerError)
+			},
+			err: errInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			input := &entity.InboundReceipt{
+				WarehouseID:     uuid.New(),
+				Type:            entity.InboundReceiptTypePurchaseOrder,
+				ReferenceNumber: "PO-001",
+				Lines:           tc.lines,
+				CreatedAt:       time.Now(),
+			}
+			err := inboundReceipt.CreateInboundReceipt(context.Background(), input)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, input.ID)
+			assert.Equal(t, tc.lines[0].ExpectedQuantity, input.Lines[0].ShortQuantity)
+		})
+	}
+}
+
+func TestGetInboundReceiptByID(t *testing.T) {
+	// t.Parallell()
+	inboundReceipt, repoInboundReceipt := inboundReceipt(t)
+	id := uuid.New()
+
+	repoInboundReceipt.EXPECT().
+		GetByID(context.Background(), id).
+		Return(&entity.InboundReceipt{
+			ID: id,
+			Lines: []*entity.InboundReceiptLine{
+				{ExpectedQuantity: 10, ReceivedQuantity: 12},
+				{ExpectedQuantity: 4, ReceivedQuantity: 1},
+			},
+		}, nil)
+
+	res, err := inboundReceipt.GetInboundReceiptByID(context.Background(), id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), res.Lines[0].OverQuantity)
+	assert.Equal(t, int64(3), res.Lines[1].ShortQuantity)
+}
+
+func TestReceiveInboundReceipt(t *testing.T) {
+	// t.Parallell()
+	inboundReceipt, repoInboundReceipt := inboundReceipt(t)
+
+	tests := []struct {
+		name  string
+		lines []*entity.InboundReceiptLine
+		mock  func()
+		err   error
+	}{
+		{
+			name:  "success",
+			lines: []*entity.InboundReceiptLine{{ProductID: uuid.New(), ReceivedQuantity: 5}},
+			mock: func() {
+				repoInboundReceipt.EXPECT().
+					Receive(context.Background(), gomock.Any(), "key-1").
+					Return(nil)
+			},
+			err: nil,
+		},
+		{
+			name:  "zero delivered quantity",
+			lines: []*entity.InboundReceiptLine{{ProductID: uuid.New()}},
+			mock:  func() {},
+			err:   entity.ErrInvalidInboundReceipt,
+		},
+		{
+			name:  "receipt closed",
+			lines: []*entity.InboundReceiptLine{{ProductID: uuid.New(), ReceivedQuantity: 5}},
+			mock: func() {
+				repoInboundReceipt.EXPECT().
+					Receive(context.Background(), gomock.Any(), "key-1").
+					Return(entity.ErrInvalidInboundReceipt)
+			},
+			err: entity.ErrInvalidInboundReceipt,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			input := &entity.InboundReceipt{ID: uuid.New(), Lines: tc.lines}
+			err := inboundReceipt.ReceiveInboundReceipt(context.Background(), input, "key-1")
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.False(t, input.UpdatedAt.IsZero())
+		})
+	}
+}
+
+func TestCloseInboundReceipt(t *testing.T) {
+	// t.Parallell()
+	inboundReceipt, repoInboundReceipt := inboundReceipt(t)
+
+	repoInboundReceipt.EXPECT().
+		Close(context.Background(), gomock.Any()).
+		Return(entity.ErrInvalidInboundReceipt)
+
+	err := inboundReceipt.CloseInboundReceipt(context.Background(), &entity.InboundReceipt{ID: uuid.New()})
+
+	assert.ErrorIs(t, err, entity.ErrInvalidInboundReceipt)
+}
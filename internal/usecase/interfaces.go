@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/idoyudha/eshop-warehouse/internal/entity"
@@ -21,7 +22,7 @@ type (
 	WarehouseProductPostgreRepo interface {
 		Save(context.Context, *entity.WarehouseProduct) error
 		Update(context.Context, *entity.WarehouseProduct) error
-		UpdateProductQuantity(context.Context, *entity.WarehouseProduct) error
+		Adjust(context.Context, *entity.StockAdjustment) error
 		GetAll(context.Context) ([]*entity.WarehouseProduct, error)
 		GetByProductID(context.Context, uuid.UUID) ([]*entity.WarehouseProduct, error)
 		GetByWarehouseID(context.Context, uuid.UUID) ([]*entity.WarehouseProduct, error)
@@ -31,15 +32,86 @@ type (
 	}
 
 	StockMovementPostgreRepo interface {
-		GetAll(ctx context.Context) ([]*entity.StockMovement, error)
-		GetByProductID(context.Context, uuid.UUID) ([]*entity.StockMovement, error)
-		GetBySourceID(context.Context, uuid.UUID) ([]*entity.StockMovement, error)
-		GetByDestinationID(context.Context, uuid.UUID) ([]*entity.StockMovement, error)
+		GetAll(context.Context, entity.StockMovementFilter) ([]*entity.StockMovement, error)
+		GetByProductID(context.Context, uuid.UUID, entity.StockMovementFilter) ([]*entity.StockMovement, error)
+		GetBySourceID(context.Context, uuid.UUID, entity.StockMovementFilter) ([]*entity.StockMovement, error)
+		GetByDestinationID(context.Context, uuid.UUID, entity.StockMovementFilter) ([]*entity.StockMovement, error)
+		GetOutboundDemand(context.Context, time.Time) ([]*entity.WarehouseDemand, error)
+		GetDemandHistory(context.Context, time.Time, time.Time, uuid.UUID, uuid.UUID) ([]*entity.DemandHistory, error)
 	}
 
 	TransactionProductPostgresRepo interface {
 		TransferIn(context.Context, *entity.StockMovement) error
-		TransferOut(context.Context, []*entity.StockMovement) error
+		TransferOut(context.Context, []*entity.StockMovement, func([]*entity.WarehouseAddressAndProductQty) ([]*entity.StockMovement, error)) ([]*entity.StockMovement, error)
+		CommitReservation(context.Context, uuid.UUID, time.Time) ([]*entity.StockMovement, error)
+		TransferReturn(context.Context, *entity.StockMovement) error
+		ReverseTransferOut(context.Context, string, string, time.Time) ([]*entity.StockMovement, error)
+	}
+
+	ReservationPostgreRepo interface {
+		Save(context.Context, *entity.Reservation, time.Duration) error
+		GetByID(context.Context, uuid.UUID) (*entity.Reservation, error)
+		UpdateStatus(context.Context, *entity.Reservation) error
+		ExpireAll(context.Context, time.Time) (int64, error)
+	}
+
+	InventoryCountPostgreRepo interface {
+		Save(context.Context, *entity.InventoryCount) error
+		GetByID(context.Context, uuid.UUID) (*entity.InventoryCount, error)
+		UpdateCountedQuantities(context.Context, *entity.InventoryCount) error
+		Post(context.Context, *entity.InventoryCount) error
+		Cancel(context.Context, *entity.InventoryCount) error
+	}
+
+	InboundReceiptPostgreRepo interface {
+		Save(context.Context, *entity.InboundReceipt) error
+		GetByID(context.Context, uuid.UUID) (*entity.InboundReceipt, error)
+		Receive(context.Context, *entity.InboundReceipt, string) error
+		Close(context.Context, *entity.InboundReceipt) error
+	}
+
+	TransferOrderPostgreRepo interface {
+		Save(context.Context, *entity.TransferOrder) error
+		GetByID(context.Context, uuid.UUID) (*entity.TransferOrder, error)
+		Pick(context.Context, *entity.TransferOrder) error
+		Ship(context.Context, *entity.TransferOrder) error
+		Receive(context.Context, *entity.TransferOrder, *entity.TransferOrderDelivery) error
+		Cancel(context.Context, *entity.TransferOrder) error
+		GetInTransitByProductID(context.Context, uuid.UUID) ([]*entity.TransferOrderInTransit, error)
+	}
+
+	StockThresholdPostgreRepo interface {
+		Save(context.Context, *entity.StockThreshold) error
+		GetByWarehouseID(context.Context, uuid.UUID) ([]*entity.StockThreshold, error)
+		Delete(context.Context, uuid.UUID, uuid.UUID) error
+		GetStockLevels(context.Context) ([]*entity.StockLevel, error)
+		EvaluateLowStocks(context.Context, time.Time) ([]*entity.StockLevel, error)
+	}
+
+	StockHistoryPostgreRepo interface {
+		GetOnHand(context.Context, uuid.UUID, uuid.UUID, time.Time) (*entity.StockHistory, error)
+		GetDiscrepancies(context.Context) ([]*entity.StockDiscrepancy, error)
+	}
+
+	StockSnapshotPostgreRepo interface {
+		Capture(context.Context, time.Time, time.Time) (int64, error)
+		GetTrend(context.Context, entity.StockSnapshotFilter) ([]*entity.StockTrendPoint, error)
+		GetByDate(context.Context, time.Time, uuid.UUID, uuid.UUID) ([]*entity.StockSnapshot, error)
+	}
+
+	CostLayerPostgreRepo interface {
+		GetProductCosts(context.Context, uuid.UUID) ([]*entity.ProductCost, error)
+		GetSalesCosts(context.Context, entity.CostOfGoodsSoldPeriod) ([]*entity.ProductSalesCost, error)
+	}
+
+	OutboxEventPostgreRepo interface {
+		GetPending(context.Context, time.Time, int) ([]*entity.OutboxEvent, error)
+		MarkSent(context.Context, *entity.OutboxEvent) error
+		MarkFailed(context.Context, *entity.OutboxEvent) error
+	}
+
+	KafkaProducer interface {
+		ProduceSync(string, []byte, interface{}) error
 	}
 
 	Warehouse interface {
@@ -54,7 +126,7 @@ type (
 	WarehouseProduct interface {
 		CreateWarehouseProduct(context.Context, *entity.WarehouseProduct) error
 		UpdateWarehouseProduct(context.Context, *entity.WarehouseProduct) error
-		UpdateWarehouseProductQuantity(context.Context, *entity.WarehouseProduct) error
+		AdjustWarehouseProductQuantity(context.Context, *entity.StockAdjustment) error
 		GetAllWarehouseProducts(context.Context) ([]*entity.WarehouseProduct, error)
 		GetWarehouseProductByProductID(context.Context, uuid.UUID) ([]*entity.WarehouseProduct, error)
 		GetWarehouseProductByWarehouseID(context.Context, uuid.UUID) ([]*entity.WarehouseProduct, error)
@@ -63,14 +135,88 @@ type (
 	}
 
 	StockMovement interface {
-		GetAllStockMovements(context.Context) ([]*entity.StockMovement, error)
-		GetStockMovementsByProductID(context.Context, uuid.UUID) ([]*entity.StockMovement, error)
-		GetStockMovementsBySourceID(context.Context, uuid.UUID) ([]*entity.StockMovement, error)
-		GetStockMovementsByDestinationID(context.Context, uuid.UUID) ([]*entity.StockMovement, error)
+		GetAllStockMovements(context.Context, entity.StockMovementFilter) ([]*entity.StockMovement, error)
+		GetStockMovementsByProductID(context.Context, uuid.UUID, entity.StockMovementFilter) ([]*entity.StockMovement, error)
+		GetStockMovementsBySourceID(context.Context, uuid.UUID, entity.StockMovementFilter) ([]*entity.StockMovement, error)
+		GetStockMovementsByDestinationID(context.Context, uuid.UUID, entity.StockMovementFilter) ([]*entity.StockMovement, error)
 	}
 
 	TransactionProduct interface {
 		MoveIn(context.Context, *entity.StockMovement) error
-		MoveOut(context.Context, []*entity.StockMovement, string) error
+		MoveOut(context.Context, []*entity.StockMovement, string, string) ([]*entity.StockMovement, *entity.AllocationPlan, error)
+		PreviewMoveOut(context.Context, []*entity.StockMovement, string, string) (*entity.AllocationPlan, error)
+		MoveReturn(context.Context, *entity.StockMovement) error
+		CommitReservation(context.Context, uuid.UUID) ([]*entity.StockMovement, error)
+		CompensateMoveOut(context.Context, string, string) ([]*entity.StockMovement, error)
+	}
+
+	Reservation interface {
+		CreateReservation(context.Context, *entity.Reservation) error
+		GetReservationByID(context.Context, uuid.UUID) (*entity.Reservation, error)
+		ReleaseReservation(context.Context, uuid.UUID) error
+		ReleaseExpiredReservations(context.Context) (int64, error)
+	}
+
+	Outbox interface {
+		RelayPendingEvents(context.Context) (int, error)
+	}
+
+	InventoryCount interface {
+		OpenInventoryCount(context.Context, *entity.InventoryCount) error
+		GetInventoryCountByID(context.Context, uuid.UUID) (*entity.InventoryCount, error)
+		SubmitInventoryCountLines(context.Context, *entity.InventoryCount) error
+		PostInventoryCount(context.Context, *entity.InventoryCount) error
+		CancelInventoryCount(context.Context, *entity.InventoryCount) error
+	}
+
+	InboundReceipt interface {
+		CreateInboundReceipt(context.Context, *entity.InboundReceipt) error
+		GetInboundReceiptByID(context.Context, uuid.UUID) (*entity.InboundReceipt, error)
+		ReceiveInboundReceipt(context.Context, *entity.InboundReceipt, string) error
+		CloseInboundReceipt(context.Context, *entity.InboundReceipt) error
+	}
+
+	TransferOrder interface {
+		CreateTransferOrder(context.Context, *entity.TransferOrder) error
+		GetTransferOrderByID(context.Context, uuid.UUID) (*entity.TransferOrder, error)
+		PickTransferOrder(context.Context, *entity.TransferOrder) error
+		ShipTransferOrder(context.Context, *entity.TransferOrder) error
+		ReceiveTransferOrder(context.Context, *entity.TransferOrder, *entity.TransferOrderDelivery) error
+		CancelTransferOrder(context.Context, *entity.TransferOrder) error
+		GetInTransitByProductID(context.Context, uuid.UUID) (*entity.ProductInTransit, error)
+	}
+
+	StockThreshold interface {
+		SetStockThreshold(context.Context, *entity.StockThreshold) error
+		GetStockThresholdsByWarehouseID(context.Context, uuid.UUID) ([]*entity.StockThreshold, error)
+		DeleteStockThreshold(context.Context, uuid.UUID, uuid.UUID) error
+		GetLowStocks(context.Context) ([]*entity.StockLevel, error)
+		EvaluateLowStocks(context.Context) (int, error)
+		SuggestReplenishments(context.Context) ([]*entity.ReplenishmentSuggestion, error)
+	}
+
+	Rebalance interface {
+		PlanRebalance(context.Context) (*entity.RebalanceReport, error)
+		Rebalance(context.Context) (*entity.RebalanceReport, error)
+	}
+
+	Forecast interface {
+		ForecastDemand(context.Context, entity.ForecastParams) ([]*entity.DemandForecast, error)
+	}
+
+	StockHistory interface {
+		GetOnHandQuantity(context.Context, uuid.UUID, uuid.UUID, time.Time) (*entity.StockHistory, error)
+		ReconcileStock(context.Context) ([]*entity.StockDiscrepancy, error)
+	}
+
+	StockSnapshot interface {
+		CaptureStockSnapshot(context.Context) (int64, error)
+		GetStockTrend(context.Context, entity.StockSnapshotFilter) ([]*entity.StockTrendPoint, error)
+		CompareStockSnapshots(context.Context, entity.StockSnapshotFilter) (*entity.StockComparison, error)
+	}
+
+	Valuation interface {
+		GetInventoryValuation(context.Context, uuid.UUID, string) (*entity.InventoryValuation, error)
+		GetCostOfGoodsSold(context.Context, entity.CostOfGoodsSoldPeriod) (*entity.CostOfGoodsSold, error)
 	}
 )
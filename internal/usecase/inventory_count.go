@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+)
+
+type InventoryCountUseCase struct {
+	repoInventoryCountPostgre InventoryCountPostgreRepo
+	repoProductPostgre        WarehouseProductPostgreRepo
+}
+
+func NewInventoryCountUseCase(
+	repoInventoryCountPostgre InventoryCountPostgreRepo,
+	repoProductPostgre WarehouseProductPostgreRepo,
+) *InventoryCountUseCase {
+	return &InventoryCountUseCase{
+		repoInventoryCountPostgre,
+		repoProductPostgre,
+	}
+}
+
+// open inventory count for the warehouse, current product quantity of the warehouse is the expected quantity
+func (u *InventoryCountUseCase) OpenInventoryCount(ctx context.Context, inventoryCount *entity.InventoryCount) error {
+	err := inventoryCount.GenerateInventoryCountID()
+	if err != nil {
+		return fmt.Errorf("failed to generate inventory count id: %w", err)
+	}
+
+	warehouseProducts, err := u.repoProductPostgre.GetByWarehouseID(ctx, inventoryCount.WarehouseID)
+	if err != nil {
+		return fmt.Errorf("failed to get warehouse products: %w", err)
+	}
+
+	if err := inventoryCount.Snapshot(warehouseProducts); err != nil {
+		return fmt.Errorf("failed to generate inventory count line id: %w", err)
+	}
+
+	inventoryCount.Status = entity.InventoryCountStatusOpen
+	inventoryCount.UpdatedAt = inventoryCount.CreatedAt
+
+	if err := u.repoInventoryCountPostgre.Save(ctx, inventoryCount); err != nil {
+		return fmt.Errorf("failed to save inventory count: %w", err)
+	}
+
+	return nil
+}
+
+// inventory count with the variance of each counted line, for reviewing before posting
+func (u *InventoryCountUseCase) GetInventoryCountByID(ctx context.Context, id uuid.UUID) (*entity.InventoryCount, error) {
+	inventoryCount, err := u.repoInventoryCountPostgre.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	inventoryCount.CalculateVariances()
+	return inventoryCount, nil
+}
+
+// save counted quantity of the lines, only product id and counted quantity of the lines are used
+func (u *InventoryCountUseCase) SubmitInventoryCountLines(ctx context.Context, inventoryCount *entity.InventoryCount) error {
+	for _, line := range inventoryCount.Lines {
+		if line.CountedQuantity == nil || *line.CountedQuantity < 0 {
+			return fmt.Errorf("counted quantity of product %s can not be empty or negative: %w", line.ProductID, entity.ErrInvalidInventoryCount)
+		}
+	}
+
+	inventoryCount.UpdatedAt = time.Now()
+	if err := u.repoInventoryCountPostgre.UpdateCountedQuantities(ctx, inventoryCount); err != nil {
+		return fmt.Errorf("failed to submit inventory count lines: %w", err)
+	}
+
+	return nil
+}
+
+// post inventory count, adjustment movements are created for each discrepancy
+func (u *InventoryCountUseCase) PostInventoryCount(ctx context.Context, inventoryCount *entity.InventoryCount) error {
+	inventoryCount.UpdatedAt = time.Now()
+	if err := u.repoInventoryCountPostgre.Post(ctx, inventoryCount); err != nil {
+		return fmt.Errorf("failed to post inventory count: %w", err)
+	}
+
+	return nil
+}
+
+func (u *InventoryCountUseCase) CancelInventoryCount(ctx context.Context, inventoryCount *entity.InventoryCount) error {
+	inventoryCount.UpdatedAt = time.Now()
+	if err := u.repoInventoryCountPostgre.Cancel(ctx, inventoryCount); err != nil {
+		return fmt.Errorf("failed to cancel inventory count: %w", err)
+	}
+
+	return nil
+}
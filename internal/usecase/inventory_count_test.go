@@ -0,0 +1,282 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+type TestInventoryCount struct {
+	name string
+	mock func()
+	err  error
+}
+
+func inventoryCount(t *testing.T) (*usecase.InventoryCountUseCase, *MockInventoryCountPostgreRepo, *MockWarehouseProductPostgreRepo) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	repoInventoryCount := NewMockInventoryCountPostgreRepo(mockCtl)
+	repoProduct := NewMockWarehouseProductPostgreRepo(mockCtl)
+	inventoryCount := usecase.NewInventoryCountUseCase(repoInventoryCount, repoProduct)
+
+	return inventoryCount, repoInventoryCount, repoProduct
+}
+
+func TestOpenInventoryCount(t *testing.T) {
+	// t.Parallell()
+	inventoryCount, repoInventoryCount, repoProduct := inventoryCount(t)
+	warehouseID := uuid.New()
+
+	tests := []TestInventoryCount{
+		{
+			name: "success",
+			mock: func() {
+				repoProduct.EXPECT().
+					GetByWarehouseID(context.Background(), warehouseID).
+					Return(mockWarehouseProducts, nil)
+
+				repoInventoryCount.EXPECT().
+					Save(context.Background(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, ic *entity.InventoryCount) error {
+						assert.Equal(t, entity.InventoryCountStatusOpen, ic.Status)
+						assert.Len(t, ic.Lines, len(mockWarehouseProducts))
+						for i, line := range ic.Lines {
+							assert.Equal(t, mockWarehouseProducts[i].ProductQuantity, line.ExpectedQuantity)
+						}
+						return nil
+					})
+			},
+			err: nil,
+		},
+		{
+			name: "failed to get warehouse products",
+			mock: func() {
+				repoProduct.EXPECT().
+					GetByWarehouseID(context.Background(), warehouseID).
+					Return(nil, errInternalServerError)
+			},
+			err: errInternalServerError,
+		},
+		{
+			name: "failed to save",
+			mock: func() {
+				repoProduct.EXPECT().
+					GetByWarehouseID(context.Background(), warehouseID).
+					Return(mockWarehouseProducts, nil)
+
+				repoInventoryCount.EXPECT().
+					Save(context.Background(), gomock.Any()).
+					Return(errInternalServerError)
+			},
+			err: errInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			input := &entity.InventoryCount{
+				WarehouseID: warehouseID,
+				CreatedAt:   time.Now(),
+			}
+			err := inventoryCount.OpenInventoryCount(context.Background(), input)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, input.ID)
+			assert.Equal(t, input.CreatedAt, input.UpdatedAt)
+		})
+	}
+}
+
+func TestGetInventoryCountByID(t *testing.T) {
+	// t.Parallell()
+	inventoryCount, repoInventoryCount, _ := inventoryCount(t)
+	id := uuid.New()
+	counted := int64(7)
+
+	repoInventoryCount.EXPECT().
+		GetByID(context.Background(), id).
+		Return(&entity.InventoryCount{
+			ID: id,
+			Lines: []*entity.InventoryCountLine{
+				{ExpectedQuantity: 10, CountedQuantity: &counted},
+				{ExpectedQuantity: 4},
+			},
+		}, nil)
+
+	res, err := inventoryCount.GetInventoryCountByID(context.Background(), id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-3), *res.Lines[0].Variance)
+	assert.Nil(t, res.Lines[1].Variance)
+}
+
+func TestSubmitInventoryCountLines(t *testing.T) {
+	// t.Parallell()
+	inventoryCount, repoInventoryCount, _ := inventoryCount(t)
+	counted := int64(3)
+	negative := int64(-1)
+
+	tests := []struct {
+		name  string
+		lines []*entity.InventoryCountLine
+		mock  func()
+		err   error
+	}{
+		{
+			name:  "success",
+			lines: []*entity.InventoryCountLine{{ProductID: uuid.New(), CountedQuantity: &counted}},
+			mock: func() {
+				repoInventoryCount.EXPECT().
+					UpdateCountedQuantities(context.Background(), gomock.Any()).
+					Return(nil)
+			},
+			err: nil,
+		},
+		{
+			name:  "missing counted quantity",
+			lines: []*entity.InventoryCountLine{{ProductID: uuid.New()}},
+			mock:  func() {},
+			err:   entity.ErrInvalidInventoryCount,
+		},
+		{
+			name:  "negative counted quantity",
+			lines: []*entity.InventoryCountLine{{ProductID: uuid.New(), CountedQuantity: &negative}},
+			mock:  func() {},
+			err:   entity.ErrInvalidInventoryCount,
+		},
+		{
+			name:  "inventory count is not open",
+			lines: []*entity.InventoryCountLine{{ProductID: uuid.New(), CountedQuantity: &counted}},
+			mock: func() {
+				repoInventoryCount.EXPECT().
+					UpdateCountedQuantities(context.Background(), gomock.Any()).
+					Return(entity.ErrInvalidInventoryCount)
+			},
+			err: entity.ErrInvalidInventoryCount,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			err := inventoryCount.SubmitInventoryCountLines(context.Background(), &entity.InventoryCount{
+				ID:    uuid.New(),
+				Lines: tc.lines,
+			})
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPostInventoryCount(t *testing.T) {
+	// t.Parallell()
+	inventoryCount, repoInventoryCount, _ := inventoryCount(t)
+
+	tests := []TestInventoryCount{
+		{
+			name: "success",
+			mock: func() {
+				repoInventoryCount.EXPECT().
+					Post(context.Background(), gomock.Any()).
+					Return(nil)
+			},
+			err: nil,
+		},
+		{
+			name: "error",
+			mock: func() {
+				repoInventoryCount.EXPECT().
+					Post(context.Background(), gomock.Any()).
+					Return(errInternalServerError)
+			},
+			err: errInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			input := &entity.InventoryCount{ID: uuid.New(), PostedBy: uuid.New()}
+			err := inventoryCount.PostInventoryCount(context.Background(), input)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.False(t, input.UpdatedAt.IsZero())
+		})
+	}
+}
+
+func TestCancelInventoryCount(t *testing.T) {
+	// t.Parallell()
+	inventoryCount, repoInventoryCount, _ := inventoryCount(t)
+
+	tests := []TestInventoryCount{
+		{
+			name: "success",
+			mock: func() {
+				repoInventoryCount.EXPECT().
+					Cancel(context.Background(), gomock.Any()).
+					Return(nil)
+			},
+			err: nil,
+		},
+		{
+			name: "error",
+			mock: func() {
+				repoInventoryCount.EXPECT().
+					Cancel(context.Background(), gomock.Any()).
+					Return(entity.ErrInvalidInventoryCount)
+			},
+			err: entity.ErrInvalidInventoryCount,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			err := inventoryCount.CancelInventoryCount(context.Background(), &entity.InventoryCount{ID: uuid.New()})
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
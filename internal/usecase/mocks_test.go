@@ -12,65 +12,13 @@ package usecase_test
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	entity "github.com/idoyudha/eshop-warehouse/internal/entity"
 	gomock "go.uber.org/mock/gomock"
 )
 
-// MockWarehouseRankRedisRepo is a mock of WarehouseRankRedisRepo interface.
-type MockWarehouseRankRedisRepo struct {
-	ctrl     *gomock.Controller
-	recorder *MockWarehouseRankRedisRepoMockRecorder
-	isgomock struct{}
-}
-
-// MockWarehouseRankRedisRepoMockRecorder is the mock recorder for MockWarehouseRankRedisRepo.
-type MockWarehouseRankRedisRepoMockRecorder struct {
-	mock *MockWarehouseRankRedisRepo
-}
-
-// NewMockWarehouseRankRedisRepo creates a new mock instance.
-func NewMockWarehouseRankRedisRepo(ctrl *gomock.Controller) *MockWarehouseRankRedisRepo {
-	mock := &MockWarehouseRankRedisRepo{ctrl: ctrl}
-	mock.recorder = &MockWarehouseRankRedisRepoMockRecorder{mock}
-	return mock
-}
-
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockWarehouseRankRedisRepo) EXPECT() *MockWarehouseRankRedisRepoMockRecorder {
-	return m.recorder
-}
-
-// GetNearestWarehouses mocks base method.
-func (m *MockWarehouseRankRedisRepo) GetNearestWarehouses(arg0 context.Context, arg1 string) ([]entity.WarehouseDistance, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNearestWarehouses", arg0, arg1)
-	ret0, _ := ret[0].([]entity.WarehouseDistance)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// GetNearestWarehouses indicates an expected call of GetNearestWarehouses.
-func (mr *MockWarehouseRankRedisRepoMockRecorder) GetNearestWarehouses(arg0, arg1 any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNearestWarehouses", reflect.TypeOf((*MockWarehouseRankRedisRepo)(nil).GetNearestWarehouses), arg0, arg1)
-}
-
-// UpdateWarehouseRanks mocks base method.
-func (m *MockWarehouseRankRedisRepo) UpdateWarehouseRanks(arg0 context.Context, arg1, arg2 string, arg3 map[string]float64) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateWarehouseRanks", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// UpdateWarehouseRanks indicates an expected call of UpdateWarehouseRanks.
-func (mr *MockWarehouseRankRedisRepoMockRecorder) UpdateWarehouseRanks(arg0, arg1, arg2, arg3 any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWarehouseRanks", reflect.TypeOf((*MockWarehouseRankRedisRepo)(nil).UpdateWarehouseRanks), arg0, arg1, arg2, arg3)
-}
-
 // MockWarehousePostgreRepo is a mock of WarehousePostgreRepo interface.
 type MockWarehousePostgreRepo struct {
 	ctrl     *gomock.Controller
@@ -222,6 +170,20 @@ func (m *MockWarehouseProductPostgreRepo) EXPECT() *MockWarehouseProductPostgreR
 	return m.recorder
 }
 
+// Adjust mocks base method.
+func (m *MockWarehouseProductPostgreRepo) Adjust(arg0 context.Context, arg1 *entity.StockAdjustment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Adjust", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Adjust indicates an expected call of Adjust.
+func (mr *MockWarehouseProductPostgreRepoMockRecorder) Adjust(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adjust", reflect.TypeOf((*MockWarehouseProductPostgreRepo)(nil).Adjust), arg0, arg1)
+}
+
 // GetAll mocks base method.
 func (m *MockWarehouseProductPostgreRepo) GetAll(arg0 context.Context) ([]*entity.WarehouseProduct, error) {
 	m.ctrl.T.Helper()
@@ -340,20 +302,6 @@ func (mr *MockWarehouseProductPostgreRepoMockRecorder) Update(arg0, arg1 any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWarehouseProductPostgreRepo)(nil).Update), arg0, arg1)
 }
 
-// UpdateProductQuantity mocks base method.
-func (m *MockWarehouseProductPostgreRepo) UpdateProductQuantity(arg0 context.Context, arg1 *entity.WarehouseProduct) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateProductQuantity", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// UpdateProductQuantity indicates an expected call of UpdateProductQuantity.
-func (mr *MockWarehouseProductPostgreRepoMockRecorder) UpdateProductQuantity(arg0, arg1 any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProductQuantity", reflect.TypeOf((*MockWarehouseProductPostgreRepo)(nil).UpdateProductQuantity), arg0, arg1)
-}
-
 // MockStockMovementPostgreRepo is a mock of StockMovementPostgreRepo interface.
 type MockStockMovementPostgreRepo struct {
 	ctrl     *gomock.Controller
@@ -379,63 +327,93 @@ func (m *MockStockMovementPostgreRepo) EXPECT() *MockStockMovementPostgreRepoMoc
 }
 
 // GetAll mocks base method.
-func (m *MockStockMovementPostgreRepo) GetAll(ctx context.Context) ([]*entity.StockMovement, error) {
+func (m *MockStockMovementPostgreRepo) GetAll(arg0 context.Context, arg1 entity.StockMovementFilter) ([]*entity.StockMovement, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret := m.ctrl.Call(m, "GetAll", arg0, arg1)
 	ret0, _ := ret[0].([]*entity.StockMovement)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockStockMovementPostgreRepoMockRecorder) GetAll(ctx any) *gomock.Call {
+func (mr *MockStockMovementPostgreRepoMockRecorder) GetAll(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockStockMovementPostgreRepo)(nil).GetAll), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockStockMovementPostgreRepo)(nil).GetAll), arg0, arg1)
 }
 
 // GetByDestinationID mocks base method.
-func (m *MockStockMovementPostgreRepo) GetByDestinationID(arg0 context.Context, arg1 uuid.UUID) ([]*entity.StockMovement, error) {
+func (m *MockStockMovementPostgreRepo) GetByDestinationID(arg0 context.Context, arg1 uuid.UUID, arg2 entity.StockMovementFilter) ([]*entity.StockMovement, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByDestinationID", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetByDestinationID", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]*entity.StockMovement)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByDestinationID indicates an expected call of GetByDestinationID.
-func (mr *MockStockMovementPostgreRepoMockRecorder) GetByDestinationID(arg0, arg1 any) *gomock.Call {
+func (mr *MockStockMovementPostgreRepoMockRecorder) GetByDestinationID(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDestinationID", reflect.TypeOf((*MockStockMovementPostgreRepo)(nil).GetByDestinationID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDestinationID", reflect.TypeOf((*MockStockMovementPostgreRepo)(nil).GetByDestinationID), arg0, arg1, arg2)
 }
 
 // GetByProductID mocks base method.
-func (m *MockStockMovementPostgreRepo) GetByProductID(arg0 context.Context, arg1 uuid.UUID) ([]*entity.StockMovement, error) {
+func (m *MockStockMovementPostgreRepo) GetByProductID(arg0 context.Context, arg1 uuid.UUID, arg2 entity.StockMovementFilter) ([]*entity.StockMovement, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByProductID", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetByProductID", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]*entity.StockMovement)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByProductID indicates an expected call of GetByProductID.
-func (mr *MockStockMovementPostgreRepoMockRecorder) GetByProductID(arg0, arg1 any) *gomock.Call {
+func (mr *MockStockMovementPostgreRepoMockRecorder) GetByProductID(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProductID", reflect.TypeOf((*MockStockMovementPostgreRepo)(nil).GetByProductID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProductID", reflect.TypeOf((*MockStockMovementPostgreRepo)(nil).GetByProductID), arg0, arg1, arg2)
 }
 
 // GetBySourceID mocks base method.
-func (m *MockStockMovementPostgreRepo) GetBySourceID(arg0 context.Context, arg1 uuid.UUID) ([]*entity.StockMovement, error) {
+func (m *MockStockMovementPostgreRepo) GetBySourceID(arg0 context.Context, arg1 uuid.UUID, arg2 entity.StockMovementFilter) ([]*entity.StockMovement, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBySourceID", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetBySourceID", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]*entity.StockMovement)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBySourceID indicates an expected call of GetBySourceID.
-func (mr *MockStockMovementPostgreRepoMockRecorder) GetBySourceID(arg0, arg1 any) *gomock.Call {
+func (mr *MockStockMovementPostgreRepoMockRecorder) GetBySourceID(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySourceID", reflect.TypeOf((*MockStockMovementPostgreRepo)(nil).GetBySourceID), arg0, arg1, arg2)
+}
+
+// GetDemandHistory mocks base method.
+func (m *MockStockMovementPostgreRepo) GetDemandHistory(arg0 context.Context, arg1, arg2 time.Time, arg3, arg4 uuid.UUID) ([]*entity.DemandHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDemandHistory", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]*entity.DemandHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDemandHistory indicates an expected call of GetDemandHistory.
+func (mr *MockStockMovementPostgreRepoMockRecorder) GetDemandHistory(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDemandHistory", reflect.TypeOf((*MockStockMovementPostgreRepo)(nil).GetDemandHistory), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetOutboundDemand mocks base method.
+func (m *MockStockMovementPostgreRepo) GetOutboundDemand(arg0 context.Context, arg1 time.Time) ([]*entity.WarehouseDemand, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutboundDemand", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.WarehouseDemand)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutboundDemand indicates an expected call of GetOutboundDemand.
+func (mr *MockStockMovementPostgreRepoMockRecorder) GetOutboundDemand(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySourceID", reflect.TypeOf((*MockStockMovementPostgreRepo)(nil).GetBySourceID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboundDemand", reflect.TypeOf((*MockStockMovementPostgreRepo)(nil).GetOutboundDemand), arg0, arg1)
 }
 
 // MockTransactionProductPostgresRepo is a mock of TransactionProductPostgresRepo interface.
@@ -462,6 +440,36 @@ func (m *MockTransactionProductPostgresRepo) EXPECT() *MockTransactionProductPos
 	return m.recorder
 }
 
+// CommitReservation mocks base method.
+func (m *MockTransactionProductPostgresRepo) CommitReservation(arg0 context.Context, arg1 uuid.UUID, arg2 time.Time) ([]*entity.StockMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommitReservation", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*entity.StockMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommitReservation indicates an expected call of CommitReservation.
+func (mr *MockTransactionProductPostgresRepoMockRecorder) CommitReservation(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitReservation", reflect.TypeOf((*MockTransactionProductPostgresRepo)(nil).CommitReservation), arg0, arg1, arg2)
+}
+
+// ReverseTransferOut mocks base method.
+func (m *MockTransactionProductPostgresRepo) ReverseTransferOut(arg0 context.Context, arg1, arg2 string, arg3 time.Time) ([]*entity.StockMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseTransferOut", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*entity.StockMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverseTransferOut indicates an expected call of ReverseTransferOut.
+func (mr *MockTransactionProductPostgresRepoMockRecorder) ReverseTransferOut(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseTransferOut", reflect.TypeOf((*MockTransactionProductPostgresRepo)(nil).ReverseTransferOut), arg0, arg1, arg2, arg3)
+}
+
 // TransferIn mocks base method.
 func (m *MockTransactionProductPostgresRepo) TransferIn(arg0 context.Context, arg1 *entity.StockMovement) error {
 	m.ctrl.T.Helper()
@@ -477,404 +485,2044 @@ func (mr *MockTransactionProductPostgresRepoMockRecorder) TransferIn(arg0, arg1
 }
 
 // TransferOut mocks base method.
-func (m *MockTransactionProductPostgresRepo) TransferOut(arg0 context.Context, arg1 []*entity.StockMovement) error {
+func (m *MockTransactionProductPostgresRepo) TransferOut(arg0 context.Context, arg1 []*entity.StockMovement, arg2 func([]*entity.WarehouseAddressAndProductQty) ([]*entity.StockMovement, error)) ([]*entity.StockMovement, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TransferOut", arg0, arg1)
+	ret := m.ctrl.Call(m, "TransferOut", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*entity.StockMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferOut indicates an expected call of TransferOut.
+func (mr *MockTransactionProductPostgresRepoMockRecorder) TransferOut(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferOut", reflect.TypeOf((*MockTransactionProductPostgresRepo)(nil).TransferOut), arg0, arg1, arg2)
+}
+
+// TransferReturn mocks base method.
+func (m *MockTransactionProductPostgresRepo) TransferReturn(arg0 context.Context, arg1 *entity.StockMovement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferReturn", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// TransferOut indicates an expected call of TransferOut.
-func (mr *MockTransactionProductPostgresRepoMockRecorder) TransferOut(arg0, arg1 any) *gomock.Call {
+// TransferReturn indicates an expected call of TransferReturn.
+func (mr *MockTransactionProductPostgresRepoMockRecorder) TransferReturn(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferOut", reflect.TypeOf((*MockTransactionProductPostgresRepo)(nil).TransferOut), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferReturn", reflect.TypeOf((*MockTransactionProductPostgresRepo)(nil).TransferReturn), arg0, arg1)
 }
 
-// MockWarehouse is a mock of Warehouse interface.
-type MockWarehouse struct {
+// MockReservationPostgreRepo is a mock of ReservationPostgreRepo interface.
+type MockReservationPostgreRepo struct {
 	ctrl     *gomock.Controller
-	recorder *MockWarehouseMockRecorder
+	recorder *MockReservationPostgreRepoMockRecorder
 	isgomock struct{}
 }
 
-// MockWarehouseMockRecorder is the mock recorder for MockWarehouse.
-type MockWarehouseMockRecorder struct {
-	mock *MockWarehouse
+// MockReservationPostgreRepoMockRecorder is the mock recorder for MockReservationPostgreRepo.
+type MockReservationPostgreRepoMockRecorder struct {
+	mock *MockReservationPostgreRepo
 }
 
-// NewMockWarehouse creates a new mock instance.
-func NewMockWarehouse(ctrl *gomock.Controller) *MockWarehouse {
-	mock := &MockWarehouse{ctrl: ctrl}
-	mock.recorder = &MockWarehouseMockRecorder{mock}
+// NewMockReservationPostgreRepo creates a new mock instance.
+func NewMockReservationPostgreRepo(ctrl *gomock.Controller) *MockReservationPostgreRepo {
+	mock := &MockReservationPostgreRepo{ctrl: ctrl}
+	mock.recorder = &MockReservationPostgreRepoMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockWarehouse) EXPECT() *MockWarehouseMockRecorder {
+func (m *MockReservationPostgreRepo) EXPECT() *MockReservationPostgreRepoMockRecorder {
 	return m.recorder
 }
 
-// CreateWarehouse mocks base method.
-func (m *MockWarehouse) CreateWarehouse(arg0 context.Context, arg1 *entity.Warehouse) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateWarehouse", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// CreateWarehouse indicates an expected call of CreateWarehouse.
-func (mr *MockWarehouseMockRecorder) CreateWarehouse(arg0, arg1 any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWarehouse", reflect.TypeOf((*MockWarehouse)(nil).CreateWarehouse), arg0, arg1)
-}
-
-// GetAllWarehouses mocks base method.
-func (m *MockWarehouse) GetAllWarehouses(arg0 context.Context) ([]*entity.Warehouse, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllWarehouses", arg0)
-	ret0, _ := ret[0].([]*entity.Warehouse)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// GetAllWarehouses indicates an expected call of GetAllWarehouses.
-func (mr *MockWarehouseMockRecorder) GetAllWarehouses(arg0 any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllWarehouses", reflect.TypeOf((*MockWarehouse)(nil).GetAllWarehouses), arg0)
-}
-
-// GetMainIDWarehouse mocks base method.
-func (m *MockWarehouse) GetMainIDWarehouse(arg0 context.Context) (uuid.UUID, error) {
+// ExpireAll mocks base method.
+func (m *MockReservationPostgreRepo) ExpireAll(arg0 context.Context, arg1 time.Time) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMainIDWarehouse", arg0)
-	ret0, _ := ret[0].(uuid.UUID)
+	ret := m.ctrl.Call(m, "ExpireAll", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetMainIDWarehouse indicates an expected call of GetMainIDWarehouse.
-func (mr *MockWarehouseMockRecorder) GetMainIDWarehouse(arg0 any) *gomock.Call {
+// ExpireAll indicates an expected call of ExpireAll.
+func (mr *MockReservationPostgreRepoMockRecorder) ExpireAll(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMainIDWarehouse", reflect.TypeOf((*MockWarehouse)(nil).GetMainIDWarehouse), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireAll", reflect.TypeOf((*MockReservationPostgreRepo)(nil).ExpireAll), arg0, arg1)
 }
 
-// GetNearestWarehouse mocks base method.
-func (m *MockWarehouse) GetNearestWarehouse(arg0 context.Context, arg1 []string) (map[string]string, error) {
+// GetByID mocks base method.
+func (m *MockReservationPostgreRepo) GetByID(arg0 context.Context, arg1 uuid.UUID) (*entity.Reservation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNearestWarehouse", arg0, arg1)
-	ret0, _ := ret[0].(map[string]string)
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Reservation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetNearestWarehouse indicates an expected call of GetNearestWarehouse.
-func (mr *MockWarehouseMockRecorder) GetNearestWarehouse(arg0, arg1 any) *gomock.Call {
+// GetByID indicates an expected call of GetByID.
+func (mr *MockReservationPostgreRepoMockRecorder) GetByID(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNearestWarehouse", reflect.TypeOf((*MockWarehouse)(nil).GetNearestWarehouse), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockReservationPostgreRepo)(nil).GetByID), arg0, arg1)
 }
 
-// GetWarehouseByID mocks base method.
-func (m *MockWarehouse) GetWarehouseByID(arg0 context.Context, arg1 uuid.UUID) (*entity.Warehouse, error) {
+// Save mocks base method.
+func (m *MockReservationPostgreRepo) Save(arg0 context.Context, arg1 *entity.Reservation, arg2 time.Duration) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetWarehouseByID", arg0, arg1)
-	ret0, _ := ret[0].(*entity.Warehouse)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "Save", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetWarehouseByID indicates an expected call of GetWarehouseByID.
-func (mr *MockWarehouseMockRecorder) GetWarehouseByID(arg0, arg1 any) *gomock.Call {
+// Save indicates an expected call of Save.
+func (mr *MockReservationPostgreRepoMockRecorder) Save(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarehouseByID", reflect.TypeOf((*MockWarehouse)(nil).GetWarehouseByID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockReservationPostgreRepo)(nil).Save), arg0, arg1, arg2)
 }
 
-// UpdateWarehouse mocks base method.
-func (m *MockWarehouse) UpdateWarehouse(arg0 context.Context, arg1 *entity.Warehouse) error {
+// UpdateStatus mocks base method.
+func (m *MockReservationPostgreRepo) UpdateStatus(arg0 context.Context, arg1 *entity.Reservation) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateWarehouse", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateStatus", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateWarehouse indicates an expected call of UpdateWarehouse.
-func (mr *MockWarehouseMockRecorder) UpdateWarehouse(arg0, arg1 any) *gomock.Call {
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockReservationPostgreRepoMockRecorder) UpdateStatus(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWarehouse", reflect.TypeOf((*MockWarehouse)(nil).UpdateWarehouse), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockReservationPostgreRepo)(nil).UpdateStatus), arg0, arg1)
 }
 
-// MockWarehouseProduct is a mock of WarehouseProduct interface.
-type MockWarehouseProduct struct {
+// MockInventoryCountPostgreRepo is a mock of InventoryCountPostgreRepo interface.
+type MockInventoryCountPostgreRepo struct {
 	ctrl     *gomock.Controller
-	recorder *MockWarehouseProductMockRecorder
+	recorder *MockInventoryCountPostgreRepoMockRecorder
 	isgomock struct{}
 }
 
-// MockWarehouseProductMockRecorder is the mock recorder for MockWarehouseProduct.
-type MockWarehouseProductMockRecorder struct {
-	mock *MockWarehouseProduct
+// MockInventoryCountPostgreRepoMockRecorder is the mock recorder for MockInventoryCountPostgreRepo.
+type MockInventoryCountPostgreRepoMockRecorder struct {
+	mock *MockInventoryCountPostgreRepo
 }
 
-// NewMockWarehouseProduct creates a new mock instance.
-func NewMockWarehouseProduct(ctrl *gomock.Controller) *MockWarehouseProduct {
-	mock := &MockWarehouseProduct{ctrl: ctrl}
-	mock.recorder = &MockWarehouseProductMockRecorder{mock}
+// NewMockInventoryCountPostgreRepo creates a new mock instance.
+func NewMockInventoryCountPostgreRepo(ctrl *gomock.Controller) *MockInventoryCountPostgreRepo {
+	mock := &MockInventoryCountPostgreRepo{ctrl: ctrl}
+	mock.recorder = &MockInventoryCountPostgreRepoMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockWarehouseProduct) EXPECT() *MockWarehouseProductMockRecorder {
+func (m *MockInventoryCountPostgreRepo) EXPECT() *MockInventoryCountPostgreRepoMockRecorder {
 	return m.recorder
 }
 
-// CreateWarehouseProduct mocks base method.
-func (m *MockWarehouseProduct) CreateWarehouseProduct(arg0 context.Context, arg1 *entity.WarehouseProduct) error {
+// Cancel mocks base method.
+func (m *MockInventoryCountPostgreRepo) Cancel(arg0 context.Context, arg1 *entity.InventoryCount) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateWarehouseProduct", arg0, arg1)
+	ret := m.ctrl.Call(m, "Cancel", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateWarehouseProduct indicates an expected call of CreateWarehouseProduct.
-func (mr *MockWarehouseProductMockRecorder) CreateWarehouseProduct(arg0, arg1 any) *gomock.Call {
+// Cancel indicates an expected call of Cancel.
+func (mr *MockInventoryCountPostgreRepoMockRecorder) Cancel(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWarehouseProduct", reflect.TypeOf((*MockWarehouseProduct)(nil).CreateWarehouseProduct), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockInventoryCountPostgreRepo)(nil).Cancel), arg0, arg1)
 }
 
-// GetAllWarehouseProducts mocks base method.
-func (m *MockWarehouseProduct) GetAllWarehouseProducts(arg0 context.Context) ([]*entity.WarehouseProduct, error) {
+// GetByID mocks base method.
+func (m *MockInventoryCountPostgreRepo) GetByID(arg0 context.Context, arg1 uuid.UUID) (*entity.InventoryCount, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllWarehouseProducts", arg0)
-	ret0, _ := ret[0].([]*entity.WarehouseProduct)
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
+	ret0, _ := ret[0].(*entity.InventoryCount)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAllWarehouseProducts indicates an expected call of GetAllWarehouseProducts.
-func (mr *MockWarehouseProductMockRecorder) GetAllWarehouseProducts(arg0 any) *gomock.Call {
+// GetByID indicates an expected call of GetByID.
+func (mr *MockInventoryCountPostgreRepoMockRecorder) GetByID(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllWarehouseProducts", reflect.TypeOf((*MockWarehouseProduct)(nil).GetAllWarehouseProducts), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockInventoryCountPostgreRepo)(nil).GetByID), arg0, arg1)
 }
 
-// GetNearestWarehouseZipCodeByProductID mocks base method.
-func (m *MockWarehouseProduct) GetNearestWarehouseZipCodeByProductID(arg0 context.Context, arg1 string, arg2 uuid.UUID) (*string, error) {
+// Post mocks base method.
+func (m *MockInventoryCountPostgreRepo) Post(arg0 context.Context, arg1 *entity.InventoryCount) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNearestWarehouseZipCodeByProductID", arg0, arg1, arg2)
-	ret0, _ := ret[0].(*string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "Post", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetNearestWarehouseZipCodeByProductID indicates an expected call of GetNearestWarehouseZipCodeByProductID.
-func (mr *MockWarehouseProductMockRecorder) GetNearestWarehouseZipCodeByProductID(arg0, arg1, arg2 any) *gomock.Call {
+// Post indicates an expected call of Post.
+func (mr *MockInventoryCountPostgreRepoMockRecorder) Post(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNearestWarehouseZipCodeByProductID", reflect.TypeOf((*MockWarehouseProduct)(nil).GetNearestWarehouseZipCodeByProductID), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockInventoryCountPostgreRepo)(nil).Post), arg0, arg1)
 }
 
-// GetWarehouseProductByProductID mocks base method.
-func (m *MockWarehouseProduct) GetWarehouseProductByProductID(arg0 context.Context, arg1 uuid.UUID) ([]*entity.WarehouseProduct, error) {
+// Save mocks base method.
+func (m *MockInventoryCountPostgreRepo) Save(arg0 context.Context, arg1 *entity.InventoryCount) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetWarehouseProductByProductID", arg0, arg1)
-	ret0, _ := ret[0].([]*entity.WarehouseProduct)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "Save", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetWarehouseProductByProductID indicates an expected call of GetWarehouseProductByProductID.
-func (mr *MockWarehouseProductMockRecorder) GetWarehouseProductByProductID(arg0, arg1 any) *gomock.Call {
+// Save indicates an expected call of Save.
+func (mr *MockInventoryCountPostgreRepoMockRecorder) Save(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarehouseProductByProductID", reflect.TypeOf((*MockWarehouseProduct)(nil).GetWarehouseProductByProductID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockInventoryCountPostgreRepo)(nil).Save), arg0, arg1)
 }
 
-// GetWarehouseProductByProductIDAndWarehouseID mocks base method.
-func (m *MockWarehouseProduct) GetWarehouseProductByProductIDAndWarehouseID(arg0 context.Context, arg1, arg2 uuid.UUID) (*entity.WarehouseProduct, error) {
+// UpdateCountedQuantities mocks base method.
+func (m *MockInventoryCountPostgreRepo) UpdateCountedQuantities(arg0 context.Context, arg1 *entity.InventoryCount) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetWarehouseProductByProductIDAndWarehouseID", arg0, arg1, arg2)
-	ret0, _ := ret[0].(*entity.WarehouseProduct)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "UpdateCountedQuantities", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetWarehouseProductByProductIDAndWarehouseID indicates an expected call of GetWarehouseProductByProductIDAndWarehouseID.
-func (mr *MockWarehouseProductMockRecorder) GetWarehouseProductByProductIDAndWarehouseID(arg0, arg1, arg2 any) *gomock.Call {
+// UpdateCountedQuantities indicates an expected call of UpdateCountedQuantities.
+func (mr *MockInventoryCountPostgreRepoMockRecorder) UpdateCountedQuantities(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarehouseProductByProductIDAndWarehouseID", reflect.TypeOf((*MockWarehouseProduct)(nil).GetWarehouseProductByProductIDAndWarehouseID), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCountedQuantities", reflect.TypeOf((*MockInventoryCountPostgreRepo)(nil).UpdateCountedQuantities), arg0, arg1)
 }
 
-// GetWarehouseProductByWarehouseID mocks base method.
-func (m *MockWarehouseProduct) GetWarehouseProductByWarehouseID(arg0 context.Context, arg1 uuid.UUID) ([]*entity.WarehouseProduct, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetWarehouseProductByWarehouseID", arg0, arg1)
-	ret0, _ := ret[0].([]*entity.WarehouseProduct)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+// MockInboundReceiptPostgreRepo is a mock of InboundReceiptPostgreRepo interface.
+type MockInboundReceiptPostgreRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockInboundReceiptPostgreRepoMockRecorder
+	isgomock struct{}
 }
 
-// GetWarehouseProductByWarehouseID indicates an expected call of GetWarehouseProductByWarehouseID.
-func (mr *MockWarehouseProductMockRecorder) GetWarehouseProductByWarehouseID(arg0, arg1 any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarehouseProductByWarehouseID", reflect.TypeOf((*MockWarehouseProduct)(nil).GetWarehouseProductByWarehouseID), arg0, arg1)
+// MockInboundReceiptPostgreRepoMockRecorder is the mock recorder for MockInboundReceiptPostgreRepo.
+type MockInboundReceiptPostgreRepoMockRecorder struct {
+	mock *MockInboundReceiptPostgreRepo
 }
 
-// UpdateWarehouseProduct mocks base method.
-func (m *MockWarehouseProduct) UpdateWarehouseProduct(arg0 context.Context, arg1 *entity.WarehouseProduct) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateWarehouseProduct", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+// NewMockInboundReceiptPostgreRepo creates a new mock instance.
+func NewMockInboundReceiptPostgreRepo(ctrl *gomock.Controller) *MockInboundReceiptPostgreRepo {
+	mock := &MockInboundReceiptPostgreRepo{ctrl: ctrl}
+	mock.recorder = &MockInboundReceiptPostgreRepoMockRecorder{mock}
+	return mock
 }
 
-// UpdateWarehouseProduct indicates an expected call of UpdateWarehouseProduct.
-func (mr *MockWarehouseProductMockRecorder) UpdateWarehouseProduct(arg0, arg1 any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWarehouseProduct", reflect.TypeOf((*MockWarehouseProduct)(nil).UpdateWarehouseProduct), arg0, arg1)
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInboundReceiptPostgreRepo) EXPECT() *MockInboundReceiptPostgreRepoMockRecorder {
+	return m.recorder
 }
 
-// UpdateWarehouseProductQuantity mocks base method.
-func (m *MockWarehouseProduct) UpdateWarehouseProductQuantity(arg0 context.Context, arg1 *entity.WarehouseProduct) error {
+// Close mocks base method.
+func (m *MockInboundReceiptPostgreRepo) Close(arg0 context.Context, arg1 *entity.InboundReceipt) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateWarehouseProductQuantity", arg0, arg1)
+	ret := m.ctrl.Call(m, "Close", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateWarehouseProductQuantity indicates an expected call of UpdateWarehouseProductQuantity.
-func (mr *MockWarehouseProductMockRecorder) UpdateWarehouseProductQuantity(arg0, arg1 any) *gomock.Call {
+// Close indicates an expected call of Close.
+func (mr *MockInboundReceiptPostgreRepoMockRecorder) Close(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWarehouseProductQuantity", reflect.TypeOf((*MockWarehouseProduct)(nil).UpdateWarehouseProductQuantity), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockInboundReceiptPostgreRepo)(nil).Close), arg0, arg1)
+}
+
+// GetByID mocks base method.
+func (m *MockInboundReceiptPostgreRepo) GetByID(arg0 context.Context, arg1 uuid.UUID) (*entity.InboundReceipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
+	ret0, _ := ret[0].(*entity.InboundReceipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockInboundReceiptPostgreRepoMockRecorder) GetByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockInboundReceiptPostgreRepo)(nil).GetByID), arg0, arg1)
+}
+
+// Receive mocks base method.
+func (m *MockInboundReceiptPostgreRepo) Receive(arg0 context.Context, arg1 *entity.InboundReceipt, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Receive", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Receive indicates an expected call of Receive.
+func (mr *MockInboundReceiptPostgreRepoMockRecorder) Receive(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Receive", reflect.TypeOf((*MockInboundReceiptPostgreRepo)(nil).Receive), arg0, arg1, arg2)
+}
+
+// Save mocks base method.
+func (m *MockInboundReceiptPostgreRepo) Save(arg0 context.Context, arg1 *entity.InboundReceipt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockInboundReceiptPostgreRepoMockRecorder) Save(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockInboundReceiptPostgreRepo)(nil).Save), arg0, arg1)
+}
+
+// MockTransferOrderPostgreRepo is a mock of TransferOrderPostgreRepo interface.
+type MockTransferOrderPostgreRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransferOrderPostgreRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockTransferOrderPostgreRepoMockRecorder is the mock recorder for MockTransferOrderPostgreRepo.
+type MockTransferOrderPostgreRepoMockRecorder struct {
+	mock *MockTransferOrderPostgreRepo
+}
+
+// NewMockTransferOrderPostgreRepo creates a new mock instance.
+func NewMockTransferOrderPostgreRepo(ctrl *gomock.Controller) *MockTransferOrderPostgreRepo {
+	mock := &MockTransferOrderPostgreRepo{ctrl: ctrl}
+	mock.recorder = &MockTransferOrderPostgreRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransferOrderPostgreRepo) EXPECT() *MockTransferOrderPostgreRepoMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockTransferOrderPostgreRepo) Cancel(arg0 context.Context, arg1 *entity.TransferOrder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockTransferOrderPostgreRepoMockRecorder) Cancel(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockTransferOrderPostgreRepo)(nil).Cancel), arg0, arg1)
+}
+
+// GetByID mocks base method.
+func (m *MockTransferOrderPostgreRepo) GetByID(arg0 context.Context, arg1 uuid.UUID) (*entity.TransferOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
+	ret0, _ := ret[0].(*entity.TransferOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockTransferOrderPostgreRepoMockRecorder) GetByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTransferOrderPostgreRepo)(nil).GetByID), arg0, arg1)
+}
+
+// GetInTransitByProductID mocks base method.
+func (m *MockTransferOrderPostgreRepo) GetInTransitByProductID(arg0 context.Context, arg1 uuid.UUID) ([]*entity.TransferOrderInTransit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInTransitByProductID", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.TransferOrderInTransit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInTransitByProductID indicates an expected call of GetInTransitByProductID.
+func (mr *MockTransferOrderPostgreRepoMockRecorder) GetInTransitByProductID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInTransitByProductID", reflect.TypeOf((*MockTransferOrderPostgreRepo)(nil).GetInTransitByProductID), arg0, arg1)
+}
+
+// Pick mocks base method.
+func (m *MockTransferOrderPostgreRepo) Pick(arg0 context.Context, arg1 *entity.TransferOrder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pick", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pick indicates an expected call of Pick.
+func (mr *MockTransferOrderPostgreRepoMockRecorder) Pick(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pick", reflect.TypeOf((*MockTransferOrderPostgreRepo)(nil).Pick), arg0, arg1)
+}
+
+// Receive mocks base method.
+func (m *MockTransferOrderPostgreRepo) Receive(arg0 context.Context, arg1 *entity.TransferOrder, arg2 *entity.TransferOrderDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Receive", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Receive indicates an expected call of Receive.
+func (mr *MockTransferOrderPostgreRepoMockRecorder) Receive(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Receive", reflect.TypeOf((*MockTransferOrderPostgreRepo)(nil).Receive), arg0, arg1, arg2)
+}
+
+// Save mocks base method.
+func (m *MockTransferOrderPostgreRepo) Save(arg0 context.Context, arg1 *entity.TransferOrder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockTransferOrderPostgreRepoMockRecorder) Save(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockTransferOrderPostgreRepo)(nil).Save), arg0, arg1)
+}
+
+// Ship mocks base method.
+func (m *MockTransferOrderPostgreRepo) Ship(arg0 context.Context, arg1 *entity.TransferOrder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ship", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ship indicates an expected call of Ship.
+func (mr *MockTransferOrderPostgreRepoMockRecorder) Ship(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ship", reflect.TypeOf((*MockTransferOrderPostgreRepo)(nil).Ship), arg0, arg1)
+}
+
+// MockStockThresholdPostgreRepo is a mock of StockThresholdPostgreRepo interface.
+type MockStockThresholdPostgreRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockThresholdPostgreRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockStockThresholdPostgreRepoMockRecorder is the mock recorder for MockStockThresholdPostgreRepo.
+type MockStockThresholdPostgreRepoMockRecorder struct {
+	mock *MockStockThresholdPostgreRepo
+}
+
+// NewMockStockThresholdPostgreRepo creates a new mock instance.
+func NewMockStockThresholdPostgreRepo(ctrl *gomock.Controller) *MockStockThresholdPostgreRepo {
+	mock := &MockStockThresholdPostgreRepo{ctrl: ctrl}
+	mock.recorder = &MockStockThresholdPostgreRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockThresholdPostgreRepo) EXPECT() *MockStockThresholdPostgreRepoMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockStockThresholdPostgreRepo) Delete(arg0 context.Context, arg1, arg2 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockStockThresholdPostgreRepoMockRecorder) Delete(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStockThresholdPostgreRepo)(nil).Delete), arg0, arg1, arg2)
+}
+
+// EvaluateLowStocks mocks base method.
+func (m *MockStockThresholdPostgreRepo) EvaluateLowStocks(arg0 context.Context, arg1 time.Time) ([]*entity.StockLevel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvaluateLowStocks", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.StockLevel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EvaluateLowStocks indicates an expected call of EvaluateLowStocks.
+func (mr *MockStockThresholdPostgreRepoMockRecorder) EvaluateLowStocks(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateLowStocks", reflect.TypeOf((*MockStockThresholdPostgreRepo)(nil).EvaluateLowStocks), arg0, arg1)
+}
+
+// GetByWarehouseID mocks base method.
+func (m *MockStockThresholdPostgreRepo) GetByWarehouseID(arg0 context.Context, arg1 uuid.UUID) ([]*entity.StockThreshold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByWarehouseID", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.StockThreshold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByWarehouseID indicates an expected call of GetByWarehouseID.
+func (mr *MockStockThresholdPostgreRepoMockRecorder) GetByWarehouseID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByWarehouseID", reflect.TypeOf((*MockStockThresholdPostgreRepo)(nil).GetByWarehouseID), arg0, arg1)
+}
+
+// GetStockLevels mocks base method.
+func (m *MockStockThresholdPostgreRepo) GetStockLevels(arg0 context.Context) ([]*entity.StockLevel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockLevels", arg0)
+	ret0, _ := ret[0].([]*entity.StockLevel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockLevels indicates an expected call of GetStockLevels.
+func (mr *MockStockThresholdPostgreRepoMockRecorder) GetStockLevels(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockLevels", reflect.TypeOf((*MockStockThresholdPostgreRepo)(nil).GetStockLevels), arg0)
+}
+
+// Save mocks base method.
+func (m *MockStockThresholdPostgreRepo) Save(arg0 context.Context, arg1 *entity.StockThreshold) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockStockThresholdPostgreRepoMockRecorder) Save(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockStockThresholdPostgreRepo)(nil).Save), arg0, arg1)
+}
+
+// MockStockHistoryPostgreRepo is a mock of StockHistoryPostgreRepo interface.
+type MockStockHistoryPostgreRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockHistoryPostgreRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockStockHistoryPostgreRepoMockRecorder is the mock recorder for MockStockHistoryPostgreRepo.
+type MockStockHistoryPostgreRepoMockRecorder struct {
+	mock *MockStockHistoryPostgreRepo
+}
+
+// NewMockStockHistoryPostgreRepo creates a new mock instance.
+func NewMockStockHistoryPostgreRepo(ctrl *gomock.Controller) *MockStockHistoryPostgreRepo {
+	mock := &MockStockHistoryPostgreRepo{ctrl: ctrl}
+	mock.recorder = &MockStockHistoryPostgreRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockHistoryPostgreRepo) EXPECT() *MockStockHistoryPostgreRepoMockRecorder {
+	return m.recorder
+}
+
+// GetDiscrepancies mocks base method.
+func (m *MockStockHistoryPostgreRepo) GetDiscrepancies(arg0 context.Context) ([]*entity.StockDiscrepancy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDiscrepancies", arg0)
+	ret0, _ := ret[0].([]*entity.StockDiscrepancy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDiscrepancies indicates an expected call of GetDiscrepancies.
+func (mr *MockStockHistoryPostgreRepoMockRecorder) GetDiscrepancies(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDiscrepancies", reflect.TypeOf((*MockStockHistoryPostgreRepo)(nil).GetDiscrepancies), arg0)
+}
+
+// GetOnHand mocks base method.
+func (m *MockStockHistoryPostgreRepo) GetOnHand(arg0 context.Context, arg1, arg2 uuid.UUID, arg3 time.Time) (*entity.StockHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOnHand", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*entity.StockHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOnHand indicates an expected call of GetOnHand.
+func (mr *MockStockHistoryPostgreRepoMockRecorder) GetOnHand(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOnHand", reflect.TypeOf((*MockStockHistoryPostgreRepo)(nil).GetOnHand), arg0, arg1, arg2, arg3)
+}
+
+// MockStockSnapshotPostgreRepo is a mock of StockSnapshotPostgreRepo interface.
+type MockStockSnapshotPostgreRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockSnapshotPostgreRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockStockSnapshotPostgreRepoMockRecorder is the mock recorder for MockStockSnapshotPostgreRepo.
+type MockStockSnapshotPostgreRepoMockRecorder struct {
+	mock *MockStockSnapshotPostgreRepo
+}
+
+// NewMockStockSnapshotPostgreRepo creates a new mock instance.
+func NewMockStockSnapshotPostgreRepo(ctrl *gomock.Controller) *MockStockSnapshotPostgreRepo {
+	mock := &MockStockSnapshotPostgreRepo{ctrl: ctrl}
+	mock.recorder = &MockStockSnapshotPostgreRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockSnapshotPostgreRepo) EXPECT() *MockStockSnapshotPostgreRepoMockRecorder {
+	return m.recorder
+}
+
+// Capture mocks base method.
+func (m *MockStockSnapshotPostgreRepo) Capture(arg0 context.Context, arg1, arg2 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capture", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Capture indicates an expected call of Capture.
+func (mr *MockStockSnapshotPostgreRepoMockRecorder) Capture(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capture", reflect.TypeOf((*MockStockSnapshotPostgreRepo)(nil).Capture), arg0, arg1, arg2)
+}
+
+// GetByDate mocks base method.
+func (m *MockStockSnapshotPostgreRepo) GetByDate(arg0 context.Context, arg1 time.Time, arg2, arg3 uuid.UUID) ([]*entity.StockSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByDate", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*entity.StockSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByDate indicates an expected call of GetByDate.
+func (mr *MockStockSnapshotPostgreRepoMockRecorder) GetByDate(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDate", reflect.TypeOf((*MockStockSnapshotPostgreRepo)(nil).GetByDate), arg0, arg1, arg2, arg3)
+}
+
+// GetTrend mocks base method.
+func (m *MockStockSnapshotPostgreRepo) GetTrend(arg0 context.Context, arg1 entity.StockSnapshotFilter) ([]*entity.StockTrendPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrend", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.StockTrendPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrend indicates an expected call of GetTrend.
+func (mr *MockStockSnapshotPostgreRepoMockRecorder) GetTrend(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrend", reflect.TypeOf((*MockStockSnapshotPostgreRepo)(nil).GetTrend), arg0, arg1)
+}
+
+// MockCostLayerPostgreRepo is a mock of CostLayerPostgreRepo interface.
+type MockCostLayerPostgreRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockCostLayerPostgreRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockCostLayerPostgreRepoMockRecorder is the mock recorder for MockCostLayerPostgreRepo.
+type MockCostLayerPostgreRepoMockRecorder struct {
+	mock *MockCostLayerPostgreRepo
+}
+
+// NewMockCostLayerPostgreRepo creates a new mock instance.
+func NewMockCostLayerPostgreRepo(ctrl *gomock.Controller) *MockCostLayerPostgreRepo {
+	mock := &MockCostLayerPostgreRepo{ctrl: ctrl}
+	mock.recorder = &MockCostLayerPostgreRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCostLayerPostgreRepo) EXPECT() *MockCostLayerPostgreRepoMockRecorder {
+	return m.recorder
+}
+
+// GetProductCosts mocks base method.
+func (m *MockCostLayerPostgreRepo) GetProductCosts(arg0 context.Context, arg1 uuid.UUID) ([]*entity.ProductCost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductCosts", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.ProductCost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductCosts indicates an expected call of GetProductCosts.
+func (mr *MockCostLayerPostgreRepoMockRecorder) GetProductCosts(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductCosts", reflect.TypeOf((*MockCostLayerPostgreRepo)(nil).GetProductCosts), arg0, arg1)
+}
+
+// GetSalesCosts mocks base method.
+func (m *MockCostLayerPostgreRepo) GetSalesCosts(arg0 context.Context, arg1 entity.CostOfGoodsSoldPeriod) ([]*entity.ProductSalesCost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSalesCosts", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.ProductSalesCost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSalesCosts indicates an expected call of GetSalesCosts.
+func (mr *MockCostLayerPostgreRepoMockRecorder) GetSalesCosts(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSalesCosts", reflect.TypeOf((*MockCostLayerPostgreRepo)(nil).GetSalesCosts), arg0, arg1)
+}
+
+// MockOutboxEventPostgreRepo is a mock of OutboxEventPostgreRepo interface.
+type MockOutboxEventPostgreRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxEventPostgreRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockOutboxEventPostgreRepoMockRecorder is the mock recorder for MockOutboxEventPostgreRepo.
+type MockOutboxEventPostgreRepoMockRecorder struct {
+	mock *MockOutboxEventPostgreRepo
+}
+
+// NewMockOutboxEventPostgreRepo creates a new mock instance.
+func NewMockOutboxEventPostgreRepo(ctrl *gomock.Controller) *MockOutboxEventPostgreRepo {
+	mock := &MockOutboxEventPostgreRepo{ctrl: ctrl}
+	mock.recorder = &MockOutboxEventPostgreRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxEventPostgreRepo) EXPECT() *MockOutboxEventPostgreRepoMockRecorder {
+	return m.recorder
+}
+
+// GetPending mocks base method.
+func (m *MockOutboxEventPostgreRepo) GetPending(arg0 context.Context, arg1 time.Time, arg2 int) ([]*entity.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPending", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*entity.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPending indicates an expected call of GetPending.
+func (mr *MockOutboxEventPostgreRepoMockRecorder) GetPending(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPending", reflect.TypeOf((*MockOutboxEventPostgreRepo)(nil).GetPending), arg0, arg1, arg2)
+}
+
+// MarkFailed mocks base method.
+func (m *MockOutboxEventPostgreRepo) MarkFailed(arg0 context.Context, arg1 *entity.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockOutboxEventPostgreRepoMockRecorder) MarkFailed(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockOutboxEventPostgreRepo)(nil).MarkFailed), arg0, arg1)
+}
+
+// MarkSent mocks base method.
+func (m *MockOutboxEventPostgreRepo) MarkSent(arg0 context.Context, arg1 *entity.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSent indicates an expected call of MarkSent.
+func (mr *MockOutboxEventPostgreRepoMockRecorder) MarkSent(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSent", reflect.TypeOf((*MockOutboxEventPostgreRepo)(nil).MarkSent), arg0, arg1)
+}
+
+// MockKafkaProducer is a mock of KafkaProducer interface.
+type MockKafkaProducer struct {
+	ctrl     *gomock.Controller
+	recorder *MockKafkaProducerMockRecorder
+	isgomock struct{}
+}
+
+// MockKafkaProducerMockRecorder is the mock recorder for MockKafkaProducer.
+type MockKafkaProducerMockRecorder struct {
+	mock *MockKafkaProducer
+}
+
+// NewMockKafkaProducer creates a new mock instance.
+func NewMockKafkaProducer(ctrl *gomock.Controller) *MockKafkaProducer {
+	mock := &MockKafkaProducer{ctrl: ctrl}
+	mock.recorder = &MockKafkaProducerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKafkaProducer) EXPECT() *MockKafkaProducerMockRecorder {
+	return m.recorder
+}
+
+// ProduceSync mocks base method.
+func (m *MockKafkaProducer) ProduceSync(arg0 string, arg1 []byte, arg2 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProduceSync", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProduceSync indicates an expected call of ProduceSync.
+func (mr *MockKafkaProducerMockRecorder) ProduceSync(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProduceSync", reflect.TypeOf((*MockKafkaProducer)(nil).ProduceSync), arg0, arg1, arg2)
+}
+
+// MockWarehouse is a mock of Warehouse interface.
+type MockWarehouse struct {
+	ctrl     *gomock.Controller
+	recorder *MockWarehouseMockRecorder
+	isgomock struct{}
+}
+
+// MockWarehouseMockRecorder is the mock recorder for MockWarehouse.
+type MockWarehouseMockRecorder struct {
+	mock *MockWarehouse
+}
+
+// NewMockWarehouse creates a new mock instance.
+func NewMockWarehouse(ctrl *gomock.Controller) *MockWarehouse {
+	mock := &MockWarehouse{ctrl: ctrl}
+	mock.recorder = &MockWarehouseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWarehouse) EXPECT() *MockWarehouseMockRecorder {
+	return m.recorder
+}
+
+// CreateWarehouse mocks base method.
+func (m *MockWarehouse) CreateWarehouse(arg0 context.Context, arg1 *entity.Warehouse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWarehouse", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWarehouse indicates an expected call of CreateWarehouse.
+func (mr *MockWarehouseMockRecorder) CreateWarehouse(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWarehouse", reflect.TypeOf((*MockWarehouse)(nil).CreateWarehouse), arg0, arg1)
+}
+
+// GetAllWarehouses mocks base method.
+func (m *MockWarehouse) GetAllWarehouses(arg0 context.Context) ([]*entity.Warehouse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllWarehouses", arg0)
+	ret0, _ := ret[0].([]*entity.Warehouse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllWarehouses indicates an expected call of GetAllWarehouses.
+func (mr *MockWarehouseMockRecorder) GetAllWarehouses(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllWarehouses", reflect.TypeOf((*MockWarehouse)(nil).GetAllWarehouses), arg0)
+}
+
+// GetMainIDWarehouse mocks base method.
+func (m *MockWarehouse) GetMainIDWarehouse(arg0 context.Context) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMainIDWarehouse", arg0)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMainIDWarehouse indicates an expected call of GetMainIDWarehouse.
+func (mr *MockWarehouseMockRecorder) GetMainIDWarehouse(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMainIDWarehouse", reflect.TypeOf((*MockWarehouse)(nil).GetMainIDWarehouse), arg0)
+}
+
+// GetNearestWarehouse mocks base method.
+func (m *MockWarehouse) GetNearestWarehouse(arg0 context.Context, arg1 []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNearestWarehouse", arg0, arg1)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNearestWarehouse indicates an expected call of GetNearestWarehouse.
+func (mr *MockWarehouseMockRecorder) GetNearestWarehouse(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNearestWarehouse", reflect.TypeOf((*MockWarehouse)(nil).GetNearestWarehouse), arg0, arg1)
+}
+
+// GetWarehouseByID mocks base method.
+func (m *MockWarehouse) GetWarehouseByID(arg0 context.Context, arg1 uuid.UUID) (*entity.Warehouse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWarehouseByID", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Warehouse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWarehouseByID indicates an expected call of GetWarehouseByID.
+func (mr *MockWarehouseMockRecorder) GetWarehouseByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarehouseByID", reflect.TypeOf((*MockWarehouse)(nil).GetWarehouseByID), arg0, arg1)
+}
+
+// UpdateWarehouse mocks base method.
+func (m *MockWarehouse) UpdateWarehouse(arg0 context.Context, arg1 *entity.Warehouse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWarehouse", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWarehouse indicates an expected call of UpdateWarehouse.
+func (mr *MockWarehouseMockRecorder) UpdateWarehouse(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWarehouse", reflect.TypeOf((*MockWarehouse)(nil).UpdateWarehouse), arg0, arg1)
+}
+
+// MockWarehouseProduct is a mock of WarehouseProduct interface.
+type MockWarehouseProduct struct {
+	ctrl     *gomock.Controller
+	recorder *MockWarehouseProductMockRecorder
+	isgomock struct{}
+}
+
+// MockWarehouseProductMockRecorder is the mock recorder for MockWarehouseProduct.
+type MockWarehouseProductMockRecorder struct {
+	mock *MockWarehouseProduct
+}
+
+// NewMockWarehouseProduct creates a new mock instance.
+func NewMockWarehouseProduct(ctrl *gomock.Controller) *MockWarehouseProduct {
+	mock := &MockWarehouseProduct{ctrl: ctrl}
+	mock.recorder = &MockWarehouseProductMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWarehouseProduct) EXPECT() *MockWarehouseProductMockRecorder {
+	return m.recorder
+}
+
+// AdjustWarehouseProductQuantity mocks base method.
+func (m *MockWarehouseProduct) AdjustWarehouseProductQuantity(arg0 context.Context, arg1 *entity.StockAdjustment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustWarehouseProductQuantity", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdjustWarehouseProductQuantity indicates an expected call of AdjustWarehouseProductQuantity.
+func (mr *MockWarehouseProductMockRecorder) AdjustWarehouseProductQuantity(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustWarehouseProductQuantity", reflect.TypeOf((*MockWarehouseProduct)(nil).AdjustWarehouseProductQuantity), arg0, arg1)
+}
+
+// CreateWarehouseProduct mocks base method.
+func (m *MockWarehouseProduct) CreateWarehouseProduct(arg0 context.Context, arg1 *entity.WarehouseProduct) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWarehouseProduct", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWarehouseProduct indicates an expected call of CreateWarehouseProduct.
+func (mr *MockWarehouseProductMockRecorder) CreateWarehouseProduct(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWarehouseProduct", reflect.TypeOf((*MockWarehouseProduct)(nil).CreateWarehouseProduct), arg0, arg1)
+}
+
+// GetAllWarehouseProducts mocks base method.
+func (m *MockWarehouseProduct) GetAllWarehouseProducts(arg0 context.Context) ([]*entity.WarehouseProduct, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllWarehouseProducts", arg0)
+	ret0, _ := ret[0].([]*entity.WarehouseProduct)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllWarehouseProducts indicates an expected call of GetAllWarehouseProducts.
+func (mr *MockWarehouseProductMockRecorder) GetAllWarehouseProducts(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllWarehouseProducts", reflect.TypeOf((*MockWarehouseProduct)(nil).GetAllWarehouseProducts), arg0)
+}
+
+// GetNearestWarehouseZipCodeByProductID mocks base method.
+func (m *MockWarehouseProduct) GetNearestWarehouseZipCodeByProductID(arg0 context.Context, arg1 string, arg2 uuid.UUID) (*string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNearestWarehouseZipCodeByProductID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNearestWarehouseZipCodeByProductID indicates an expected call of GetNearestWarehouseZipCodeByProductID.
+func (mr *MockWarehouseProductMockRecorder) GetNearestWarehouseZipCodeByProductID(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNearestWarehouseZipCodeByProductID", reflect.TypeOf((*MockWarehouseProduct)(nil).GetNearestWarehouseZipCodeByProductID), arg0, arg1, arg2)
+}
+
+// GetWarehouseProductByProductID mocks base method.
+func (m *MockWarehouseProduct) GetWarehouseProductByProductID(arg0 context.Context, arg1 uuid.UUID) ([]*entity.WarehouseProduct, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWarehouseProductByProductID", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.WarehouseProduct)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWarehouseProductByProductID indicates an expected call of GetWarehouseProductByProductID.
+func (mr *MockWarehouseProductMockRecorder) GetWarehouseProductByProductID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarehouseProductByProductID", reflect.TypeOf((*MockWarehouseProduct)(nil).GetWarehouseProductByProductID), arg0, arg1)
+}
+
+// GetWarehouseProductByProductIDAndWarehouseID mocks base method.
+func (m *MockWarehouseProduct) GetWarehouseProductByProductIDAndWarehouseID(arg0 context.Context, arg1, arg2 uuid.UUID) (*entity.WarehouseProduct, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWarehouseProductByProductIDAndWarehouseID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.WarehouseProduct)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWarehouseProductByProductIDAndWarehouseID indicates an expected call of GetWarehouseProductByProductIDAndWarehouseID.
+func (mr *MockWarehouseProductMockRecorder) GetWarehouseProductByProductIDAndWarehouseID(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarehouseProductByProductIDAndWarehouseID", reflect.TypeOf((*MockWarehouseProduct)(nil).GetWarehouseProductByProductIDAndWarehouseID), arg0, arg1, arg2)
+}
+
+// GetWarehouseProductByWarehouseID mocks base method.
+func (m *MockWarehouseProduct) GetWarehouseProductByWarehouseID(arg0 context.Context, arg1 uuid.UUID) ([]*entity.WarehouseProduct, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWarehouseProductByWarehouseID", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.WarehouseProduct)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWarehouseProductByWarehouseID indicates an expected call of GetWarehouseProductByWarehouseID.
+func (mr *MockWarehouseProductMockRecorder) GetWarehouseProductByWarehouseID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarehouseProductByWarehouseID", reflect.TypeOf((*MockWarehouseProduct)(nil).GetWarehouseProductByWarehouseID), arg0, arg1)
+}
+
+// UpdateWarehouseProduct mocks base method.
+func (m *MockWarehouseProduct) UpdateWarehouseProduct(arg0 context.Context, arg1 *entity.WarehouseProduct) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWarehouseProduct", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWarehouseProduct indicates an expected call of UpdateWarehouseProduct.
+func (mr *MockWarehouseProductMockRecorder) UpdateWarehouseProduct(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWarehouseProduct", reflect.TypeOf((*MockWarehouseProduct)(nil).UpdateWarehouseProduct), arg0, arg1)
 }
 
 // MockStockMovement is a mock of StockMovement interface.
 type MockStockMovement struct {
 	ctrl     *gomock.Controller
-	recorder *MockStockMovementMockRecorder
+	recorder *MockStockMovementMockRecorder
+	isgomock struct{}
+}
+
+// MockStockMovementMockRecorder is the mock recorder for MockStockMovement.
+type MockStockMovementMockRecorder struct {
+	mock *MockStockMovement
+}
+
+// NewMockStockMovement creates a new mock instance.
+func NewMockStockMovement(ctrl *gomock.Controller) *MockStockMovement {
+	mock := &MockStockMovement{ctrl: ctrl}
+	mock.recorder = &MockStockMovementMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockMovement) EXPECT() *MockStockMovementMockRecorder {
+	return m.recorder
+}
+
+// GetAllStockMovements mocks base method.
+func (m *MockStockMovement) GetAllStockMovements(arg0 context.Context, arg1 entity.StockMovementFilter) ([]*entity.StockMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllStockMovements", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.StockMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllStockMovements indicates an expected call of GetAllStockMovements.
+func (mr *MockStockMovementMockRecorder) GetAllStockMovements(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllStockMovements", reflect.TypeOf((*MockStockMovement)(nil).GetAllStockMovements), arg0, arg1)
+}
+
+// GetStockMovementsByDestinationID mocks base method.
+func (m *MockStockMovement) GetStockMovementsByDestinationID(arg0 context.Context, arg1 uuid.UUID, arg2 entity.StockMovementFilter) ([]*entity.StockMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockMovementsByDestinationID", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*entity.StockMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockMovementsByDestinationID indicates an expected call of GetStockMovementsByDestinationID.
+func (mr *MockStockMovementMockRecorder) GetStockMovementsByDestinationID(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockMovementsByDestinationID", reflect.TypeOf((*MockStockMovement)(nil).GetStockMovementsByDestinationID), arg0, arg1, arg2)
+}
+
+// GetStockMovementsByProductID mocks base method.
+func (m *MockStockMovement) GetStockMovementsByProductID(arg0 context.Context, arg1 uuid.UUID, arg2 entity.StockMovementFilter) ([]*entity.StockMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockMovementsByProductID", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*entity.StockMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockMovementsByProductID indicates an expected call of GetStockMovementsByProductID.
+func (mr *MockStockMovementMockRecorder) GetStockMovementsByProductID(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockMovementsByProductID", reflect.TypeOf((*MockStockMovement)(nil).GetStockMovementsByProductID), arg0, arg1, arg2)
+}
+
+// GetStockMovementsBySourceID mocks base method.
+func (m *MockStockMovement) GetStockMovementsBySourceID(arg0 context.Context, arg1 uuid.UUID, arg2 entity.StockMovementFilter) ([]*entity.StockMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockMovementsBySourceID", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*entity.StockMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockMovementsBySourceID indicates an expected call of GetStockMovementsBySourceID.
+func (mr *MockStockMovementMockRecorder) GetStockMovementsBySourceID(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockMovementsBySourceID", reflect.TypeOf((*MockStockMovement)(nil).GetStockMovementsBySourceID), arg0, arg1, arg2)
+}
+
+// MockTransactionProduct is a mock of TransactionProduct interface.
+type MockTransactionProduct struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionProductMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactionProductMockRecorder is the mock recorder for MockTransactionProduct.
+type MockTransactionProductMockRecorder struct {
+	mock *MockTransactionProduct
+}
+
+// NewMockTransactionProduct creates a new mock instance.
+func NewMockTransactionProduct(ctrl *gomock.Controller) *MockTransactionProduct {
+	mock := &MockTransactionProduct{ctrl: ctrl}
+	mock.recorder = &MockTransactionProductMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionProduct) EXPECT() *MockTransactionProductMockRecorder {
+	return m.recorder
+}
+
+// CommitReservation mocks base method.
+func (m *MockTransactionProduct) CommitReservation(arg0 context.Context, arg1 uuid.UUID) ([]*entity.StockMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommitReservation", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.StockMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommitReservation indicates an expected call of CommitReservation.
+func (mr *MockTransactionProductMockRecorder) CommitReservation(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitReservation", reflect.TypeOf((*MockTransactionProduct)(nil).CommitReservation), arg0, arg1)
+}
+
+// CompensateMoveOut mocks base method.
+func (m *MockTransactionProduct) CompensateMoveOut(arg0 context.Context, arg1, arg2 string) ([]*entity.StockMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompensateMoveOut", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*entity.StockMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompensateMoveOut indicates an expected call of CompensateMoveOut.
+func (mr *MockTransactionProductMockRecorder) CompensateMoveOut(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompensateMoveOut", reflect.TypeOf((*MockTransactionProduct)(nil).CompensateMoveOut), arg0, arg1, arg2)
+}
+
+// MoveIn mocks base method.
+func (m *MockTransactionProduct) MoveIn(arg0 context.Context, arg1 *entity.StockMovement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveIn", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveIn indicates an expected call of MoveIn.
+func (mr *MockTransactionProductMockRecorder) MoveIn(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveIn", reflect.TypeOf((*MockTransactionProduct)(nil).MoveIn), arg0, arg1)
+}
+
+// MoveOut mocks base method.
+func (m *MockTransactionProduct) MoveOut(arg0 context.Context, arg1 []*entity.StockMovement, arg2, arg3 string) ([]*entity.StockMovement, *entity.AllocationPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveOut", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*entity.StockMovement)
+	ret1, _ := ret[1].(*entity.AllocationPlan)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// MoveOut indicates an expected call of MoveOut.
+func (mr *MockTransactionProductMockRecorder) MoveOut(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveOut", reflect.TypeOf((*MockTransactionProduct)(nil).MoveOut), arg0, arg1, arg2, arg3)
+}
+
+// MoveReturn mocks base method.
+func (m *MockTransactionProduct) MoveReturn(arg0 context.Context, arg1 *entity.StockMovement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveReturn", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveReturn indicates an expected call of MoveReturn.
+func (mr *MockTransactionProductMockRecorder) MoveReturn(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveReturn", reflect.TypeOf((*MockTransactionProduct)(nil).MoveReturn), arg0, arg1)
+}
+
+// PreviewMoveOut mocks base method.
+func (m *MockTransactionProduct) PreviewMoveOut(arg0 context.Context, arg1 []*entity.StockMovement, arg2, arg3 string) (*entity.AllocationPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewMoveOut", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*entity.AllocationPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewMoveOut indicates an expected call of PreviewMoveOut.
+func (mr *MockTransactionProductMockRecorder) PreviewMoveOut(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewMoveOut", reflect.TypeOf((*MockTransactionProduct)(nil).PreviewMoveOut), arg0, arg1, arg2, arg3)
+}
+
+// MockReservation is a mock of Reservation interface.
+type MockReservation struct {
+	ctrl     *gomock.Controller
+	recorder *MockReservationMockRecorder
+	isgomock struct{}
+}
+
+// MockReservationMockRecorder is the mock recorder for MockReservation.
+type MockReservationMockRecorder struct {
+	mock *MockReservation
+}
+
+// NewMockReservation creates a new mock instance.
+func NewMockReservation(ctrl *gomock.Controller) *MockReservation {
+	mock := &MockReservation{ctrl: ctrl}
+	mock.recorder = &MockReservationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReservation) EXPECT() *MockReservationMockRecorder {
+	return m.recorder
+}
+
+// CreateReservation mocks base method.
+func (m *MockReservation) CreateReservation(arg0 context.Context, arg1 *entity.Reservation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReservation", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateReservation indicates an expected call of CreateReservation.
+func (mr *MockReservationMockRecorder) CreateReservation(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReservation", reflect.TypeOf((*MockReservation)(nil).CreateReservation), arg0, arg1)
+}
+
+// GetReservationByID mocks base method.
+func (m *MockReservation) GetReservationByID(arg0 context.Context, arg1 uuid.UUID) (*entity.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReservationByID", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReservationByID indicates an expected call of GetReservationByID.
+func (mr *MockReservationMockRecorder) GetReservationByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReservationByID", reflect.TypeOf((*MockReservation)(nil).GetReservationByID), arg0, arg1)
+}
+
+// ReleaseExpiredReservations mocks base method.
+func (m *MockReservation) ReleaseExpiredReservations(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseExpiredReservations", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseExpiredReservations indicates an expected call of ReleaseExpiredReservations.
+func (mr *MockReservationMockRecorder) ReleaseExpiredReservations(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseExpiredReservations", reflect.TypeOf((*MockReservation)(nil).ReleaseExpiredReservations), arg0)
+}
+
+// ReleaseReservation mocks base method.
+func (m *MockReservation) ReleaseReservation(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseReservation", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseReservation indicates an expected call of ReleaseReservation.
+func (mr *MockReservationMockRecorder) ReleaseReservation(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseReservation", reflect.TypeOf((*MockReservation)(nil).ReleaseReservation), arg0, arg1)
+}
+
+// MockOutbox is a mock of Outbox interface.
+type MockOutbox struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxMockRecorder
+	isgomock struct{}
+}
+
+// MockOutboxMockRecorder is the mock recorder for MockOutbox.
+type MockOutboxMockRecorder struct {
+	mock *MockOutbox
+}
+
+// NewMockOutbox creates a new mock instance.
+func NewMockOutbox(ctrl *gomock.Controller) *MockOutbox {
+	mock := &MockOutbox{ctrl: ctrl}
+	mock.recorder = &MockOutboxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutbox) EXPECT() *MockOutboxMockRecorder {
+	return m.recorder
+}
+
+// RelayPendingEvents mocks base method.
+func (m *MockOutbox) RelayPendingEvents(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RelayPendingEvents", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RelayPendingEvents indicates an expected call of RelayPendingEvents.
+func (mr *MockOutboxMockRecorder) RelayPendingEvents(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelayPendingEvents", reflect.TypeOf((*MockOutbox)(nil).RelayPendingEvents), arg0)
+}
+
+// MockInventoryCount is a mock of InventoryCount interface.
+type MockInventoryCount struct {
+	ctrl     *gomock.Controller
+	recorder *MockInventoryCountMockRecorder
+	isgomock struct{}
+}
+
+// MockInventoryCountMockRecorder is the mock recorder for MockInventoryCount.
+type MockInventoryCountMockRecorder struct {
+	mock *MockInventoryCount
+}
+
+// NewMockInventoryCount creates a new mock instance.
+func NewMockInventoryCount(ctrl *gomock.Controller) *MockInventoryCount {
+	mock := &MockInventoryCount{ctrl: ctrl}
+	mock.recorder = &MockInventoryCountMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInventoryCount) EXPECT() *MockInventoryCountMockRecorder {
+	return m.recorder
+}
+
+// CancelInventoryCount mocks base method.
+func (m *MockInventoryCount) CancelInventoryCount(arg0 context.Context, arg1 *entity.InventoryCount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelInventoryCount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelInventoryCount indicates an expected call of CancelInventoryCount.
+func (mr *MockInventoryCountMockRecorder) CancelInventoryCount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelInventoryCount", reflect.TypeOf((*MockInventoryCount)(nil).CancelInventoryCount), arg0, arg1)
+}
+
+// GetInventoryCountByID mocks base method.
+func (m *MockInventoryCount) GetInventoryCountByID(arg0 context.Context, arg1 uuid.UUID) (*entity.InventoryCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInventoryCountByID", arg0, arg1)
+	ret0, _ := ret[0].(*entity.InventoryCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInventoryCountByID indicates an expected call of GetInventoryCountByID.
+func (mr *MockInventoryCountMockRecorder) GetInventoryCountByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInventoryCountByID", reflect.TypeOf((*MockInventoryCount)(nil).GetInventoryCountByID), arg0, arg1)
+}
+
+// OpenInventoryCount mocks base method.
+func (m *MockInventoryCount) OpenInventoryCount(arg0 context.Context, arg1 *entity.InventoryCount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenInventoryCount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OpenInventoryCount indicates an expected call of OpenInventoryCount.
+func (mr *MockInventoryCountMockRecorder) OpenInventoryCount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenInventoryCount", reflect.TypeOf((*MockInventoryCount)(nil).OpenInventoryCount), arg0, arg1)
+}
+
+// PostInventoryCount mocks base method.
+func (m *MockInventoryCount) PostInventoryCount(arg0 context.Context, arg1 *entity.InventoryCount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostInventoryCount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostInventoryCount indicates an expected call of PostInventoryCount.
+func (mr *MockInventoryCountMockRecorder) PostInventoryCount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostInventoryCount", reflect.TypeOf((*MockInventoryCount)(nil).PostInventoryCount), arg0, arg1)
+}
+
+// SubmitInventoryCountLines mocks base method.
+func (m *MockInventoryCount) SubmitInventoryCountLines(arg0 context.Context, arg1 *entity.InventoryCount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitInventoryCountLines", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubmitInventoryCountLines indicates an expected call of SubmitInventoryCountLines.
+func (mr *MockInventoryCountMockRecorder) SubmitInventoryCountLines(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitInventoryCountLines", reflect.TypeOf((*MockInventoryCount)(nil).SubmitInventoryCountLines), arg0, arg1)
+}
+
+// MockInboundReceipt is a mock of InboundReceipt interface.
+type MockInboundReceipt struct {
+	ctrl     *gomock.Controller
+	recorder *MockInboundReceiptMockRecorder
+	isgomock struct{}
+}
+
+// MockInboundReceiptMockRecorder is the mock recorder for MockInboundReceipt.
+type MockInboundReceiptMockRecorder struct {
+	mock *MockInboundReceipt
+}
+
+// NewMockInboundReceipt creates a new mock instance.
+func NewMockInboundReceipt(ctrl *gomock.Controller) *MockInboundReceipt {
+	mock := &MockInboundReceipt{ctrl: ctrl}
+	mock.recorder = &MockInboundReceiptMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInboundReceipt) EXPECT() *MockInboundReceiptMockRecorder {
+	return m.recorder
+}
+
+// CloseInboundReceipt mocks base method.
+func (m *MockInboundReceipt) CloseInboundReceipt(arg0 context.Context, arg1 *entity.InboundReceipt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseInboundReceipt", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseInboundReceipt indicates an expected call of CloseInboundReceipt.
+func (mr *MockInboundReceiptMockRecorder) CloseInboundReceipt(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseInboundReceipt", reflect.TypeOf((*MockInboundReceipt)(nil).CloseInboundReceipt), arg0, arg1)
+}
+
+// CreateInboundReceipt mocks base method.
+func (m *MockInboundReceipt) CreateInboundReceipt(arg0 context.Context, arg1 *entity.InboundReceipt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInboundReceipt", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateInboundReceipt indicates an expected call of CreateInboundReceipt.
+func (mr *MockInboundReceiptMockRecorder) CreateInboundReceipt(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInboundReceipt", reflect.TypeOf((*MockInboundReceipt)(nil).CreateInboundReceipt), arg0, arg1)
+}
+
+// GetInboundReceiptByID mocks base method.
+func (m *MockInboundReceipt) GetInboundReceiptByID(arg0 context.Context, arg1 uuid.UUID) (*entity.InboundReceipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInboundReceiptByID", arg0, arg1)
+	ret0, _ := ret[0].(*entity.InboundReceipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInboundReceiptByID indicates an expected call of GetInboundReceiptByID.
+func (mr *MockInboundReceiptMockRecorder) GetInboundReceiptByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInboundReceiptByID", reflect.TypeOf((*MockInboundReceipt)(nil).GetInboundReceiptByID), arg0, arg1)
+}
+
+// ReceiveInboundReceipt mocks base method.
+func (m *MockInboundReceipt) ReceiveInboundReceipt(arg0 context.Context, arg1 *entity.InboundReceipt, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReceiveInboundReceipt", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReceiveInboundReceipt indicates an expected call of ReceiveInboundReceipt.
+func (mr *MockInboundReceiptMockRecorder) ReceiveInboundReceipt(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiveInboundReceipt", reflect.TypeOf((*MockInboundReceipt)(nil).ReceiveInboundReceipt), arg0, arg1, arg2)
+}
+
+// MockTransferOrder is a mock of TransferOrder interface.
+type MockTransferOrder struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransferOrderMockRecorder
 	isgomock struct{}
 }
 
-// MockStockMovementMockRecorder is the mock recorder for MockStockMovement.
-type MockStockMovementMockRecorder struct {
-	mock *MockStockMovement
+// MockTransferOrderMockRecorder is the mock recorder for MockTransferOrder.
+type MockTransferOrderMockRecorder struct {
+	mock *MockTransferOrder
 }
 
-// NewMockStockMovement creates a new mock instance.
-func NewMockStockMovement(ctrl *gomock.Controller) *MockStockMovement {
-	mock := &MockStockMovement{ctrl: ctrl}
-	mock.recorder = &MockStockMovementMockRecorder{mock}
+// NewMockTransferOrder creates a new mock instance.
+func NewMockTransferOrder(ctrl *gomock.Controller) *MockTransferOrder {
+	mock := &MockTransferOrder{ctrl: ctrl}
+	mock.recorder = &MockTransferOrderMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockStockMovement) EXPECT() *MockStockMovementMockRecorder {
+func (m *MockTransferOrder) EXPECT() *MockTransferOrderMockRecorder {
 	return m.recorder
 }
 
-// GetAllStockMovements mocks base method.
-func (m *MockStockMovement) GetAllStockMovements(arg0 context.Context) ([]*entity.StockMovement, error) {
+// CancelTransferOrder mocks base method.
+func (m *MockTransferOrder) CancelTransferOrder(arg0 context.Context, arg1 *entity.TransferOrder) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllStockMovements", arg0)
-	ret0, _ := ret[0].([]*entity.StockMovement)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "CancelTransferOrder", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetAllStockMovements indicates an expected call of GetAllStockMovements.
-func (mr *MockStockMovementMockRecorder) GetAllStockMovements(arg0 any) *gomock.Call {
+// CancelTransferOrder indicates an expected call of CancelTransferOrder.
+func (mr *MockTransferOrderMockRecorder) CancelTransferOrder(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllStockMovements", reflect.TypeOf((*MockStockMovement)(nil).GetAllStockMovements), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelTransferOrder", reflect.TypeOf((*MockTransferOrder)(nil).CancelTransferOrder), arg0, arg1)
 }
 
-// GetStockMovementsByDestinationID mocks base method.
-func (m *MockStockMovement) GetStockMovementsByDestinationID(arg0 context.Context, arg1 uuid.UUID) ([]*entity.StockMovement, error) {
+// CreateTransferOrder mocks base method.
+func (m *MockTransferOrder) CreateTransferOrder(arg0 context.Context, arg1 *entity.TransferOrder) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetStockMovementsByDestinationID", arg0, arg1)
-	ret0, _ := ret[0].([]*entity.StockMovement)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "CreateTransferOrder", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetStockMovementsByDestinationID indicates an expected call of GetStockMovementsByDestinationID.
-func (mr *MockStockMovementMockRecorder) GetStockMovementsByDestinationID(arg0, arg1 any) *gomock.Call {
+// CreateTransferOrder indicates an expected call of CreateTransferOrder.
+func (mr *MockTransferOrderMockRecorder) CreateTransferOrder(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockMovementsByDestinationID", reflect.TypeOf((*MockStockMovement)(nil).GetStockMovementsByDestinationID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferOrder", reflect.TypeOf((*MockTransferOrder)(nil).CreateTransferOrder), arg0, arg1)
 }
 
-// GetStockMovementsByProductID mocks base method.
-func (m *MockStockMovement) GetStockMovementsByProductID(arg0 context.Context, arg1 uuid.UUID) ([]*entity.StockMovement, error) {
+// GetInTransitByProductID mocks base method.
+func (m *MockTransferOrder) GetInTransitByProductID(arg0 context.Context, arg1 uuid.UUID) (*entity.ProductInTransit, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetStockMovementsByProductID", arg0, arg1)
-	ret0, _ := ret[0].([]*entity.StockMovement)
+	ret := m.ctrl.Call(m, "GetInTransitByProductID", arg0, arg1)
+	ret0, _ := ret[0].(*entity.ProductInTransit)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetStockMovementsByProductID indicates an expected call of GetStockMovementsByProductID.
-func (mr *MockStockMovementMockRecorder) GetStockMovementsByProductID(arg0, arg1 any) *gomock.Call {
+// GetInTransitByProductID indicates an expected call of GetInTransitByProductID.
+func (mr *MockTransferOrderMockRecorder) GetInTransitByProductID(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockMovementsByProductID", reflect.TypeOf((*MockStockMovement)(nil).GetStockMovementsByProductID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInTransitByProductID", reflect.TypeOf((*MockTransferOrder)(nil).GetInTransitByProductID), arg0, arg1)
 }
 
-// GetStockMovementsBySourceID mocks base method.
-func (m *MockStockMovement) GetStockMovementsBySourceID(arg0 context.Context, arg1 uuid.UUID) ([]*entity.StockMovement, error) {
+// GetTransferOrderByID mocks base method.
+func (m *MockTransferOrder) GetTransferOrderByID(arg0 context.Context, arg1 uuid.UUID) (*entity.TransferOrder, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetStockMovementsBySourceID", arg0, arg1)
-	ret0, _ := ret[0].([]*entity.StockMovement)
+	ret := m.ctrl.Call(m, "GetTransferOrderByID", arg0, arg1)
+	ret0, _ := ret[0].(*entity.TransferOrder)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetStockMovementsBySourceID indicates an expected call of GetStockMovementsBySourceID.
-func (mr *MockStockMovementMockRecorder) GetStockMovementsBySourceID(arg0, arg1 any) *gomock.Call {
+// GetTransferOrderByID indicates an expected call of GetTransferOrderByID.
+func (mr *MockTransferOrderMockRecorder) GetTransferOrderByID(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockMovementsBySourceID", reflect.TypeOf((*MockStockMovement)(nil).GetStockMovementsBySourceID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferOrderByID", reflect.TypeOf((*MockTransferOrder)(nil).GetTransferOrderByID), arg0, arg1)
 }
 
-// MockTransactionProduct is a mock of TransactionProduct interface.
-type MockTransactionProduct struct {
+// PickTransferOrder mocks base method.
+func (m *MockTransferOrder) PickTransferOrder(arg0 context.Context, arg1 *entity.TransferOrder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PickTransferOrder", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PickTransferOrder indicates an expected call of PickTransferOrder.
+func (mr *MockTransferOrderMockRecorder) PickTransferOrder(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PickTransferOrder", reflect.TypeOf((*MockTransferOrder)(nil).PickTransferOrder), arg0, arg1)
+}
+
+// ReceiveTransferOrder mocks base method.
+func (m *MockTransferOrder) ReceiveTransferOrder(arg0 context.Context, arg1 *entity.TransferOrder, arg2 *entity.TransferOrderDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReceiveTransferOrder", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReceiveTransferOrder indicates an expected call of ReceiveTransferOrder.
+func (mr *MockTransferOrderMockRecorder) ReceiveTransferOrder(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiveTransferOrder", reflect.TypeOf((*MockTransferOrder)(nil).ReceiveTransferOrder), arg0, arg1, arg2)
+}
+
+// ShipTransferOrder mocks base method.
+func (m *MockTransferOrder) ShipTransferOrder(arg0 context.Context, arg1 *entity.TransferOrder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShipTransferOrder", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ShipTransferOrder indicates an expected call of ShipTransferOrder.
+func (mr *MockTransferOrderMockRecorder) ShipTransferOrder(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShipTransferOrder", reflect.TypeOf((*MockTransferOrder)(nil).ShipTransferOrder), arg0, arg1)
+}
+
+// MockStockThreshold is a mock of StockThreshold interface.
+type MockStockThreshold struct {
 	ctrl     *gomock.Controller
-	recorder *MockTransactionProductMockRecorder
+	recorder *MockStockThresholdMockRecorder
 	isgomock struct{}
 }
 
-// MockTransactionProductMockRecorder is the mock recorder for MockTransactionProduct.
-type MockTransactionProductMockRecorder struct {
-	mock *MockTransactionProduct
+// MockStockThresholdMockRecorder is the mock recorder for MockStockThreshold.
+type MockStockThresholdMockRecorder struct {
+	mock *MockStockThreshold
 }
 
-// NewMockTransactionProduct creates a new mock instance.
-func NewMockTransactionProduct(ctrl *gomock.Controller) *MockTransactionProduct {
-	mock := &MockTransactionProduct{ctrl: ctrl}
-	mock.recorder = &MockTransactionProductMockRecorder{mock}
+// NewMockStockThreshold creates a new mock instance.
+func NewMockStockThreshold(ctrl *gomock.Controller) *MockStockThreshold {
+	mock := &MockStockThreshold{ctrl: ctrl}
+	mock.recorder = &MockStockThresholdMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockTransactionProduct) EXPECT() *MockTransactionProductMockRecorder {
+func (m *MockStockThreshold) EXPECT() *MockStockThresholdMockRecorder {
 	return m.recorder
 }
 
-// MoveIn mocks base method.
-func (m *MockTransactionProduct) MoveIn(arg0 context.Context, arg1 *entity.StockMovement) error {
+// DeleteStockThreshold mocks base method.
+func (m *MockStockThreshold) DeleteStockThreshold(arg0 context.Context, arg1, arg2 uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MoveIn", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteStockThreshold", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// MoveIn indicates an expected call of MoveIn.
-func (mr *MockTransactionProductMockRecorder) MoveIn(arg0, arg1 any) *gomock.Call {
+// DeleteStockThreshold indicates an expected call of DeleteStockThreshold.
+func (mr *MockStockThresholdMockRecorder) DeleteStockThreshold(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveIn", reflect.TypeOf((*MockTransactionProduct)(nil).MoveIn), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStockThreshold", reflect.TypeOf((*MockStockThreshold)(nil).DeleteStockThreshold), arg0, arg1, arg2)
 }
 
-// MoveOut mocks base method.
-func (m *MockTransactionProduct) MoveOut(arg0 context.Context, arg1 []*entity.StockMovement, arg2 string) error {
+// EvaluateLowStocks mocks base method.
+func (m *MockStockThreshold) EvaluateLowStocks(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvaluateLowStocks", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EvaluateLowStocks indicates an expected call of EvaluateLowStocks.
+func (mr *MockStockThresholdMockRecorder) EvaluateLowStocks(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateLowStocks", reflect.TypeOf((*MockStockThreshold)(nil).EvaluateLowStocks), arg0)
+}
+
+// GetLowStocks mocks base method.
+func (m *MockStockThreshold) GetLowStocks(arg0 context.Context) ([]*entity.StockLevel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLowStocks", arg0)
+	ret0, _ := ret[0].([]*entity.StockLevel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLowStocks indicates an expected call of GetLowStocks.
+func (mr *MockStockThresholdMockRecorder) GetLowStocks(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLowStocks", reflect.TypeOf((*MockStockThreshold)(nil).GetLowStocks), arg0)
+}
+
+// GetStockThresholdsByWarehouseID mocks base method.
+func (m *MockStockThreshold) GetStockThresholdsByWarehouseID(arg0 context.Context, arg1 uuid.UUID) ([]*entity.StockThreshold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockThresholdsByWarehouseID", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.StockThreshold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockThresholdsByWarehouseID indicates an expected call of GetStockThresholdsByWarehouseID.
+func (mr *MockStockThresholdMockRecorder) GetStockThresholdsByWarehouseID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockThresholdsByWarehouseID", reflect.TypeOf((*MockStockThreshold)(nil).GetStockThresholdsByWarehouseID), arg0, arg1)
+}
+
+// SetStockThreshold mocks base method.
+func (m *MockStockThreshold) SetStockThreshold(arg0 context.Context, arg1 *entity.StockThreshold) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MoveOut", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "SetStockThreshold", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// MoveOut indicates an expected call of MoveOut.
-func (mr *MockTransactionProductMockRecorder) MoveOut(arg0, arg1, arg2 any) *gomock.Call {
+// SetStockThreshold indicates an expected call of SetStockThreshold.
+func (mr *MockStockThresholdMockRecorder) SetStockThreshold(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStockThreshold", reflect.TypeOf((*MockStockThreshold)(nil).SetStockThreshold), arg0, arg1)
+}
+
+// SuggestReplenishments mocks base method.
+func (m *MockStockThreshold) SuggestReplenishments(arg0 context.Context) ([]*entity.ReplenishmentSuggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestReplenishments", arg0)
+	ret0, _ := ret[0].([]*entity.ReplenishmentSuggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestReplenishments indicates an expected call of SuggestReplenishments.
+func (mr *MockStockThresholdMockRecorder) SuggestReplenishments(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestReplenishments", reflect.TypeOf((*MockStockThreshold)(nil).SuggestReplenishments), arg0)
+}
+
+// MockRebalance is a mock of Rebalance interface.
+type MockRebalance struct {
+	ctrl     *gomock.Controller
+	recorder *MockRebalanceMockRecorder
+	isgomock struct{}
+}
+
+// MockRebalanceMockRecorder is the mock recorder for MockRebalance.
+type MockRebalanceMockRecorder struct {
+	mock *MockRebalance
+}
+
+// NewMockRebalance creates a new mock instance.
+func NewMockRebalance(ctrl *gomock.Controller) *MockRebalance {
+	mock := &MockRebalance{ctrl: ctrl}
+	mock.recorder = &MockRebalanceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRebalance) EXPECT() *MockRebalanceMockRecorder {
+	return m.recorder
+}
+
+// PlanRebalance mocks base method.
+func (m *MockRebalance) PlanRebalance(arg0 context.Context) (*entity.RebalanceReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlanRebalance", arg0)
+	ret0, _ := ret[0].(*entity.RebalanceReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlanRebalance indicates an expected call of PlanRebalance.
+func (mr *MockRebalanceMockRecorder) PlanRebalance(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlanRebalance", reflect.TypeOf((*MockRebalance)(nil).PlanRebalance), arg0)
+}
+
+// Rebalance mocks base method.
+func (m *MockRebalance) Rebalance(arg0 context.Context) (*entity.RebalanceReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rebalance", arg0)
+	ret0, _ := ret[0].(*entity.RebalanceReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rebalance indicates an expected call of Rebalance.
+func (mr *MockRebalanceMockRecorder) Rebalance(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rebalance", reflect.TypeOf((*MockRebalance)(nil).Rebalance), arg0)
+}
+
+// MockForecast is a mock of Forecast interface.
+type MockForecast struct {
+	ctrl     *gomock.Controller
+	recorder *MockForecastMockRecorder
+	isgomock struct{}
+}
+
+// MockForecastMockRecorder is the mock recorder for MockForecast.
+type MockForecastMockRecorder struct {
+	mock *MockForecast
+}
+
+// NewMockForecast creates a new mock instance.
+func NewMockForecast(ctrl *gomock.Controller) *MockForecast {
+	mock := &MockForecast{ctrl: ctrl}
+	mock.recorder = &MockForecastMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockForecast) EXPECT() *MockForecastMockRecorder {
+	return m.recorder
+}
+
+// ForecastDemand mocks base method.
+func (m *MockForecast) ForecastDemand(arg0 context.Context, arg1 entity.ForecastParams) ([]*entity.DemandForecast, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForecastDemand", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.DemandForecast)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForecastDemand indicates an expected call of ForecastDemand.
+func (mr *MockForecastMockRecorder) ForecastDemand(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForecastDemand", reflect.TypeOf((*MockForecast)(nil).ForecastDemand), arg0, arg1)
+}
+
+// MockStockHistory is a mock of StockHistory interface.
+type MockStockHistory struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockHistoryMockRecorder
+	isgomock struct{}
+}
+
+// MockStockHistoryMockRecorder is the mock recorder for MockStockHistory.
+type MockStockHistoryMockRecorder struct {
+	mock *MockStockHistory
+}
+
+// NewMockStockHistory creates a new mock instance.
+func NewMockStockHistory(ctrl *gomock.Controller) *MockStockHistory {
+	mock := &MockStockHistory{ctrl: ctrl}
+	mock.recorder = &MockStockHistoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockHistory) EXPECT() *MockStockHistoryMockRecorder {
+	return m.recorder
+}
+
+// GetOnHandQuantity mocks base method.
+func (m *MockStockHistory) GetOnHandQuantity(arg0 context.Context, arg1, arg2 uuid.UUID, arg3 time.Time) (*entity.StockHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOnHandQuantity", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*entity.StockHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOnHandQuantity indicates an expected call of GetOnHandQuantity.
+func (mr *MockStockHistoryMockRecorder) GetOnHandQuantity(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOnHandQuantity", reflect.TypeOf((*MockStockHistory)(nil).GetOnHandQuantity), arg0, arg1, arg2, arg3)
+}
+
+// ReconcileStock mocks base method.
+func (m *MockStockHistory) ReconcileStock(arg0 context.Context) ([]*entity.StockDiscrepancy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileStock", arg0)
+	ret0, _ := ret[0].([]*entity.StockDiscrepancy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileStock indicates an expected call of ReconcileStock.
+func (mr *MockStockHistoryMockRecorder) ReconcileStock(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileStock", reflect.TypeOf((*MockStockHistory)(nil).ReconcileStock), arg0)
+}
+
+// MockStockSnapshot is a mock of StockSnapshot interface.
+type MockStockSnapshot struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockSnapshotMockRecorder
+	isgomock struct{}
+}
+
+// MockStockSnapshotMockRecorder is the mock recorder for MockStockSnapshot.
+type MockStockSnapshotMockRecorder struct {
+	mock *MockStockSnapshot
+}
+
+// NewMockStockSnapshot creates a new mock instance.
+func NewMockStockSnapshot(ctrl *gomock.Controller) *MockStockSnapshot {
+	mock := &MockStockSnapshot{ctrl: ctrl}
+	mock.recorder = &MockStockSnapshotMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockSnapshot) EXPECT() *MockStockSnapshotMockRecorder {
+	return m.recorder
+}
+
+// CaptureStockSnapshot mocks base method.
+func (m *MockStockSnapshot) CaptureStockSnapshot(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureStockSnapshot", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureStockSnapshot indicates an expected call of CaptureStockSnapshot.
+func (mr *MockStockSnapshotMockRecorder) CaptureStockSnapshot(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureStockSnapshot", reflect.TypeOf((*MockStockSnapshot)(nil).CaptureStockSnapshot), arg0)
+}
+
+// CompareStockSnapshots mocks base method.
+func (m *MockStockSnapshot) CompareStockSnapshots(arg0 context.Context, arg1 entity.StockSnapshotFilter) (*entity.StockComparison, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareStockSnapshots", arg0, arg1)
+	ret0, _ := ret[0].(*entity.StockComparison)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompareStockSnapshots indicates an expected call of CompareStockSnapshots.
+func (mr *MockStockSnapshotMockRecorder) CompareStockSnapshots(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareStockSnapshots", reflect.TypeOf((*MockStockSnapshot)(nil).CompareStockSnapshots), arg0, arg1)
+}
+
+// GetStockTrend mocks base method.
+func (m *MockStockSnapshot) GetStockTrend(arg0 context.Context, arg1 entity.StockSnapshotFilter) ([]*entity.StockTrendPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockTrend", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.StockTrendPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockTrend indicates an expected call of GetStockTrend.
+func (mr *MockStockSnapshotMockRecorder) GetStockTrend(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockTrend", reflect.TypeOf((*MockStockSnapshot)(nil).GetStockTrend), arg0, arg1)
+}
+
+// MockValuation is a mock of Valuation interface.
+type MockValuation struct {
+	ctrl     *gomock.Controller
+	recorder *MockValuationMockRecorder
+	isgomock struct{}
+}
+
+// MockValuationMockRecorder is the mock recorder for MockValuation.
+type MockValuationMockRecorder struct {
+	mock *MockValuation
+}
+
+// NewMockValuation creates a new mock instance.
+func NewMockValuation(ctrl *gomock.Controller) *MockValuation {
+	mock := &MockValuation{ctrl: ctrl}
+	mock.recorder = &MockValuationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockValuation) EXPECT() *MockValuationMockRecorder {
+	return m.recorder
+}
+
+// GetCostOfGoodsSold mocks base method.
+func (m *MockValuation) GetCostOfGoodsSold(arg0 context.Context, arg1 entity.CostOfGoodsSoldPeriod) (*entity.CostOfGoodsSold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCostOfGoodsSold", arg0, arg1)
+	ret0, _ := ret[0].(*entity.CostOfGoodsSold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCostOfGoodsSold indicates an expected call of GetCostOfGoodsSold.
+func (mr *MockValuationMockRecorder) GetCostOfGoodsSold(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCostOfGoodsSold", reflect.TypeOf((*MockValuation)(nil).GetCostOfGoodsSold), arg0, arg1)
+}
+
+// GetInventoryValuation mocks base method.
+func (m *MockValuation) GetInventoryValuation(arg0 context.Context, arg1 uuid.UUID, arg2 string) (*entity.InventoryValuation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInventoryValuation", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*entity.InventoryValuation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInventoryValuation indicates an expected call of GetInventoryValuation.
+func (mr *MockValuationMockRecorder) GetInventoryValuation(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveOut", reflect.TypeOf((*MockTransactionProduct)(nil).MoveOut), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInventoryValuation", reflect.TypeOf((*MockValuation)(nil).GetInventoryValuation), arg0, arg1, arg2)
 }
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+)
+
+type OutboxUseCase struct {
+	repoOutboxPostgre OutboxEventPostgreRepo
+	producer          KafkaProducer
+	batchSize         int
+	maxAttempts       int
+	retryBackoff      time.Duration
+}
+
+func NewOutboxUseCase(
+	repoOutboxPostgre OutboxEventPostgreRepo,
+	producer KafkaProducer,
+	batchSize int,
+	maxAttempts int,
+	retryBackoff time.Duration,
+) *OutboxUseCase {
+	return &OutboxUseCase{
+		repoOutboxPostgre,
+		producer,
+		batchSize,
+		maxAttempts,
+		retryBackoff,
+	}
+}
+
+// publish pending outbox events to kafka and mark them as sent.
+// failed event is retried with exponential backoff until it reaches the max attempts,
+// then it is marked as failed and need to be checked manually.
+// Later events of the same key wait for the failed event, so consumers see the key in order.
+func (u *OutboxUseCase) RelayPendingEvents(ctx context.Context) (int, error) {
+	outboxEvents, err := u.repoOutboxPostgre.GetPending(ctx, time.Now(), u.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pending outbox events: %w", err)
+	}
+
+	var sent int
+	failedKeys := make(map[string]bool)
+	for _, outboxEvent := range outboxEvents {
+		failedKey := outboxEvent.Topic + ":" + outboxEvent.Key
+		if failedKeys[failedKey] {
+			continue
+		}
+
+		outboxEvent.Attempts++
+
+		err := u.producer.ProduceSync(outboxEvent.Topic, []byte(outboxEvent.Key), json.RawMessage(outboxEvent.Payload))
+		if err != nil {
+			outboxEvent.LastError = err.Error()
+			outboxEvent.NextAttemptAt = time.Now().Add(u.retryBackoff * time.Duration(1<<(outboxEvent.Attempts-1)))
+			if outboxEvent.Attempts >= u.maxAttempts {
+				outboxEvent.Status = entity.OutboxEventStatusFailed
+			}
+
+			if err := u.repoOutboxPostgre.MarkFailed(ctx, outboxEvent); err != nil {
+				return sent, fmt.Errorf("failed to mark outbox event as failed: %w", err)
+			}
+			failedKeys[failedKey] = true
+			continue
+		}
+
+		outboxEvent.Status = entity.OutboxEventStatusSent
+		outboxEvent.SentAt = time.Now()
+		if err := u.repoOutboxPostgre.MarkSent(ctx, outboxEvent); err != nil {
+			return sent, fmt.Errorf("failed to mark outbox event as sent: %w", err)
+		}
+		sent++
+	}
+
+	return sent, nil
+}
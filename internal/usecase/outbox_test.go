@@ -0,0 +1,175 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+const (
+	outboxBatchSize    = 10
+	outboxMaxAttempts  = 3
+	outboxRetryBackoff = time.Second
+)
+
+func outbox(t *testing.T) (*usecase.OutboxUseCase, *MockOutboxEventPostgreRepo, *MockKafkaProducer) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	repo := NewMockOutboxEventPostgreRepo(mockCtl)
+	producer := NewMockKafkaProducer(mockCtl)
+	outbox := usecase.NewOutboxUseCase(repo, producer, outboxBatchSize, outboxMaxAttempts, outboxRetryBackoff)
+
+	return outbox, repo, producer
+}
+
+func TestRelayPendingEvents(t *testing.T) {
+	// t.Parallell()
+	outbox, repo, producer := outbox(t)
+
+	newEvent := func(attempts int) *entity.OutboxEvent {
+		return &entity.OutboxEvent{
+			ID:       uuid.New(),
+			Topic:    "product-quantity-updated",
+			Key:      uuid.NewString(),
+			Payload:  []byte(`{"quantity":10}`),
+			Status:   entity.OutboxEventStatusPending,
+			Attempts: attempts,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		mock     func()
+		expected int
+		err      error
+	}{
+		{
+			name: "publish and mark sent",
+			mock: func() {
+				event := newEvent(0)
+				repo.EXPECT().
+					GetPending(context.Background(), gomock.Any(), outboxBatchSize).
+					Return([]*entity.OutboxEvent{event}, nil)
+				producer.EXPECT().
+					ProduceSync(event.Topic, []byte(event.Key), json.RawMessage(event.Payload)).
+					Return(nil)
+				repo.EXPECT().
+					MarkSent(context.Background(), event).
+					DoAndReturn(func(_ context.Context, e *entity.OutboxEvent) error {
+						assert.Equal(t, entity.OutboxEventStatusSent, e.Status)
+						assert.Equal(t, 1, e.Attempts)
+						assert.False(t, e.SentAt.IsZero())
+						return nil
+					})
+			},
+			expected: 1,
+			err:      nil,
+		},
+		{
+			name: "publish failed and retry later",
+			mock: func() {
+				event := newEvent(1)
+				repo.EXPECT().
+					GetPending(context.Background(), gomock.Any(), outboxBatchSize).
+					Return([]*entity.OutboxEvent{event}, nil)
+				producer.EXPECT().
+					ProduceSync(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(errInternalServerError)
+				repo.EXPECT().
+					MarkFailed(context.Background(), event).
+					DoAndReturn(func(_ context.Context, e *entity.OutboxEvent) error {
+						assert.Equal(t, entity.OutboxEventStatusPending, e.Status)
+						assert.Equal(t, 2, e.Attempts)
+						assert.Equal(t, errInternalServerError.Error(), e.LastError)
+						assert.WithinDuration(t, time.Now().Add(2*outboxRetryBackoff), e.NextAttemptAt, time.Second)
+						return nil
+					})
+			},
+			expected: 0,
+			err:      nil,
+		},
+		{
+			name: "publish failed on last attempt",
+			mock: func() {
+				event := newEvent(outboxMaxAttempts - 1)
+				repo.EXPECT().
+					GetPending(context.Background(), gomock.Any(), outboxBatchSize).
+					Return([]*entity.OutboxEvent{event}, nil)
+				producer.EXPECT().
+					ProduceSync(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(errInternalServerError)
+				repo.EXPECT().
+					MarkFailed(context.Background(), event).
+					DoAndReturn(func(_ context.Context, e *entity.OutboxEvent) error {
+						assert.Equal(t, entity.OutboxEventStatusFailed, e.Status)
+						return nil
+					})
+			},
+			expected: 0,
+			err:      nil,
+		},
+		{
+			name: "publish failed holds later events of the same key",
+			mock: func() {
+				failed := newEvent(0)
+				sameKey := newEvent(0)
+				sameKey.Key = failed.Key
+				otherKey := newEvent(0)
+				repo.EXPECT().
+					GetPending(context.Background(), gomock.Any(), outboxBatchSize).
+					Return([]*entity.OutboxEvent{failed, sameKey, otherKey}, nil)
+				producer.EXPECT().
+					ProduceSync(failed.Topic, []byte(failed.Key), json.RawMessage(failed.Payload)).
+					Return(errInternalServerError)
+				repo.EXPECT().
+					MarkFailed(context.Background(), failed).
+					Return(nil)
+				producer.EXPECT().
+					ProduceSync(otherKey.Topic, []byte(otherKey.Key), json.RawMessage(otherKey.Payload)).
+					Return(nil)
+				repo.EXPECT().
+					MarkSent(context.Background(), otherKey).
+					Return(nil)
+			},
+			expected: 1,
+			err:      nil,
+		},
+		{
+			name: "get pending error",
+			mock: func() {
+				repo.EXPECT().
+					GetPending(context.Background(), gomock.Any(), outboxBatchSize).
+					Return(nil, errInternalServerError)
+			},
+			expected: 0,
+			err:      errInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			sent, err := outbox.RelayPendingEvents(context.Background())
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expected, sent)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+)
+
+type RebalanceUseCase struct {
+	repoMovePostgre        StockMovementPostgreRepo
+	repoWarehousePostgre   WarehousePostgreRepo
+	repoProductPostgre     WarehouseProductPostgreRepo
+	repoTransactionPostgre TransactionProductPostgresRepo
+	limits                 entity.RebalanceLimits
+}
+
+func NewRebalanceUseCase(
+	repoMovePostgre StockMovementPostgreRepo,
+	repoWarehousePostgre WarehousePostgreRepo,
+	repoProductPostgre WarehouseProductPostgreRepo,
+	repoTransactionPostgre TransactionProductPostgresRepo,
+	limits entity.RebalanceLimits,
+) *RebalanceUseCase {
+	return &RebalanceUseCase{
+		repoMovePostgre,
+		repoWarehousePostgre,
+		repoProductPostgre,
+		repoTransactionPostgre,
+		limits,
+	}
+}
+
+// report the transfers from the main warehouse which would cover the recent demand of every region,
+// nothing is moved
+func (u *RebalanceUseCase) PlanRebalance(ctx context.Context) (*entity.RebalanceReport, error) {
+	return u.plan(ctx, true)
+}
+
+// create the planned transfers. A failed transfer is reported in its line
+// and does not stop the remaining transfers.
+func (u *RebalanceUseCase) Rebalance(ctx context.Context) (*entity.RebalanceReport, error) {
+	report, err := u.plan(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range report.Lines {
+		stockMovement := line.ToStockMovement(report.ID, report.CreatedAt)
+		if err := stockMovement.GenerateStockMovementID(); err != nil {
+			line.Error = fmt.Sprintf("failed to generate stock movement id: %s", err)
+			continue
+		}
+		if err := u.repoTransactionPostgre.TransferIn(ctx, stockMovement); err != nil {
+			line.Error = err.Error()
+			continue
+		}
+		line.StockMovement = stockMovement
+	}
+
+	return report, nil
+}
+
+func (u *RebalanceUseCase) plan(ctx context.Context, dryRun bool) (*entity.RebalanceReport, error) {
+	report := &entity.RebalanceReport{
+		DryRun:    dryRun,
+		CreatedAt: time.Now(),
+	}
+	report.Since = report.CreatedAt.Add(-u.limits.Lookback)
+	if err := report.GenerateRebalanceReportID(); err != nil {
+		return nil, fmt.Errorf("failed to generate rebalance report id: %w", err)
+	}
+
+	mainWarehouseID, err := u.repoWarehousePostgre.GetMainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get main warehouse id: %w", err)
+	}
+
+	demands, err := u.repoMovePostgre.GetOutboundDemand(ctx, report.Since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outbound demand: %w", err)
+	}
+
+	mainAvailable := make(map[uuid.UUID]int64)
+	for _, demand := range demands {
+		if _, ok := mainAvailable[demand.ProductID]; ok || demand.WarehouseID == mainWarehouseID {
+			continue
+		}
+
+		warehouses, err := u.repoProductPostgre.GetWarehouseIDZipCodeAndQtyByProductID(ctx, demand.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get available quantity of product %s: %w", demand.ProductID, err)
+		}
+
+		mainAvailable[demand.ProductID] = 0
+		for _, warehouse := range warehouses {
+			if warehouse.WarehouseID == mainWarehouseID {
+				mainAvailable[demand.ProductID] = warehouse.ProductQuantity
+			}
+		}
+	}
+
+	report.Lines = entity.PlanRebalance(mainWarehouseID, demands, mainAvailable, u.limits)
+	return report, nil
+}
@@ -0,0 +1,150 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func rebalance(t *testing.T) (*usecase.RebalanceUseCase, *MockStockMovementPostgreRepo, *MockWarehousePostgreRepo, *MockWarehouseProductPostgreRepo, *MockTransactionProductPostgresRepo) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	repoMove := NewMockStockMovementPostgreRepo(mockCtl)
+	repoWarehouse := NewMockWarehousePostgreRepo(mockCtl)
+	repoProduct := NewMockWarehouseProductPostgreRepo(mockCtl)
+	repoTransaction := NewMockTransactionProductPostgresRepo(mockCtl)
+	rebalance := usecase.NewRebalanceUseCase(repoMove, repoWarehouse, repoProduct, repoTransaction, entity.RebalanceLimits{
+		Lookback:               7 * 24 * time.Hour,
+		Coverage:               7 * 24 * time.Hour,
+		MaxTransfers:           10,
+		MaxQuantityPerTransfer: 100,
+	})
+
+	return rebalance, repoMove, repoWarehouse, repoProduct, repoTransaction
+}
+
+func TestPlanRebalance(t *testing.T) {
+	// t.Parallell()
+	rebalance, repoMove, repoWarehouse, repoProduct, _ := rebalance(t)
+
+	mainWarehouseID := uuid.New()
+	warehouseID := uuid.New()
+	productID := uuid.New()
+
+	tests := []struct {
+		name  string
+		mock  func()
+		lines int
+		err   error
+	}{
+		{
+			name: "success",
+			mock: func() {
+				repoWarehouse.EXPECT().
+					GetMainID(context.Background()).
+					Return(mainWarehouseID, nil)
+				repoMove.EXPECT().
+					GetOutboundDemand(context.Background(), gomock.Any()).
+					Return([]*entity.WarehouseDemand{
+						{WarehouseID: warehouseID, ProductID: productID, OutboundQuantity: 10, AvailableQuantity: 4},
+					}, nil)
+				repoProduct.EXPECT().
+					GetWarehouseIDZipCodeAndQtyByProductID(context.Background(), productID).
+					Return([]*entity.WarehouseAddressAndProductQty{
+						{WarehouseID: mainWarehouseID, ProductID: productID, ProductQuantity: 50},
+						{WarehouseID: warehouseID, ProductID: productID, ProductQuantity: 4},
+					}, nil)
+			},
+			lines: 1,
+			err:   nil,
+		},
+		{
+			name: "failed to get outbound demand",
+			mock: func() {
+				repoWarehouse.EXPECT().
+					GetMainID(context.Background()).
+					Return(mainWarehouseID, nil)
+				repoMove.EXPECT().
+					GetOutboundDemand(context.Background(), gomock.Any()).
+					Return(nil, errInternalServerError)
+			},
+			err: errInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			report, err := rebalance.PlanRebalance(context.Background())
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, report.DryRun)
+			assert.Len(t, report.Lines, tc.lines)
+			assert.Equal(t, int64(6), report.Lines[0].Quantity)
+			assert.Nil(t, report.Lines[0].StockMovement)
+		})
+	}
+}
+
+func TestRebalance(t *testing.T) {
+	// t.Parallell()
+	rebalance, repoMove, repoWarehouse, repoProduct, repoTransaction := rebalance(t)
+
+	mainWarehouseID := uuid.New()
+	warehouseA := uuid.New()
+	warehouseB := uuid.New()
+	productID := uuid.New()
+
+	repoWarehouse.EXPECT().
+		GetMainID(context.Background()).
+		Return(mainWarehouseID, nil)
+	repoMove.EXPECT().
+		GetOutboundDemand(context.Background(), gomock.Any()).
+		Return([]*entity.WarehouseDemand{
+			{WarehouseID: warehouseA, ProductID: productID, OutboundQuantity: 10},
+			{WarehouseID: warehouseB, ProductID: productID, OutboundQuantity: 5},
+		}, nil)
+	repoProduct.EXPECT().
+		GetWarehouseIDZipCodeAndQtyByProductID(context.Background(), productID).
+		Return([]*entity.WarehouseAddressAndProductQty{
+			{WarehouseID: mainWarehouseID, ProductID: productID, ProductQuantity: 50},
+		}, nil)
+	repoTransaction.EXPECT().
+		TransferIn(context.Background(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, stockMovement *entity.StockMovement) error {
+			if stockMovement.ToWarehouseID == warehouseB {
+				return errInternalServerError
+			}
+			return nil
+		}).
+		Times(2)
+
+	report, err := rebalance.Rebalance(context.Background())
+
+	// a failed transfer is reported without stopping the others
+	assert.NoError(t, err)
+	assert.False(t, report.DryRun)
+	assert.Len(t, report.Lines, 2)
+	assert.NotNil(t, report.Lines[0].StockMovement)
+	assert.Equal(t, report.ID.String(), report.Lines[0].StockMovement.ReferenceID)
+	assert.Equal(t, entity.RebalanceReason, report.Lines[0].StockMovement.Reason)
+	assert.Nil(t, report.Lines[1].StockMovement)
+	assert.Equal(t, errInternalServerError.Error(), report.Lines[1].Error)
+}
@@ -0,0 +1,349 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/pkg/postgresql"
+)
+
+type CostLayerPostgreRepo struct {
+	*postgresql.Postgres
+}
+
+func NewCostLayerPostgreRepo(client *postgresql.Postgres) *CostLayerPostgreRepo {
+	return &CostLayerPostgreRepo{
+		client,
+	}
+}
+
+const (
+	queryInsertCostLayer = `
+		INSERT INTO cost_layers (id, warehouse_id, product_id, stock_movement_id, quantity, remaining_quantity, unit_cost, created_at)
+		VALUES ($1, $2, $3, NULLIF($4::uuid, '00000000-0000-0000-0000-000000000000'), $5, $6, $7, $8)`
+
+	// oldest layers first, locked so concurrent movements can not consume the same quantity
+	queryLockCostLayers = `
+		SELECT id, quantity, remaining_quantity, unit_cost, created_at
+		FROM cost_layers
+		WHERE warehouse_id = $1
+		AND product_id = $2
+		AND remaining_quantity > 0
+		ORDER BY created_at, id
+		FOR UPDATE`
+
+	queryUpdateCostLayerRemainingQuantity = `UPDATE cost_layers SET remaining_quantity = $1 WHERE id = $2`
+
+	queryInsertCostLayerConsumption = `
+		INSERT INTO cost_layer_consumptions (id, cost_layer_id, stock_movement_id, warehouse_id, product_id, quantity, unit_cost, created_at)
+		VALUES ($1, NULLIF($2::uuid, '00000000-0000-0000-0000-000000000000'), $3, $4, $5, $6, $7, $8)`
+
+	// average cost of the remaining layers, the product price when no layer is left
+	queryGetCurrentUnitCost = `
+		SELECT COALESCE(
+			(SELECT SUM(remaining_quantity * unit_cost) / NULLIF(SUM(remaining_quantity), 0)
+			 FROM cost_layers
+			 WHERE warehouse_id = $1
+			 AND product_id = $2
+			 AND remaining_quantity > 0),
+			(SELECT product_price
+			 FROM warehouse_products
+			 WHERE warehouse_id = $1
+			 AND product_id = $2
+			 AND deleted_at IS NULL),
+			0)`
+)
+
+// insert the layer of quantity entering the warehouse, nothing of it is consumed yet
+func insertCostLayer(ctx context.Context, tx *sql.Tx, layer *entity.CostLayer) error {
+	if err := layer.GenerateCostLayerID(); err != nil {
+		return fmt.Errorf("failed to generate cost layer id: %w", err)
+	}
+	layer.RemainingQuantity = layer.Quantity
+
+	_, err := tx.ExecContext(ctx, queryInsertCostLayer,
+		layer.ID,
+		layer.WarehouseID,
+		layer.ProductID,
+		layer.StockMovementID,
+		layer.Quantity,
+		layer.RemainingQuantity,
+		layer.UnitCost,
+		layer.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert cost layer: %w", err)
+	}
+
+	return nil
+}
+
+func getCurrentUnitCost(ctx context.Context, tx *sql.Tx, warehouseID, productID uuid.UUID) (float64, error) {
+	var unitCost float64
+	if err := tx.QueryRowContext(ctx, queryGetCurrentUnitCost, warehouseID, productID).Scan(&unitCost); err != nil {
+		return 0, fmt.Errorf("failed to get current unit cost: %w", err)
+	}
+
+	return unitCost, nil
+}
+
+// insert the layer of quantity entering the warehouse of the movement at the current cost of the product
+func insertCostLayerAtCurrentCost(ctx context.Context, tx *sql.Tx, movement *entity.StockMovement) error {
+	unitCost, err := getCurrentUnitCost(ctx, tx, movement.ToWarehouseID, movement.ProductID)
+	if err != nil {
+		return err
+	}
+
+	return insertCostLayer(ctx, tx, &entity.CostLayer{
+		WarehouseID:     movement.ToWarehouseID,
+		ProductID:       movement.ProductID,
+		StockMovementID: movement.ID,
+		Quantity:        movement.Quantity,
+		UnitCost:        unitCost,
+		CreatedAt:       movement.CreatedAt,
+	})
+}
+
+// insert the layer of quantity entering the warehouse of the movement at the cost it had when it left another place,
+// the cost is the average of the consumptions selected by the query, or the current cost when there are none
+func insertCostLayerAtConsumedCost(ctx context.Context, tx *sql.Tx, movement *entity.StockMovement, queryConsumedUnitCost string, args ...interface{}) error {
+	var consumedUnitCost sql.NullFloat64
+	if err := tx.QueryRowContext(ctx, queryConsumedUnitCost, args...).Scan(&consumedUnitCost); err != nil {
+		return fmt.Errorf("failed to get consumed unit cost: %w", err)
+	}
+	if !consumedUnitCost.Valid {
+		return insertCostLayerAtCurrentCost(ctx, tx, movement)
+	}
+
+	return insertCostLayer(ctx, tx, &entity.CostLayer{
+		WarehouseID:     movement.ToWarehouseID,
+		ProductID:       movement.ProductID,
+		StockMovementID: movement.ID,
+		Quantity:        movement.Quantity,
+		UnitCost:        consumedUnitCost.Float64,
+		CreatedAt:       movement.CreatedAt,
+	})
+}
+
+// consume the layers of the source warehouse of the movement from the oldest,
+// the movement must be inserted before as the consumptions reference it
+func consumeCostLayers(ctx context.Context, tx *sql.Tx, movement *entity.StockMovement) ([]*entity.CostLayerConsumption, error) {
+	// 1. lock remaining layers
+	rows, err := tx.QueryContext(ctx, queryLockCostLayers, movement.FromWarehouseID, movement.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock cost layers: %w", err)
+	}
+	var layers []*entity.CostLayer
+	for rows.Next() {
+		layer := entity.CostLayer{
+			WarehouseID: movement.FromWarehouseID,
+			ProductID:   movement.ProductID,
+		}
+		if err := rows.Scan(
+			&layer.ID,
+			&layer.Quantity,
+			&layer.RemainingQuantity,
+			&layer.UnitCost,
+			&layer.CreatedAt,
+		); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan cost layer: %w", err)
+		}
+		layers = append(layers, &layer)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to lock cost layers: %w", err)
+	}
+
+	// 2. quantity not covered by the layers is taken at the current cost
+	fallbackUnitCost, err := getCurrentUnitCost(ctx, tx, movement.FromWarehouseID, movement.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := make(map[uuid.UUID]int64, len(layers))
+	for _, layer := range layers {
+		remaining[layer.ID] = layer.RemainingQuantity
+	}
+	consumptions := entity.ConsumeCostLayers(layers, movement.Quantity, fallbackUnitCost)
+
+	// 3. update remaining quantity of the consumed layers
+	for _, layer := range layers {
+		if layer.RemainingQuantity == remaining[layer.ID] {
+			continue
+		}
+		_, err = tx.ExecContext(ctx, queryUpdateCostLayerRemainingQuantity, layer.RemainingQuantity, layer.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update cost layer: %w", err)
+		}
+	}
+
+	// 4. insert consumptions
+	for _, consumption := range consumptions {
+		if err = consumption.GenerateCostLayerConsumptionID(); err != nil {
+			return nil, fmt.Errorf("failed to generate cost layer consumption id: %w", err)
+		}
+		consumption.StockMovementID = movement.ID
+
+		_, err = tx.ExecContext(ctx, queryInsertCostLayerConsumption,
+			consumption.ID,
+			consumption.CostLayerID,
+			consumption.StockMovementID,
+			movement.FromWarehouseID,
+			movement.ProductID,
+			consumption.Quantity,
+			consumption.UnitCost,
+			movement.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to insert cost layer consumption: %w", err)
+		}
+	}
+
+	return consumptions, nil
+}
+
+const queryGetProductCostsByWarehouseID = `
+	SELECT
+		wp.warehouse_id,
+		wp.product_id,
+		wp.product_name,
+		COALESCE(SUM(cl.remaining_quantity), 0),
+		COALESCE(SUM(cl.remaining_quantity * cl.unit_cost), 0),
+		COALESCE(SUM(cl.quantity), 0),
+		COALESCE(SUM(cl.quantity * cl.unit_cost), 0)
+	FROM warehouse_products wp
+	LEFT JOIN cost_layers cl
+	ON cl.warehouse_id = wp.warehouse_id
+	AND cl.product_id = wp.product_id
+	WHERE wp.warehouse_id = $1
+	AND wp.deleted_at IS NULL
+	GROUP BY wp.warehouse_id, wp.product_id, wp.product_name
+	ORDER BY wp.product_name, wp.product_id;`
+
+// remaining and received cost of every product of the warehouse
+func (r *CostLayerPostgreRepo) GetProductCosts(ctx context.Context, warehouseID uuid.UUID) ([]*entity.ProductCost, error) {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetProductCostsByWarehouseID)
+	if errStmt != nil {
+		return nil, errStmt
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var costs []*entity.ProductCost
+	for rows.Next() {
+		var cost entity.ProductCost
+		err := rows.Scan(
+			&cost.WarehouseID,
+			&cost.ProductID,
+			&cost.ProductName,
+			&cost.RemainingQuantity,
+			&cost.RemainingCost,
+			&cost.ReceivedQuantity,
+			&cost.ReceivedCost,
+		)
+		if err != nil {
+			return nil, err
+		}
+		costs = append(costs, &cost)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return costs, nil
+}
+
+// sale movements of the period grouped by warehouse and product, with the cost of the layers they consumed
+// and the cost of the layers received until the end of the period
+const queryGetSalesCosts = `
+	SELECT
+		sales.warehouse_id,
+		sales.product_id,
+		sales.product_name,
+		sales.sold_quantity,
+		sales.fifo_cost,
+		COALESCE(received.quantity, 0),
+		COALESCE(received.cost, 0)
+	FROM (
+		SELECT
+			sm.from_warehouse_id AS warehouse_id,
+			sm.product_id,
+			MAX(sm.product_name) AS product_name,
+			SUM(sm.quantity) AS sold_quantity,
+			COALESCE(SUM(consumed.cost), 0) AS fifo_cost
+		FROM stock_movements sm
+		LEFT JOIN (
+			SELECT stock_movement_id, SUM(quantity * unit_cost) AS cost
+			FROM cost_layer_consumptions
+			GROUP BY stock_movement_id
+		) consumed
+		ON consumed.stock_movement_id = sm.id
+		WHERE sm.movement_type = 'sale'
+		AND sm.created_at >= $1::date
+		AND sm.created_at < $2::date + 1
+		AND ($3::uuid = '00000000-0000-0000-0000-000000000000' OR sm.from_warehouse_id = $3)
+		GROUP BY sm.from_warehouse_id, sm.product_id
+	) sales
+	LEFT JOIN LATERAL (
+		SELECT SUM(cl.quantity) AS quantity, SUM(cl.quantity * cl.unit_cost) AS cost
+		FROM cost_layers cl
+		WHERE cl.warehouse_id = sales.warehouse_id
+		AND cl.product_id = sales.product_id
+		AND cl.created_at < $2::date + 1
+	) received ON true
+	ORDER BY sales.warehouse_id, sales.product_name, sales.product_id;`
+
+func (r *CostLayerPostgreRepo) GetSalesCosts(ctx context.Context, period entity.CostOfGoodsSoldPeriod) ([]*entity.ProductSalesCost, error) {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetSalesCosts)
+	if errStmt != nil {
+		return nil, errStmt
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx,
+		period.From.Format(time.DateOnly),
+		period.To.Format(time.DateOnly),
+		period.WarehouseID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sales []*entity.ProductSalesCost
+	for rows.Next() {
+		var sale entity.ProductSalesCost
+		err := rows.Scan(
+			&sale.WarehouseID,
+			&sale.ProductID,
+			&sale.ProductName,
+			&sale.SoldQuantity,
+			&sale.FIFOCost,
+			&sale.ProductCost.ReceivedQuantity,
+			&sale.ProductCost.ReceivedCost,
+		)
+		if err != nil {
+			return nil, err
+		}
+		sale.ProductCost.WarehouseID = sale.WarehouseID
+		sale.ProductCost.ProductID = sale.ProductID
+		sale.ProductCost.ProductName = sale.ProductName
+		sales = append(sales, &sale)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sales, nil
+}
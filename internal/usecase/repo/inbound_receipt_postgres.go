@@ -0,0 +1,426 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/pkg/postgresql"
+)
+
+type InboundReceiptPostgreRepo struct {
+	*postgresql.Postgres
+}
+
+func NewInboundReceiptPostgreRepo(client *postgresql.Postgres) *InboundReceiptPostgreRepo {
+	return &InboundReceiptPostgreRepo{
+		client,
+	}
+}
+
+const (
+	queryInsertInboundReceipt = `
+		INSERT INTO inbound_receipts (id, warehouse_id, type, reference_number, status, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	queryInsertInboundReceiptLine = `
+		INSERT INTO inbound_receipt_lines (id, inbound_receipt_id, product_id, product_name, expected_quantity, received_quantity, unit_cost)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+)
+
+// save inbound receipt with its expected lines
+func (r *InboundReceiptPostgreRepo) Save(ctx context.Context, inboundReceipt *entity.InboundReceipt) error {
+	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, queryInsertInboundReceipt,
+		inboundReceipt.ID,
+		inboundReceipt.WarehouseID,
+		inboundReceipt.Type,
+		inboundReceipt.ReferenceNumber,
+		inboundReceipt.Status,
+		inboundReceipt.CreatedBy,
+		inboundReceipt.CreatedAt,
+		inboundReceipt.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert inbound receipt: %w", err)
+	}
+
+	for _, line := range inboundReceipt.Lines {
+		_, err = tx.ExecContext(ctx, queryInsertInboundReceiptLine,
+			line.ID,
+			line.InboundReceiptID,
+			line.ProductID,
+			line.ProductName,
+			line.ExpectedQuantity,
+			line.ReceivedQuantity,
+			line.UnitCost,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert inbound receipt line: %w", err)
+		}
+	}
+
+	if errCommit := tx.Commit(); errCommit != nil {
+		return fmt.Errorf("failed to commit transaction: %w", errCommit)
+	}
+
+	return nil
+}
+
+const (
+	queryGetInboundReceiptByID = `
+		SELECT id, warehouse_id, type, reference_number, status, created_by, created_at, updated_at
+		FROM inbound_receipts
+		WHERE id = $1;`
+
+	queryGetInboundReceiptLinesByInboundReceiptID = `
+		SELECT id, inbound_receipt_id, product_id, product_name, expected_quantity, received_quantity, unit_cost
+		FROM inbound_receipt_lines
+		WHERE inbound_receipt_id = $1
+		ORDER BY product_id;`
+)
+
+func (r *InboundReceiptPostgreRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.InboundReceipt, error) {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetInboundReceiptByID)
+	if errStmt != nil {
+		return nil, errStmt
+	}
+	defer stmt.Close()
+
+	var inboundReceipt entity.InboundReceipt
+	err := stmt.QueryRowContext(ctx, id).Scan(
+		&inboundReceipt.ID,
+		&inboundReceipt.WarehouseID,
+		&inboundReceipt.Type,
+		&inboundReceipt.ReferenceNumber,
+		&inboundReceipt.Status,
+		&inboundReceipt.CreatedBy,
+		&inboundReceipt.CreatedAt,
+		&inboundReceipt.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stmtLines, errStmt := r.Conn.PrepareContext(ctx, queryGetInboundReceiptLinesByInboundReceiptID)
+	if errStmt != nil {
+		return nil, errStmt
+	}
+	defer stmtLines.Close()
+
+	rows, err := stmtLines.QueryContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	inboundReceipt.Lines, err = scanInboundReceiptLines(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inboundReceipt, nil
+}
+
+func scanInboundReceiptLines(rows *sql.Rows) ([]*entity.InboundReceiptLine, error) {
+	var lines []*entity.InboundReceiptLine
+	for rows.Next() {
+		var line entity.InboundReceiptLine
+		if err := rows.Scan(
+			&line.ID,
+			&line.InboundReceiptID,
+			&line.ProductID,
+			&line.ProductName,
+			&line.ExpectedQuantity,
+			&line.ReceivedQuantity,
+			&line.UnitCost,
+		); err != nil {
+			return nil, err
+		}
+		lines = append(lines, &line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+const (
+	queryLockInboundReceipt = `
+		SELECT warehouse_id, type, reference_number, status, created_by, created_at
+		FROM inbound_receipts
+		WHERE id = $1
+		FOR UPDATE`
+
+	queryUpdateInboundReceiptLineReceivedQuantity = `
+		UPDATE inbound_receipt_lines
+		SET received_quantity = received_quantity + $1
+		WHERE inbound_receipt_id = $2
+		AND product_id = $3
+		RETURNING product_name, unit_cost`
+
+	queryUpdateInboundReceiptStatus = `UPDATE inbound_receipts SET status = $1, updated_at = $2 WHERE id = $3`
+
+	queryInsertReceiptMovement = `
+		INSERT INTO stock_movements (
+			id,
+			product_id,
+			product_name,
+			quantity,
+			to_warehouse_id,
+			movement_type,
+			reference_id,
+			created_by,
+			idempotency_key,
+			created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10)`
+)
+
+// receive a delivery of the inbound receipt, the received quantity of each delivered line is added
+// to the product quantity of the receipt warehouse and recorded as receipt stock movement.
+// receiving more than expected is allowed and shown as over quantity of the line
+func (r *InboundReceiptPostgreRepo) Receive(ctx context.Context, inboundReceipt *entity.InboundReceipt, idempotencyKey string) error {
+	// begin transaction
+	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// a retried request returns the movements created by the first request
+	existing, err := findIdempotentStockMovements(ctx, tx, idempotencyKey)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		requested := make(map[uuid.UUID]int64, len(inboundReceipt.Lines))
+		for _, line := range inboundReceipt.Lines {
+			requested[line.ProductID] += line.ReceivedQuantity
+		}
+		if err := entity.MatchIdempotentStockMovements(existing, requested); err != nil {
+			return err
+		}
+		inboundReceipt.StockMovements = existing
+		return nil
+	}
+
+	// 1. lock inbound receipt, so deliveries of the same receipt are serialized
+	err = tx.QueryRowContext(ctx, queryLockInboundReceipt, inboundReceipt.ID).Scan(
+		&inboundReceipt.WarehouseID,
+		&inboundReceipt.Type,
+		&inboundReceipt.ReferenceNumber,
+		&inboundReceipt.Status,
+		&inboundReceipt.CreatedBy,
+		&inboundReceipt.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("inbound receipt not found: %w", entity.ErrInvalidInboundReceipt)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock inbound receipt: %w", err)
+	}
+	if !inboundReceipt.IsReceivable() {
+		return fmt.Errorf("inbound receipt is closed: %w", entity.ErrInvalidInboundReceipt)
+	}
+
+	var stockMovements []*entity.StockMovement
+	var productIDs []uuid.UUID
+	for _, line := range inboundReceipt.Lines {
+		// 2. add received quantity to the receipt line
+		err = tx.QueryRowContext(ctx, queryUpdateInboundReceiptLineReceivedQuantity,
+			line.ReceivedQuantity, inboundReceipt.ID, line.ProductID,
+		).Scan(&line.ProductName, &line.UnitCost)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("product %s is not expected by the inbound receipt: %w", line.ProductID, entity.ErrInvalidInboundReceipt)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update received quantity: %w", err)
+		}
+
+		movement := &entity.StockMovement{
+			ProductID:      line.ProductID,
+			ProductName:    line.ProductName,
+			Quantity:       line.ReceivedQuantity,
+			ToWarehouseID:  inboundReceipt.WarehouseID,
+			MovementType:   entity.MovementTypeReceipt,
+			ReferenceID:    inboundReceipt.ReferenceNumber,
+			CreatedBy:      inboundReceipt.ReceivedBy,
+			IdempotencyKey: idempotencyKey,
+			CreatedAt:      inboundReceipt.UpdatedAt,
+		}
+		if err = movement.GenerateStockMovementID(); err != nil {
+			return fmt.Errorf("failed to generate stock movement id: %w", err)
+		}
+
+		// 3. add received quantity to the warehouse product
+		if err = receiveProductQuantity(ctx, tx, movement); err != nil {
+			return err
+		}
+
+		// 4. insert stock movement
+		_, err = tx.ExecContext(ctx, queryInsertReceiptMovement,
+			movement.ID,
+			movement.ProductID,
+			movement.ProductName,
+			movement.Quantity,
+			movement.ToWarehouseID,
+			movement.MovementType,
+			movement.ReferenceID,
+			movement.CreatedBy,
+			movement.IdempotencyKey,
+			movement.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert stock movement: %w", err)
+		}
+
+		// 5. value received quantity at the purchase cost
+		err = insertCostLayer(ctx, tx, &entity.CostLayer{
+			WarehouseID:     movement.ToWarehouseID,
+			ProductID:       movement.ProductID,
+			StockMovementID: movement.ID,
+			Quantity:        movement.Quantity,
+			UnitCost:        line.UnitCost,
+			CreatedAt:       movement.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		stockMovements = append(stockMovements, movement)
+		productIDs = append(productIDs, movement.ProductID)
+	}
+
+	// 6. update inbound receipt status from all of its lines
+	rows, err := tx.QueryContext(ctx, queryGetInboundReceiptLinesByInboundReceiptID, inboundReceipt.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get inbound receipt lines: %w", err)
+	}
+	inboundReceipt.Lines, err = scanInboundReceiptLines(rows)
+	rows.Close()
+	if err != nil {
+		return fmt.Errorf("failed to get inbound receipt lines: %w", err)
+	}
+
+	inboundReceipt.UpdateStatus()
+	_, err = tx.ExecContext(ctx, queryUpdateInboundReceiptStatus, inboundReceipt.Status, inboundReceipt.UpdatedAt, inboundReceipt.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update inbound receipt status: %w", err)
+	}
+
+	// 7. save product quantity updated events to outbox
+	err = insertProductQuantityUpdatedEvents(ctx, tx, productIDs, inboundReceipt.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	// commit transaction
+	if errCommit := tx.Commit(); errCommit != nil {
+		return fmt.Errorf("failed to commit transaction: %w", errCommit)
+	}
+
+	inboundReceipt.StockMovements = stockMovements
+	return nil
+}
+
+// add the movement quantity to the destination warehouse product,
+// the warehouse product is created from the product details of another warehouse when it does not exist yet
+func receiveProductQuantity(ctx context.Context, tx *sql.Tx, movement *entity.StockMovement) error {
+	// 1. lock destination product row if exists
+	var whDestProductID uuid.UUID
+	err := tx.QueryRowContext(ctx, queryLockDestProduct,
+		movement.ProductID, movement.ToWarehouseID,
+	).Scan(&whDestProductID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to check or lock destination product: %w", err)
+	}
+
+	// 2. update destination quantity
+	if err == nil {
+		_, err = tx.ExecContext(ctx, queryUpdateDestQuantity,
+			movement.Quantity, movement.CreatedAt, movement.ProductID, movement.ToWarehouseID)
+		if err != nil {
+			return fmt.Errorf("failed to update destination quantity: %w", err)
+		}
+		return nil
+	}
+
+	// 3. create new product in destination warehouse
+	var whProduct entity.WarehouseProduct
+	err = tx.QueryRowContext(ctx, queryGetProductDetails, movement.ProductID).Scan(
+		&whProduct.ProductSKU,
+		&whProduct.ProductImageURL,
+		&whProduct.ProductDescription,
+		&whProduct.ProductPrice,
+		&whProduct.ProductCategoryID,
+	)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("product %s is not known by any warehouse: %w", movement.ProductID, entity.ErrInvalidInboundReceipt)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get product details: %w", err)
+	}
+
+	if err = whProduct.GenerateWarehouseProductID(); err != nil {
+		return fmt.Errorf("failed to generate warehouse product id: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, queryInsertDestProduct,
+		whProduct.ID,
+		movement.ToWarehouseID,
+		movement.ProductID,
+		whProduct.ProductSKU,
+		movement.ProductName,
+		whProduct.ProductImageURL,
+		whProduct.ProductDescription,
+		whProduct.ProductPrice,
+		movement.Quantity,
+		whProduct.ProductCategoryID,
+		movement.CreatedAt,
+		movement.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert destination product: %w", err)
+	}
+
+	return nil
+}
+
+const queryCloseInboundReceipt = `UPDATE inbound_receipts SET status = $1, updated_at = $2 WHERE id = $3 AND status <> 'closed';`
+
+// close the inbound receipt, quantity not received until now stays as short quantity of the lines
+func (r *InboundReceiptPostgreRepo) Close(ctx context.Context, inboundReceipt *entity.InboundReceipt) error {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryCloseInboundReceipt)
+	if errStmt != nil {
+		return errStmt
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, entity.InboundReceiptStatusClosed, inboundReceipt.UpdatedAt, inboundReceipt.ID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("inbound receipt not found or already closed: %w", entity.ErrInvalidInboundReceipt)
+	}
+
+	inboundReceipt.Status = entity.InboundReceiptStatusClosed
+	return nil
+}
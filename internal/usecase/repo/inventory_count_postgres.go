@@ -0,0 +1,324 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/pkg/postgresql"
+)
+
+type InventoryCountPostgreRepo struct {
+	*postgresql.Postgres
+}
+
+func NewInventoryCountPostgreRepo(client *postgresql.Postgres) *InventoryCountPostgreRepo {
+	return &InventoryCountPostgreRepo{
+		client,
+	}
+}
+
+const (
+	queryInsertInventoryCount = `
+		INSERT INTO inventory_counts (id, warehouse_id, status, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	queryInsertInventoryCountLine = `
+		INSERT INTO inventory_count_lines (id, inventory_count_id, product_id, product_name, expected_quantity)
+		VALUES ($1, $2, $3, $4, $5)`
+)
+
+// save inventory count with its snapshotted lines
+func (r *InventoryCountPostgreRepo) Save(ctx context.Context, inventoryCount *entity.InventoryCount) error {
+	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, queryInsertInventoryCount,
+		inventoryCount.ID,
+		inventoryCount.WarehouseID,
+		inventoryCount.Status,
+		inventoryCount.CreatedBy,
+		inventoryCount.CreatedAt,
+		inventoryCount.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert inventory count: %w", err)
+	}
+
+	for _, line := range inventoryCount.Lines {
+		_, err = tx.ExecContext(ctx, queryInsertInventoryCountLine,
+			line.ID,
+			line.InventoryCountID,
+			line.ProductID,
+			line.ProductName,
+			line.ExpectedQuantity,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert inventory count line: %w", err)
+		}
+	}
+
+	if errCommit := tx.Commit(); errCommit != nil {
+		return fmt.Errorf("failed to commit transaction: %w", errCommit)
+	}
+
+	return nil
+}
+
+const (
+	queryGetInventoryCountByID = `
+		SELECT id, warehouse_id, status, created_by, posted_by, created_at, updated_at
+		FROM inventory_counts
+		WHERE id = $1;`
+
+	// ordered by product id, so posting locks the product rows in a consistent order
+	queryGetInventoryCountLinesByInventoryCountID = `
+		SELECT id, inventory_count_id, product_id, product_name, expected_quantity, counted_quantity
+		FROM inventory_count_lines
+		WHERE inventory_count_id = $1
+		ORDER BY product_id;`
+)
+
+func (r *InventoryCountPostgreRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.InventoryCount, error) {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetInventoryCountByID)
+	if errStmt != nil {
+		return nil, errStmt
+	}
+	defer stmt.Close()
+
+	var inventoryCount entity.InventoryCount
+	err := stmt.QueryRowContext(ctx, id).Scan(
+		&inventoryCount.ID,
+		&inventoryCount.WarehouseID,
+		&inventoryCount.Status,
+		&inventoryCount.CreatedBy,
+		&inventoryCount.PostedBy,
+		&inventoryCount.CreatedAt,
+		&inventoryCount.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stmtLines, errStmt := r.Conn.PrepareContext(ctx, queryGetInventoryCountLinesByInventoryCountID)
+	if errStmt != nil {
+		return nil, errStmt
+	}
+	defer stmtLines.Close()
+
+	rows, err := stmtLines.QueryContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	inventoryCount.Lines, err = scanInventoryCountLines(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inventoryCount, nil
+}
+
+func scanInventoryCountLines(rows *sql.Rows) ([]*entity.InventoryCountLine, error) {
+	var lines []*entity.InventoryCountLine
+	for rows.Next() {
+		var line entity.InventoryCountLine
+		if err := rows.Scan(
+			&line.ID,
+			&line.InventoryCountID,
+			&line.ProductID,
+			&line.ProductName,
+			&line.ExpectedQuantity,
+			&line.CountedQuantity,
+		); err != nil {
+			return nil, err
+		}
+		lines = append(lines, &line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+const (
+	queryLockInventoryCount = `
+		SELECT warehouse_id, status
+		FROM inventory_counts
+		WHERE id = $1
+		FOR UPDATE`
+
+	queryUpdateInventoryCountLineCountedQuantity = `
+		UPDATE inventory_count_lines
+		SET counted_quantity = $1
+		WHERE inventory_count_id = $2
+		AND product_id = $3`
+
+	queryUpdateInventoryCountUpdatedAt = `UPDATE inventory_counts SET updated_at = $1 WHERE id = $2`
+)
+
+// lock the inventory count row, only open inventory count can be changed
+func lockOpenInventoryCount(ctx context.Context, tx *sql.Tx, inventoryCount *entity.InventoryCount) error {
+	err := tx.QueryRowContext(ctx, queryLockInventoryCount, inventoryCount.ID).Scan(
+		&inventoryCount.WarehouseID,
+		&inventoryCount.Status,
+	)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("inventory count not found: %w", entity.ErrInvalidInventoryCount)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock inventory count: %w", err)
+	}
+	if !inventoryCount.IsOpen() {
+		return fmt.Errorf("inventory count is %s: %w", inventoryCount.Status, entity.ErrInvalidInventoryCount)
+	}
+
+	return nil
+}
+
+// save counted quantity of the submitted lines, counting a product again overwrites the previous count
+func (r *InventoryCountPostgreRepo) UpdateCountedQuantities(ctx context.Context, inventoryCount *entity.InventoryCount) error {
+	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. lock inventory count
+	if err = lockOpenInventoryCount(ctx, tx, inventoryCount); err != nil {
+		return err
+	}
+
+	// 2. update counted quantity of each line
+	for _, line := range inventoryCount.Lines {
+		res, err := tx.ExecContext(ctx, queryUpdateInventoryCountLineCountedQuantity,
+			line.CountedQuantity, inventoryCount.ID, line.ProductID)
+		if err != nil {
+			return fmt.Errorf("failed to update counted quantity: %w", err)
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("product %s is not in the inventory count: %w", line.ProductID, entity.ErrInvalidInventoryCount)
+		}
+	}
+
+	// 3. update inventory count
+	if _, err = tx.ExecContext(ctx, queryUpdateInventoryCountUpdatedAt, inventoryCount.UpdatedAt, inventoryCount.ID); err != nil {
+		return fmt.Errorf("failed to update inventory count: %w", err)
+	}
+
+	if errCommit := tx.Commit(); errCommit != nil {
+		return fmt.Errorf("failed to commit transaction: %w", errCommit)
+	}
+
+	return nil
+}
+
+const queryUpdatePostedInventoryCount = `
+	UPDATE inventory_counts
+	SET status = $1,
+	    posted_by = $2,
+	    updated_at = $3
+	WHERE id = $4`
+
+// post the inventory count, every variance is adjusted in the same transaction
+// so the session is either fully posted or not at all
+func (r *InventoryCountPostgreRepo) Post(ctx context.Context, inventoryCount *entity.InventoryCount) error {
+	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. lock inventory count
+	if err = lockOpenInventoryCount(ctx, tx, inventoryCount); err != nil {
+		return err
+	}
+
+	// 2. get lines with their counted quantity
+	rows, err := tx.QueryContext(ctx, queryGetInventoryCountLinesByInventoryCountID, inventoryCount.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get inventory count lines: %w", err)
+	}
+	inventoryCount.Lines, err = scanInventoryCountLines(rows)
+	rows.Close()
+	if err != nil {
+		return fmt.Errorf("failed to get inventory count lines: %w", err)
+	}
+
+	// 3. adjust product quantity of each variance
+	adjustments := inventoryCount.ToStockAdjustments(inventoryCount.PostedBy, inventoryCount.UpdatedAt)
+	var productIDs []uuid.UUID
+	for _, adjustment := range adjustments {
+		if err = adjustProductQuantity(ctx, tx, adjustment); err != nil {
+			return fmt.Errorf("failed to adjust product %s: %w", adjustment.ProductID, err)
+		}
+		productIDs = append(productIDs, adjustment.ProductID)
+	}
+
+	// 4. update inventory count status
+	_, err = tx.ExecContext(ctx, queryUpdatePostedInventoryCount,
+		entity.InventoryCountStatusPosted, inventoryCount.PostedBy, inventoryCount.UpdatedAt, inventoryCount.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update inventory count status: %w", err)
+	}
+
+	// 5. save product quantity updated events to outbox
+	err = insertProductQuantityUpdatedEvents(ctx, tx, productIDs, inventoryCount.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	// commit transaction
+	if errCommit := tx.Commit(); errCommit != nil {
+		return fmt.Errorf("failed to commit transaction: %w", errCommit)
+	}
+
+	inventoryCount.Status = entity.InventoryCountStatusPosted
+	inventoryCount.Adjustments = adjustments
+	return nil
+}
+
+const queryCancelInventoryCount = `UPDATE inventory_counts SET status = $1, updated_at = $2 WHERE id = $3 AND status = 'open';`
+
+// cancel the inventory count which is still open, nothing is adjusted
+func (r *InventoryCountPostgreRepo) Cancel(ctx context.Context, inventoryCount *entity.InventoryCount) error {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryCancelInventoryCount)
+	if errStmt != nil {
+		return errStmt
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, entity.InventoryCountStatusCancelled, inventoryCount.UpdatedAt, inventoryCount.ID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("inventory count is not open: %w", entity.ErrInvalidInventoryCount)
+	}
+
+	inventoryCount.Status = entity.InventoryCountStatusCancelled
+	return nil
+}
@@ -0,0 +1,217 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/pkg/kafka"
+	"github.com/idoyudha/eshop-warehouse/pkg/postgresql"
+)
+
+type OutboxEventPostgreRepo struct {
+	*postgresql.Postgres
+}
+
+func NewOutboxEventPostgreRepo(client *postgresql.Postgres) *OutboxEventPostgreRepo {
+	return &OutboxEventPostgreRepo{
+		client,
+	}
+}
+
+// seq is left to its sequence default, so events are ordered by the time they are inserted
+const queryInsertOutboxEvent = `
+	INSERT INTO outbox_events (id, topic, key, payload, status, attempts, next_attempt_at, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+// save outbox event within the transaction of the stock change
+func insertOutboxEvent(ctx context.Context, tx *sql.Tx, topic string, key string, message interface{}, createdAt time.Time) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox event payload: %w", err)
+	}
+
+	outboxEvent := entity.OutboxEvent{
+		Topic:         topic,
+		Key:           key,
+		Payload:       payload,
+		Status:        entity.OutboxEventStatusPending,
+		NextAttemptAt: createdAt,
+		CreatedAt:     createdAt,
+	}
+	if err := outboxEvent.GenerateOutboxEventID(); err != nil {
+		return fmt.Errorf("failed to generate outbox event id: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, queryInsertOutboxEvent,
+		outboxEvent.ID,
+		outboxEvent.Topic,
+		outboxEvent.Key,
+		string(outboxEvent.Payload),
+		outboxEvent.Status,
+		outboxEvent.Attempts,
+		outboxEvent.NextAttemptAt,
+		outboxEvent.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert outbox event: %w", err)
+	}
+
+	return nil
+}
+
+type productQuantityUpdatedMessage struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Quantity  int       `json:"quantity"`
+}
+
+// save product-quantity-updated event with the total quantity seen by the transaction,
+// call it after all quantity updates of the product in the transaction
+func insertProductQuantityUpdatedEvents(ctx context.Context, tx *sql.Tx, productIDs []uuid.UUID, createdAt time.Time) error {
+	saved := make(map[uuid.UUID]bool, len(productIDs))
+	for _, productID := range productIDs {
+		if saved[productID] {
+			continue
+		}
+		saved[productID] = true
+
+		var totalQuantity int
+		if err := tx.QueryRowContext(ctx, queryGetTotalQuantityOfProductInAllWarehouse, productID).Scan(&totalQuantity); err != nil {
+			return fmt.Errorf("failed to get total quantity of product in all warehouse: %w", err)
+		}
+
+		message := productQuantityUpdatedMessage{
+			ProductID: productID,
+			Quantity:  totalQuantity,
+		}
+		if err := insertOutboxEvent(ctx, tx, kafka.ProductQuantityUpdatedTopic, productID.String(), message, createdAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// an event waits while an older event of the same topic and key is pending, so the key is published in order.
+// The order is the seq taken at insert, created_at comes from the app clock before the stock rows are locked.
+// Rows locked by another relay are skipped instead of published twice
+const queryGetPendingOutboxEvents = `
+	SELECT id, topic, key, payload, status, attempts, COALESCE(last_error, ''), next_attempt_at, created_at
+	FROM outbox_events
+	WHERE status = 'pending' AND next_attempt_at <= $1
+	AND NOT EXISTS (
+		SELECT 1 FROM outbox_events older
+		WHERE older.topic = outbox_events.topic
+		AND older.key = outbox_events.key
+		AND older.status = 'pending'
+		AND older.seq < outbox_events.seq
+	)
+	ORDER BY seq ASC
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED;`
+
+const queryClaimOutboxEvent = `UPDATE outbox_events SET next_attempt_at = $1 WHERE id = $2;`
+
+// claimed events are not due for other relays until the claim expires, e.g. the relay crashed while publishing
+const outboxClaimTimeout = time.Minute
+
+// get pending events which are due to be published, oldest first, and claim them for this relay
+func (r *OutboxEventPostgreRepo) GetPending(ctx context.Context, now time.Time, limit int) ([]*entity.OutboxEvent, error) {
+	// begin transaction
+	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. lock due events which are not locked by another relay
+	rows, err := tx.QueryContext(ctx, queryGetPendingOutboxEvents, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending outbox events: %w", err)
+	}
+
+	var outboxEvents []*entity.OutboxEvent
+	for rows.Next() {
+		var outboxEvent entity.OutboxEvent
+		if err := rows.Scan(
+			&outboxEvent.ID,
+			&outboxEvent.Topic,
+			&outboxEvent.Key,
+			&outboxEvent.Payload,
+			&outboxEvent.Status,
+			&outboxEvent.Attempts,
+			&outboxEvent.LastError,
+			&outboxEvent.NextAttemptAt,
+			&outboxEvent.CreatedAt,
+		); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		outboxEvents = append(outboxEvents, &outboxEvent)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get pending outbox events: %w", err)
+	}
+
+	// 2. claim the events, the lock is released on commit
+	for _, outboxEvent := range outboxEvents {
+		if _, err = tx.ExecContext(ctx, queryClaimOutboxEvent, now.Add(outboxClaimTimeout), outboxEvent.ID); err != nil {
+			return nil, fmt.Errorf("failed to claim outbox event: %w", err)
+		}
+	}
+
+	// commit transaction
+	if errCommit := tx.Commit(); errCommit != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", errCommit)
+	}
+
+	return outboxEvents, nil
+}
+
+const queryUpdateOutboxEventSent = `UPDATE outbox_events SET status = 'sent', attempts = $1, sent_at = $2 WHERE id = $3;`
+
+func (r *OutboxEventPostgreRepo) MarkSent(ctx context.Context, outboxEvent *entity.OutboxEvent) error {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryUpdateOutboxEventSent)
+	if errStmt != nil {
+		return errStmt
+	}
+	defer stmt.Close()
+
+	_, err := stmt.ExecContext(ctx, outboxEvent.Attempts, outboxEvent.SentAt, outboxEvent.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const queryUpdateOutboxEventAttempt = `UPDATE outbox_events SET status = $1, attempts = $2, last_error = $3, next_attempt_at = $4 WHERE id = $5;`
+
+// save failed publish attempt, the event stays pending until it reaches the max attempts
+func (r *OutboxEventPostgreRepo) MarkFailed(ctx context.Context, outboxEvent *entity.OutboxEvent) error {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryUpdateOutboxEventAttempt)
+	if errStmt != nil {
+		return errStmt
+	}
+	defer stmt.Close()
+
+	_, err := stmt.ExecContext(ctx,
+		outboxEvent.Status,
+		outboxEvent.Attempts,
+		outboxEvent.LastError,
+		outboxEvent.NextAttemptAt,
+		outboxEvent.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the app clock of a later event can be older, e.g. it was read before waiting for the stock row lock
+func TestGetPendingOutboxEventsKeyOrder(t *testing.T) {
+	client := newTestPostgres(t)
+	repo := NewOutboxEventPostgreRepo(client)
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, event := range []struct {
+		quantity  int
+		createdAt time.Time
+	}{
+		{quantity: 10, createdAt: now.Add(-time.Second)},
+		{quantity: 7, createdAt: now.Add(-2 * time.Second)},
+	} {
+		tx, err := client.Conn.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		require.NoError(t, insertOutboxEvent(ctx, tx, "product-quantity-updated", "product-1", productQuantityUpdatedMessage{Quantity: event.quantity}, event.createdAt))
+		require.NoError(t, tx.Commit())
+	}
+
+	events, err := repo.GetPending(ctx, now, 10)
+	require.NoError(t, err)
+	// the later event waits for the first one of the key
+	require.Len(t, events, 1)
+	assert.JSONEq(t, `{"product_id": "00000000-0000-0000-0000-000000000000", "quantity": 10}`, string(events[0].Payload))
+}
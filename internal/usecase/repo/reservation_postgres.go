@@ -0,0 +1,249 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/pkg/postgresql"
+)
+
+type ReservationPostgreRepo struct {
+	*postgresql.Postgres
+}
+
+func NewReservationPostgreRepo(client *postgresql.Postgres) *ReservationPostgreRepo {
+	return &ReservationPostgreRepo{
+		client,
+	}
+}
+
+// quantity that is held by active reservations, grouped per warehouse and product
+const queryHeldQuantity = `
+	SELECT reservation_items.warehouse_id, reservation_items.product_id, SUM(reservation_items.quantity) AS held_quantity
+	FROM reservation_items
+	JOIN reservations
+	ON reservation_items.reservation_id = reservations.id
+	WHERE reservations.status = 'active' AND reservations.expires_at > now()
+	GROUP BY reservation_items.warehouse_id, reservation_items.product_id`
+
+const (
+	queryLockProductQuantity = `
+		SELECT product_quantity
+		FROM warehouse_products
+		WHERE product_id = $1
+		AND warehouse_id = $2
+		AND deleted_at IS NULL
+		FOR UPDATE`
+
+	queryGetHeldQuantityByProductIDAndWarehouseID = `
+		SELECT COALESCE(SUM(reservation_items.quantity), 0)
+		FROM reservation_items
+		JOIN reservations
+		ON reservation_items.reservation_id = reservations.id
+		WHERE reservation_items.product_id = $1
+		AND reservation_items.warehouse_id = $2
+		AND reservations.status = 'active'
+		AND reservations.expires_at > now()`
+
+	// expires_at is set by the database clock, the same clock that decides whether it is still holding
+	queryInsertReservation = `
+		INSERT INTO reservations (id, user_id, zip_code, status, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, now() + make_interval(secs => $5), $6, $7)
+		RETURNING expires_at`
+
+	queryInsertReservationItem = `
+		INSERT INTO reservation_items (id, reservation_id, warehouse_id, product_id, product_name, quantity)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+)
+
+// save reservation with its items, the warehouse product rows are locked
+// so the same quantity can not be held twice by concurrent reservations
+func (r *ReservationPostgreRepo) Save(ctx context.Context, reservation *entity.Reservation, ttl time.Duration) error {
+	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// lock in a consistent order to avoid deadlocks between reservations
+	items := make([]*entity.ReservationItem, len(reservation.Items))
+	copy(items, reservation.Items)
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].WarehouseID != items[j].WarehouseID {
+			return items[i].WarehouseID.String() < items[j].WarehouseID.String()
+		}
+		return items[i].ProductID.String() < items[j].ProductID.String()
+	})
+
+	for _, item := range items {
+		var productQuantity int64
+		if err := tx.QueryRowContext(ctx, queryLockProductQuantity,
+			item.ProductID, item.WarehouseID,
+		).Scan(&productQuantity); err != nil {
+			return fmt.Errorf("failed to lock warehouse product: %w", err)
+		}
+
+		var heldQuantity int64
+		if err := tx.QueryRowContext(ctx, queryGetHeldQuantityByProductIDAndWarehouseID,
+			item.ProductID, item.WarehouseID,
+		).Scan(&heldQuantity); err != nil {
+			return fmt.Errorf("failed to get held quantity: %w", err)
+		}
+
+		if productQuantity-heldQuantity < item.Quantity {
+			return &entity.InsufficientStockError{Items: []entity.InsufficientStockItem{{
+				ProductID: item.ProductID,
+				Requested: item.Quantity,
+				Available: productQuantity - heldQuantity,
+			}}}
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, queryInsertReservation,
+		reservation.ID,
+		reservation.UserID,
+		reservation.ZipCode,
+		reservation.Status,
+		ttl.Seconds(),
+		reservation.CreatedAt,
+		reservation.UpdatedAt,
+	).Scan(&reservation.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert reservation: %w", err)
+	}
+
+	for _, item := range reservation.Items {
+		_, err = tx.ExecContext(ctx, queryInsertReservationItem,
+			item.ID,
+			item.ReservationID,
+			item.WarehouseID,
+			item.ProductID,
+			item.ProductName,
+			item.Quantity,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert reservation item: %w", err)
+		}
+	}
+
+	if errCommit := tx.Commit(); errCommit != nil {
+		return fmt.Errorf("failed to commit transaction: %w", errCommit)
+	}
+
+	return nil
+}
+
+const (
+	queryGetReservationByID = `
+		SELECT id, user_id, zip_code, status, expires_at, created_at, updated_at
+		FROM reservations
+		WHERE id = $1;`
+
+	queryGetReservationItemsByReservationID = `
+		SELECT id, reservation_id, warehouse_id, product_id, product_name, quantity
+		FROM reservation_items
+		WHERE reservation_id = $1;`
+)
+
+func (r *ReservationPostgreRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.Reservation, error) {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetReservationByID)
+	if errStmt != nil {
+		return nil, errStmt
+	}
+	defer stmt.Close()
+
+	var reservation entity.Reservation
+	err := stmt.QueryRowContext(ctx, id).Scan(
+		&reservation.ID,
+		&reservation.UserID,
+		&reservation.ZipCode,
+		&reservation.Status,
+		&reservation.ExpiresAt,
+		&reservation.CreatedAt,
+		&reservation.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stmtItems, errStmt := r.Conn.PrepareContext(ctx, queryGetReservationItemsByReservationID)
+	if errStmt != nil {
+		return nil, errStmt
+	}
+	defer stmtItems.Close()
+
+	rows, err := stmtItems.QueryContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.ReservationItem
+		if err := rows.Scan(
+			&item.ID,
+			&item.ReservationID,
+			&item.WarehouseID,
+			&item.ProductID,
+			&item.ProductName,
+			&item.Quantity,
+		); err != nil {
+			return nil, err
+		}
+		reservation.Items = append(reservation.Items, &item)
+	}
+
+	return &reservation, nil
+}
+
+const queryUpdateActiveReservationStatus = `UPDATE reservations SET status = $1, updated_at = $2 WHERE id = $3 AND status = 'active';`
+
+// update status of reservation which is still active
+func (r *ReservationPostgreRepo) UpdateStatus(ctx context.Context, reservation *entity.Reservation) error {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryUpdateActiveReservationStatus)
+	if errStmt != nil {
+		return errStmt
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, reservation.Status, reservation.UpdatedAt, reservation.ID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("reservation %s: %w", reservation.ID, entity.ErrReservationNotHolding)
+	}
+
+	return nil
+}
+
+// expiry is compared with the database clock, the same clock that decides which reservations are holding quantity
+const queryExpireReservations = `UPDATE reservations SET status = 'expired', updated_at = $1 WHERE status = 'active' AND expires_at <= now();`
+
+// mark all active reservations which passed their expiry time as expired
+func (r *ReservationPostgreRepo) ExpireAll(ctx context.Context, now time.Time) (int64, error) {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryExpireReservations)
+	if errStmt != nil {
+		return 0, errStmt
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
@@ -0,0 +1,159 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/pkg/postgresql"
+)
+
+type StockHistoryPostgreRepo struct {
+	*postgresql.Postgres
+}
+
+func NewStockHistoryPostgreRepo(client *postgresql.Postgres) *StockHistoryPostgreRepo {
+	return &StockHistoryPostgreRepo{
+		client,
+	}
+}
+
+// movements into a warehouse add to its product quantity and movements out of it subtract from it,
+// except quarantined returns which are kept apart from the product quantity
+const queryIsQuarantinedReturn = `stock_movements.movement_type = 'return' AND COALESCE(stock_movements.disposition, '') = 'quarantine'`
+
+const (
+	queryGetOpeningBalance = `
+		SELECT quantity, as_of
+		FROM stock_opening_balances
+		WHERE warehouse_id = $1 AND product_id = $2;`
+
+	// movements after the opening balance until the given time
+	queryGetLedgerQuantity = `
+		SELECT COALESCE(SUM(CASE WHEN stock_movements.to_warehouse_id = $1 AND NOT (` + queryIsQuarantinedReturn + `)
+				THEN stock_movements.quantity ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN stock_movements.from_warehouse_id = $1
+				THEN stock_movements.quantity ELSE 0 END), 0)
+		FROM stock_movements
+		WHERE stock_movements.product_id = $2
+		AND (stock_movements.from_warehouse_id = $1 OR stock_movements.to_warehouse_id = $1)
+		AND stock_movements.created_at > $3
+		AND stock_movements.created_at <= $4;`
+)
+
+// reconstruct the on-hand quantity of the product in the warehouse at the given time
+func (r *StockHistoryPostgreRepo) GetOnHand(ctx context.Context, warehouseID, productID uuid.UUID, at time.Time) (*entity.StockHistory, error) {
+	// both reads see the same snapshot, so a movement between them is not counted twice
+	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stockHistory := &entity.StockHistory{
+		WarehouseID: warehouseID,
+		ProductID:   productID,
+		At:          at,
+	}
+
+	// 1. get opening balance, without it the ledger starts from the first movement
+	var from time.Time
+	var openingBalance entity.OpeningBalance
+	err = tx.QueryRowContext(ctx, queryGetOpeningBalance, warehouseID, productID).Scan(
+		&openingBalance.Quantity,
+		&openingBalance.AsOf,
+	)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get opening balance: %w", err)
+	}
+	if err == nil {
+		openingBalance.WarehouseID = warehouseID
+		openingBalance.ProductID = productID
+		stockHistory.OpeningBalance = &openingBalance
+		from = openingBalance.AsOf
+	}
+	if err = stockHistory.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 2. sum movements after the opening balance
+	err = tx.QueryRowContext(ctx, queryGetLedgerQuantity, warehouseID, productID, from, at).Scan(
+		&stockHistory.InboundQuantity,
+		&stockHistory.OutboundQuantity,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger quantity: %w", err)
+	}
+
+	stockHistory.CalculateQuantity()
+	return stockHistory, nil
+}
+
+// quantity of every warehouse product explained by its opening balance and the movements after it
+const queryGetStockDiscrepancies = `
+	SELECT warehouse_products.warehouse_id,
+		warehouse_products.product_id,
+		warehouse_products.product_name,
+		warehouse_products.product_quantity,
+		COALESCE(stock_opening_balances.quantity, 0) + COALESCE(ledger.quantity, 0) AS ledger_quantity
+	FROM warehouse_products
+	LEFT JOIN stock_opening_balances
+	ON warehouse_products.warehouse_id = stock_opening_balances.warehouse_id
+	AND warehouse_products.product_id = stock_opening_balances.product_id
+	LEFT JOIN LATERAL (
+		SELECT SUM(CASE
+			WHEN stock_movements.to_warehouse_id = warehouse_products.warehouse_id AND NOT (` + queryIsQuarantinedReturn + `)
+			THEN stock_movements.quantity
+			WHEN stock_movements.from_warehouse_id = warehouse_products.warehouse_id
+			THEN -stock_movements.quantity
+			ELSE 0 END) AS quantity
+		FROM stock_movements
+		WHERE stock_movements.product_id = warehouse_products.product_id
+		AND (stock_movements.from_warehouse_id = warehouse_products.warehouse_id
+			OR stock_movements.to_warehouse_id = warehouse_products.warehouse_id)
+		AND stock_movements.created_at > COALESCE(stock_opening_balances.as_of, '-infinity'::timestamp)
+	) ledger ON true
+	WHERE warehouse_products.deleted_at IS NULL
+	AND warehouse_products.product_quantity <> COALESCE(stock_opening_balances.quantity, 0) + COALESCE(ledger.quantity, 0)
+	ORDER BY warehouse_products.warehouse_id, warehouse_products.product_id;`
+
+// warehouse products whose current quantity disagrees with their ledger
+func (r *StockHistoryPostgreRepo) GetDiscrepancies(ctx context.Context) ([]*entity.StockDiscrepancy, error) {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetStockDiscrepancies)
+	if errStmt != nil {
+		return nil, errStmt
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var discrepancies []*entity.StockDiscrepancy
+	for rows.Next() {
+		var discrepancy entity.StockDiscrepancy
+		if err := rows.Scan(
+			&discrepancy.WarehouseID,
+			&discrepancy.ProductID,
+			&discrepancy.ProductName,
+			&discrepancy.Quantity,
+			&discrepancy.LedgerQuantity,
+		); err != nil {
+			return nil, err
+		}
+		discrepancies = append(discrepancies, &discrepancy)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return discrepancies, nil
+}
@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/idoyudha/eshop-warehouse/internal/entity"
@@ -18,9 +19,19 @@ func NewStockMovementPostgreRepo(client *postgresql.Postgres) *StockMovementPost
 	}
 }
 
-const queryGetAllStockMovements = `SELECT * FROM stock_movements;`
+// idempotency key, reason, reference id and created by are null for movements created without them
+const stockMovementColumns = `
+	id, product_id, product_name, quantity, from_warehouse_id, to_warehouse_id, to_user_id, from_user_id,
+	movement_type, original_movement_id, COALESCE(disposition, ''), COALESCE(reason, ''), COALESCE(reference_id, ''),
+	created_by, COALESCE(idempotency_key, ''), created_at`
 
-func (r *StockMovementPostgreRepo) GetAll(ctx context.Context) ([]*entity.StockMovement, error) {
+// an empty movement type or reference id matches every movement
+const queryGetAllStockMovements = `
+	SELECT ` + stockMovementColumns + ` FROM stock_movements
+	WHERE ($1::varchar = '' OR movement_type = $1)
+	AND ($2::varchar = '' OR reference_id = $2);`
+
+func (r *StockMovementPostgreRepo) GetAll(ctx context.Context, filter entity.StockMovementFilter) ([]*entity.StockMovement, error) {
 	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetAllStockMovements)
 	if errStmt != nil {
 		return nil, errStmt
@@ -28,7 +39,7 @@ func (r *StockMovementPostgreRepo) GetAll(ctx context.Context) ([]*entity.StockM
 	defer stmt.Close()
 
 	var stockMovements []*entity.StockMovement
-	rows, err := stmt.QueryContext(ctx)
+	rows, err := stmt.QueryContext(ctx, filter.MovementType, filter.ReferenceID)
 	if err != nil {
 		return nil, err
 	}
@@ -44,6 +55,14 @@ func (r *StockMovementPostgreRepo) GetAll(ctx context.Context) ([]*entity.StockM
 			&stockMovement.FromWarehouseID,
 			&stockMovement.ToWarehouseID,
 			&stockMovement.ToUserID,
+			&stockMovement.FromUserID,
+			&stockMovement.MovementType,
+			&stockMovement.OriginalMovementID,
+			&stockMovement.Disposition,
+			&stockMovement.Reason,
+			&stockMovement.ReferenceID,
+			&stockMovement.CreatedBy,
+			&stockMovement.IdempotencyKey,
 			&stockMovement.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -54,9 +73,13 @@ func (r *StockMovementPostgreRepo) GetAll(ctx context.Context) ([]*entity.StockM
 	return stockMovements, nil
 }
 
-const queryGetByProductID = `SELECT * FROM stock_movements WHERE product_id = $1;`
+const queryGetByProductID = `
+	SELECT ` + stockMovementColumns + ` FROM stock_movements
+	WHERE product_id = $1
+	AND ($2::varchar = '' OR movement_type = $2)
+	AND ($3::varchar = '' OR reference_id = $3);`
 
-func (r *StockMovementPostgreRepo) GetByProductID(ctx context.Context, productID uuid.UUID) ([]*entity.StockMovement, error) {
+func (r *StockMovementPostgreRepo) GetByProductID(ctx context.Context, productID uuid.UUID, filter entity.StockMovementFilter) ([]*entity.StockMovement, error) {
 	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetByProductID)
 	if errStmt != nil {
 		return nil, errStmt
@@ -64,7 +87,7 @@ func (r *StockMovementPostgreRepo) GetByProductID(ctx context.Context, productID
 	defer stmt.Close()
 
 	var stockMovements []*entity.StockMovement
-	rows, err := stmt.QueryContext(ctx, productID)
+	rows, err := stmt.QueryContext(ctx, productID, filter.MovementType, filter.ReferenceID)
 	if err != nil {
 		return nil, err
 	}
@@ -80,6 +103,14 @@ func (r *StockMovementPostgreRepo) GetByProductID(ctx context.Context, productID
 			&stockMovement.FromWarehouseID,
 			&stockMovement.ToWarehouseID,
 			&stockMovement.ToUserID,
+			&stockMovement.FromUserID,
+			&stockMovement.MovementType,
+			&stockMovement.OriginalMovementID,
+			&stockMovement.Disposition,
+			&stockMovement.Reason,
+			&stockMovement.ReferenceID,
+			&stockMovement.CreatedBy,
+			&stockMovement.IdempotencyKey,
 			&stockMovement.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -90,9 +121,13 @@ func (r *StockMovementPostgreRepo) GetByProductID(ctx context.Context, productID
 	return stockMovements, nil
 }
 
-const queryGetBySourceID = `SELECT * FROM stock_movements WHERE from_warehouse_id = $1;`
+const queryGetBySourceID = `
+	SELECT ` + stockMovementColumns + ` FROM stock_movements
+	WHERE from_warehouse_id = $1
+	AND ($2::varchar = '' OR movement_type = $2)
+	AND ($3::varchar = '' OR reference_id = $3);`
 
-func (r *StockMovementPostgreRepo) GetBySourceID(ctx context.Context, sourceID uuid.UUID) ([]*entity.StockMovement, error) {
+func (r *StockMovementPostgreRepo) GetBySourceID(ctx context.Context, sourceID uuid.UUID, filter entity.StockMovementFilter) ([]*entity.StockMovement, error) {
 	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetBySourceID)
 	if errStmt != nil {
 		return nil, errStmt
@@ -100,7 +135,7 @@ func (r *StockMovementPostgreRepo) GetBySourceID(ctx context.Context, sourceID u
 	defer stmt.Close()
 
 	var stockMovements []*entity.StockMovement
-	rows, err := stmt.QueryContext(ctx, sourceID)
+	rows, err := stmt.QueryContext(ctx, sourceID, filter.MovementType, filter.ReferenceID)
 	if err != nil {
 		return nil, err
 	}
@@ -116,6 +151,14 @@ func (r *StockMovementPostgreRepo) GetBySourceID(ctx context.Context, sourceID u
 			&stockMovement.FromWarehouseID,
 			&stockMovement.ToWarehouseID,
 			&stockMovement.ToUserID,
+			&stockMovement.FromUserID,
+			&stockMovement.MovementType,
+			&stockMovement.OriginalMovementID,
+			&stockMovement.Disposition,
+			&stockMovement.Reason,
+			&stockMovement.ReferenceID,
+			&stockMovement.CreatedBy,
+			&stockMovement.IdempotencyKey,
 			&stockMovement.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -126,9 +169,13 @@ func (r *StockMovementPostgreRepo) GetBySourceID(ctx context.Context, sourceID u
 	return stockMovements, nil
 }
 
-const queryGetByDestinationID = `SELECT * FROM stock_movements WHERE to_warehouse_id = $1;`
+const queryGetByDestinationID = `
+	SELECT ` + stockMovementColumns + ` FROM stock_movements
+	WHERE to_warehouse_id = $1
+	AND ($2::varchar = '' OR movement_type = $2)
+	AND ($3::varchar = '' OR reference_id = $3);`
 
-func (r *StockMovementPostgreRepo) GetByDestinationID(ctx context.Context, destinationID uuid.UUID) ([]*entity.StockMovement, error) {
+func (r *StockMovementPostgreRepo) GetByDestinationID(ctx context.Context, destinationID uuid.UUID, filter entity.StockMovementFilter) ([]*entity.StockMovement, error) {
 	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetByDestinationID)
 	if errStmt != nil {
 		return nil, errStmt
@@ -136,7 +183,7 @@ func (r *StockMovementPostgreRepo) GetByDestinationID(ctx context.Context, desti
 	defer stmt.Close()
 
 	var stockMovements []*entity.StockMovement
-	rows, err := stmt.QueryContext(ctx, destinationID)
+	rows, err := stmt.QueryContext(ctx, destinationID, filter.MovementType, filter.ReferenceID)
 	if err != nil {
 		return nil, err
 	}
@@ -152,6 +199,14 @@ func (r *StockMovementPostgreRepo) GetByDestinationID(ctx context.Context, desti
 			&stockMovement.FromWarehouseID,
 			&stockMovement.ToWarehouseID,
 			&stockMovement.ToUserID,
+			&stockMovement.FromUserID,
+			&stockMovement.MovementType,
+			&stockMovement.OriginalMovementID,
+			&stockMovement.Disposition,
+			&stockMovement.Reason,
+			&stockMovement.ReferenceID,
+			&stockMovement.CreatedBy,
+			&stockMovement.IdempotencyKey,
 			&stockMovement.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -161,3 +216,132 @@ func (r *StockMovementPostgreRepo) GetByDestinationID(ctx context.Context, desti
 
 	return stockMovements, nil
 }
+
+// sale movements shipped by each warehouse since the given time,
+// with the available and in-transit quantity of the warehouse
+const queryGetOutboundDemand = `
+	SELECT outbound.warehouse_id,
+		outbound.product_id,
+		outbound.product_name,
+		outbound.outbound_quantity,
+		COALESCE(warehouse_products.product_quantity, 0) - COALESCE(held.held_quantity, 0),
+		COALESCE(transit.in_transit_quantity, 0)
+	FROM (
+		SELECT from_warehouse_id AS warehouse_id, product_id, MAX(product_name) AS product_name, SUM(quantity) AS outbound_quantity
+		FROM stock_movements
+		WHERE movement_type = 'sale' AND from_warehouse_id IS NOT NULL AND created_at >= $1
+		GROUP BY from_warehouse_id, product_id
+	) outbound
+	LEFT JOIN warehouse_products
+	ON outbound.warehouse_id = warehouse_products.warehouse_id
+	AND outbound.product_id = warehouse_products.product_id
+	AND warehouse_products.deleted_at IS NULL
+	LEFT JOIN (` + queryHeldQuantity + `) held
+	ON outbound.warehouse_id = held.warehouse_id AND outbound.product_id = held.product_id
+	LEFT JOIN (` + queryInTransitQuantity + `) transit
+	ON outbound.warehouse_id = transit.to_warehouse_id AND outbound.product_id = transit.product_id
+	ORDER BY outbound.warehouse_id, outbound.product_id;`
+
+func (r *StockMovementPostgreRepo) GetOutboundDemand(ctx context.Context, since time.Time) ([]*entity.WarehouseDemand, error) {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetOutboundDemand)
+	if errStmt != nil {
+		return nil, errStmt
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var demands []*entity.WarehouseDemand
+	for rows.Next() {
+		var demand entity.WarehouseDemand
+		if err := rows.Scan(
+			&demand.WarehouseID,
+			&demand.ProductID,
+			&demand.ProductName,
+			&demand.OutboundQuantity,
+			&demand.AvailableQuantity,
+			&demand.InTransitQuantity,
+		); err != nil {
+			return nil, err
+		}
+		demands = append(demands, &demand)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return demands, nil
+}
+
+// daily sale quantity of each product shipped by each warehouse between the given times,
+// with the available quantity of the warehouse. A nil warehouse or product id matches every warehouse or product.
+const queryGetDemandHistory = `
+	SELECT sales.warehouse_id,
+		sales.product_id,
+		sales.product_name,
+		COALESCE(warehouse_products.product_quantity, 0) - COALESCE(held.held_quantity, 0),
+		sales.day,
+		sales.quantity
+	FROM (
+		SELECT from_warehouse_id AS warehouse_id, product_id, MAX(product_name) AS product_name,
+			date_trunc('day', created_at) AS day, SUM(quantity) AS quantity
+		FROM stock_movements
+		WHERE movement_type = 'sale' AND from_warehouse_id IS NOT NULL
+		AND created_at >= $1 AND created_at < $2
+		AND ($3::uuid = '00000000-0000-0000-0000-000000000000' OR from_warehouse_id = $3)
+		AND ($4::uuid = '00000000-0000-0000-0000-000000000000' OR product_id = $4)
+		GROUP BY from_warehouse_id, product_id, date_trunc('day', created_at)
+	) sales
+	LEFT JOIN warehouse_products
+	ON sales.warehouse_id = warehouse_products.warehouse_id
+	AND sales.product_id = warehouse_products.product_id
+	AND warehouse_products.deleted_at IS NULL
+	LEFT JOIN (` + queryHeldQuantity + `) held
+	ON sales.warehouse_id = held.warehouse_id AND sales.product_id = held.product_id
+	ORDER BY sales.warehouse_id, sales.product_id, sales.day;`
+
+func (r *StockMovementPostgreRepo) GetDemandHistory(ctx context.Context, from, until time.Time, warehouseID, productID uuid.UUID) ([]*entity.DemandHistory, error) {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetDemandHistory)
+	if errStmt != nil {
+		return nil, errStmt
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, from, until, warehouseID, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// rows are ordered, so the days of a product in a warehouse are next to each other
+	var histories []*entity.DemandHistory
+	var current *entity.DemandHistory
+	for rows.Next() {
+		var history entity.DemandHistory
+		var sales entity.DailySales
+		if err := rows.Scan(
+			&history.WarehouseID,
+			&history.ProductID,
+			&history.ProductName,
+			&history.AvailableQuantity,
+			&sales.Day,
+			&sales.Quantity,
+		); err != nil {
+			return nil, err
+		}
+		if current == nil || current.WarehouseID != history.WarehouseID || current.ProductID != history.ProductID {
+			current = &history
+			histories = append(histories, current)
+		}
+		current.Sales = append(current.Sales, &sales)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return histories, nil
+}
@@ -0,0 +1,146 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/pkg/postgresql"
+)
+
+type StockSnapshotPostgreRepo struct {
+	*postgresql.Postgres
+}
+
+func NewStockSnapshotPostgreRepo(client *postgresql.Postgres) *StockSnapshotPostgreRepo {
+	return &StockSnapshotPostgreRepo{
+		client,
+	}
+}
+
+// capturing the same date again replaces its snapshot, so the last capture of the day is kept
+const queryCaptureStockSnapshots = `
+	INSERT INTO stock_snapshots (snapshot_date, warehouse_id, product_id, product_name, quantity, product_price, value, created_at)
+	SELECT $1::date, warehouse_id, product_id, product_name, product_quantity, product_price, product_price * product_quantity, $2
+	FROM warehouse_products
+	WHERE deleted_at IS NULL
+	ON CONFLICT (snapshot_date, warehouse_id, product_id) DO UPDATE
+	SET product_name = EXCLUDED.product_name,
+	    quantity = EXCLUDED.quantity,
+	    product_price = EXCLUDED.product_price,
+	    value = EXCLUDED.value,
+	    created_at = EXCLUDED.created_at;`
+
+func (r *StockSnapshotPostgreRepo) Capture(ctx context.Context, snapshotDate, createdAt time.Time) (int64, error) {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryCaptureStockSnapshots)
+	if errStmt != nil {
+		return 0, errStmt
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, snapshotDate.Format(time.DateOnly), createdAt)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// a nil warehouse or product id matches every warehouse or product
+const queryGetStockTrend = `
+	SELECT snapshot_date, SUM(quantity), SUM(value)
+	FROM stock_snapshots
+	WHERE snapshot_date BETWEEN $1::date AND $2::date
+	AND ($3::uuid = '00000000-0000-0000-0000-000000000000' OR warehouse_id = $3)
+	AND ($4::uuid = '00000000-0000-0000-0000-000000000000' OR product_id = $4)
+	GROUP BY snapshot_date
+	ORDER BY snapshot_date;`
+
+func (r *StockSnapshotPostgreRepo) GetTrend(ctx context.Context, filter entity.StockSnapshotFilter) ([]*entity.StockTrendPoint, error) {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetStockTrend)
+	if errStmt != nil {
+		return nil, errStmt
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx,
+		filter.From.Format(time.DateOnly),
+		filter.To.Format(time.DateOnly),
+		filter.WarehouseID,
+		filter.ProductID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var trend []*entity.StockTrendPoint
+	for rows.Next() {
+		var point entity.StockTrendPoint
+		if err := rows.Scan(
+			&point.SnapshotDate,
+			&point.Quantity,
+			&point.Value,
+		); err != nil {
+			return nil, err
+		}
+		trend = append(trend, &point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return trend, nil
+}
+
+const queryGetStockSnapshotsByDate = `
+	SELECT snapshot_date, warehouse_id, product_id, product_name, quantity, product_price, value, created_at
+	FROM stock_snapshots
+	WHERE snapshot_date = $1::date
+	AND ($2::uuid = '00000000-0000-0000-0000-000000000000' OR warehouse_id = $2)
+	AND ($3::uuid = '00000000-0000-0000-0000-000000000000' OR product_id = $3)
+	ORDER BY warehouse_id, product_id;`
+
+func (r *StockSnapshotPostgreRepo) GetByDate(ctx context.Context, snapshotDate time.Time, warehouseID, productID uuid.UUID) ([]*entity.StockSnapshot, error) {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetStockSnapshotsByDate)
+	if errStmt != nil {
+		return nil, errStmt
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, snapshotDate.Format(time.DateOnly), warehouseID, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []*entity.StockSnapshot
+	for rows.Next() {
+		var snapshot entity.StockSnapshot
+		if err := rows.Scan(
+			&snapshot.SnapshotDate,
+			&snapshot.WarehouseID,
+			&snapshot.ProductID,
+			&snapshot.ProductName,
+			&snapshot.Quantity,
+			&snapshot.ProductPrice,
+			&snapshot.Value,
+			&snapshot.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
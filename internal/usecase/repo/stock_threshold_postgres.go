@@ -0,0 +1,269 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/pkg/kafka"
+	"github.com/idoyudha/eshop-warehouse/pkg/postgresql"
+)
+
+type StockThresholdPostgreRepo struct {
+	*postgresql.Postgres
+}
+
+func NewStockThresholdPostgreRepo(client *postgresql.Postgres) *StockThresholdPostgreRepo {
+	return &StockThresholdPostgreRepo{
+		client,
+	}
+}
+
+// setting the threshold again replaces the reorder point and target level
+const queryUpsertStockThreshold = `
+	INSERT INTO stock_thresholds (id, warehouse_id, product_id, reorder_point, target_level, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (warehouse_id, product_id) DO UPDATE
+	SET reorder_point = EXCLUDED.reorder_point,
+	    target_level = EXCLUDED.target_level,
+	    updated_at = EXCLUDED.updated_at
+	RETURNING id, created_at;`
+
+func (r *StockThresholdPostgreRepo) Save(ctx context.Context, stockThreshold *entity.StockThreshold) error {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryUpsertStockThreshold)
+	if errStmt != nil {
+		return errStmt
+	}
+	defer stmt.Close()
+
+	err := stmt.QueryRowContext(ctx,
+		stockThreshold.ID,
+		stockThreshold.WarehouseID,
+		stockThreshold.ProductID,
+		stockThreshold.ReorderPoint,
+		stockThreshold.TargetLevel,
+		stockThreshold.CreatedAt,
+		stockThreshold.UpdatedAt,
+	).Scan(&stockThreshold.ID, &stockThreshold.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const queryGetStockThresholdsByWarehouseID = `
+	SELECT id, warehouse_id, product_id, reorder_point, target_level, created_at, updated_at
+	FROM stock_thresholds
+	WHERE warehouse_id = $1
+	ORDER BY product_id;`
+
+func (r *StockThresholdPostgreRepo) GetByWarehouseID(ctx context.Context, warehouseID uuid.UUID) ([]*entity.StockThreshold, error) {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetStockThresholdsByWarehouseID)
+	if errStmt != nil {
+		return nil, errStmt
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stockThresholds []*entity.StockThreshold
+	for rows.Next() {
+		var stockThreshold entity.StockThreshold
+		if err := rows.Scan(
+			&stockThreshold.ID,
+			&stockThreshold.WarehouseID,
+			&stockThreshold.ProductID,
+			&stockThreshold.ReorderPoint,
+			&stockThreshold.TargetLevel,
+			&stockThreshold.CreatedAt,
+			&stockThreshold.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		stockThresholds = append(stockThresholds, &stockThreshold)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stockThresholds, nil
+}
+
+const queryDeleteStockThreshold = `DELETE FROM stock_thresholds WHERE warehouse_id = $1 AND product_id = $2;`
+
+func (r *StockThresholdPostgreRepo) Delete(ctx context.Context, warehouseID, productID uuid.UUID) error {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryDeleteStockThreshold)
+	if errStmt != nil {
+		return errStmt
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, warehouseID, productID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("stock threshold not found: %w", entity.ErrInvalidStockThreshold)
+	}
+
+	return nil
+}
+
+// available quantity excludes the quantity held by active reservations,
+// in-transit quantity is shipped to the warehouse by transfer orders but not received yet
+const queryStockLevels = `
+	SELECT stock_thresholds.warehouse_id,
+		stock_thresholds.product_id,
+		COALESCE(warehouse_products.product_name, ''),
+		COALESCE(warehouse_products.product_quantity, 0) - COALESCE(held.held_quantity, 0),
+		COALESCE(transit.in_transit_quantity, 0),
+		stock_thresholds.reorder_point,
+		stock_thresholds.target_level,
+		stock_thresholds.is_low
+	FROM stock_thresholds
+	LEFT JOIN warehouse_products
+	ON stock_thresholds.warehouse_id = warehouse_products.warehouse_id
+	AND stock_thresholds.product_id = warehouse_products.product_id
+	AND warehouse_products.deleted_at IS NULL
+	LEFT JOIN (` + queryHeldQuantity + `) held
+	ON stock_thresholds.warehouse_id = held.warehouse_id AND stock_thresholds.product_id = held.product_id
+	LEFT JOIN (` + queryInTransitQuantity + `) transit
+	ON stock_thresholds.warehouse_id = transit.to_warehouse_id AND stock_thresholds.product_id = transit.product_id`
+
+const (
+	queryGetStockLevels = queryStockLevels + `
+	ORDER BY stock_thresholds.warehouse_id, stock_thresholds.product_id;`
+
+	// locks the thresholds, so concurrent evaluations do not notify the same low stock twice
+	queryLockStockLevels = queryStockLevels + `
+	ORDER BY stock_thresholds.warehouse_id, stock_thresholds.product_id
+	FOR UPDATE OF stock_thresholds;`
+
+	queryUpdateStockThresholdIsLow = `UPDATE stock_thresholds SET is_low = $1 WHERE warehouse_id = $2 AND product_id = $3`
+)
+
+func scanStockLevels(rows *sql.Rows) ([]*entity.StockLevel, error) {
+	var stockLevels []*entity.StockLevel
+	for rows.Next() {
+		var stockLevel entity.StockLevel
+		if err := rows.Scan(
+			&stockLevel.WarehouseID,
+			&stockLevel.ProductID,
+			&stockLevel.ProductName,
+			&stockLevel.AvailableQuantity,
+			&stockLevel.InTransitQuantity,
+			&stockLevel.ReorderPoint,
+			&stockLevel.TargetLevel,
+			&stockLevel.WasLow,
+		); err != nil {
+			return nil, err
+		}
+		stockLevels = append(stockLevels, &stockLevel)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stockLevels, nil
+}
+
+// stock level of every product which has a threshold
+func (r *StockThresholdPostgreRepo) GetStockLevels(ctx context.Context) ([]*entity.StockLevel, error) {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetStockLevels)
+	if errStmt != nil {
+		return nil, errStmt
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanStockLevels(rows)
+}
+
+type stockLowMessage struct {
+	WarehouseID  uuid.UUID `json:"warehouse_id"`
+	ProductID    uuid.UUID `json:"product_id"`
+	ProductName  string    `json:"product_name"`
+	Quantity     int64     `json:"quantity"`
+	ReorderPoint int64     `json:"reorder_point"`
+	TargetLevel  int64     `json:"target_level"`
+}
+
+// compare the stock levels against their thresholds. Stock which became low since the previous evaluation
+// is saved as stock-low event to outbox and returned, stock which is not low anymore can be notified again later.
+func (r *StockThresholdPostgreRepo) EvaluateLowStocks(ctx context.Context, evaluatedAt time.Time) ([]*entity.StockLevel, error) {
+	// begin transaction
+	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. lock thresholds with their stock levels
+	rows, err := tx.QueryContext(ctx, queryLockStockLevels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock stock levels: %w", err)
+	}
+	stockLevels, err := scanStockLevels(rows)
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock levels: %w", err)
+	}
+
+	var newLowStocks []*entity.StockLevel
+	for _, stockLevel := range stockLevels {
+		isLow := stockLevel.IsLow()
+		if isLow == stockLevel.WasLow {
+			continue
+		}
+
+		// 2. update low flag of the threshold
+		_, err = tx.ExecContext(ctx, queryUpdateStockThresholdIsLow, isLow, stockLevel.WarehouseID, stockLevel.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update stock threshold: %w", err)
+		}
+		if !isLow {
+			continue
+		}
+
+		// 3. save stock-low event to outbox
+		message := stockLowMessage{
+			WarehouseID:  stockLevel.WarehouseID,
+			ProductID:    stockLevel.ProductID,
+			ProductName:  stockLevel.ProductName,
+			Quantity:     stockLevel.Position(),
+			ReorderPoint: stockLevel.ReorderPoint,
+			TargetLevel:  stockLevel.TargetLevel,
+		}
+		if err = insertOutboxEvent(ctx, tx, kafka.StockLowTopic, stockLevel.ProductID.String(), message, evaluatedAt); err != nil {
+			return nil, err
+		}
+		newLowStocks = append(newLowStocks, stockLevel)
+	}
+
+	// commit transaction
+	if errCommit := tx.Commit(); errCommit != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", errCommit)
+	}
+
+	return newLowStocks, nil
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/idoyudha/eshop-warehouse/internal/entity"
 	"github.com/idoyudha/eshop-warehouse/pkg/postgresql"
+	"github.com/lib/pq"
 )
 
 type TransactionProductPostgresRepo struct {
@@ -45,6 +47,7 @@ const (
 		    updated_at = $2 
 		WHERE product_id = $3 
 		AND warehouse_id = $4 
+		AND product_quantity >= $1
 		AND deleted_at IS NULL`
 
 	queryUpdateDestQuantity = `
@@ -79,10 +82,104 @@ const (
 			quantity, 
 			from_warehouse_id, 
 			to_warehouse_id, 
+			movement_type,
+			reason,
+			reference_id,
+			idempotency_key,
 			created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), NULLIF($10, ''), $11)`
 )
 
+const (
+	// serializes requests with the same idempotency key until the transaction ends
+	queryLockIdempotencyKey = `SELECT pg_advisory_xact_lock(hashtext($1))`
+
+	queryGetStockMovementsByIdempotencyKey = `SELECT ` + stockMovementColumns + ` FROM stock_movements WHERE idempotency_key = $1 ORDER BY id`
+)
+
+const (
+	// serializes moving out and compensating the same reference until the transaction ends,
+	// prefixed so it is not the lock of an idempotency key with the same value
+	queryLockReference = `SELECT pg_advisory_xact_lock(hashtext('reference:' || $1))`
+
+	queryIsReferenceCancelled = `SELECT EXISTS (SELECT 1 FROM cancelled_references WHERE reference_id = $1)`
+
+	queryInsertCancelledReference = `
+		INSERT INTO cancelled_references (reference_id, reason, cancelled_at)
+		VALUES ($1, NULLIF($2, ''), $3)
+		ON CONFLICT (reference_id) DO NOTHING`
+)
+
+// lock the reference and check it is not compensated yet, an empty reference is never cancelled
+func checkReferenceNotCancelled(ctx context.Context, tx *sql.Tx, referenceID string) error {
+	if referenceID == "" {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, queryLockReference, referenceID); err != nil {
+		return fmt.Errorf("failed to lock reference: %w", err)
+	}
+
+	var cancelled bool
+	if err := tx.QueryRowContext(ctx, queryIsReferenceCancelled, referenceID).Scan(&cancelled); err != nil {
+		return fmt.Errorf("failed to check cancelled reference: %w", err)
+	}
+	if cancelled {
+		return fmt.Errorf("reference %s: %w", referenceID, entity.ErrReferenceCancelled)
+	}
+
+	return nil
+}
+
+// lock the idempotency key and return the movements already created with it,
+// returns nil when the key is empty or has not been used yet
+func findIdempotentStockMovements(ctx context.Context, tx *sql.Tx, idempotencyKey string) ([]*entity.StockMovement, error) {
+	if idempotencyKey == "" {
+		return nil, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, queryLockIdempotencyKey, idempotencyKey); err != nil {
+		return nil, fmt.Errorf("failed to lock idempotency key: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, queryGetStockMovementsByIdempotencyKey, idempotencyKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock movements by idempotency key: %w", err)
+	}
+	defer rows.Close()
+
+	var stockMovements []*entity.StockMovement
+	for rows.Next() {
+		var stockMovement entity.StockMovement
+		if err := rows.Scan(
+			&stockMovement.ID,
+			&stockMovement.ProductID,
+			&stockMovement.ProductName,
+			&stockMovement.Quantity,
+			&stockMovement.FromWarehouseID,
+			&stockMovement.ToWarehouseID,
+			&stockMovement.ToUserID,
+			&stockMovement.FromUserID,
+			&stockMovement.MovementType,
+			&stockMovement.OriginalMovementID,
+			&stockMovement.Disposition,
+			&stockMovement.Reason,
+			&stockMovement.ReferenceID,
+			&stockMovement.CreatedBy,
+			&stockMovement.IdempotencyKey,
+			&stockMovement.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan stock movement: %w", err)
+		}
+		stockMovements = append(stockMovements, &stockMovement)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get stock movements by idempotency key: %w", err)
+	}
+
+	return stockMovements, nil
+}
+
 // handling transfer from warehouse to warehouse
 func (r *TransactionProductPostgresRepo) TransferIn(ctx context.Context, stockMovement *entity.StockMovement) error {
 	// begin transaction
@@ -94,6 +191,20 @@ func (r *TransactionProductPostgresRepo) TransferIn(ctx context.Context, stockMo
 	}
 	defer tx.Rollback()
 
+	// a retried request returns the movement created by the first request
+	existing, err := findIdempotentStockMovements(ctx, tx, stockMovement.IdempotencyKey)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		requested := map[uuid.UUID]int64{stockMovement.ProductID: stockMovement.Quantity}
+		if err := entity.MatchIdempotentStockMovements(existing, requested); err != nil {
+			return err
+		}
+		*stockMovement = *existing[0]
+		return nil
+	}
+
 	// 1. lock source product row if exists
 	var whSrcProduct entity.WarehouseProduct
 	if err = tx.QueryRowContext(ctx, queryLockSourceProduct,
@@ -110,6 +221,21 @@ func (r *TransactionProductPostgresRepo) TransferIn(ctx context.Context, stockMo
 		return fmt.Errorf("failed to lock source product: %w", err)
 	}
 
+	// quantity held by active reservations can not be transferred
+	var heldQuantity int64
+	if err = tx.QueryRowContext(ctx, queryGetHeldQuantityByProductIDAndWarehouseID,
+		stockMovement.ProductID, stockMovement.FromWarehouseID,
+	).Scan(&heldQuantity); err != nil {
+		return fmt.Errorf("failed to get held quantity: %w", err)
+	}
+	if whSrcProduct.ProductQuantity-heldQuantity < stockMovement.Quantity {
+		return &entity.InsufficientStockError{Items: []entity.InsufficientStockItem{{
+			ProductID: stockMovement.ProductID,
+			Requested: stockMovement.Quantity,
+			Available: whSrcProduct.ProductQuantity - heldQuantity,
+		}}}
+	}
+
 	// 2. lock destination product row if exists
 	var destExist bool
 	var whDestProductID uuid.UUID
@@ -133,7 +259,7 @@ func (r *TransactionProductPostgresRepo) TransferIn(ctx context.Context, stockMo
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("source product not found")
+		return fmt.Errorf("source product quantity is not enough")
 	}
 
 	// 4. handle destination product
@@ -170,6 +296,7 @@ func (r *TransactionProductPostgresRepo) TransferIn(ctx context.Context, stockMo
 	}
 
 	// 5. insert stock movement
+	stockMovement.MovementType = entity.MovementTypeTransfer
 	_, err = tx.ExecContext(ctx, queryInsertWarehouseMovement,
 		stockMovement.ID,
 		stockMovement.ProductID,
@@ -177,12 +304,41 @@ func (r *TransactionProductPostgresRepo) TransferIn(ctx context.Context, stockMo
 		stockMovement.Quantity,
 		stockMovement.FromWarehouseID,
 		stockMovement.ToWarehouseID,
+		stockMovement.MovementType,
+		stockMovement.Reason,
+		stockMovement.ReferenceID,
+		stockMovement.IdempotencyKey,
 		stockMovement.CreatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert stock movement: %w", err)
 	}
 
+	// 6. move the consumed cost layers of the source to the destination
+	consumptions, err := consumeCostLayers(ctx, tx, stockMovement)
+	if err != nil {
+		return err
+	}
+	for _, consumption := range consumptions {
+		err = insertCostLayer(ctx, tx, &entity.CostLayer{
+			WarehouseID:     stockMovement.ToWarehouseID,
+			ProductID:       stockMovement.ProductID,
+			StockMovementID: stockMovement.ID,
+			Quantity:        consumption.Quantity,
+			UnitCost:        consumption.UnitCost,
+			CreatedAt:       stockMovement.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// 7. save product quantity updated event to outbox
+	err = insertProductQuantityUpdatedEvents(ctx, tx, []uuid.UUID{stockMovement.ProductID}, stockMovement.CreatedAt)
+	if err != nil {
+		return err
+	}
+
 	// commit transaction
 	if errCommit := tx.Commit(); errCommit != nil {
 		return fmt.Errorf("failed to commit transaction: %w", errCommit)
@@ -199,47 +355,333 @@ const queryInsertUserMovement = `
 		quantity, 
 		from_warehouse_id, 
 		to_user_id,
+		movement_type,
+		reason,
+		reference_id,
+		idempotency_key,
 		created_at
-	) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), NULLIF($10, ''), $11)`
+
+const (
+	// locks all warehouse rows of the requested products, ordered by id to avoid deadlocks
+	queryLockProductsInAllWarehouse = `
+		SELECT warehouse_products.warehouse_id, warehouse_products.product_id, warehouses.zip_code, warehouses.latitude, warehouses.longitude, warehouse_products.product_name, warehouse_products.product_quantity
+		FROM warehouse_products
+		JOIN warehouses
+		ON warehouse_products.warehouse_id = warehouses.id
+		WHERE warehouse_products.product_id = ANY($1::uuid[])
+		AND warehouse_products.deleted_at IS NULL
+		AND warehouses.deleted_at IS NULL
+		ORDER BY warehouse_products.id
+		FOR UPDATE OF warehouse_products`
+
+	queryGetHeldQuantityByProductIDs = `
+		SELECT held.warehouse_id, held.product_id, held.held_quantity
+		FROM (` + queryHeldQuantity + `) held
+		WHERE held.product_id = ANY($1::uuid[])`
+)
+
+// lock the warehouse rows of the products and return their available quantity,
+// held quantity is read after the lock so it includes reservations committed while waiting
+func lockAvailableQuantities(ctx context.Context, tx *sql.Tx, productIDs []uuid.UUID) ([]*entity.WarehouseAddressAndProductQty, error) {
+	ids := make([]string, 0, len(productIDs))
+	for _, productID := range productIDs {
+		ids = append(ids, productID.String())
+	}
+
+	rows, err := tx.QueryContext(ctx, queryLockProductsInAllWarehouse, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock products: %w", err)
+	}
+	var warehouses []*entity.WarehouseAddressAndProductQty
+	for rows.Next() {
+		var warehouse entity.WarehouseAddressAndProductQty
+		if err := rows.Scan(
+			&warehouse.WarehouseID,
+			&warehouse.ProductID,
+			&warehouse.ZipCode,
+			&warehouse.Latitude,
+			&warehouse.Longitude,
+			&warehouse.ProductName,
+			&warehouse.ProductQuantity,
+		); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan locked product: %w", err)
+		}
+		warehouses = append(warehouses, &warehouse)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to lock products: %w", err)
+	}
+
+	rows, err = tx.QueryContext(ctx, queryGetHeldQuantityByProductIDs, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get held quantity: %w", err)
+	}
+	defer rows.Close()
+
+	type warehouseProductKey struct {
+		warehouseID uuid.UUID
+		productID   uuid.UUID
+	}
+	held := make(map[warehouseProductKey]int64)
+	for rows.Next() {
+		var key warehouseProductKey
+		var heldQuantity int64
+		if err := rows.Scan(&key.warehouseID, &key.productID, &heldQuantity); err != nil {
+			return nil, fmt.Errorf("failed to scan held quantity: %w", err)
+		}
+		held[key] = heldQuantity
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get held quantity: %w", err)
+	}
+
+	for _, warehouse := range warehouses {
+		warehouse.ProductQuantity -= held[warehouseProductKey{warehouse.WarehouseID, warehouse.ProductID}]
+	}
+
+	return warehouses, nil
+}
 
 // handling transfer from warehouse to user
-// if one warehouse is not enough products, then take it from another warehouse
+// rows of the requested products are locked first, then allocate decides the source warehouses
+// based on the locked available quantity, so concurrent orders can not allocate the same quantity.
+// if one warehouse is not enough products, allocate takes it from another warehouse,
 // it will be multiple stock movement transactions
-func (r *TransactionProductPostgresRepo) TransferOut(ctx context.Context, stockMovement []*entity.StockMovement) error {
+func (r *TransactionProductPostgresRepo) TransferOut(
+	ctx context.Context,
+	stockMovementReq []*entity.StockMovement,
+	allocate func([]*entity.WarehouseAddressAndProductQty) ([]*entity.StockMovement, error),
+) ([]*entity.StockMovement, error) {
 	// begin transaction
 	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
 		Isolation: sql.LevelReadCommitted,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	for _, movement := range stockMovement {
-		// 1. lock source product row
-		var whSrcProduct entity.WarehouseProduct
-		if err = tx.QueryRowContext(ctx, queryLockSourceProduct,
-			movement.ProductID, movement.FromWarehouseID,
-		).Scan(
-			&whSrcProduct.ID,
-			&whSrcProduct.ProductSKU,
-			&whSrcProduct.ProductImageURL,
-			&whSrcProduct.ProductDescription,
-			&whSrcProduct.ProductPrice,
-			&whSrcProduct.ProductCategoryID,
-			&whSrcProduct.ProductQuantity,
+	// a cancelled reference is rejected before a retried request returns the movements created by the first request,
+	// the movements are already reversed
+	if len(stockMovementReq) > 0 {
+		if err := checkReferenceNotCancelled(ctx, tx, stockMovementReq[0].ReferenceID); err != nil {
+			return nil, err
+		}
+
+		existing, err := findIdempotentStockMovements(ctx, tx, stockMovementReq[0].IdempotencyKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(existing) > 0 {
+			if err := entity.MatchIdempotentStockMovements(existing, entity.StockMovementQuantities(stockMovementReq)); err != nil {
+				return nil, err
+			}
+			return existing, nil
+		}
+	}
+
+	var productIDs []uuid.UUID
+	for _, movement := range stockMovementReq {
+		productIDs = append(productIDs, movement.ProductID)
+	}
+
+	// 1. lock all warehouse rows of the requested products
+	warehouses, err := lockAvailableQuantities(ctx, tx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// available quantity read under the lock, reported when the source update fails
+	type warehouseProductKey struct{ warehouseID, productID uuid.UUID }
+	available := make(map[warehouseProductKey]int64, len(warehouses))
+	for _, warehouse := range warehouses {
+		available[warehouseProductKey{warehouse.WarehouseID, warehouse.ProductID}] = warehouse.ProductQuantity
+	}
+
+	// 2. allocate source warehouses under the lock
+	stockMovements, err := allocate(warehouses)
+	if err != nil {
+		return nil, err
+	}
+
+	var insufficientItems []entity.InsufficientStockItem
+	for _, movement := range stockMovements {
+		// 3. update source quantity, guarded so quantity never goes negative
+		res, err := tx.ExecContext(ctx, queryUpdateSourceQuantity,
+			movement.Quantity, movement.CreatedAt, movement.ProductID, movement.FromWarehouseID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update source quantity: %w", err)
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			insufficientItems = append(insufficientItems, entity.InsufficientStockItem{
+				ProductID: movement.ProductID,
+				Requested: movement.Quantity,
+				Available: available[warehouseProductKey{movement.FromWarehouseID, movement.ProductID}],
+			})
+			continue
+		}
+
+		// 4. insert stock movement
+		movement.MovementType = entity.MovementTypeSale
+		_, err = tx.ExecContext(ctx, queryInsertUserMovement,
+			movement.ID,
+			movement.ProductID,
+			movement.ProductName,
+			movement.Quantity,
+			movement.FromWarehouseID,
+			movement.ToUserID,
+			movement.MovementType,
+			movement.Reason,
+			movement.ReferenceID,
+			movement.IdempotencyKey,
+			movement.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to insert stock movement: %w", err)
+		}
+
+		// 5. consume cost layers of the source warehouse
+		if _, err = consumeCostLayers(ctx, tx, movement); err != nil {
+			return nil, err
+		}
+	}
+	if len(insufficientItems) > 0 {
+		return nil, &entity.InsufficientStockError{Items: insufficientItems}
+	}
+
+	// 6. save product quantity updated events to outbox
+	if len(stockMovements) > 0 {
+		err = insertProductQuantityUpdatedEvents(ctx, tx, productIDs, stockMovements[0].CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// commit transaction
+	if errCommit := tx.Commit(); errCommit != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", errCommit)
+	}
+
+	return stockMovements, nil
+}
+
+// the database clock is returned, so expiry is decided by the same clock as the held quantity.
+// expires_at has no time zone, so the clock is read the same way
+const queryLockReservation = `
+	SELECT user_id, status, expires_at, LOCALTIMESTAMP
+	FROM reservations
+	WHERE id = $1
+	FOR UPDATE`
+
+// handling transfer from warehouse to user based on reservation
+// the held quantity of each reservation item is moved out from the reserved warehouse
+func (r *TransactionProductPostgresRepo) CommitReservation(ctx context.Context, reservationID uuid.UUID, committedAt time.Time) ([]*entity.StockMovement, error) {
+	// begin transaction
+	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. lock reservation row, only active and not expired reservation can be committed
+	reservation := entity.Reservation{ID: reservationID}
+	var now time.Time
+	err = tx.QueryRowContext(ctx, queryLockReservation, reservationID).Scan(
+		&reservation.UserID,
+		&reservation.Status,
+		&reservation.ExpiresAt,
+		&now,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("reservation %s: %w", reservationID, entity.ErrReservationNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock reservation: %w", err)
+	}
+	if !reservation.IsHolding(now) {
+		return nil, fmt.Errorf("reservation %s is %s: %w", reservationID, reservation.Status, entity.ErrReservationNotHolding)
+	}
+
+	// 2. get reservation items
+	rows, err := tx.QueryContext(ctx, queryGetReservationItemsByReservationID, reservationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservation items: %w", err)
+	}
+	for rows.Next() {
+		var item entity.ReservationItem
+		if err := rows.Scan(
+			&item.ID,
+			&item.ReservationID,
+			&item.WarehouseID,
+			&item.ProductID,
+			&item.ProductName,
+			&item.Quantity,
 		); err != nil {
-			return fmt.Errorf("failed to lock source product: %w", err)
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan reservation item: %w", err)
+		}
+		reservation.Items = append(reservation.Items, &item)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get reservation items: %w", err)
+	}
+
+	var stockMovements []*entity.StockMovement
+	var productIDs []uuid.UUID
+	for _, item := range reservation.Items {
+		movement := &entity.StockMovement{
+			ProductID:       item.ProductID,
+			ProductName:     item.ProductName,
+			Quantity:        item.Quantity,
+			FromWarehouseID: item.WarehouseID,
+			ToUserID:        reservation.UserID,
+			ReferenceID:     reservationID.String(),
+			CreatedAt:       committedAt,
+		}
+		if err = movement.GenerateStockMovementID(); err != nil {
+			return nil, fmt.Errorf("failed to generate stock movement id: %w", err)
 		}
 
-		// 2. update source quantity
-		_, err = tx.ExecContext(ctx, queryUpdateSourceQuantity,
+		// 3. lock source product row
+		var productQuantity int64
+		if err = tx.QueryRowContext(ctx, queryLockProductQuantity,
+			movement.ProductID, movement.FromWarehouseID,
+		).Scan(&productQuantity); err != nil {
+			return nil, fmt.Errorf("failed to lock source product: %w", err)
+		}
+
+		// 4. update source quantity, guarded so quantity never goes negative
+		res, err := tx.ExecContext(ctx, queryUpdateSourceQuantity,
 			movement.Quantity, movement.CreatedAt, movement.ProductID, movement.FromWarehouseID)
 		if err != nil {
-			return fmt.Errorf("failed to update source quantity: %w", err)
+			return nil, fmt.Errorf("failed to update source quantity: %w", err)
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return nil, &entity.InsufficientStockError{Items: []entity.InsufficientStockItem{{
+				ProductID: movement.ProductID,
+				Requested: movement.Quantity,
+				Available: productQuantity,
+			}}}
 		}
 
-		// 3. insert stock movement
+		// 5. insert stock movement
+		movement.MovementType = entity.MovementTypeSale
 		_, err = tx.ExecContext(ctx, queryInsertUserMovement,
 			movement.ID,
 			movement.ProductID,
@@ -247,11 +689,270 @@ func (r *TransactionProductPostgresRepo) TransferOut(ctx context.Context, stockM
 			movement.Quantity,
 			movement.FromWarehouseID,
 			movement.ToUserID,
+			movement.MovementType,
+			movement.Reason,
+			movement.ReferenceID,
+			movement.IdempotencyKey,
 			movement.CreatedAt,
 		)
 		if err != nil {
-			return fmt.Errorf("failed to insert stock movement: %w", err)
+			return nil, fmt.Errorf("failed to insert stock movement: %w", err)
 		}
+
+		// 6. consume cost layers of the source warehouse
+		if _, err = consumeCostLayers(ctx, tx, movement); err != nil {
+			return nil, err
+		}
+
+		stockMovements = append(stockMovements, movement)
+		productIDs = append(productIDs, movement.ProductID)
+	}
+
+	// 7. reservation is no longer holding the quantity
+	_, err = tx.ExecContext(ctx, queryUpdateActiveReservationStatus,
+		entity.ReservationStatusCommitted, committedAt, reservationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update reservation status: %w", err)
+	}
+
+	// 8. save product quantity updated events to outbox
+	err = insertProductQuantityUpdatedEvents(ctx, tx, productIDs, committedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// commit transaction
+	if errCommit := tx.Commit(); errCommit != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", errCommit)
+	}
+
+	return stockMovements, nil
+}
+
+const (
+	queryLockOriginalMovement = `
+		SELECT product_id, product_name, quantity, to_user_id, movement_type
+		FROM stock_movements
+		WHERE id = $1
+		FOR UPDATE`
+
+	// reversed quantity is back in the warehouse, so it can not be returned anymore
+	queryGetReturnedQuantity = `
+		SELECT COALESCE(SUM(quantity), 0)
+		FROM stock_movements
+		WHERE original_movement_id = $1
+		AND movement_type IN ('return', 'reversal')`
+
+	// product details of the returned product are copied from any warehouse having it
+	queryGetProductDetails = `
+		SELECT product_sku, product_image_url, product_description, product_price, product_category_id
+		FROM warehouse_products
+		WHERE product_id = $1
+		ORDER BY deleted_at IS NULL DESC, updated_at DESC
+		LIMIT 1`
+
+	queryUpdateReturnQuantity = `
+		UPDATE warehouse_products 
+		SET product_quantity = product_quantity + $1, 
+		    quarantined_quantity = quarantined_quantity + $2,
+		    updated_at = $3
+		WHERE product_id = $4 
+		AND warehouse_id = $5
+		AND deleted_at IS NULL`
+
+	queryInsertReturnProduct = `
+		INSERT INTO warehouse_products (
+			id, 
+			warehouse_id, 
+			product_id,
+			product_sku,
+			product_name,
+			product_image_url,
+			product_description,
+			product_price, 
+			product_quantity,
+			quarantined_quantity,
+			product_category_id,
+			created_at, 
+			updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+	queryInsertReturnMovement = `
+		INSERT INTO stock_movements (
+			id, 
+			product_id, 
+			product_name, 
+			quantity, 
+			to_warehouse_id, 
+			from_user_id,
+			movement_type,
+			original_movement_id,
+			disposition,
+			reason,
+			reference_id,
+			idempotency_key,
+			created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), NULLIF($11, ''), NULLIF($12, ''), $13)`
+
+	queryGetSaleConsumedUnitCost = `
+		SELECT SUM(quantity * unit_cost) / NULLIF(SUM(quantity), 0)
+		FROM cost_layer_consumptions
+		WHERE stock_movement_id = $1`
+)
+
+// handling return from user to warehouse
+// the returned quantity is linked to the original sale movement and can not exceed what is left of it,
+// quarantined units are kept in the warehouse but not added to the product quantity
+func (r *TransactionProductPostgresRepo) TransferReturn(ctx context.Context, stockMovement *entity.StockMovement) error {
+	// begin transaction
+	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// a retried request returns the movement created by the first request
+	existing, err := findIdempotentStockMovements(ctx, tx, stockMovement.IdempotencyKey)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		if existing[0].OriginalMovementID != stockMovement.OriginalMovementID || existing[0].Quantity != stockMovement.Quantity {
+			return entity.ErrIdempotencyKeyReused
+		}
+		*stockMovement = *existing[0]
+		return nil
+	}
+
+	// 1. lock original movement, so concurrent returns of it are serialized
+	var original entity.StockMovement
+	err = tx.QueryRowContext(ctx, queryLockOriginalMovement, stockMovement.OriginalMovementID).Scan(
+		&original.ProductID,
+		&original.ProductName,
+		&original.Quantity,
+		&original.ToUserID,
+		&original.MovementType,
+	)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("original stock movement not found: %w", entity.ErrReturnNotAllowed)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock original stock movement: %w", err)
+	}
+	if original.MovementType != entity.MovementTypeSale {
+		return fmt.Errorf("original stock movement is not a sale: %w", entity.ErrReturnNotAllowed)
+	}
+
+	// 2. check quantity left to return
+	var returnedQuantity int64
+	if err = tx.QueryRowContext(ctx, queryGetReturnedQuantity, stockMovement.OriginalMovementID).Scan(&returnedQuantity); err != nil {
+		return fmt.Errorf("failed to get returned quantity: %w", err)
+	}
+	if returnedQuantity+stockMovement.Quantity > original.Quantity {
+		return fmt.Errorf("return quantity %d exceeds remaining quantity %d: %w",
+			stockMovement.Quantity, original.Quantity-returnedQuantity, entity.ErrReturnNotAllowed)
+	}
+
+	stockMovement.ProductID = original.ProductID
+	stockMovement.ProductName = original.ProductName
+	stockMovement.FromUserID = original.ToUserID
+	stockMovement.MovementType = entity.MovementTypeReturn
+
+	var restockQuantity, quarantinedQuantity int64
+	if stockMovement.Disposition == entity.DispositionQuarantine {
+		quarantinedQuantity = stockMovement.Quantity
+	} else {
+		restockQuantity = stockMovement.Quantity
+	}
+
+	// 3. lock destination product row if exists
+	var whDestProductID uuid.UUID
+	err = tx.QueryRowContext(ctx, queryLockDestProduct,
+		stockMovement.ProductID, stockMovement.ToWarehouseID,
+	).Scan(&whDestProductID)
+	destExist := err != sql.ErrNoRows
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to check or lock destination product: %w", err)
+	}
+
+	// 4. update or insert destination quantity
+	if destExist {
+		_, err = tx.ExecContext(ctx, queryUpdateReturnQuantity,
+			restockQuantity, quarantinedQuantity, stockMovement.CreatedAt, stockMovement.ProductID, stockMovement.ToWarehouseID)
+		if err != nil {
+			return fmt.Errorf("failed to update destination quantity: %w", err)
+		}
+	} else {
+		var whProduct entity.WarehouseProduct
+		err = tx.QueryRowContext(ctx, queryGetProductDetails, stockMovement.ProductID).Scan(
+			&whProduct.ProductSKU,
+			&whProduct.ProductImageURL,
+			&whProduct.ProductDescription,
+			&whProduct.ProductPrice,
+			&whProduct.ProductCategoryID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to get product details: %w", err)
+		}
+
+		if err = whProduct.GenerateWarehouseProductID(); err != nil {
+			return fmt.Errorf("failed to generate warehouse product id: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, queryInsertReturnProduct,
+			whProduct.ID,
+			stockMovement.ToWarehouseID,
+			stockMovement.ProductID,
+			whProduct.ProductSKU,
+			stockMovement.ProductName,
+			whProduct.ProductImageURL,
+			whProduct.ProductDescription,
+			whProduct.ProductPrice,
+			restockQuantity,
+			quarantinedQuantity,
+			whProduct.ProductCategoryID,
+			stockMovement.CreatedAt,
+			stockMovement.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert destination product: %w", err)
+		}
+	}
+
+	// 5. insert stock movement
+	_, err = tx.ExecContext(ctx, queryInsertReturnMovement,
+		stockMovement.ID,
+		stockMovement.ProductID,
+		stockMovement.ProductName,
+		stockMovement.Quantity,
+		stockMovement.ToWarehouseID,
+		stockMovement.FromUserID,
+		stockMovement.MovementType,
+		stockMovement.OriginalMovementID,
+		stockMovement.Disposition,
+		stockMovement.Reason,
+		stockMovement.ReferenceID,
+		stockMovement.IdempotencyKey,
+		stockMovement.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert stock movement: %w", err)
+	}
+
+	// 6. restocked quantity is valued at the cost it was sold at, quarantined quantity is not on hand
+	if restockQuantity > 0 {
+		err = insertCostLayerAtConsumedCost(ctx, tx, stockMovement, queryGetSaleConsumedUnitCost, stockMovement.OriginalMovementID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// 7. save product quantity updated event to outbox
+	err = insertProductQuantityUpdatedEvents(ctx, tx, []uuid.UUID{stockMovement.ProductID}, stockMovement.CreatedAt)
+	if err != nil {
+		return err
 	}
 
 	// commit transaction
@@ -261,3 +962,146 @@ func (r *TransactionProductPostgresRepo) TransferOut(ctx context.Context, stockM
 
 	return nil
 }
+
+const (
+	// sale movements of the reference, locked so concurrent returns and reversals of them are serialized
+	queryLockSaleMovementsByReferenceID = `
+		SELECT id, product_id, product_name, quantity, from_warehouse_id, to_user_id, reference_id
+		FROM stock_movements
+		WHERE reference_id = $1
+		AND movement_type = 'sale'
+		ORDER BY id
+		FOR UPDATE`
+
+	queryInsertReversalMovement = `
+		INSERT INTO stock_movements (
+			id,
+			product_id,
+			product_name,
+			quantity,
+			to_warehouse_id,
+			from_user_id,
+			movement_type,
+			original_movement_id,
+			reason,
+			reference_id,
+			idempotency_key,
+			created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10, $11, $12)`
+)
+
+// handling compensation of transfer from warehouse to user
+// every sale movement of the reference is put back to the exact warehouse it left from in one transaction,
+// quantity already returned or reversed is skipped so compensating again does not move anything.
+// The reference is marked cancelled, so stock is not moved out for it when the compensation comes first
+func (r *TransactionProductPostgresRepo) ReverseTransferOut(ctx context.Context, referenceID string, reason string, reversedAt time.Time) ([]*entity.StockMovement, error) {
+	// begin transaction
+	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 0. lock the reference and mark it cancelled
+	if _, err = tx.ExecContext(ctx, queryLockReference, referenceID); err != nil {
+		return nil, fmt.Errorf("failed to lock reference: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, queryInsertCancelledReference, referenceID, reason, reversedAt); err != nil {
+		return nil, fmt.Errorf("failed to insert cancelled reference: %w", err)
+	}
+
+	// 1. lock sale movements of the reference
+	rows, err := tx.QueryContext(ctx, queryLockSaleMovementsByReferenceID, referenceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock sale movements: %w", err)
+	}
+	var sales []*entity.StockMovement
+	for rows.Next() {
+		var sale entity.StockMovement
+		if err := rows.Scan(
+			&sale.ID,
+			&sale.ProductID,
+			&sale.ProductName,
+			&sale.Quantity,
+			&sale.FromWarehouseID,
+			&sale.ToUserID,
+			&sale.ReferenceID,
+		); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan sale movement: %w", err)
+		}
+		sales = append(sales, &sale)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to lock sale movements: %w", err)
+	}
+
+	var reversals []*entity.StockMovement
+	var productIDs []uuid.UUID
+	for _, sale := range sales {
+		// 2. check quantity left to reverse
+		var returnedQuantity int64
+		if err = tx.QueryRowContext(ctx, queryGetReturnedQuantity, sale.ID).Scan(&returnedQuantity); err != nil {
+			return nil, fmt.Errorf("failed to get returned quantity: %w", err)
+		}
+
+		reversal, err := sale.Reverse(returnedQuantity, reason, reversedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate stock movement id: %w", err)
+		}
+		if reversal == nil {
+			continue
+		}
+
+		// 3. add reversed quantity back to the source warehouse
+		if err = receiveProductQuantity(ctx, tx, reversal); err != nil {
+			return nil, err
+		}
+
+		// 4. insert stock movement
+		_, err = tx.ExecContext(ctx, queryInsertReversalMovement,
+			reversal.ID,
+			reversal.ProductID,
+			reversal.ProductName,
+			reversal.Quantity,
+			reversal.ToWarehouseID,
+			reversal.FromUserID,
+			reversal.MovementType,
+			reversal.OriginalMovementID,
+			reversal.Reason,
+			reversal.ReferenceID,
+			reversal.IdempotencyKey,
+			reversal.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to insert stock movement: %w", err)
+		}
+
+		// 5. reversed quantity is valued at the cost it was sold at
+		err = insertCostLayerAtConsumedCost(ctx, tx, reversal, queryGetSaleConsumedUnitCost, reversal.OriginalMovementID)
+		if err != nil {
+			return nil, err
+		}
+
+		reversals = append(reversals, reversal)
+		productIDs = append(productIDs, reversal.ProductID)
+	}
+
+	// 6. save product quantity updated events to outbox
+	if len(productIDs) > 0 {
+		err = insertProductQuantityUpdatedEvents(ctx, tx, productIDs, reversedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// commit transaction
+	if errCommit := tx.Commit(); errCommit != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", errCommit)
+	}
+
+	return reversals, nil
+}
@@ -0,0 +1,176 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/pkg/postgresql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// main warehouse inserted by the populate migration
+var testMainWarehouseID = uuid.MustParse("0193d7b4-a3d7-7022-a547-b987b1f9c6a8")
+
+// the migrations are applied to a new schema of the database in TEST_POSTGRESQL_URL,
+// the test is skipped when it is not set
+func newTestPostgres(t *testing.T) *postgresql.Postgres {
+	databaseURL := os.Getenv("TEST_POSTGRESQL_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_POSTGRESQL_URL is not set")
+	}
+
+	admin, err := sql.Open("postgres", databaseURL)
+	require.NoError(t, err)
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	_, err = admin.Exec("CREATE SCHEMA " + schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		admin.Close()
+	})
+
+	// every connection of the pool runs in the search path of the test schema
+	u, err := url.Parse(databaseURL)
+	require.NoError(t, err)
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+
+	conn, err := sql.Open("postgres", u.String())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	migrations, err := filepath.Glob("../../../migrations/*.up.sql")
+	require.NoError(t, err)
+	sort.Strings(migrations)
+	for _, migration := range migrations {
+		query, err := os.ReadFile(migration)
+		require.NoError(t, err)
+		_, err = conn.Exec(string(query))
+		require.NoError(t, err, migration)
+	}
+
+	return &postgresql.Postgres{Conn: conn}
+}
+
+func TestReverseTransferOut(t *testing.T) {
+	client := newTestPostgres(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	product := &entity.WarehouseProduct{
+		WarehouseID:       testMainWarehouseID,
+		ProductID:         uuid.New(),
+		ProductSKU:        "SKU-1",
+		ProductName:       "Product 1",
+		ProductPrice:      10,
+		ProductQuantity:   10,
+		ProductCategoryID: uuid.New(),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	require.NoError(t, product.GenerateWarehouseProductID())
+	require.NoError(t, NewWarehouseProductPostgreRepo(client).Save(ctx, product))
+
+	repo := NewTransactionProductPostgreRepo(client)
+	referenceID := uuid.NewString()
+	sales, err := repo.TransferOut(ctx,
+		[]*entity.StockMovement{{ProductID: product.ProductID, Quantity: 4}},
+		func([]*entity.WarehouseAddressAndProductQty) ([]*entity.StockMovement, error) {
+			sale := &entity.StockMovement{
+				ProductID:       product.ProductID,
+				ProductName:     product.ProductName,
+				Quantity:        4,
+				FromWarehouseID: testMainWarehouseID,
+				ToUserID:        uuid.New(),
+				ReferenceID:     referenceID,
+				CreatedAt:       now,
+			}
+			if err := sale.GenerateStockMovementID(); err != nil {
+				return nil, err
+			}
+			return []*entity.StockMovement{sale}, nil
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, sales, 1)
+
+	reversals, err := repo.ReverseTransferOut(ctx, referenceID, "order cancelled", now)
+	require.NoError(t, err)
+	require.Len(t, reversals, 1)
+	assert.Equal(t, entity.MovementTypeReversal, reversals[0].MovementType)
+	assert.Equal(t, int64(4), reversals[0].Quantity)
+	assert.Equal(t, sales[0].ID, reversals[0].OriginalMovementID)
+
+	var movementType, idempotencyKey string
+	err = client.Conn.QueryRow(`SELECT movement_type, idempotency_key FROM stock_movements WHERE id = $1`, reversals[0].ID).Scan(&movementType, &idempotencyKey)
+	require.NoError(t, err)
+	assert.Equal(t, entity.MovementTypeReversal, movementType)
+	assert.Equal(t, "reversal:"+referenceID+":"+sales[0].ID.String(), idempotencyKey)
+
+	var quantity int64
+	err = client.Conn.QueryRow(`SELECT product_quantity FROM warehouse_products WHERE warehouse_id = $1 AND product_id = $2`,
+		testMainWarehouseID, product.ProductID).Scan(&quantity)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), quantity)
+
+	// compensating again finds nothing left to reverse
+	reversals, err = repo.ReverseTransferOut(ctx, referenceID, "order cancelled", now)
+	require.NoError(t, err)
+	assert.Empty(t, reversals)
+}
+
+// the order cancelled event is handled before the order created event
+func TestTransferOutAfterReverseTransferOut(t *testing.T) {
+	client := newTestPostgres(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	product := &entity.WarehouseProduct{
+		WarehouseID:       testMainWarehouseID,
+		ProductID:         uuid.New(),
+		ProductSKU:        "SKU-1",
+		ProductName:       "Product 1",
+		ProductPrice:      10,
+		ProductQuantity:   10,
+		ProductCategoryID: uuid.New(),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	require.NoError(t, product.GenerateWarehouseProductID())
+	require.NoError(t, NewWarehouseProductPostgreRepo(client).Save(ctx, product))
+
+	repo := NewTransactionProductPostgreRepo(client)
+	referenceID := uuid.NewString()
+
+	reversals, err := repo.ReverseTransferOut(ctx, referenceID, "order cancelled", now)
+	require.NoError(t, err)
+	assert.Empty(t, reversals)
+
+	allocated := false
+	_, err = repo.TransferOut(ctx,
+		[]*entity.StockMovement{{ProductID: product.ProductID, Quantity: 4, ReferenceID: referenceID, IdempotencyKey: "order-created:" + referenceID}},
+		func([]*entity.WarehouseAddressAndProductQty) ([]*entity.StockMovement, error) {
+			allocated = true
+			return nil, nil
+		},
+	)
+	require.ErrorIs(t, err, entity.ErrReferenceCancelled)
+	assert.False(t, allocated)
+
+	var quantity int64
+	err = client.Conn.QueryRow(`SELECT product_quantity FROM warehouse_products WHERE warehouse_id = $1 AND product_id = $2`,
+		testMainWarehouseID, product.ProductID).Scan(&quantity)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), quantity)
+}
@@ -0,0 +1,629 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/pkg/postgresql"
+)
+
+type TransferOrderPostgreRepo struct {
+	*postgresql.Postgres
+}
+
+func NewTransferOrderPostgreRepo(client *postgresql.Postgres) *TransferOrderPostgreRepo {
+	return &TransferOrderPostgreRepo{
+		client,
+	}
+}
+
+const (
+	queryInsertTransferOrder = `
+		INSERT INTO transfer_orders (id, from_warehouse_id, to_warehouse_id, status, reason, created_by, updated_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9)`
+
+	// product name is taken from the source warehouse, so only products stored there can be transferred
+	queryInsertTransferOrderLine = `
+		INSERT INTO transfer_order_lines (id, transfer_order_id, product_id, product_name, quantity)
+		SELECT $1, $2, $3, product_name, $4
+		FROM warehouse_products
+		WHERE product_id = $3
+		AND warehouse_id = $5
+		AND deleted_at IS NULL
+		RETURNING product_name`
+)
+
+// save transfer order with its requested lines
+func (r *TransferOrderPostgreRepo) Save(ctx context.Context, transferOrder *entity.TransferOrder) error {
+	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, queryInsertTransferOrder,
+		transferOrder.ID,
+		transferOrder.FromWarehouseID,
+		transferOrder.ToWarehouseID,
+		transferOrder.Status,
+		transferOrder.Reason,
+		transferOrder.CreatedBy,
+		transferOrder.UpdatedBy,
+		transferOrder.CreatedAt,
+		transferOrder.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert transfer order: %w", err)
+	}
+
+	for _, line := range transferOrder.Lines {
+		err = tx.QueryRowContext(ctx, queryInsertTransferOrderLine,
+			line.ID,
+			line.TransferOrderID,
+			line.ProductID,
+			line.Quantity,
+			transferOrder.FromWarehouseID,
+		).Scan(&line.ProductName)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("product %s is not in the source warehouse: %w", line.ProductID, entity.ErrInvalidTransferOrder)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to insert transfer order line: %w", err)
+		}
+	}
+
+	if errCommit := tx.Commit(); errCommit != nil {
+		return fmt.Errorf("failed to commit transaction: %w", errCommit)
+	}
+
+	return nil
+}
+
+const (
+	queryGetTransferOrderByID = `
+		SELECT id, from_warehouse_id, to_warehouse_id, status, COALESCE(reason, ''), created_by, updated_by, created_at, updated_at
+		FROM transfer_orders
+		WHERE id = $1;`
+
+	// ordered by product id, so shipping and receiving lock the product rows in a consistent order
+	queryGetTransferOrderLinesByTransferOrderID = `
+		SELECT id, transfer_order_id, product_id, product_name, quantity, shipped_quantity, received_quantity, discrepancy_quantity
+		FROM transfer_order_lines
+		WHERE transfer_order_id = $1
+		ORDER BY product_id;`
+)
+
+func (r *TransferOrderPostgreRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.TransferOrder, error) {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetTransferOrderByID)
+	if errStmt != nil {
+		return nil, errStmt
+	}
+	defer stmt.Close()
+
+	var transferOrder entity.TransferOrder
+	err := stmt.QueryRowContext(ctx, id).Scan(
+		&transferOrder.ID,
+		&transferOrder.FromWarehouseID,
+		&transferOrder.ToWarehouseID,
+		&transferOrder.Status,
+		&transferOrder.Reason,
+		&transferOrder.CreatedBy,
+		&transferOrder.UpdatedBy,
+		&transferOrder.CreatedAt,
+		&transferOrder.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stmtLines, errStmt := r.Conn.PrepareContext(ctx, queryGetTransferOrderLinesByTransferOrderID)
+	if errStmt != nil {
+		return nil, errStmt
+	}
+	defer stmtLines.Close()
+
+	rows, err := stmtLines.QueryContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transferOrder.Lines, err = scanTransferOrderLines(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transferOrder, nil
+}
+
+func scanTransferOrderLines(rows *sql.Rows) ([]*entity.TransferOrderLine, error) {
+	var lines []*entity.TransferOrderLine
+	for rows.Next() {
+		var line entity.TransferOrderLine
+		if err := rows.Scan(
+			&line.ID,
+			&line.TransferOrderID,
+			&line.ProductID,
+			&line.ProductName,
+			&line.Quantity,
+			&line.ShippedQuantity,
+			&line.ReceivedQuantity,
+			&line.DiscrepancyQuantity,
+		); err != nil {
+			return nil, err
+		}
+		lines = append(lines, &line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+const (
+	queryLockTransferOrder = `
+		SELECT from_warehouse_id, to_warehouse_id, status, COALESCE(reason, ''), created_by, created_at
+		FROM transfer_orders
+		WHERE id = $1
+		FOR UPDATE`
+
+	queryUpdateTransferOrderStatus = `UPDATE transfer_orders SET status = $1, updated_by = $2, updated_at = $3 WHERE id = $4`
+
+	queryUpdateTransferOrderLineQuantities = `
+		UPDATE transfer_order_lines
+		SET shipped_quantity = $1,
+		    received_quantity = $2,
+		    discrepancy_quantity = $3
+		WHERE id = $4`
+
+	queryInsertTransferOrderMovement = `
+		INSERT INTO stock_movements (
+			id,
+			product_id,
+			product_name,
+			quantity,
+			from_warehouse_id,
+			to_warehouse_id,
+			movement_type,
+			reason,
+			reference_id,
+			created_by,
+			idempotency_key,
+			created_at
+		) VALUES (
+			$1, $2, $3, $4,
+			NULLIF($5::uuid, '00000000-0000-0000-0000-000000000000'),
+			NULLIF($6::uuid, '00000000-0000-0000-0000-000000000000'),
+			$7, NULLIF($8, ''), $9, $10, NULLIF($11, ''), $12
+		)`
+)
+
+// lock the transfer order with its lines, the status is kept as stored
+func lockTransferOrder(ctx context.Context, tx *sql.Tx, transferOrder *entity.TransferOrder) error {
+	err := tx.QueryRowContext(ctx, queryLockTransferOrder, transferOrder.ID).Scan(
+		&transferOrder.FromWarehouseID,
+		&transferOrder.ToWarehouseID,
+		&transferOrder.Status,
+		&transferOrder.Reason,
+		&transferOrder.CreatedBy,
+		&transferOrder.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("transfer order not found: %w", entity.ErrInvalidTransferOrder)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock transfer order: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, queryGetTransferOrderLinesByTransferOrderID, transferOrder.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get transfer order lines: %w", err)
+	}
+	defer rows.Close()
+
+	transferOrder.Lines, err = scanTransferOrderLines(rows)
+	if err != nil {
+		return fmt.Errorf("failed to get transfer order lines: %w", err)
+	}
+
+	return nil
+}
+
+// lock the source product rows of the lines and check the quantity not held by reservations is enough
+func checkTransferOrderAvailability(ctx context.Context, tx *sql.Tx, transferOrder *entity.TransferOrder) error {
+	var insufficientItems []entity.InsufficientStockItem
+	for _, line := range transferOrder.Lines {
+		var quantity int64
+		err := tx.QueryRowContext(ctx, queryLockProductQuantity, line.ProductID, transferOrder.FromWarehouseID).Scan(&quantity)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to lock source product: %w", err)
+		}
+
+		var heldQuantity int64
+		if err = tx.QueryRowContext(ctx, queryGetHeldQuantityByProductIDAndWarehouseID,
+			line.ProductID, transferOrder.FromWarehouseID,
+		).Scan(&heldQuantity); err != nil {
+			return fmt.Errorf("failed to get held quantity: %w", err)
+		}
+
+		if quantity-heldQuantity < line.Quantity {
+			insufficientItems = append(insufficientItems, entity.InsufficientStockItem{
+				ProductID: line.ProductID,
+				Requested: line.Quantity,
+				Available: quantity - heldQuantity,
+			})
+		}
+	}
+
+	if len(insufficientItems) > 0 {
+		return &entity.InsufficientStockError{Items: insufficientItems}
+	}
+	return nil
+}
+
+// mark the transfer order as picked, the source warehouse must have the quantity of every line available
+func (r *TransferOrderPostgreRepo) Pick(ctx context.Context, transferOrder *entity.TransferOrder) error {
+	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. lock transfer order
+	if err = lockTransferOrder(ctx, tx, transferOrder); err != nil {
+		return err
+	}
+	if err = transferOrder.TransitionTo(entity.TransferOrderStatusPicked); err != nil {
+		return err
+	}
+
+	// 2. check availability in source warehouse
+	if err = checkTransferOrderAvailability(ctx, tx, transferOrder); err != nil {
+		return err
+	}
+
+	// 3. update transfer order status
+	_, err = tx.ExecContext(ctx, queryUpdateTransferOrderStatus,
+		transferOrder.Status, transferOrder.UpdatedBy, transferOrder.UpdatedAt, transferOrder.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update transfer order status: %w", err)
+	}
+
+	if errCommit := tx.Commit(); errCommit != nil {
+		return fmt.Errorf("failed to commit transaction: %w", errCommit)
+	}
+
+	return nil
+}
+
+// ship the transfer order, quantity of every line is taken out of the source warehouse
+// and stays in transit until it is received
+func (r *TransferOrderPostgreRepo) Ship(ctx context.Context, transferOrder *entity.TransferOrder) error {
+	// begin transaction
+	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. lock transfer order
+	if err = lockTransferOrder(ctx, tx, transferOrder); err != nil {
+		return err
+	}
+	if err = transferOrder.TransitionTo(entity.TransferOrderStatusInTransit); err != nil {
+		return err
+	}
+
+	// 2. check availability in source warehouse, picking does not hold the quantity
+	if err = checkTransferOrderAvailability(ctx, tx, transferOrder); err != nil {
+		return err
+	}
+
+	var stockMovements []*entity.StockMovement
+	var productIDs []uuid.UUID
+	for _, line := range transferOrder.Lines {
+		// 3. update source quantity
+		_, err = tx.ExecContext(ctx, queryUpdateSourceQuantity,
+			line.Quantity, transferOrder.UpdatedAt, line.ProductID, transferOrder.FromWarehouseID)
+		if err != nil {
+			return fmt.Errorf("failed to update source quantity: %w", err)
+		}
+
+		line.ShippedQuantity = line.Quantity
+		_, err = tx.ExecContext(ctx, queryUpdateTransferOrderLineQuantities,
+			line.ShippedQuantity, line.ReceivedQuantity, line.DiscrepancyQuantity, line.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update transfer order line: %w", err)
+		}
+
+		// 4. insert stock movement, the destination is credited when the line is received
+		movement := &entity.StockMovement{
+			ProductID:       line.ProductID,
+			ProductName:     line.ProductName,
+			Quantity:        line.ShippedQuantity,
+			FromWarehouseID: transferOrder.FromWarehouseID,
+			MovementType:    entity.MovementTypeTransferShipment,
+			Reason:          transferOrder.Reason,
+			ReferenceID:     transferOrder.ID.String(),
+			CreatedBy:       transferOrder.UpdatedBy,
+			CreatedAt:       transferOrder.UpdatedAt,
+		}
+		if err = insertTransferOrderMovement(ctx, tx, movement); err != nil {
+			return err
+		}
+
+		// 5. consume cost layers of the source warehouse
+		if _, err = consumeCostLayers(ctx, tx, movement); err != nil {
+			return err
+		}
+
+		stockMovements = append(stockMovements, movement)
+		productIDs = append(productIDs, movement.ProductID)
+	}
+
+	// 6. update transfer order status
+	_, err = tx.ExecContext(ctx, queryUpdateTransferOrderStatus,
+		transferOrder.Status, transferOrder.UpdatedBy, transferOrder.UpdatedAt, transferOrder.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update transfer order status: %w", err)
+	}
+
+	// 7. save product quantity updated events to outbox
+	err = insertProductQuantityUpdatedEvents(ctx, tx, productIDs, transferOrder.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	// commit transaction
+	if errCommit := tx.Commit(); errCommit != nil {
+		return fmt.Errorf("failed to commit transaction: %w", errCommit)
+	}
+
+	transferOrder.StockMovements = stockMovements
+	return nil
+}
+
+// average cost of the quantity of the product shipped by the transfer order
+const queryGetShipmentConsumedUnitCost = `
+	SELECT SUM(clc.quantity * clc.unit_cost) / NULLIF(SUM(clc.quantity), 0)
+	FROM cost_layer_consumptions clc
+	JOIN stock_movements sm
+	ON sm.id = clc.stock_movement_id
+	WHERE sm.reference_id = $1
+	AND sm.product_id = $2
+	AND sm.movement_type = 'transfer-shipment'`
+
+// receive a delivery of the in-transit transfer order into the destination warehouse
+func (r *TransferOrderPostgreRepo) Receive(ctx context.Context, transferOrder *entity.TransferOrder, delivery *entity.TransferOrderDelivery) error {
+	// begin transaction
+	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// a retried request returns the movements created by the first request
+	existing, err := findIdempotentStockMovements(ctx, tx, delivery.IdempotencyKey)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		requested := make(map[uuid.UUID]int64, len(delivery.Lines))
+		for _, delivered := range delivery.Lines {
+			requested[delivered.ProductID] += delivered.Quantity
+		}
+		if err := entity.MatchIdempotentStockMovements(existing, requested); err != nil {
+			return err
+		}
+		transferOrder.StockMovements = existing
+		return nil
+	}
+
+	// 1. lock transfer order
+	if err = lockTransferOrder(ctx, tx, transferOrder); err != nil {
+		return err
+	}
+
+	// 2. apply delivery to the lines
+	if err = transferOrder.Receive(delivery); err != nil {
+		return err
+	}
+
+	var stockMovements []*entity.StockMovement
+	var productIDs []uuid.UUID
+	received := make(map[uuid.UUID]int64, len(delivery.Lines))
+	for _, delivered := range delivery.Lines {
+		received[delivered.ProductID] = delivered.Quantity
+	}
+	for _, line := range transferOrder.Lines {
+		// 3. update received and discrepancy quantity of the line
+		_, err = tx.ExecContext(ctx, queryUpdateTransferOrderLineQuantities,
+			line.ShippedQuantity, line.ReceivedQuantity, line.DiscrepancyQuantity, line.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update transfer order line: %w", err)
+		}
+
+		quantity, ok := received[line.ProductID]
+		if !ok {
+			continue
+		}
+
+		movement := &entity.StockMovement{
+			ProductID:      line.ProductID,
+			ProductName:    line.ProductName,
+			Quantity:       quantity,
+			ToWarehouseID:  transferOrder.ToWarehouseID,
+			MovementType:   entity.MovementTypeTransferReceipt,
+			Reason:         transferOrder.Reason,
+			ReferenceID:    transferOrder.ID.String(),
+			CreatedBy:      transferOrder.UpdatedBy,
+			IdempotencyKey: delivery.IdempotencyKey,
+			CreatedAt:      transferOrder.UpdatedAt,
+		}
+
+		// 4. add received quantity to the destination warehouse
+		if err = receiveProductQuantity(ctx, tx, movement); err != nil {
+			return err
+		}
+
+		// 5. insert stock movement
+		if err = insertTransferOrderMovement(ctx, tx, movement); err != nil {
+			return err
+		}
+
+		// 6. received quantity is valued at the cost it was shipped at
+		err = insertCostLayerAtConsumedCost(ctx, tx, movement, queryGetShipmentConsumedUnitCost, movement.ReferenceID, movement.ProductID)
+		if err != nil {
+			return err
+		}
+
+		stockMovements = append(stockMovements, movement)
+		productIDs = append(productIDs, movement.ProductID)
+	}
+
+	// 7. update transfer order status
+	_, err = tx.ExecContext(ctx, queryUpdateTransferOrderStatus,
+		transferOrder.Status, transferOrder.UpdatedBy, transferOrder.UpdatedAt, transferOrder.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update transfer order status: %w", err)
+	}
+
+	// 8. save product quantity updated events to outbox
+	err = insertProductQuantityUpdatedEvents(ctx, tx, productIDs, transferOrder.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	// commit transaction
+	if errCommit := tx.Commit(); errCommit != nil {
+		return fmt.Errorf("failed to commit transaction: %w", errCommit)
+	}
+
+	transferOrder.StockMovements = stockMovements
+	return nil
+}
+
+func insertTransferOrderMovement(ctx context.Context, tx *sql.Tx, movement *entity.StockMovement) error {
+	if err := movement.GenerateStockMovementID(); err != nil {
+		return fmt.Errorf("failed to generate stock movement id: %w", err)
+	}
+
+	_, err := tx.ExecContext(ctx, queryInsertTransferOrderMovement,
+		movement.ID,
+		movement.ProductID,
+		movement.ProductName,
+		movement.Quantity,
+		movement.FromWarehouseID,
+		movement.ToWarehouseID,
+		movement.MovementType,
+		movement.Reason,
+		movement.ReferenceID,
+		movement.CreatedBy,
+		movement.IdempotencyKey,
+		movement.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert stock movement: %w", err)
+	}
+
+	return nil
+}
+
+const queryCancelTransferOrder = `
+	UPDATE transfer_orders
+	SET status = $1,
+	    updated_by = $2,
+	    updated_at = $3
+	WHERE id = $4
+	AND status IN ('requested', 'picked');`
+
+// cancel the transfer order which is not shipped yet, nothing left the source warehouse
+func (r *TransferOrderPostgreRepo) Cancel(ctx context.Context, transferOrder *entity.TransferOrder) error {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryCancelTransferOrder)
+	if errStmt != nil {
+		return errStmt
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, entity.TransferOrderStatusCancelled, transferOrder.UpdatedBy, transferOrder.UpdatedAt, transferOrder.ID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("transfer order not found or already shipped: %w", entity.ErrInvalidTransferOrder)
+	}
+
+	transferOrder.Status = entity.TransferOrderStatusCancelled
+	return nil
+}
+
+// in-transit quantity of each product per destination warehouse
+const queryInTransitQuantity = `
+	SELECT transfer_orders.to_warehouse_id, transfer_order_lines.product_id,
+		SUM(transfer_order_lines.shipped_quantity - transfer_order_lines.received_quantity - transfer_order_lines.discrepancy_quantity) AS in_transit_quantity
+	FROM transfer_order_lines
+	JOIN transfer_orders
+	ON transfer_order_lines.transfer_order_id = transfer_orders.id
+	WHERE transfer_orders.status = 'in-transit'
+	GROUP BY transfer_orders.to_warehouse_id, transfer_order_lines.product_id`
+
+const queryGetInTransitByProductID = `
+	SELECT transfer_orders.id, transfer_orders.from_warehouse_id, transfer_orders.to_warehouse_id,
+		transfer_order_lines.shipped_quantity - transfer_order_lines.received_quantity - transfer_order_lines.discrepancy_quantity
+	FROM transfer_order_lines
+	JOIN transfer_orders
+	ON transfer_order_lines.transfer_order_id = transfer_orders.id
+	WHERE transfer_order_lines.product_id = $1
+	AND transfer_orders.status = 'in-transit'
+	AND transfer_order_lines.shipped_quantity - transfer_order_lines.received_quantity - transfer_order_lines.discrepancy_quantity > 0
+	ORDER BY transfer_orders.id;`
+
+func (r *TransferOrderPostgreRepo) GetInTransitByProductID(ctx context.Context, productID uuid.UUID) ([]*entity.TransferOrderInTransit, error) {
+	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetInTransitByProductID)
+	if errStmt != nil {
+		return nil, errStmt
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var inTransits []*entity.TransferOrderInTransit
+	for rows.Next() {
+		var inTransit entity.TransferOrderInTransit
+		if err := rows.Scan(
+			&inTransit.TransferOrderID,
+			&inTransit.FromWarehouseID,
+			&inTransit.ToWarehouseID,
+			&inTransit.Quantity,
+		); err != nil {
+			return nil, err
+		}
+		inTransits = append(inTransits, &inTransit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return inTransits, nil
+}
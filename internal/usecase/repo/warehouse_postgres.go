@@ -20,8 +20,8 @@ func NewWarehousePostgreRepo(client *postgresql.Postgres) *WarehousePostgreRepo
 }
 
 const queryInsertWarehouse = `
-	INSERT INTO warehouses (id, name, street, city, state, zip_code, is_main_warehouse, created_at, updated_at) 
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+	INSERT INTO warehouses (id, name, street, city, state, zip_code, latitude, longitude, is_main_warehouse, created_at, updated_at) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
 `
 
 func (r *WarehousePostgreRepo) Save(ctx context.Context, warehouse *entity.Warehouse) error {
@@ -38,6 +38,8 @@ func (r *WarehousePostgreRepo) Save(ctx context.Context, warehouse *entity.Wareh
 		warehouse.City,
 		warehouse.State,
 		warehouse.ZipCode,
+		warehouse.Latitude,
+		warehouse.Longitude,
 		warehouse.IsMainWarehouse,
 		warehouse.CreatedAt,
 		warehouse.UpdatedAt,
@@ -49,7 +51,8 @@ func (r *WarehousePostgreRepo) Save(ctx context.Context, warehouse *entity.Wareh
 	return nil
 }
 
-const queryUpdateWarehouse = `UPDATE warehouses SET name = $1, street = $2, updated_at = $3 WHERE id = $4;`
+// coordinate is kept when not given
+const queryUpdateWarehouse = `UPDATE warehouses SET name = $1, street = $2, latitude = COALESCE($3, latitude), longitude = COALESCE($4, longitude), updated_at = $5 WHERE id = $6;`
 
 func (r *WarehousePostgreRepo) Update(ctx context.Context, warehouse *entity.Warehouse) error {
 	stmt, errStmt := r.Conn.PrepareContext(ctx, queryUpdateWarehouse)
@@ -58,7 +61,7 @@ func (r *WarehousePostgreRepo) Update(ctx context.Context, warehouse *entity.War
 	}
 	defer stmt.Close()
 
-	_, updateErr := stmt.ExecContext(ctx, warehouse.Name, warehouse.Street, warehouse.UpdatedAt, warehouse.ID)
+	_, updateErr := stmt.ExecContext(ctx, warehouse.Name, warehouse.Street, warehouse.Latitude, warehouse.Longitude, warehouse.UpdatedAt, warehouse.ID)
 	if updateErr != nil {
 		return updateErr
 	}
@@ -66,7 +69,7 @@ func (r *WarehousePostgreRepo) Update(ctx context.Context, warehouse *entity.War
 	return nil
 }
 
-const queryGetByID = `SELECT id, name, street, city, state, zip_code, is_main_warehouse, created_at, updated_at FROM warehouses WHERE id = $1 AND deleted_at IS NULL;`
+const queryGetByID = `SELECT id, name, street, city, state, zip_code, latitude, longitude, is_main_warehouse, created_at, updated_at FROM warehouses WHERE id = $1 AND deleted_at IS NULL;`
 
 func (r *WarehousePostgreRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.Warehouse, error) {
 	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetByID)
@@ -83,6 +86,8 @@ func (r *WarehousePostgreRepo) GetByID(ctx context.Context, id uuid.UUID) (*enti
 		&warehouse.City,
 		&warehouse.State,
 		&warehouse.ZipCode,
+		&warehouse.Latitude,
+		&warehouse.Longitude,
 		&warehouse.IsMainWarehouse,
 		&warehouse.CreatedAt,
 		&warehouse.UpdatedAt,
@@ -94,7 +99,7 @@ func (r *WarehousePostgreRepo) GetByID(ctx context.Context, id uuid.UUID) (*enti
 	return &warehouse, nil
 }
 
-const queryGetAllWarehouse = `SELECT id, name, street, city, state, zip_code, is_main_warehouse, created_at, updated_at FROM warehouses WHERE deleted_at IS NULL;`
+const queryGetAllWarehouse = `SELECT id, name, street, city, state, zip_code, latitude, longitude, is_main_warehouse, created_at, updated_at FROM warehouses WHERE deleted_at IS NULL;`
 
 func (r *WarehousePostgreRepo) GetAll(ctx context.Context) ([]*entity.Warehouse, error) {
 	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetAllWarehouse)
@@ -119,6 +124,8 @@ func (r *WarehousePostgreRepo) GetAll(ctx context.Context) ([]*entity.Warehouse,
 			&warehouse.City,
 			&warehouse.State,
 			&warehouse.ZipCode,
+			&warehouse.Latitude,
+			&warehouse.Longitude,
 			&warehouse.IsMainWarehouse,
 			&warehouse.CreatedAt,
 			&warehouse.UpdatedAt,
@@ -132,7 +139,7 @@ func (r *WarehousePostgreRepo) GetAll(ctx context.Context) ([]*entity.Warehouse,
 	return warehouses, nil
 }
 
-const queryGetAllExceptMainWarehouse = `SELECT id, name, street, city, state, zip_code, is_main_warehouse, created_at, updated_at FROM warehouses WHERE is_main_warehouse = false AND deleted_at IS NULL;`
+const queryGetAllExceptMainWarehouse = `SELECT id, name, street, city, state, zip_code, latitude, longitude, is_main_warehouse, created_at, updated_at FROM warehouses WHERE is_main_warehouse = false AND deleted_at IS NULL;`
 
 func (r *WarehousePostgreRepo) GetAllExceptMain(ctx context.Context) ([]*entity.Warehouse, error) {
 	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetAllExceptMainWarehouse)
@@ -157,6 +164,8 @@ func (r *WarehousePostgreRepo) GetAllExceptMain(ctx context.Context) ([]*entity.
 			&warehouse.City,
 			&warehouse.State,
 			&warehouse.ZipCode,
+			&warehouse.Latitude,
+			&warehouse.Longitude,
 			&warehouse.IsMainWarehouse,
 			&warehouse.CreatedAt,
 			&warehouse.UpdatedAt,
@@ -188,7 +197,7 @@ func (r *WarehousePostgreRepo) GetMainID(ctx context.Context) (uuid.UUID, error)
 	return id, nil
 }
 
-const queryGetAllWarehouseIDAndZipCode = `SELECT id, zip_code FROM warehouses WHERE deleted_at IS NULL ORDER BY zip_code ASC;`
+const queryGetAllWarehouseIDAndZipCode = `SELECT id, zip_code, latitude, longitude FROM warehouses WHERE deleted_at IS NULL ORDER BY zip_code ASC;`
 
 func (r *WarehousePostgreRepo) GetAllIDAndZipCode(ctx context.Context) ([]*entity.Warehouse, error) {
 	stmt, errStmt := r.Conn.PrepareContext(ctx, queryGetAllWarehouseIDAndZipCode)
@@ -209,6 +218,8 @@ func (r *WarehousePostgreRepo) GetAllIDAndZipCode(ctx context.Context) ([]*entit
 		err := rows.Scan(
 			&warehouse.ID,
 			&warehouse.ZipCode,
+			&warehouse.Latitude,
+			&warehouse.Longitude,
 		)
 		if err != nil {
 			return nil, err
@@ -2,6 +2,8 @@ package repo
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/idoyudha/eshop-warehouse/internal/entity"
@@ -18,19 +20,34 @@ func NewWarehouseProductPostgreRepo(client *postgresql.Postgres) *WarehouseProdu
 	}
 }
 
+// nothing is inserted when the product is already in the warehouse, so a redelivered product event is harmless.
+// The unique index of the warehouse and product also holds when the same product is saved concurrently
 const queryInsertWarehouseProduct = `
 	INSERT INTO warehouse_products (id, warehouse_id, product_id, product_sku, product_name, product_image_url, product_description, product_price, product_quantity, product_category_id, created_at, updated_at) 
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	ON CONFLICT (warehouse_id, product_id) WHERE deleted_at IS NULL DO NOTHING;
 `
 
+// initial quantity of the product is not a movement, it is saved as opening balance of the ledger.
+// A balance already saved belongs to a deleted product of the warehouse, the ledger starts again from the new product
+const queryInsertOpeningBalance = `
+	INSERT INTO stock_opening_balances (warehouse_id, product_id, quantity, as_of, created_at)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (warehouse_id, product_id) DO UPDATE
+	SET quantity = EXCLUDED.quantity, as_of = EXCLUDED.as_of, created_at = EXCLUDED.created_at;`
+
 func (r *WarehouseProductPostgreRepo) Save(ctx context.Context, warehouseProduct *entity.WarehouseProduct) error {
-	stmt, errStmt := r.Conn.PrepareContext(ctx, queryInsertWarehouseProduct)
-	if errStmt != nil {
-		return errStmt
+	// begin transaction
+	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	defer stmt.Close()
+	defer tx.Rollback()
 
-	_, saveErr := stmt.ExecContext(ctx,
+	// 1. insert warehouse product
+	res, err := tx.ExecContext(ctx, queryInsertWarehouseProduct,
 		warehouseProduct.ID,
 		warehouseProduct.WarehouseID,
 		warehouseProduct.ProductID,
@@ -44,8 +61,47 @@ func (r *WarehouseProductPostgreRepo) Save(ctx context.Context, warehouseProduct
 		warehouseProduct.CreatedAt,
 		warehouseProduct.UpdatedAt,
 	)
-	if saveErr != nil {
-		return saveErr
+	if err != nil {
+		return fmt.Errorf("failed to insert warehouse product: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	// the product is already saved with its opening balance and cost layer
+	if rowsAffected == 0 {
+		return fmt.Errorf("product %s in warehouse %s: %w", warehouseProduct.ProductID, warehouseProduct.WarehouseID, entity.ErrWarehouseProductExists)
+	}
+
+	// 2. insert opening balance
+	_, err = tx.ExecContext(ctx, queryInsertOpeningBalance,
+		warehouseProduct.WarehouseID,
+		warehouseProduct.ProductID,
+		warehouseProduct.ProductQuantity,
+		warehouseProduct.CreatedAt,
+		warehouseProduct.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert opening balance: %w", err)
+	}
+
+	// 3. initial quantity is valued at the product price
+	if warehouseProduct.ProductQuantity > 0 {
+		err = insertCostLayer(ctx, tx, &entity.CostLayer{
+			WarehouseID: warehouseProduct.WarehouseID,
+			ProductID:   warehouseProduct.ProductID,
+			Quantity:    warehouseProduct.ProductQuantity,
+			UnitCost:    warehouseProduct.ProductPrice,
+			CreatedAt:   warehouseProduct.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// commit transaction
+	if errCommit := tx.Commit(); errCommit != nil {
+		return fmt.Errorf("failed to commit transaction: %w", errCommit)
 	}
 
 	return nil
@@ -80,25 +136,158 @@ func (r *WarehouseProductPostgreRepo) Update(ctx context.Context, warehouseProdu
 	return nil
 }
 
-const queryUpdateProductQuantity = `UPDATE warehouse_products SET product_quantity = $1, updated_at = $2 WHERE product_id = $3;`
+const (
+	queryLockAdjustedProduct = `
+		SELECT product_name, product_quantity
+		FROM warehouse_products
+		WHERE product_id = $1
+		AND warehouse_id = $2
+		AND deleted_at IS NULL
+		FOR UPDATE`
+
+	queryUpdateAdjustedQuantity = `
+		UPDATE warehouse_products
+		SET product_quantity = $1,
+		    updated_at = $2
+		WHERE product_id = $3
+		AND warehouse_id = $4
+		AND deleted_at IS NULL`
+
+	// the warehouse is the source for negative adjustments and the destination for positive ones
+	queryInsertAdjustmentMovement = `
+		INSERT INTO stock_movements (
+			id,
+			product_id,
+			product_name,
+			quantity,
+			from_warehouse_id,
+			to_warehouse_id,
+			movement_type,
+			reason,
+			reference_id,
+			created_by,
+			created_at
+		) VALUES (
+			$1, $2, $3, $4,
+			NULLIF($5::uuid, '00000000-0000-0000-0000-000000000000'),
+			NULLIF($6::uuid, '00000000-0000-0000-0000-000000000000'),
+			$7, $8, NULLIF($9, ''),
+			NULLIF($10::uuid, '00000000-0000-0000-0000-000000000000'),
+			$11
+		)`
+)
 
-func (r *WarehouseProductPostgreRepo) UpdateProductQuantity(ctx context.Context, warehouseProduct *entity.WarehouseProduct) error {
-	stmt, errStmt := r.Conn.PrepareContext(ctx, queryUpdateProductQuantity)
-	if errStmt != nil {
-		return errStmt
+// adjust the product quantity of one warehouse and record it as adjustment stock movement,
+// counted quantity is compared with the quantity of the locked row
+func (r *WarehouseProductPostgreRepo) Adjust(ctx context.Context, stockAdjustment *entity.StockAdjustment) error {
+	// begin transaction
+	tx, err := r.Conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	defer stmt.Close()
+	defer tx.Rollback()
 
-	_, updateErr := stmt.ExecContext(ctx, warehouseProduct.ProductQuantity, warehouseProduct.UpdatedAt, warehouseProduct.ProductID)
-	if updateErr != nil {
-		return updateErr
+	// 1. adjust product quantity
+	if err = adjustProductQuantity(ctx, tx, stockAdjustment); err != nil {
+		return err
+	}
+	// counted quantity is the same as the current quantity, nothing to adjust
+	if stockAdjustment.StockMovement == nil {
+		return nil
+	}
+
+	// 2. save product quantity updated event to outbox
+	err = insertProductQuantityUpdatedEvents(ctx, tx, []uuid.UUID{stockAdjustment.ProductID}, stockAdjustment.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	// commit transaction
+	if errCommit := tx.Commit(); errCommit != nil {
+		return fmt.Errorf("failed to commit transaction: %w", errCommit)
 	}
 
 	return nil
 }
 
+// lock the product row, update its quantity and insert the adjustment stock movement,
+// the stock movement of the adjustment is nil when the quantity is unchanged
+func adjustProductQuantity(ctx context.Context, tx *sql.Tx, stockAdjustment *entity.StockAdjustment) error {
+	// 1. lock product row
+	var productName string
+	var productQuantity int64
+	err := tx.QueryRowContext(ctx, queryLockAdjustedProduct,
+		stockAdjustment.ProductID, stockAdjustment.WarehouseID,
+	).Scan(&productName, &productQuantity)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("product not found in warehouse: %w", entity.ErrInvalidAdjustment)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock product: %w", err)
+	}
+
+	// 2. compute the new quantity, the quantity held by active reservations must stay in stock
+	var heldQuantity int64
+	if err = tx.QueryRowContext(ctx, queryGetHeldQuantityByProductIDAndWarehouseID,
+		stockAdjustment.ProductID, stockAdjustment.WarehouseID,
+	).Scan(&heldQuantity); err != nil {
+		return fmt.Errorf("failed to get held quantity: %w", err)
+	}
+	if err = stockAdjustment.Apply(productQuantity, heldQuantity); err != nil {
+		return err
+	}
+
+	movement, err := stockAdjustment.ToStockMovement(productName)
+	if err != nil {
+		return fmt.Errorf("failed to generate stock movement id: %w", err)
+	}
+	if movement == nil {
+		return nil
+	}
+
+	// 3. update product quantity
+	_, err = tx.ExecContext(ctx, queryUpdateAdjustedQuantity,
+		stockAdjustment.NewQuantity, stockAdjustment.CreatedAt, stockAdjustment.ProductID, stockAdjustment.WarehouseID)
+	if err != nil {
+		return fmt.Errorf("failed to update product quantity: %w", err)
+	}
+
+	// 4. insert stock movement
+	_, err = tx.ExecContext(ctx, queryInsertAdjustmentMovement,
+		movement.ID,
+		movement.ProductID,
+		movement.ProductName,
+		movement.Quantity,
+		movement.FromWarehouseID,
+		movement.ToWarehouseID,
+		movement.MovementType,
+		movement.Reason,
+		movement.ReferenceID,
+		movement.CreatedBy,
+		movement.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert stock movement: %w", err)
+	}
+
+	// 5. found quantity is valued at the current cost, lost quantity consumes the oldest layers
+	if movement.ToWarehouseID != uuid.Nil {
+		err = insertCostLayerAtCurrentCost(ctx, tx, movement)
+	} else {
+		_, err = consumeCostLayers(ctx, tx, movement)
+	}
+	if err != nil {
+		return err
+	}
+
+	stockAdjustment.StockMovement = movement
+	return nil
+}
+
 const queryGetAllWarehouseProducts = `
-	SELECT id, warehouse_id, product_id, product_sku, product_name, product_image_url, product_description, product_price, product_quantity, product_category_id, created_at, updated_at
+	SELECT id, warehouse_id, product_id, product_sku, product_name, product_image_url, product_description, product_price, product_quantity, quarantined_quantity, product_category_id, created_at, updated_at
 	FROM warehouse_products 
 	WHERE deleted_at IS NULL;
 `
@@ -129,6 +318,7 @@ func (r *WarehouseProductPostgreRepo) GetAll(ctx context.Context) ([]*entity.War
 			&warehouseProduct.ProductDescription,
 			&warehouseProduct.ProductPrice,
 			&warehouseProduct.ProductQuantity,
+			&warehouseProduct.QuarantinedQuantity,
 			&warehouseProduct.ProductCategoryID,
 			&warehouseProduct.CreatedAt,
 			&warehouseProduct.UpdatedAt,
@@ -144,7 +334,7 @@ func (r *WarehouseProductPostgreRepo) GetAll(ctx context.Context) ([]*entity.War
 }
 
 const queryGetWarehouseProductByProductID = `
-	SELECT id, warehouse_id, product_id, product_sku, product_name, product_image_url, product_description, product_price, product_quantity, product_category_id, created_at, updated_at
+	SELECT id, warehouse_id, product_id, product_sku, product_name, product_image_url, product_description, product_price, product_quantity, quarantined_quantity, product_category_id, created_at, updated_at
 	FROM warehouse_products 
 	WHERE product_id = $1 AND deleted_at IS NULL;
 `
@@ -175,6 +365,7 @@ func (r *WarehouseProductPostgreRepo) GetByProductID(ctx context.Context, id uui
 			&warehouseProduct.ProductDescription,
 			&warehouseProduct.ProductPrice,
 			&warehouseProduct.ProductQuantity,
+			&warehouseProduct.QuarantinedQuantity,
 			&warehouseProduct.ProductCategoryID,
 			&warehouseProduct.CreatedAt,
 			&warehouseProduct.UpdatedAt,
@@ -190,7 +381,7 @@ func (r *WarehouseProductPostgreRepo) GetByProductID(ctx context.Context, id uui
 }
 
 const queryGetWarehouseProductByWarehouseID = `
-	SELECT id, warehouse_id, product_id, product_sku, product_name, product_image_url, product_description, product_price, product_quantity, product_category_id, created_at, updated_at
+	SELECT id, warehouse_id, product_id, product_sku, product_name, product_image_url, product_description, product_price, product_quantity, quarantined_quantity, product_category_id, created_at, updated_at
 	FROM warehouse_products 
 	WHERE warehouse_id = $1 AND deleted_at IS NULL;
 `
@@ -221,6 +412,7 @@ func (r *WarehouseProductPostgreRepo) GetByWarehouseID(ctx context.Context, id u
 			&warehouseProduct.ProductDescription,
 			&warehouseProduct.ProductPrice,
 			&warehouseProduct.ProductQuantity,
+			&warehouseProduct.QuarantinedQuantity,
 			&warehouseProduct.ProductCategoryID,
 			&warehouseProduct.CreatedAt,
 			&warehouseProduct.UpdatedAt,
@@ -236,7 +428,7 @@ func (r *WarehouseProductPostgreRepo) GetByWarehouseID(ctx context.Context, id u
 }
 
 const queryGetWarehouseProductByProductIDAndWarehouseID = `
-	SELECT id, warehouse_id, product_id, product_sku, product_name, product_image_url, product_description, product_price, product_quantity, product_category_id, created_at, updated_at
+	SELECT id, warehouse_id, product_id, product_sku, product_name, product_image_url, product_description, product_price, product_quantity, quarantined_quantity, product_category_id, created_at, updated_at
 	FROM warehouse_products 
 	WHERE product_id = $1 AND warehouse_id = $2 AND deleted_at IS NULL;
 `
@@ -259,6 +451,7 @@ func (r *WarehouseProductPostgreRepo) GetByProductIDAndWarehouseID(ctx context.C
 		&warehouseProduct.ProductDescription,
 		&warehouseProduct.ProductPrice,
 		&warehouseProduct.ProductQuantity,
+		&warehouseProduct.QuarantinedQuantity,
 		&warehouseProduct.ProductCategoryID,
 		&warehouseProduct.CreatedAt,
 		&warehouseProduct.UpdatedAt,
@@ -271,11 +464,14 @@ func (r *WarehouseProductPostgreRepo) GetByProductIDAndWarehouseID(ctx context.C
 	return &warehouseProduct, nil
 }
 
+// product quantity returned is the available quantity, the quantity held by active reservations is excluded
 const queryGetWarehouseIDAndZipCodeByProductID = `
-	SELECT warehouse_id, zip_code, product_name,product_quantity
+	SELECT warehouse_products.warehouse_id, warehouse_products.product_id, zip_code, latitude, longitude, product_name, product_quantity - COALESCE(held.held_quantity, 0)
 	FROM warehouse_products
 	JOIN warehouses
 	ON warehouse_products.warehouse_id = warehouses.id
+	LEFT JOIN (` + queryHeldQuantity + `) held
+	ON warehouse_products.warehouse_id = held.warehouse_id AND warehouse_products.product_id = held.product_id
 	WHERE warehouse_products.product_id = $1 AND warehouse_products.deleted_at IS NULL and warehouses.deleted_at IS NULL;
 `
 
@@ -297,7 +493,10 @@ func (r *WarehouseProductPostgreRepo) GetWarehouseIDZipCodeAndQtyByProductID(ctx
 		var warehouseAndProduct entity.WarehouseAddressAndProductQty
 		err := rows.Scan(
 			&warehouseAndProduct.WarehouseID,
+			&warehouseAndProduct.ProductID,
 			&warehouseAndProduct.ZipCode,
+			&warehouseAndProduct.Latitude,
+			&warehouseAndProduct.Longitude,
 			&warehouseAndProduct.ProductName,
 			&warehouseAndProduct.ProductQuantity,
 		)
@@ -310,8 +509,13 @@ func (r *WarehouseProductPostgreRepo) GetWarehouseIDZipCodeAndQtyByProductID(ctx
 	return warehouseAndProducts, nil
 }
 
+// total available quantity, the quantity held by active reservations is excluded
 const queryGetTotalQuantityOfProductInAllWarehouse = `
-	SELECT SUM(product_quantity) FROM warehouse_products WHERE product_id = $1 AND deleted_at IS NULL;
+	SELECT COALESCE(SUM(product_quantity - COALESCE(held.held_quantity, 0)), 0)
+	FROM warehouse_products
+	LEFT JOIN (` + queryHeldQuantity + `) held
+	ON warehouse_products.warehouse_id = held.warehouse_id AND warehouse_products.product_id = held.product_id
+	WHERE warehouse_products.product_id = $1 AND warehouse_products.deleted_at IS NULL;
 `
 
 func (r *WarehouseProductPostgreRepo) GetTotalQuantityOfProductInAllWarehouse(ctx context.Context, productID uuid.UUID) (int, error) {
@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarehouseProductSaveRedelivered(t *testing.T) {
+	client := newTestPostgres(t)
+	repo := NewWarehouseProductPostgreRepo(client)
+	productID := uuid.New()
+
+	// the same product created event handled by several workers at once
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			now := time.Now()
+			product := &entity.WarehouseProduct{
+				WarehouseID:       testMainWarehouseID,
+				ProductID:         productID,
+				ProductSKU:        "SKU-1",
+				ProductName:       "Product 1",
+				ProductPrice:      10,
+				ProductQuantity:   10,
+				ProductCategoryID: uuid.New(),
+				CreatedAt:         now,
+				UpdatedAt:         now,
+			}
+			if errs[i] = product.GenerateWarehouseProductID(); errs[i] != nil {
+				return
+			}
+			errs[i] = repo.Save(context.Background(), product)
+		}()
+	}
+	wg.Wait()
+	// one is saved, the others find the product already in the warehouse
+	var saved int
+	for _, err := range errs {
+		if err == nil {
+			saved++
+			continue
+		}
+		require.ErrorIs(t, err, entity.ErrWarehouseProductExists)
+	}
+	assert.Equal(t, 1, saved)
+
+	var count int
+	err := client.Conn.QueryRow(`SELECT COUNT(*) FROM warehouse_products WHERE warehouse_id = $1 AND product_id = $2`,
+		testMainWarehouseID, productID).Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestWarehouseProductSaveRecreated(t *testing.T) {
+	client := newTestPostgres(t)
+	repo := NewWarehouseProductPostgreRepo(client)
+	ctx := context.Background()
+	productID := uuid.New()
+
+	save := func(quantity int64, createdAt time.Time) {
+		product := &entity.WarehouseProduct{
+			WarehouseID:       testMainWarehouseID,
+			ProductID:         productID,
+			ProductSKU:        "SKU-1",
+			ProductName:       "Product 1",
+			ProductPrice:      10,
+			ProductQuantity:   quantity,
+			ProductCategoryID: uuid.New(),
+			CreatedAt:         createdAt,
+			UpdatedAt:         createdAt,
+		}
+		require.NoError(t, product.GenerateWarehouseProductID())
+		require.NoError(t, repo.Save(ctx, product))
+	}
+	openingBalance := func() (quantity int64, asOf time.Time) {
+		err := client.Conn.QueryRow(`SELECT quantity, as_of FROM stock_opening_balances WHERE warehouse_id = $1 AND product_id = $2`,
+			testMainWarehouseID, productID).Scan(&quantity, &asOf)
+		require.NoError(t, err)
+		return quantity, asOf
+	}
+
+	now := time.Now()
+	save(10, now.Add(-2*time.Hour))
+	_, deletedAsOf := openingBalance()
+	_, err := client.Conn.Exec(`UPDATE warehouse_products SET deleted_at = $1 WHERE warehouse_id = $2 AND product_id = $3`,
+		now.Add(-time.Hour), testMainWarehouseID, productID)
+	require.NoError(t, err)
+
+	// the product is created again after it was deleted
+	save(4, now)
+
+	quantity, asOf := openingBalance()
+	assert.Equal(t, int64(4), quantity)
+	assert.True(t, asOf.After(deletedAsOf), asOf)
+}
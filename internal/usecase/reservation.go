@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/utils"
+)
+
+type ReservationUseCase struct {
+	repoReservationPostgre ReservationPostgreRepo
+	repoProductPostgre     WarehouseProductPostgreRepo
+	distanceCalculator     utils.DistanceCalculator
+	ttl                    time.Duration
+}
+
+func NewReservationUseCase(
+	repoReservationPostgre ReservationPostgreRepo,
+	repoProductPostgre WarehouseProductPostgreRepo,
+	distanceCalculator utils.DistanceCalculator,
+	ttl time.Duration,
+) *ReservationUseCase {
+	return &ReservationUseCase{
+		repoReservationPostgre,
+		repoProductPostgre,
+		distanceCalculator,
+		ttl,
+	}
+}
+
+// hold requested product quantity in the nearest warehouses until the reservation expires
+// reservation items only need product id and quantity, the warehouse is decided here
+func (u *ReservationUseCase) CreateReservation(ctx context.Context, reservation *entity.Reservation) error {
+	if err := reservation.Validate(); err != nil {
+		return err
+	}
+
+	err := reservation.GenerateReservationID()
+	if err != nil {
+		return fmt.Errorf("failed to generate reservation id: %w", err)
+	}
+
+	requestedItems := reservation.Items
+	reservation.Items = nil
+	for _, requestedItem := range requestedItems {
+		// product quantity of each warehouse already excludes quantity held by other reservations
+		warehouses, err := u.repoProductPostgre.GetWarehouseIDZipCodeAndQtyByProductID(ctx, requestedItem.ProductID)
+		if err != nil {
+			return fmt.Errorf("failed to get warehouse id and zip code by product id: %w", err)
+		}
+
+		nearestWarehouseIDs, err := utils.FindNearestWarehouseWithQty(u.distanceCalculator, reservation.ZipCode, warehouses, requestedItem.ProductID, requestedItem.Quantity)
+		if err != nil {
+			return fmt.Errorf("failed to calculate nearest warehouse: %w", err)
+		}
+
+		productName := requestedItem.ProductName
+		if productName == "" && len(warehouses) > 0 {
+			productName = warehouses[0].ProductName
+		}
+
+		for warehouseID, quantity := range nearestWarehouseIDs {
+			item := &entity.ReservationItem{
+				ReservationID: reservation.ID,
+				WarehouseID:   warehouseID,
+				ProductID:     requestedItem.ProductID,
+				ProductName:   productName,
+				Quantity:      quantity,
+			}
+			if err := item.GenerateReservationItemID(); err != nil {
+				return fmt.Errorf("failed to generate reservation item id: %w", err)
+			}
+			reservation.Items = append(reservation.Items, item)
+		}
+	}
+
+	reservation.Status = entity.ReservationStatusActive
+	reservation.UpdatedAt = reservation.CreatedAt
+
+	if err := u.repoReservationPostgre.Save(ctx, reservation, u.ttl); err != nil {
+		return fmt.Errorf("failed to save reservation: %w", err)
+	}
+
+	return nil
+}
+
+func (u *ReservationUseCase) GetReservationByID(ctx context.Context, id uuid.UUID) (*entity.Reservation, error) {
+	return u.repoReservationPostgre.GetByID(ctx, id)
+}
+
+// release the held quantity before the reservation expires, e.g. the checkout is cancelled
+func (u *ReservationUseCase) ReleaseReservation(ctx context.Context, id uuid.UUID) error {
+	reservation := &entity.Reservation{
+		ID:        id,
+		Status:    entity.ReservationStatusReleased,
+		UpdatedAt: time.Now(),
+	}
+
+	return u.repoReservationPostgre.UpdateStatus(ctx, reservation)
+}
+
+// expired reservations already stop holding quantity, this only updates their status
+func (u *ReservationUseCase) ReleaseExpiredReservations(ctx context.Context) (int64, error) {
+	return u.repoReservationPostgre.ExpireAll(ctx, time.Now())
+}
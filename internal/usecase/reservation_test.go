@@ -0,0 +1,202 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/idoyudha/eshop-warehouse/internal/utils"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+const reservationTTL = 15 * time.Minute
+
+type TestReservation struct {
+	name string
+	mock func()
+	err  error
+}
+
+func reservation(t *testing.T) (*usecase.ReservationUseCase, *MockReservationPostgreRepo, *MockWarehouseProductPostgreRepo) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	repoReservation := NewMockReservationPostgreRepo(mockCtl)
+	repoProduct := NewMockWarehouseProductPostgreRepo(mockCtl)
+	reservation := usecase.NewReservationUseCase(repoReservation, repoProduct, utils.ZipCodeDistanceCalculator{}, reservationTTL)
+
+	return reservation, repoReservation, repoProduct
+}
+
+func TestCreateReservation(t *testing.T) {
+	// t.Parallell()
+	reservation, repoReservation, repoProduct := reservation(t)
+
+	productID := uuid.New()
+	nearWarehouseID := uuid.New()
+	farWarehouseID := uuid.New()
+	warehouses := []*entity.WarehouseAddressAndProductQty{
+		{WarehouseID: nearWarehouseID, ZipCode: "10100", ProductName: "Product A", ProductQuantity: 3},
+		{WarehouseID: farWarehouseID, ZipCode: "19000", ProductName: "Product A", ProductQuantity: 10},
+	}
+
+	tests := []TestReservation{
+		{
+			name: "success",
+			mock: func() {
+				repoProduct.EXPECT().
+					GetWarehouseIDZipCodeAndQtyByProductID(context.Background(), productID).
+					Return(warehouses, nil)
+				repoReservation.EXPECT().
+					Save(context.Background(), gomock.Any(), reservationTTL).
+					DoAndReturn(func(_ context.Context, r *entity.Reservation, ttl time.Duration) error {
+						held := make(map[uuid.UUID]int64)
+						for _, item := range r.Items {
+							assert.Equal(t, r.ID, item.ReservationID)
+							assert.Equal(t, "Product A", item.ProductName)
+							held[item.WarehouseID] += item.Quantity
+						}
+						assert.Equal(t, map[uuid.UUID]int64{nearWarehouseID: 3, farWarehouseID: 2}, held)
+						// expiry is set by the database clock
+						r.ExpiresAt = r.CreatedAt.Add(ttl)
+						return nil
+					})
+			},
+			err: nil,
+		},
+		{
+			name: "not enough quantity",
+			mock: func() {
+				repoProduct.EXPECT().
+					GetWarehouseIDZipCodeAndQtyByProductID(context.Background(), productID).
+					Return(warehouses[:1], nil)
+			},
+			err: errors.New("failed to calculate nearest warehouse"),
+		},
+		{
+			name: "save error",
+			mock: func() {
+				repoProduct.EXPECT().
+					GetWarehouseIDZipCodeAndQtyByProductID(context.Background(), productID).
+					Return(warehouses, nil)
+				repoReservation.EXPECT().
+					Save(context.Background(), gomock.Any(), reservationTTL).
+					Return(errInternalServerError)
+			},
+			err: errInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			createdAt := time.Now()
+			input := &entity.Reservation{
+				UserID:    uuid.New(),
+				ZipCode:   "10000",
+				Items:     []*entity.ReservationItem{{ProductID: productID, Quantity: 5}},
+				CreatedAt: createdAt,
+			}
+
+			err := reservation.CreateReservation(context.Background(), input)
+			if tc.err != nil {
+				assert.ErrorContains(t, err, tc.err.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, input.ID)
+			assert.Equal(t, entity.ReservationStatusActive, input.Status)
+			assert.Equal(t, createdAt.Add(reservationTTL), input.ExpiresAt)
+		})
+	}
+}
+
+func TestCreateReservationDuplicatedProduct(t *testing.T) {
+	// t.Parallell()
+	reservation, _, _ := reservation(t)
+	productID := uuid.New()
+
+	// availability is checked per line, so the same product twice could hold more than is available
+	input := &entity.Reservation{
+		UserID:  uuid.New(),
+		ZipCode: "10000",
+		Items: []*entity.ReservationItem{
+			{ProductID: productID, Quantity: 3},
+			{ProductID: productID, Quantity: 3},
+		},
+		CreatedAt: time.Now(),
+	}
+
+	err := reservation.CreateReservation(context.Background(), input)
+
+	assert.ErrorIs(t, err, entity.ErrInvalidReservation)
+}
+
+func TestReleaseReservation(t *testing.T) {
+	// t.Parallell()
+	reservation, repoReservation, _ := reservation(t)
+	reservationID := uuid.New()
+
+	tests := []TestReservation{
+		{
+			name: "success",
+			mock: func() {
+				repoReservation.EXPECT().
+					UpdateStatus(context.Background(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, r *entity.Reservation) error {
+						assert.Equal(t, reservationID, r.ID)
+						assert.Equal(t, entity.ReservationStatusReleased, r.Status)
+						return nil
+					})
+			},
+			err: nil,
+		},
+		{
+			name: "error",
+			mock: func() {
+				repoReservation.EXPECT().
+					UpdateStatus(context.Background(), gomock.Any()).
+					Return(errInternalServerError)
+			},
+			err: errInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			err := reservation.ReleaseReservation(context.Background(), reservationID)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestReleaseExpiredReservations(t *testing.T) {
+	// t.Parallell()
+	reservation, repoReservation, _ := reservation(t)
+
+	repoReservation.EXPECT().
+		ExpireAll(context.Background(), gomock.Any()).
+		Return(int64(2), nil)
+
+	released, err := reservation.ReleaseExpiredReservations(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), released)
+}
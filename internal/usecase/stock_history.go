@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+)
+
+type StockHistoryUseCase struct {
+	repoStockHistoryPostgre StockHistoryPostgreRepo
+}
+
+func NewStockHistoryUseCase(repoStockHistoryPostgre StockHistoryPostgreRepo) *StockHistoryUseCase {
+	return &StockHistoryUseCase{
+		repoStockHistoryPostgre,
+	}
+}
+
+// on-hand quantity of the product in the warehouse at the given time, a time in the future is the current quantity
+func (u *StockHistoryUseCase) GetOnHandQuantity(ctx context.Context, warehouseID, productID uuid.UUID, at time.Time) (*entity.StockHistory, error) {
+	return u.repoStockHistoryPostgre.GetOnHand(ctx, warehouseID, productID, at)
+}
+
+// compare every warehouse product against its ledger, nothing is corrected
+func (u *StockHistoryUseCase) ReconcileStock(ctx context.Context) ([]*entity.StockDiscrepancy, error) {
+	discrepancies, err := u.repoStockHistoryPostgre.GetDiscrepancies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock discrepancies: %w", err)
+	}
+
+	return discrepancies, nil
+}
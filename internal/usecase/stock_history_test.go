@@ -0,0 +1,93 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func stockHistory(t *testing.T) (*usecase.StockHistoryUseCase, *MockStockHistoryPostgreRepo) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	repoStockHistory := NewMockStockHistoryPostgreRepo(mockCtl)
+	stockHistory := usecase.NewStockHistoryUseCase(repoStockHistory)
+
+	return stockHistory, repoStockHistory
+}
+
+func TestGetOnHandQuantity(t *testing.T) {
+	// t.Parallell()
+	stockHistory, repoStockHistory := stockHistory(t)
+
+	warehouseID := uuid.New()
+	productID := uuid.New()
+	at := time.Now().AddDate(0, -1, 0)
+
+	repoStockHistory.EXPECT().
+		GetOnHand(context.Background(), warehouseID, productID, at).
+		Return(&entity.StockHistory{WarehouseID: warehouseID, ProductID: productID, At: at, Quantity: 8}, nil)
+
+	result, err := stockHistory.GetOnHandQuantity(context.Background(), warehouseID, productID, at)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8), result.Quantity)
+}
+
+func TestReconcileStock(t *testing.T) {
+	// t.Parallell()
+	stockHistory, repoStockHistory := stockHistory(t)
+
+	tests := []struct {
+		name  string
+		mock  func()
+		count int
+		err   error
+	}{
+		{
+			name: "success",
+			mock: func() {
+				repoStockHistory.EXPECT().
+					GetDiscrepancies(context.Background()).
+					Return([]*entity.StockDiscrepancy{{Quantity: 5, LedgerQuantity: 3}}, nil)
+			},
+			count: 1,
+			err:   nil,
+		},
+		{
+			name: "failed to get discrepancies",
+			mock: func() {
+				repoStockHistory.EXPECT().
+					GetDiscrepancies(context.Background()).
+					Return(nil, errInternalServerError)
+			},
+			err: errInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			discrepancies, err := stockHistory.ReconcileStock(context.Background())
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, discrepancies, tc.count)
+		})
+	}
+}
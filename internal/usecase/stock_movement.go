@@ -17,18 +17,18 @@ func NewStockMovementUseCase(repoMovePostgre StockMovementPostgreRepo) *StockMov
 	}
 }
 
-func (u *StockMovementUseCase) GetAllStockMovements(ctx context.Context) ([]*entity.StockMovement, error) {
-	return u.repoMovePostgre.GetAll(ctx)
+func (u *StockMovementUseCase) GetAllStockMovements(ctx context.Context, filter entity.StockMovementFilter) ([]*entity.StockMovement, error) {
+	return u.repoMovePostgre.GetAll(ctx, filter)
 }
 
-func (u *StockMovementUseCase) GetStockMovementsByProductID(ctx context.Context, productID uuid.UUID) ([]*entity.StockMovement, error) {
-	return u.repoMovePostgre.GetByProductID(ctx, productID)
+func (u *StockMovementUseCase) GetStockMovementsByProductID(ctx context.Context, productID uuid.UUID, filter entity.StockMovementFilter) ([]*entity.StockMovement, error) {
+	return u.repoMovePostgre.GetByProductID(ctx, productID, filter)
 }
 
-func (u *StockMovementUseCase) GetStockMovementsBySourceID(ctx context.Context, sourceID uuid.UUID) ([]*entity.StockMovement, error) {
-	return u.repoMovePostgre.GetBySourceID(ctx, sourceID)
+func (u *StockMovementUseCase) GetStockMovementsBySourceID(ctx context.Context, sourceID uuid.UUID, filter entity.StockMovementFilter) ([]*entity.StockMovement, error) {
+	return u.repoMovePostgre.GetBySourceID(ctx, sourceID, filter)
 }
 
-func (u *StockMovementUseCase) GetStockMovementsByDestinationID(ctx context.Context, destinationID uuid.UUID) ([]*entity.StockMovement, error) {
-	return u.repoMovePostgre.GetByDestinationID(ctx, destinationID)
+func (u *StockMovementUseCase) GetStockMovementsByDestinationID(ctx context.Context, destinationID uuid.UUID, filter entity.StockMovementFilter) ([]*entity.StockMovement, error) {
+	return u.repoMovePostgre.GetByDestinationID(ctx, destinationID, filter)
 }
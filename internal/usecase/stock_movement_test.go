@@ -62,6 +62,7 @@ func stockMovement(t *testing.T) (*usecase.StockMovementUseCase, *MockStockMovem
 func TestGetAllStockMovements(t *testing.T) {
 	// allow this function run in parallel with other test function
 	// t.Parallell()
+	filter := entity.StockMovementFilter{MovementType: entity.MovementTypeSale}
 	stockMovement, repo := stockMovement(t)
 
 	tests := []TestStockMovement{
@@ -69,7 +70,7 @@ func TestGetAllStockMovements(t *testing.T) {
 			name: "success",
 			mock: func() {
 				repo.EXPECT().
-					GetAll(context.Background()).
+					GetAll(context.Background(), filter).
 					Return(mockStockMovements, nil)
 			},
 			res: mockStockMovements,
@@ -79,7 +80,7 @@ func TestGetAllStockMovements(t *testing.T) {
 			name: "error",
 			mock: func() {
 				repo.EXPECT().
-					GetAll(context.Background()).
+					GetAll(context.Background(), filter).
 					Return(nil, errInternalServerError)
 			},
 			res: nil,
@@ -95,7 +96,7 @@ func TestGetAllStockMovements(t *testing.T) {
 
 			tc.mock()
 
-			res, err := stockMovement.GetAllStockMovements(context.Background())
+			res, err := stockMovement.GetAllStockMovements(context.Background(), filter)
 
 			assert.Equal(t, tc.err, err)
 			if err == nil {
@@ -123,6 +124,7 @@ func TestGetByProductID(t *testing.T) {
 	// allow this function run in parallel with other test function
 	// t.Parallell()
 	productID := uuid.New()
+	filter := entity.StockMovementFilter{MovementType: entity.MovementTypeSale}
 	stockMovement, repo := stockMovement(t)
 
 	tests := []TestStockMovement{
@@ -130,7 +132,7 @@ func TestGetByProductID(t *testing.T) {
 			name: "success",
 			mock: func() {
 				repo.EXPECT().
-					GetByProductID(context.Background(), productID).
+					GetByProductID(context.Background(), productID, filter).
 					Return(mockStockMovements, nil)
 			},
 			res: mockStockMovements,
@@ -140,7 +142,7 @@ func TestGetByProductID(t *testing.T) {
 			name: "error",
 			mock: func() {
 				repo.EXPECT().
-					GetByProductID(context.Background(), productID).
+					GetByProductID(context.Background(), productID, filter).
 					Return(nil, errInternalServerError)
 			},
 			res: nil,
@@ -155,7 +157,7 @@ func TestGetByProductID(t *testing.T) {
 			// t.Parallell()
 
 			tc.mock()
-			res, err := stockMovement.GetStockMovementsByProductID(context.Background(), productID)
+			res, err := stockMovement.GetStockMovementsByProductID(context.Background(), productID, filter)
 
 			assert.Equal(t, tc.err, err)
 			if err == nil {
@@ -183,6 +185,7 @@ func TestGetStockMovementsBySourceID(t *testing.T) {
 	// allow this function run in parallel with other test function
 	// t.Parallell()
 	sourceID := uuid.New()
+	filter := entity.StockMovementFilter{MovementType: entity.MovementTypeSale}
 	stockMovement, repo := stockMovement(t)
 
 	tests := []TestStockMovement{
@@ -190,7 +193,7 @@ func TestGetStockMovementsBySourceID(t *testing.T) {
 			name: "success",
 			mock: func() {
 				repo.EXPECT().
-					GetBySourceID(context.Background(), sourceID).
+					GetBySourceID(context.Background(), sourceID, filter).
 					Return(mockStockMovements, nil)
 			},
 			res: mockStockMovements,
@@ -200,7 +203,7 @@ func TestGetStockMovementsBySourceID(t *testing.T) {
 			name: "error",
 			mock: func() {
 				repo.EXPECT().
-					GetBySourceID(context.Background(), sourceID).
+					GetBySourceID(context.Background(), sourceID, filter).
 					Return(nil, errInternalServerError)
 			},
 			res: nil,
@@ -215,7 +218,7 @@ func TestGetStockMovementsBySourceID(t *testing.T) {
 			// t.Parallell()
 
 			tc.mock()
-			res, err := stockMovement.GetStockMovementsBySourceID(context.Background(), sourceID)
+			res, err := stockMovement.GetStockMovementsBySourceID(context.Background(), sourceID, filter)
 
 			assert.Equal(t, tc.err, err)
 			if err == nil {
@@ -243,6 +246,7 @@ func TestGetStockMovementsByDestinationID(t *testing.T) {
 	// allow this function run in parallel with other test function
 	// t.Parallell()
 	destinationID := uuid.New()
+	filter := entity.StockMovementFilter{MovementType: entity.MovementTypeSale}
 	stockMovement, repo := stockMovement(t)
 
 	tests := []TestStockMovement{
@@ -250,7 +254,7 @@ func TestGetStockMovementsByDestinationID(t *testing.T) {
 			name: "success",
 			mock: func() {
 				repo.EXPECT().
-					GetByDestinationID(context.Background(), destinationID).
+					GetByDestinationID(context.Background(), destinationID, filter).
 					Return(mockStockMovements, nil)
 			},
 			res: mockStockMovements,
@@ -260,7 +264,7 @@ func TestGetStockMovementsByDestinationID(t *testing.T) {
 			name: "error",
 			mock: func() {
 				repo.EXPECT().
-					GetByDestinationID(context.Background(), destinationID).
+					GetByDestinationID(context.Background(), destinationID, filter).
 					Return(nil, errInternalServerError)
 			},
 			res: nil,
@@ -275,7 +279,7 @@ func TestGetStockMovementsByDestinationID(t *testing.T) {
 			// t.Parallell()
 
 			tc.mock()
-			res, err := stockMovement.GetStockMovementsByDestinationID(context.Background(), destinationID)
+			res, err := stockMovement.GetStockMovementsByDestinationID(context.Background(), destinationID, filter)
 
 			assert.Equal(t, tc.err, err)
 			if err == nil {
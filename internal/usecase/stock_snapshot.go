@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+)
+
+type StockSnapshotUseCase struct {
+	repoStockSnapshotPostgre StockSnapshotPostgreRepo
+}
+
+func NewStockSnapshotUseCase(repoStockSnapshotPostgre StockSnapshotPostgreRepo) *StockSnapshotUseCase {
+	return &StockSnapshotUseCase{
+		repoStockSnapshotPostgre,
+	}
+}
+
+// snapshot the quantity and value of every warehouse product for today, returns the number of snapshots
+func (u *StockSnapshotUseCase) CaptureStockSnapshot(ctx context.Context) (int64, error) {
+	now := time.Now()
+	return u.repoStockSnapshotPostgre.Capture(ctx, entity.SnapshotDate(now), now)
+}
+
+// total quantity and value of every day between the filter dates
+func (u *StockSnapshotUseCase) GetStockTrend(ctx context.Context, filter entity.StockSnapshotFilter) ([]*entity.StockTrendPoint, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	return u.repoStockSnapshotPostgre.GetTrend(ctx, filter)
+}
+
+// compare the snapshots of the from and to dates of the filter
+func (u *StockSnapshotUseCase) CompareStockSnapshots(ctx context.Context, filter entity.StockSnapshotFilter) (*entity.StockComparison, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	fromSnapshots, err := u.repoStockSnapshotPostgre.GetByDate(ctx, filter.From, filter.WarehouseID, filter.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshots of %s: %w", filter.From.Format(time.DateOnly), err)
+	}
+
+	toSnapshots, err := u.repoStockSnapshotPostgre.GetByDate(ctx, filter.To, filter.WarehouseID, filter.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshots of %s: %w", filter.To.Format(time.DateOnly), err)
+	}
+
+	return entity.CompareStockSnapshots(filter.From, filter.To, fromSnapshots, toSnapshots), nil
+}
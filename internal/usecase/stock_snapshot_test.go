@@ -0,0 +1,145 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func stockSnapshot(t *testing.T) (*usecase.StockSnapshotUseCase, *MockStockSnapshotPostgreRepo) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	repoStockSnapshot := NewMockStockSnapshotPostgreRepo(mockCtl)
+	stockSnapshot := usecase.NewStockSnapshotUseCase(repoStockSnapshot)
+
+	return stockSnapshot, repoStockSnapshot
+}
+
+func TestCaptureStockSnapshot(t *testing.T) {
+	// t.Parallell()
+	stockSnapshot, repoStockSnapshot := stockSnapshot(t)
+
+	repoStockSnapshot.EXPECT().
+		Capture(context.Background(), entity.SnapshotDate(time.Now()), gomock.Any()).
+		Return(int64(12), nil)
+
+	count, err := stockSnapshot.CaptureStockSnapshot(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), count)
+}
+
+func TestGetStockTrend(t *testing.T) {
+	// t.Parallell()
+	stockSnapshot, repoStockSnapshot := stockSnapshot(t)
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter entity.StockSnapshotFilter
+		mock   func()
+		err    error
+	}{
+		{
+			name:   "success",
+			filter: entity.StockSnapshotFilter{From: from, To: from.AddDate(0, 0, 7)},
+			mock: func() {
+				repoStockSnapshot.EXPECT().
+					GetTrend(context.Background(), gomock.Any()).
+					Return([]*entity.StockTrendPoint{{SnapshotDate: from, Quantity: 10}}, nil)
+			},
+			err: nil,
+		},
+		{
+			name:   "from after to",
+			filter: entity.StockSnapshotFilter{From: from, To: from.AddDate(0, 0, -1)},
+			mock:   func() {},
+			err:    entity.ErrInvalidStockSnapshot,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			trend, err := stockSnapshot.GetStockTrend(context.Background(), tc.filter)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, trend, 1)
+		})
+	}
+}
+
+func TestCompareStockSnapshots(t *testing.T) {
+	// t.Parallell()
+	stockSnapshot, repoStockSnapshot := stockSnapshot(t)
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	warehouseID := uuid.New()
+	productID := uuid.New()
+
+	tests := []struct {
+		name string
+		mock func()
+		err  error
+	}{
+		{
+			name: "success",
+			mock: func() {
+				repoStockSnapshot.EXPECT().
+					GetByDate(context.Background(), from, uuid.Nil, uuid.Nil).
+					Return([]*entity.StockSnapshot{{WarehouseID: warehouseID, ProductID: productID, Quantity: 10, Value: 50}}, nil)
+				repoStockSnapshot.EXPECT().
+					GetByDate(context.Background(), to, uuid.Nil, uuid.Nil).
+					Return([]*entity.StockSnapshot{{WarehouseID: warehouseID, ProductID: productID, Quantity: 4, Value: 20}}, nil)
+			},
+			err: nil,
+		},
+		{
+			name: "failed to get snapshots",
+			mock: func() {
+				repoStockSnapshot.EXPECT().
+					GetByDate(context.Background(), from, uuid.Nil, uuid.Nil).
+					Return(nil, errInternalServerError)
+			},
+			err: errInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			comparison, err := stockSnapshot.CompareStockSnapshots(context.Background(), entity.StockSnapshotFilter{From: from, To: to})
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, int64(-6), comparison.QuantityChange)
+			assert.Len(t, comparison.Lines, 1)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+)
+
+type StockThresholdUseCase struct {
+	repoStockThresholdPostgre StockThresholdPostgreRepo
+	repoWarehousePostgre      WarehousePostgreRepo
+	repoProductPostgre        WarehouseProductPostgreRepo
+}
+
+func NewStockThresholdUseCase(
+	repoStockThresholdPostgre StockThresholdPostgreRepo,
+	repoWarehousePostgre WarehousePostgreRepo,
+	repoProductPostgre WarehouseProductPostgreRepo,
+) *StockThresholdUseCase {
+	return &StockThresholdUseCase{
+		repoStockThresholdPostgre,
+		repoWarehousePostgre,
+		repoProductPostgre,
+	}
+}
+
+// create or replace the threshold of the product in the warehouse
+func (u *StockThresholdUseCase) SetStockThreshold(ctx context.Context, stockThreshold *entity.StockThreshold) error {
+	if err := stockThreshold.Validate(); err != nil {
+		return err
+	}
+
+	err := stockThreshold.GenerateStockThresholdID()
+	if err != nil {
+		return fmt.Errorf("failed to generate stock threshold id: %w", err)
+	}
+
+	stockThreshold.UpdatedAt = stockThreshold.CreatedAt
+	if err := u.repoStockThresholdPostgre.Save(ctx, stockThreshold); err != nil {
+		return fmt.Errorf("failed to save stock threshold: %w", err)
+	}
+
+	return nil
+}
+
+func (u *StockThresholdUseCase) GetStockThresholdsByWarehouseID(ctx context.Context, warehouseID uuid.UUID) ([]*entity.StockThreshold, error) {
+	return u.repoStockThresholdPostgre.GetByWarehouseID(ctx, warehouseID)
+}
+
+func (u *StockThresholdUseCase) DeleteStockThreshold(ctx context.Context, warehouseID, productID uuid.UUID) error {
+	if err := u.repoStockThresholdPostgre.Delete(ctx, warehouseID, productID); err != nil {
+		return fmt.Errorf("failed to delete stock threshold: %w", err)
+	}
+
+	return nil
+}
+
+// stock levels which reached their reorder point
+func (u *StockThresholdUseCase) GetLowStocks(ctx context.Context) ([]*entity.StockLevel, error) {
+	stockLevels, err := u.repoStockThresholdPostgre.GetStockLevels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock levels: %w", err)
+	}
+
+	var lowStocks []*entity.StockLevel
+	for _, stockLevel := range stockLevels {
+		if stockLevel.IsLow() {
+			lowStocks = append(lowStocks, stockLevel)
+		}
+	}
+
+	return lowStocks, nil
+}
+
+// publish stock-low event for each stock which became low, returns the number of new low stocks
+func (u *StockThresholdUseCase) EvaluateLowStocks(ctx context.Context) (int, error) {
+	newLowStocks, err := u.repoStockThresholdPostgre.EvaluateLowStocks(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to evaluate low stocks: %w", err)
+	}
+
+	return len(newLowStocks), nil
+}
+
+// propose transfers from the main warehouse to fill the gap of every low stock up to its target level
+func (u *StockThresholdUseCase) SuggestReplenishments(ctx context.Context) ([]*entity.ReplenishmentSuggestion, error) {
+	mainWarehouseID, err := u.repoWarehousePostgre.GetMainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get main warehouse id: %w", err)
+	}
+
+	lowStocks, err := u.GetLowStocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mainAvailable := make(map[uuid.UUID]int64)
+	for _, lowStock := range lowStocks {
+		if _, ok := mainAvailable[lowStock.ProductID]; ok || lowStock.WarehouseID == mainWarehouseID {
+			continue
+		}
+
+		warehouses, err := u.repoProductPostgre.GetWarehouseIDZipCodeAndQtyByProductID(ctx, lowStock.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get available quantity of product %s: %w", lowStock.ProductID, err)
+		}
+
+		mainAvailable[lowStock.ProductID] = 0
+		for _, warehouse := range warehouses {
+			if warehouse.WarehouseID == mainWarehouseID {
+				mainAvailable[lowStock.ProductID] = warehouse.ProductQuantity
+			}
+		}
+	}
+
+	return entity.SuggestReplenishments(mainWarehouseID, lowStocks, mainAvailable), nil
+}
@@ -0,0 +1,199 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func stockThreshold(t *testing.T) (*usecase.StockThresholdUseCase, *MockStockThresholdPostgreRepo, *MockWarehousePostgreRepo, *MockWarehouseProductPostgreRepo) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	repoStockThreshold := NewMockStockThresholdPostgreRepo(mockCtl)
+	repoWarehouse := NewMockWarehousePostgreRepo(mockCtl)
+	repoProduct := NewMockWarehouseProductPostgreRepo(mockCtl)
+	stockThreshold := usecase.NewStockThresholdUseCase(repoStockThreshold, repoWarehouse, repoProduct)
+
+	return stockThreshold, repoStockThreshold, repoWarehouse, repoProduct
+}
+
+func TestSetStockThreshold(t *testing.T) {
+	// t.Parallell()
+	stockThreshold, repoStockThreshold, _, _ := stockThreshold(t)
+
+	tests := []struct {
+		name        string
+		targetLevel int64
+		mock        func()
+		err         error
+	}{
+		{
+			name:        "success",
+			targetLevel: 20,
+			mock: func() {
+				repoStockThreshold.EXPECT().
+					Save(context.Background(), gomock.Any()).
+					Return(nil)
+			},
+			err: nil,
+		},
+		{
+			name:        "target level below reorder point",
+			targetLevel: 2,
+			mock:        func() {},
+			err:         entity.ErrInvalidStockThreshold,
+		},
+		{
+			name:        "failed to save",
+			targetLevel: 20,
+			mock: func() {
+				repoStockThreshold.EXPECT().
+					Save(context.Background(), gomock.Any()).
+					Return(errInternalServerError)
+			},
+			err: errInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			input := &entity.StockThreshold{
+				WarehouseID:  uuid.New(),
+				ProductID:    uuid.New(),
+				ReorderPoint: 5,
+				TargetLevel:  tc.targetLevel,
+				CreatedAt:    time.Now(),
+			}
+			err := stockThreshold.SetStockThreshold(context.Background(), input)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, input.ID)
+		})
+	}
+}
+
+func TestGetLowStocks(t *testing.T) {
+	// t.Parallell()
+	stockThreshold, repoStockThreshold, _, _ := stockThreshold(t)
+
+	repoStockThreshold.EXPECT().
+		GetStockLevels(context.Background()).
+		Return([]*entity.StockLevel{
+			{AvailableQuantity: 3, ReorderPoint: 5, TargetLevel: 10},
+			{AvailableQuantity: 8, ReorderPoint: 5, TargetLevel: 10},
+			{AvailableQuantity: 1, InTransitQuantity: 6, ReorderPoint: 5, TargetLevel: 10},
+		}, nil)
+
+	lowStocks, err := stockThreshold.GetLowStocks(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, lowStocks, 1)
+	assert.Equal(t, int64(3), lowStocks[0].AvailableQuantity)
+}
+
+func TestEvaluateLowStocks(t *testing.T) {
+	// t.Parallell()
+	stockThreshold, repoStockThreshold, _, _ := stockThreshold(t)
+
+	repoStockThreshold.EXPECT().
+		EvaluateLowStocks(context.Background(), gomock.Any()).
+		Return([]*entity.StockLevel{{}, {}}, nil)
+
+	count, err := stockThreshold.EvaluateLowStocks(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestSuggestReplenishments(t *testing.T) {
+	// t.Parallell()
+	stockThreshold, repoStockThreshold, repoWarehouse, repoProduct := stockThreshold(t)
+	mainWarehouseID := uuid.New()
+	warehouseID := uuid.New()
+	productID := uuid.New()
+
+	tests := []struct {
+		name     string
+		mock     func()
+		expected []*entity.ReplenishmentSuggestion
+		err      error
+	}{
+		{
+			name: "success",
+			mock: func() {
+				repoWarehouse.EXPECT().
+					GetMainID(context.Background()).
+					Return(mainWarehouseID, nil)
+
+				repoStockThreshold.EXPECT().
+					GetStockLevels(context.Background()).
+					Return([]*entity.StockLevel{
+						{WarehouseID: warehouseID, ProductID: productID, ProductName: "Product A", AvailableQuantity: 2, ReorderPoint: 5, TargetLevel: 10},
+					}, nil)
+
+				repoProduct.EXPECT().
+					GetWarehouseIDZipCodeAndQtyByProductID(context.Background(), productID).
+					Return([]*entity.WarehouseAddressAndProductQty{
+						{WarehouseID: mainWarehouseID, ProductID: productID, ProductQuantity: 50},
+						{WarehouseID: warehouseID, ProductID: productID, ProductQuantity: 2},
+					}, nil)
+			},
+			expected: []*entity.ReplenishmentSuggestion{
+				{
+					ProductID:       productID,
+					ProductName:     "Product A",
+					FromWarehouseID: mainWarehouseID,
+					ToWarehouseID:   warehouseID,
+					Quantity:        8,
+					Shortage:        8,
+				},
+			},
+			err: nil,
+		},
+		{
+			name: "failed to get main warehouse",
+			mock: func() {
+				repoWarehouse.EXPECT().
+					GetMainID(context.Background()).
+					Return(uuid.Nil, errInternalServerError)
+			},
+			err: errInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			suggestions, err := stockThreshold.SuggestReplenishments(context.Background())
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, suggestions)
+		})
+	}
+}
@@ -3,109 +3,211 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/idoyudha/eshop-warehouse/internal/entity"
 	"github.com/idoyudha/eshop-warehouse/internal/utils"
-	"github.com/idoyudha/eshop-warehouse/pkg/kafka"
 )
 
-const productQuantityUpdated = "product-quantity-updated"
-
+// product quantity updated events are saved to outbox by the repository
+// in the same transaction as the stock change, and published by the outbox relay
 type TransactionProductUseCase struct {
 	repoTransactionPostgre TransactionProductPostgresRepo
 	repoProductPostgre     WarehouseProductPostgreRepo
-	producer               *kafka.ProducerServer
+	distanceCalculator     utils.DistanceCalculator
+	allocationStrategy     utils.AllocationStrategy // used when the request does not choose a strategy
 }
 
 func NewTransactionProductUseCase(
 	repoTransactionPostgre TransactionProductPostgresRepo,
 	repoProductPostgre WarehouseProductPostgreRepo,
-	producer *kafka.ProducerServer,
+	distanceCalculator utils.DistanceCalculator,
+	allocationStrategy utils.AllocationStrategy,
 ) *TransactionProductUseCase {
 	return &TransactionProductUseCase{
 		repoTransactionPostgre,
 		repoProductPostgre,
-		producer,
+		distanceCalculator,
+		allocationStrategy,
 	}
 }
 
+// available quantity of the source warehouse is checked by the repository under the row lock,
+// so a retried request with the same idempotency key is not rejected after the first one moved the stock
 func (u *TransactionProductUseCase) MoveIn(ctx context.Context, stockMovement *entity.StockMovement) error {
 	err := stockMovement.GenerateStockMovementID()
 	if err != nil {
 		return err
 	}
 
-	warehouseProduct, err := u.repoProductPostgre.GetByProductIDAndWarehouseID(ctx, stockMovement.ProductID, stockMovement.FromWarehouseID)
+	return u.repoTransactionPostgre.TransferIn(ctx, stockMovement)
+}
+
+// move from warehouse to user
+// the source warehouses are allocated inside the repository transaction, after the product rows are locked.
+// the returned allocation plan explains the picked warehouses, it is nil when the request is a retry
+func (u *TransactionProductUseCase) MoveOut(ctx context.Context, stockMovementReq []*entity.StockMovement, zipCode string, strategyName string) ([]*entity.StockMovement, *entity.AllocationPlan, error) {
+	strategy, err := u.getAllocationStrategy(strategyName)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	if warehouseProduct.ProductQuantity < stockMovement.Quantity {
-		return fmt.Errorf("warehouse product quantity is not enough")
+	var plan *entity.AllocationPlan
+	allocate := func(warehouses []*entity.WarehouseAddressAndProductQty) ([]*entity.StockMovement, error) {
+		plan, err = strategy.Allocate(zipCode, stockMovementsToAllocationItems(stockMovementReq), warehouses)
+		if err != nil {
+			return nil, fmt.Errorf("failed to allocate warehouses: %w: %w", err, entity.ErrInvalidAllocation)
+		}
+		if len(plan.Shortages) > 0 {
+			return nil, &entity.InsufficientStockError{Items: plan.Shortages}
+		}
+
+		return allocationPlanToStockMovements(plan, stockMovementReq, warehouses)
 	}
 
-	return u.repoTransactionPostgre.TransferIn(ctx, stockMovement)
-}
+	stockMovements, err := u.repoTransactionPostgre.TransferOut(ctx, stockMovementReq, allocate)
+	if err != nil {
+		return nil, nil, err
+	}
 
-type kafkaProductQuantityUpdatedMessage struct {
-	ProductID uuid.UUID `json:"product_id"`
-	Quantity  int       `json:"quantity"`
+	return stockMovements, plan, nil
 }
 
-// move from warehouse to user
-func (u *TransactionProductUseCase) MoveOut(ctx context.Context, stockMovementReq []*entity.StockMovement, zipCode string) error {
-	var stockMovements []*entity.StockMovement
+// run the same allocation as MoveOut on the current available quantity,
+// nothing is locked or written so the result may differ from a later MoveOut
+func (u *TransactionProductUseCase) PreviewMoveOut(ctx context.Context, stockMovementReq []*entity.StockMovement, zipCode string, strategyName string) (*entity.AllocationPlan, error) {
+	strategy, err := u.getAllocationStrategy(strategyName)
+	if err != nil {
+		return nil, err
+	}
+
+	var warehouses []*entity.WarehouseAddressAndProductQty
+	seen := make(map[uuid.UUID]bool)
 	for _, stockMovement := range stockMovementReq {
-		totalProduct, err := u.repoProductPostgre.GetTotalQuantityOfProductInAllWarehouse(ctx, stockMovement.ProductID)
-		if err != nil {
-			return fmt.Errorf("failed to get total quantity of product in all warehouse: %w", err)
+		if seen[stockMovement.ProductID] {
+			continue
 		}
+		seen[stockMovement.ProductID] = true
 
-		if totalProduct < int(stockMovement.Quantity) {
-			return fmt.Errorf("product quantity is not enough")
-		}
-		// find the nearest warehouse and remaining product quantity for each warehouse
-		// TODO: can be improved if we get from in memory database (redis)
-		warehouses, err := u.repoProductPostgre.GetWarehouseIDZipCodeAndQtyByProductID(ctx, stockMovement.ProductID)
+		// product quantity of each warehouse already excludes quantity held by reservations
+		productWarehouses, err := u.repoProductPostgre.GetWarehouseIDZipCodeAndQtyByProductID(ctx, stockMovement.ProductID)
 		if err != nil {
-			return fmt.Errorf("failed to get warehouse id and zip code by product id: %w", err)
-		}
-		nearestWarehouseIDs, err := utils.FindNearestWarehouseWithQty(zipCode, warehouses, stockMovement.Quantity)
-		if err != nil {
-			return fmt.Errorf("failed to calculate nearest warehouse: %w", err)
+			return nil, fmt.Errorf("failed to get warehouse id and zip code by product id: %w", err)
 		}
+		warehouses = append(warehouses, productWarehouses...)
+	}
 
-		for warehouseID, quantity := range nearestWarehouseIDs {
-			var newStockMovement entity.StockMovement
-			err = newStockMovement.GenerateStockMovementID()
-			if err != nil {
-				return fmt.Errorf("failed to generate stock movement id: %w", err)
-			}
-			newStockMovement.ProductID = stockMovement.ProductID
-			newStockMovement.ProductName = stockMovement.ProductName
-			newStockMovement.Quantity = quantity
-			newStockMovement.FromWarehouseID = warehouseID
-			newStockMovement.ToUserID = stockMovement.ToUserID
-			newStockMovement.CreatedAt = stockMovement.CreatedAt
-			stockMovements = append(stockMovements, &newStockMovement)
-		}
+	plan, err := strategy.Allocate(zipCode, stockMovementsToAllocationItems(stockMovementReq), warehouses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate warehouses: %w: %w", err, entity.ErrInvalidAllocation)
+	}
+
+	return plan, nil
+}
+
+func (u *TransactionProductUseCase) getAllocationStrategy(name string) (utils.AllocationStrategy, error) {
+	if name == "" || name == u.allocationStrategy.Name() {
+		return u.allocationStrategy, nil
+	}
+
+	return utils.NewAllocationStrategy(name, u.distanceCalculator)
+}
 
-		message := kafkaProductQuantityUpdatedMessage{
+func stockMovementsToAllocationItems(stockMovements []*entity.StockMovement) []entity.AllocationItem {
+	items := make([]entity.AllocationItem, 0, len(stockMovements))
+	for _, stockMovement := range stockMovements {
+		items = append(items, entity.AllocationItem{
 			ProductID: stockMovement.ProductID,
-			Quantity:  totalProduct - int(stockMovement.Quantity),
+			Quantity:  stockMovement.Quantity,
+		})
+	}
+	return items
+}
+
+// create one stock movement for each allocation line,
+// user, time and idempotency key are the same for every requested item
+func allocationPlanToStockMovements(plan *entity.AllocationPlan, stockMovementReq []*entity.StockMovement, warehouses []*entity.WarehouseAddressAndProductQty) ([]*entity.StockMovement, error) {
+	if len(stockMovementReq) == 0 {
+		return nil, nil
+	}
+	request := stockMovementReq[0]
+
+	productNames := make(map[uuid.UUID]string)
+	for _, warehouse := range warehouses {
+		productNames[warehouse.ProductID] = warehouse.ProductName
+	}
+	for _, stockMovement := range stockMovementReq {
+		if stockMovement.ProductName != "" {
+			productNames[stockMovement.ProductID] = stockMovement.ProductName
 		}
+	}
 
-		err = u.producer.Produce(
-			productQuantityUpdated,
-			[]byte(stockMovement.ProductID.String()),
-			message,
-		)
+	stockMovements := make([]*entity.StockMovement, 0, len(plan.Allocations))
+	for _, line := range plan.Allocations {
+		var newStockMovement entity.StockMovement
+		err := newStockMovement.GenerateStockMovementID()
 		if err != nil {
-			// TODO: handle error, cancel the update if failed. or try use retry mechanism
-			return fmt.Errorf("failed to produce kafka message: %w", err)
+			return nil, fmt.Errorf("failed to generate stock movement id: %w", err)
 		}
+		newStockMovement.ProductID = line.ProductID
+		newStockMovement.ProductName = productNames[line.ProductID]
+		newStockMovement.Quantity = line.Quantity
+		newStockMovement.FromWarehouseID = line.WarehouseID
+		newStockMovement.ToUserID = request.ToUserID
+		newStockMovement.Reason = request.Reason
+		newStockMovement.ReferenceID = request.ReferenceID
+		newStockMovement.IdempotencyKey = request.IdempotencyKey
+		newStockMovement.CreatedAt = request.CreatedAt
+		stockMovements = append(stockMovements, &newStockMovement)
+	}
+
+	return stockMovements, nil
+}
+
+// move returned product quantity from user back to warehouse
+// product and user are taken from the original movement by the repository
+func (u *TransactionProductUseCase) MoveReturn(ctx context.Context, stockMovement *entity.StockMovement) error {
+	err := stockMovement.GenerateStockMovementID()
+	if err != nil {
+		return err
+	}
+
+	if stockMovement.Disposition == "" {
+		stockMovement.Disposition = entity.DispositionRestock
+	}
+	if stockMovement.Disposition != entity.DispositionRestock && stockMovement.Disposition != entity.DispositionQuarantine {
+		return fmt.Errorf("invalid disposition %q: %w", stockMovement.Disposition, entity.ErrReturnNotAllowed)
+	}
+
+	if err := u.repoTransactionPostgre.TransferReturn(ctx, stockMovement); err != nil {
+		return fmt.Errorf("failed to return stock movement: %w", err)
+	}
+
+	return nil
+}
+
+// move reserved product quantity from the reserved warehouses to user
+func (u *TransactionProductUseCase) CommitReservation(ctx context.Context, reservationID uuid.UUID) ([]*entity.StockMovement, error) {
+	stockMovements, err := u.repoTransactionPostgre.CommitReservation(ctx, reservationID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit reservation: %w", err)
+	}
+
+	return stockMovements, nil
+}
+
+// put the stock moved out for the reference, e.g. order id, back to the warehouses it was taken from.
+// it is used by the order saga when payment or shipping fails after the stock is moved out
+func (u *TransactionProductUseCase) CompensateMoveOut(ctx context.Context, referenceID string, reason string) ([]*entity.StockMovement, error) {
+	if referenceID == "" {
+		return nil, fmt.Errorf("reference id is required: %w", entity.ErrReversalNotAllowed)
+	}
+
+	reversals, err := u.repoTransactionPostgre.ReverseTransferOut(ctx, referenceID, reason, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to reverse stock movements: %w", err)
 	}
 
-	return u.repoTransactionPostgre.TransferOut(ctx, stockMovements)
+	return reversals, nil
 }
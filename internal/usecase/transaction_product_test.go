@@ -1,84 +1,489 @@
 package usecase_test
 
-// import (
-// 	"context"
-// 	"testing"
+import (
+	"context"
+	"testing"
+	"time"
 
-// 	"github.com/idoyudha/eshop-warehouse/internal/usecase"
-// 	"github.com/idoyudha/eshop-warehouse/pkg/kafka"
-// 	gomock "go.uber.org/mock/gomock"
-// )
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/idoyudha/eshop-warehouse/internal/utils"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
 
 type TestTransferProduct struct {
 	name string
 	mock func()
-	res  error
+	err  bool
 }
 
-// TODO: mock kafka producer, add to interface
-
-// type MockKafkaProducer struct {
-// 	mock *gomock.Controller
-// }
-
-// func NewMockKafkaProducer(mock *gomock.Controller) *MockKafkaProducer {
-// 	return &MockKafkaProducer{
-// 		mock: mock,
-// 	}
-// }
-
-// func (m *MockKafkaProducer) Produce(topic string, key []byte, value interface{}) error {
-// 	return nil
-// }
-
-// func transactionProduct(t *testing.T) (
-// 	*usecase.TransactionProductUseCase,
-// 	*MockWarehouseRankRedisRepo,
-// 	*MockTransactionProductPostgresRepo,
-// 	*MockWarehouseProductPostgreRepo,
-// 	*MockKafkaProducer,
-// ) {
-// 	t.Helper()
-
-// 	mockCtl := gomock.NewController(t)
-// 	defer mockCtl.Finish()
-
-// 	repoRedis := NewMockWarehouseRankRedisRepo(mockCtl)
-// 	repoTransactionPostgres := NewMockTransactionProductPostgresRepo(mockCtl)
-// 	repoProductPostgres := NewMockWarehouseProductPostgreRepo(mockCtl)
-// 	producer := NewMockKafkaProducer(mockCtl)
-
-// 	transactionProduct := usecase.NewTransactionProductUseCase(
-// 		repoRedis,
-// 		repoTransactionPostgres,
-// 		repoProductPostgres,
-// 		producer,
-// 	)
-
-// 	return transactionProduct, repoRedis, repoTransactionPostgres, repoProductPostgres, producer
-// }
-
-// func TestMovementIn(t *testing.T) {
-// 	// allow this function run in parallel with other test function
-// 	// t.Parallell()
-
-// 	tests := []TestTransferProduct{
-// 		{
-// 			name: "success",
-// 			mock: func(repo *MockTransactionProductPostgresRepo) {
-// 				repo.EXPECT().
-// 					TransferIn(context.Background(), mockStockMovements).
-// 					Return(mockStockMovements, nil)
-// 			},
-// 			res: nil,
-// 		},
-// 	}
-
-// 	for _, tc := range tests {
-// 		tc := tc
-// 		t.Run(tc.name, func(t *testing.T) {
-// 			// test case will run in parallel
-// 			// t.Parallell()
-// 		})
-// 	}
-// }
+func transactionProduct(t *testing.T) (
+	*usecase.TransactionProductUseCase,
+	*MockTransactionProductPostgresRepo,
+	*MockWarehouseProductPostgreRepo,
+) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	repoTransactionPostgres := NewMockTransactionProductPostgresRepo(mockCtl)
+	repoProductPostgres := NewMockWarehouseProductPostgreRepo(mockCtl)
+	allocationStrategy, err := utils.NewAllocationStrategy(utils.AllocationStrategyNearestFirst, utils.ZipCodeDistanceCalculator{})
+	assert.NoError(t, err)
+
+	transactionProduct := usecase.NewTransactionProductUseCase(
+		repoTransactionPostgres,
+		repoProductPostgres,
+		utils.ZipCodeDistanceCalculator{},
+		allocationStrategy,
+	)
+
+	return transactionProduct, repoTransactionPostgres, repoProductPostgres
+}
+
+func TestMoveIn(t *testing.T) {
+	// t.Parallell()
+	transactionProduct, repoTransaction, _ := transactionProduct(t)
+
+	input := &entity.StockMovement{
+		ProductID:       uuid.New(),
+		ProductName:     "Product A",
+		Quantity:        10,
+		FromWarehouseID: uuid.New(),
+		ToWarehouseID:   uuid.New(),
+		CreatedAt:       time.Now(),
+	}
+
+	tests := []TestTransferProduct{
+		{
+			name: "success",
+			mock: func() {
+				repoTransaction.EXPECT().
+					TransferIn(context.Background(), input).
+					Return(nil)
+			},
+			err: false,
+		},
+		{
+			name: "not enough quantity",
+			mock: func() {
+				repoTransaction.EXPECT().
+					TransferIn(context.Background(), input).
+					Return(&entity.InsufficientStockError{Items: []entity.InsufficientStockItem{
+						{ProductID: input.ProductID, Requested: 10, Available: 9},
+					}})
+			},
+			err: true,
+		},
+		{
+			name: "transfer error",
+			mock: func() {
+				repoTransaction.EXPECT().
+					TransferIn(context.Background(), input).
+					Return(errInternalServerError)
+			},
+			err: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			err := transactionProduct.MoveIn(context.Background(), input)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, input.ID)
+		})
+	}
+}
+
+func TestMoveOut(t *testing.T) {
+	// t.Parallell()
+	transactionProduct, repoTransaction, _ := transactionProduct(t)
+
+	productID := uuid.New()
+	userID := uuid.New()
+	nearWarehouseID := uuid.New()
+	farWarehouseID := uuid.New()
+	lockedWarehouses := func(nearQty, farQty int64) []*entity.WarehouseAddressAndProductQty {
+		return []*entity.WarehouseAddressAndProductQty{
+			{WarehouseID: nearWarehouseID, ProductID: productID, ZipCode: "10100", ProductName: "Product A", ProductQuantity: nearQty},
+			{WarehouseID: farWarehouseID, ProductID: productID, ZipCode: "19000", ProductName: "Product A", ProductQuantity: farQty},
+		}
+	}
+	input := []*entity.StockMovement{
+		{ProductID: productID, Quantity: 5, ToUserID: userID, IdempotencyKey: "order-1", CreatedAt: time.Now()},
+	}
+	type allocateFunc func([]*entity.WarehouseAddressAndProductQty) ([]*entity.StockMovement, error)
+
+	tests := []struct {
+		name     string
+		strategy string
+		mock     func()
+		expected map[uuid.UUID]int64
+		err      bool
+	}{
+		{
+			name:     "success with default strategy",
+			strategy: "",
+			mock: func() {
+				repoTransaction.EXPECT().
+					TransferOut(context.Background(), input, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ []*entity.StockMovement, allocate allocateFunc) ([]*entity.StockMovement, error) {
+						movements, err := allocate(lockedWarehouses(3, 10))
+						assert.NoError(t, err)
+						for _, movement := range movements {
+							assert.Equal(t, userID, movement.ToUserID)
+							assert.Equal(t, "Product A", movement.ProductName)
+							assert.Equal(t, "order-1", movement.IdempotencyKey)
+						}
+						return movements, nil
+					})
+			},
+			expected: map[uuid.UUID]int64{nearWarehouseID: 3, farWarehouseID: 2},
+			err:      false,
+		},
+		{
+			name:     "success with requested strategy",
+			strategy: utils.AllocationStrategyMinimizeWarehouses,
+			mock: func() {
+				repoTransaction.EXPECT().
+					TransferOut(context.Background(), input, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ []*entity.StockMovement, allocate allocateFunc) ([]*entity.StockMovement, error) {
+						return allocate(lockedWarehouses(3, 10))
+					})
+			},
+			expected: map[uuid.UUID]int64{farWarehouseID: 5},
+			err:      false,
+		},
+		{
+			name:     "insufficient stock under lock",
+			strategy: "",
+			mock: func() {
+				repoTransaction.EXPECT().
+					TransferOut(context.Background(), input, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ []*entity.StockMovement, allocate allocateFunc) ([]*entity.StockMovement, error) {
+						movements, err := allocate(lockedWarehouses(1, 3))
+
+						var insufficientStockErr *entity.InsufficientStockError
+						assert.ErrorAs(t, err, &insufficientStockErr)
+						assert.Equal(t, []entity.InsufficientStockItem{
+							{ProductID: productID, Requested: 5, Available: 4},
+						}, insufficientStockErr.Items)
+						return movements, err
+					})
+			},
+			err: true,
+		},
+		{
+			name:     "unknown strategy",
+			strategy: "random",
+			mock:     func() {},
+			err:      true,
+		},
+		{
+			name:     "failed to transfer out",
+			strategy: "",
+			mock: func() {
+				repoTransaction.EXPECT().
+					TransferOut(context.Background(), input, gomock.Any()).
+					Return(nil, errInternalServerError)
+			},
+			err: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			stockMovements, plan, err := transactionProduct.MoveOut(context.Background(), input, "10000", tc.strategy)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotNil(t, plan)
+			moved := make(map[uuid.UUID]int64)
+			for _, stockMovement := range stockMovements {
+				moved[stockMovement.FromWarehouseID] += stockMovement.Quantity
+			}
+			assert.Equal(t, tc.expected, moved)
+		})
+	}
+}
+
+func TestPreviewMoveOut(t *testing.T) {
+	// t.Parallell()
+	transactionProduct, _, repoProduct := transactionProduct(t)
+
+	productA := uuid.New()
+	productB := uuid.New()
+	warehouseID := uuid.New()
+	input := []*entity.StockMovement{
+		{ProductID: productA, Quantity: 2},
+		{ProductID: productB, Quantity: 5},
+		{ProductID: productA, Quantity: 1},
+	}
+
+	tests := []struct {
+		name     string
+		mock     func()
+		expected *entity.AllocationPlan
+		err      bool
+	}{
+		{
+			name: "success with shortages",
+			mock: func() {
+				repoProduct.EXPECT().
+					GetWarehouseIDZipCodeAndQtyByProductID(context.Background(), productA).
+					Return([]*entity.WarehouseAddressAndProductQty{
+						{WarehouseID: warehouseID, ProductID: productA, ZipCode: "10100", ProductQuantity: 3},
+					}, nil)
+				repoProduct.EXPECT().
+					GetWarehouseIDZipCodeAndQtyByProductID(context.Background(), productB).
+					Return([]*entity.WarehouseAddressAndProductQty{
+						{WarehouseID: warehouseID, ProductID: productB, ZipCode: "10100", ProductQuantity: 4},
+					}, nil)
+			},
+			expected: &entity.AllocationPlan{
+				Strategy: utils.AllocationStrategyNearestFirst,
+				Allocations: []entity.AllocationLine{
+					{ProductID: productA, WarehouseID: warehouseID, Quantity: 3, Distance: 100, Reason: "nearest warehouse with available quantity 3 at distance 100.0"},
+				},
+				Shipments: []entity.Shipment{
+					{WarehouseID: warehouseID, Distance: 100, Items: []entity.AllocationItem{{ProductID: productA, Quantity: 3}}},
+				},
+				TotalDistance: 100,
+				Shortages: []entity.InsufficientStockItem{
+					{ProductID: productB, Requested: 5, Available: 4},
+				},
+			},
+			err: false,
+		},
+		{
+			name: "failed to get warehouses",
+			mock: func() {
+				repoProduct.EXPECT().
+					GetWarehouseIDZipCodeAndQtyByProductID(context.Background(), productA).
+					Return(nil, errInternalServerError)
+			},
+			err: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			plan, err := transactionProduct.PreviewMoveOut(context.Background(), input, "10000", "")
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, plan)
+		})
+	}
+}
+
+func TestMoveReturn(t *testing.T) {
+	// t.Parallell()
+	transactionProduct, repoTransaction, _ := transactionProduct(t)
+
+	tests := []struct {
+		name        string
+		disposition string
+		mock        func()
+		expected    string
+		err         bool
+	}{
+		{
+			name:        "default disposition is restock",
+			disposition: "",
+			mock: func() {
+				repoTransaction.EXPECT().
+					TransferReturn(context.Background(), gomock.Any()).
+					Return(nil)
+			},
+			expected: entity.DispositionRestock,
+			err:      false,
+		},
+		{
+			name:        "quarantine",
+			disposition: entity.DispositionQuarantine,
+			mock: func() {
+				repoTransaction.EXPECT().
+					TransferReturn(context.Background(), gomock.Any()).
+					Return(nil)
+			},
+			expected: entity.DispositionQuarantine,
+			err:      false,
+		},
+		{
+			name:        "invalid disposition",
+			disposition: "lost",
+			mock:        func() {},
+			err:         true,
+		},
+		{
+			name:        "return error",
+			disposition: entity.DispositionRestock,
+			mock: func() {
+				repoTransaction.EXPECT().
+					TransferReturn(context.Background(), gomock.Any()).
+					Return(errInternalServerError)
+			},
+			err: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			input := &entity.StockMovement{
+				OriginalMovementID: uuid.New(),
+				Quantity:           1,
+				ToWarehouseID:      uuid.New(),
+				Disposition:        tc.disposition,
+				CreatedAt:          time.Now(),
+			}
+			err := transactionProduct.MoveReturn(context.Background(), input)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, input.ID)
+			assert.Equal(t, tc.expected, input.Disposition)
+		})
+	}
+}
+
+func TestCommitReservation(t *testing.T) {
+	// t.Parallell()
+	transactionProduct, repoTransaction, _ := transactionProduct(t)
+	reservationID := uuid.New()
+
+	tests := []TestTransferProduct{
+		{
+			name: "success",
+			mock: func() {
+				repoTransaction.EXPECT().
+					CommitReservation(context.Background(), reservationID, gomock.Any()).
+					Return(mockStockMovements, nil)
+			},
+			err: false,
+		},
+		{
+			name: "reservation expired",
+			mock: func() {
+				repoTransaction.EXPECT().
+					CommitReservation(context.Background(), reservationID, gomock.Any()).
+					Return(nil, errInternalServerError)
+			},
+			err: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			res, err := transactionProduct.CommitReservation(context.Background(), reservationID)
+			if tc.err {
+				assert.Error(t, err)
+				assert.Nil(t, res)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, mockStockMovements, res)
+		})
+	}
+}
+
+func TestCompensateMoveOut(t *testing.T) {
+	// t.Parallell()
+	transactionProduct, repoTransaction, _ := transactionProduct(t)
+
+	tests := []struct {
+		name        string
+		referenceID string
+		mock        func()
+		err         error
+	}{
+		{
+			name:        "success",
+			referenceID: "order-1",
+			mock: func() {
+				repoTransaction.EXPECT().
+					ReverseTransferOut(context.Background(), "order-1", "payment failed", gomock.Any()).
+					Return(mockStockMovements, nil)
+			},
+			err: nil,
+		},
+		{
+			name:        "missing reference id",
+			referenceID: "",
+			mock:        func() {},
+			err:         entity.ErrReversalNotAllowed,
+		},
+		{
+			name:        "failed to reverse",
+			referenceID: "order-1",
+			mock: func() {
+				repoTransaction.EXPECT().
+					ReverseTransferOut(context.Background(), "order-1", "payment failed", gomock.Any()).
+					Return(nil, errInternalServerError)
+			},
+			err: errInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			res, err := transactionProduct.CompensateMoveOut(context.Background(), tc.referenceID, "payment failed")
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.Nil(t, res)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, mockStockMovements, res)
+		})
+	}
+}
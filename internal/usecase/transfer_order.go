@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+)
+
+type TransferOrderUseCase struct {
+	repoTransferOrderPostgre TransferOrderPostgreRepo
+}
+
+func NewTransferOrderUseCase(repoTransferOrderPostgre TransferOrderPostgreRepo) *TransferOrderUseCase {
+	return &TransferOrderUseCase{
+		repoTransferOrderPostgre,
+	}
+}
+
+// request transfer order, nothing is moved until it is shipped
+func (u *TransferOrderUseCase) CreateTransferOrder(ctx context.Context, transferOrder *entity.TransferOrder) error {
+	if err := transferOrder.Validate(); err != nil {
+		return err
+	}
+
+	err := transferOrder.GenerateTransferOrderID()
+	if err != nil {
+		return fmt.Errorf("failed to generate transfer order id: %w", err)
+	}
+
+	for _, line := range transferOrder.Lines {
+		if err := line.GenerateTransferOrderLineID(); err != nil {
+			return fmt.Errorf("failed to generate transfer order line id: %w", err)
+		}
+		line.TransferOrderID = transferOrder.ID
+	}
+
+	transferOrder.Status = entity.TransferOrderStatusRequested
+	transferOrder.UpdatedBy = transferOrder.CreatedBy
+	transferOrder.UpdatedAt = transferOrder.CreatedAt
+
+	if err := u.repoTransferOrderPostgre.Save(ctx, transferOrder); err != nil {
+		return fmt.Errorf("failed to save transfer order: %w", err)
+	}
+
+	return nil
+}
+
+func (u *TransferOrderUseCase) GetTransferOrderByID(ctx context.Context, id uuid.UUID) (*entity.TransferOrder, error) {
+	return u.repoTransferOrderPostgre.GetByID(ctx, id)
+}
+
+func (u *TransferOrderUseCase) PickTransferOrder(ctx context.Context, transferOrder *entity.TransferOrder) error {
+	transferOrder.UpdatedAt = time.Now()
+	if err := u.repoTransferOrderPostgre.Pick(ctx, transferOrder); err != nil {
+		return fmt.Errorf("failed to pick transfer order: %w", err)
+	}
+
+	return nil
+}
+
+func (u *TransferOrderUseCase) ShipTransferOrder(ctx context.Context, transferOrder *entity.TransferOrder) error {
+	transferOrder.UpdatedAt = time.Now()
+	if err := u.repoTransferOrderPostgre.Ship(ctx, transferOrder); err != nil {
+		return fmt.Errorf("failed to ship transfer order: %w", err)
+	}
+
+	return nil
+}
+
+func (u *TransferOrderUseCase) ReceiveTransferOrder(ctx context.Context, transferOrder *entity.TransferOrder, delivery *entity.TransferOrderDelivery) error {
+	if err := delivery.Validate(); err != nil {
+		return err
+	}
+
+	transferOrder.UpdatedAt = time.Now()
+	if err := u.repoTransferOrderPostgre.Receive(ctx, transferOrder, delivery); err != nil {
+		return fmt.Errorf("failed to receive transfer order: %w", err)
+	}
+
+	return nil
+}
+
+func (u *TransferOrderUseCase) CancelTransferOrder(ctx context.Context, transferOrder *entity.TransferOrder) error {
+	transferOrder.UpdatedAt = time.Now()
+	if err := u.repoTransferOrderPostgre.Cancel(ctx, transferOrder); err != nil {
+		return fmt.Errorf("failed to cancel transfer order: %w", err)
+	}
+
+	return nil
+}
+
+// quantity of the product shipped by transfer orders but not received yet
+func (u *TransferOrderUseCase) GetInTransitByProductID(ctx context.Context, productID uuid.UUID) (*entity.ProductInTransit, error) {
+	transferOrders, err := u.repoTransferOrderPostgre.GetInTransitByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	productInTransit := &entity.ProductInTransit{
+		ProductID:      productID,
+		TransferOrders: transferOrders,
+	}
+	for _, transferOrder := range transferOrders {
+		productInTransit.Quantity += transferOrder.Quantity
+	}
+
+	return productInTransit, nil
+}
@@ -0,0 +1,201 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func transferOrder(t *testing.T) (*usecase.TransferOrderUseCase, *MockTransferOrderPostgreRepo) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	repoTransferOrder := NewMockTransferOrderPostgreRepo(mockCtl)
+	transferOrder := usecase.NewTransferOrderUseCase(repoTransferOrder)
+
+	return transferOrder, repoTransferOrder
+}
+
+func TestCreateTransferOrder(t *testing.T) {
+	// t.Parallell()
+	transferOrder, repoTransferOrder := transferOrder(t)
+	fromWarehouseID := uuid.New()
+
+	tests := []struct {
+		name          string
+		toWarehouseID uuid.UUID
+		mock          func()
+		err           error
+	}{
+		{
+			name:          "success",
+			toWarehouseID: uuid.New(),
+			mock: func() {
+				repoTransferOrder.EXPECT().
+					Save(context.Background(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, to *entity.TransferOrder) error {
+						assert.Equal(t, entity.TransferOrderStatusRequested, to.Status)
+						assert.Equal(t, to.CreatedBy, to.UpdatedBy)
+						for _, line := range to.Lines {
+							assert.NotEqual(t, uuid.Nil, line.ID)
+							assert.Equal(t, to.ID, line.TransferOrderID)
+						}
+						return nil
+					})
+			},
+			err: nil,
+		},
+		{
+			name:          "same warehouse",
+			toWarehouseID: fromWarehouseID,
+			mock:          func() {},
+			err:           entity.ErrInvalidTransferOrder,
+		},
+		{
+			name:          "product not in source warehouse",
+			toWarehouseID: uuid.New(),
+			mock: func() {
+				repoTransferOrder.EXPECT().
+					Save(context.Background(), gomock.Any()).
+					Return(entity.ErrInvalidTransferOrder)
+			},
+			err: entity.ErrInvalidTransferOrder,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			input := &entity.TransferOrder{
+				FromWarehouseID: fromWarehouseID,
+				ToWarehouseID:   tc.toWarehouseID,
+				Lines:           []*entity.TransferOrderLine{{ProductID: uuid.New(), Quantity: 3}},
+				CreatedBy:       uuid.New(),
+				CreatedAt:       time.Now(),
+			}
+			err := transferOrder.CreateTransferOrder(context.Background(), input)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, input.ID)
+		})
+	}
+}
+
+func TestShipTransferOrder(t *testing.T) {
+	// t.Parallell()
+	transferOrder, repoTransferOrder := transferOrder(t)
+	insufficientStockErr := &entity.InsufficientStockError{Items: []entity.InsufficientStockItem{{ProductID: uuid.New(), Requested: 3}}}
+
+	repoTransferOrder.EXPECT().
+		Ship(context.Background(), gomock.Any()).
+		Return(insufficientStockErr)
+
+	input := &entity.TransferOrder{ID: uuid.New()}
+	err := transferOrder.ShipTransferOrder(context.Background(), input)
+
+	var target *entity.InsufficientStockError
+	assert.ErrorAs(t, err, &target)
+	assert.False(t, input.UpdatedAt.IsZero())
+}
+
+func TestReceiveTransferOrder(t *testing.T) {
+	// t.Parallell()
+	transferOrder, repoTransferOrder := transferOrder(t)
+
+	tests := []struct {
+		name     string
+		delivery *entity.TransferOrderDelivery
+		mock     func()
+		err      error
+	}{
+		{
+			name:     "success",
+			delivery: &entity.TransferOrderDelivery{Lines: []*entity.TransferOrderDeliveryLine{{ProductID: uuid.New(), Quantity: 2}}},
+			mock: func() {
+				repoTransferOrder.EXPECT().
+					Receive(context.Background(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			err: nil,
+		},
+		{
+			name:     "close without lines",
+			delivery: &entity.TransferOrderDelivery{Close: true},
+			mock: func() {
+				repoTransferOrder.EXPECT().
+					Receive(context.Background(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			err: nil,
+		},
+		{
+			name:     "empty delivery",
+			delivery: &entity.TransferOrderDelivery{},
+			mock:     func() {},
+			err:      entity.ErrInvalidTransferOrder,
+		},
+		{
+			name:     "failed to receive",
+			delivery: &entity.TransferOrderDelivery{Lines: []*entity.TransferOrderDeliveryLine{{ProductID: uuid.New(), Quantity: 2}}},
+			mock: func() {
+				repoTransferOrder.EXPECT().
+					Receive(context.Background(), gomock.Any(), gomock.Any()).
+					Return(errInternalServerError)
+			},
+			err: errInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			err := transferOrder.ReceiveTransferOrder(context.Background(), &entity.TransferOrder{ID: uuid.New()}, tc.delivery)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGetInTransitByProductID(t *testing.T) {
+	// t.Parallell()
+	transferOrder, repoTransferOrder := transferOrder(t)
+	productID := uuid.New()
+
+	repoTransferOrder.EXPECT().
+		GetInTransitByProductID(context.Background(), productID).
+		Return([]*entity.TransferOrderInTransit{
+			{TransferOrderID: uuid.New(), Quantity: 3},
+			{TransferOrderID: uuid.New(), Quantity: 5},
+		}, nil)
+
+	res, err := transferOrder.GetInTransitByProductID(context.Background(), productID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, productID, res.ProductID)
+	assert.Equal(t, int64(8), res.Quantity)
+	assert.Len(t, res.TransferOrders, 2)
+}
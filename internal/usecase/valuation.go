@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+)
+
+type ValuationUseCase struct {
+	repoCostLayerPostgre CostLayerPostgreRepo
+}
+
+func NewValuationUseCase(repoCostLayerPostgre CostLayerPostgreRepo) *ValuationUseCase {
+	return &ValuationUseCase{
+		repoCostLayerPostgre,
+	}
+}
+
+// value the stock of the warehouse, FIFO is used when the method is not set
+func (u *ValuationUseCase) GetInventoryValuation(ctx context.Context, warehouseID uuid.UUID, method string) (*entity.InventoryValuation, error) {
+	if method == "" {
+		method = entity.ValuationMethodFIFO
+	}
+	if !entity.IsValidValuationMethod(method) {
+		return nil, fmt.Errorf("unknown valuation method %q: %w", method, entity.ErrInvalidValuation)
+	}
+
+	costs, err := u.repoCostLayerPostgre.GetProductCosts(ctx, warehouseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product costs: %w", err)
+	}
+
+	return entity.ValueInventory(warehouseID, method, costs, time.Now()), nil
+}
+
+// cost of the quantity sold in the period, FIFO is used when the method is not set
+func (u *ValuationUseCase) GetCostOfGoodsSold(ctx context.Context, period entity.CostOfGoodsSoldPeriod) (*entity.CostOfGoodsSold, error) {
+	if period.Method == "" {
+		period.Method = entity.ValuationMethodFIFO
+	}
+	if err := period.Validate(); err != nil {
+		return nil, err
+	}
+
+	sales, err := u.repoCostLayerPostgre.GetSalesCosts(ctx, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sales costs: %w", err)
+	}
+
+	return entity.CalculateCostOfGoodsSold(period, sales), nil
+}
@@ -0,0 +1,158 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func valuation(t *testing.T) (*usecase.ValuationUseCase, *MockCostLayerPostgreRepo) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	repoCostLayer := NewMockCostLayerPostgreRepo(mockCtl)
+	valuation := usecase.NewValuationUseCase(repoCostLayer)
+
+	return valuation, repoCostLayer
+}
+
+func TestGetInventoryValuation(t *testing.T) {
+	// t.Parallell()
+	valuation, repoCostLayer := valuation(t)
+	warehouseID := uuid.New()
+
+	tests := []struct {
+		name          string
+		method        string
+		mock          func()
+		expectedValue float64
+		err           error
+	}{
+		{
+			name:   "default method is fifo",
+			method: "",
+			mock: func() {
+				repoCostLayer.EXPECT().
+					GetProductCosts(context.Background(), warehouseID).
+					Return([]*entity.ProductCost{{RemainingQuantity: 15, RemainingCost: 250, ReceivedQuantity: 20, ReceivedCost: 300}}, nil)
+			},
+			expectedValue: 250,
+		},
+		{
+			name:   "weighted average",
+			method: entity.ValuationMethodWeightedAverage,
+			mock: func() {
+				repoCostLayer.EXPECT().
+					GetProductCosts(context.Background(), warehouseID).
+					Return([]*entity.ProductCost{{RemainingQuantity: 15, RemainingCost: 250, ReceivedQuantity: 20, ReceivedCost: 300}}, nil)
+			},
+			expectedValue: 225,
+		},
+		{
+			name:   "unknown method",
+			method: "lifo",
+			mock:   func() {},
+			err:    entity.ErrInvalidValuation,
+		},
+		{
+			name:   "failed to get product costs",
+			method: entity.ValuationMethodFIFO,
+			mock: func() {
+				repoCostLayer.EXPECT().
+					GetProductCosts(context.Background(), warehouseID).
+					Return(nil, errInternalServerError)
+			},
+			err: errInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			result, err := valuation.GetInventoryValuation(context.Background(), warehouseID, tc.method)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedValue, result.TotalValue)
+		})
+	}
+}
+
+func TestGetCostOfGoodsSold(t *testing.T) {
+	// t.Parallell()
+	valuation, repoCostLayer := valuation(t)
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		period       entity.CostOfGoodsSoldPeriod
+		mock         func()
+		expectedCost float64
+		err          error
+	}{
+		{
+			name:   "success",
+			period: entity.CostOfGoodsSoldPeriod{From: from, To: from.AddDate(0, 0, 30)},
+			mock: func() {
+				repoCostLayer.EXPECT().
+					GetSalesCosts(context.Background(), entity.CostOfGoodsSoldPeriod{
+						Method: entity.ValuationMethodFIFO,
+						From:   from,
+						To:     from.AddDate(0, 0, 30),
+					}).
+					Return([]*entity.ProductSalesCost{{SoldQuantity: 4, FIFOCost: 40}}, nil)
+			},
+			expectedCost: 40,
+		},
+		{
+			name:   "to before from",
+			period: entity.CostOfGoodsSoldPeriod{Method: entity.ValuationMethodFIFO, From: from, To: from.AddDate(0, 0, -1)},
+			mock:   func() {},
+			err:    entity.ErrInvalidValuation,
+		},
+		{
+			name:   "failed to get sales costs",
+			period: entity.CostOfGoodsSoldPeriod{Method: entity.ValuationMethodWeightedAverage, From: from, To: from},
+			mock: func() {
+				repoCostLayer.EXPECT().
+					GetSalesCosts(context.Background(), gomock.Any()).
+					Return(nil, errInternalServerError)
+			},
+			err: errInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			cogs, err := valuation.GetCostOfGoodsSold(context.Background(), tc.period)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCost, cogs.TotalCost)
+		})
+	}
+}
@@ -10,12 +10,14 @@ import (
 )
 
 type WarehouseUseCase struct {
-	repoPostgre WarehousePostgreRepo
+	repoPostgre        WarehousePostgreRepo
+	distanceCalculator utils.DistanceCalculator
 }
 
-func NewWarehouseUseCase(repoPostgre WarehousePostgreRepo) *WarehouseUseCase {
+func NewWarehouseUseCase(repoPostgre WarehousePostgreRepo, distanceCalculator utils.DistanceCalculator) *WarehouseUseCase {
 	return &WarehouseUseCase{
 		repoPostgre,
+		distanceCalculator,
 	}
 }
 
@@ -57,7 +59,7 @@ func (u *WarehouseUseCase) GetNearestWarehouse(ctx context.Context, zipCodes []s
 
 	result := make(map[string]string)
 	for _, zipCode := range zipCodes {
-		nearest, err := utils.FindNearestWarehouseByZipCode(zipCode, idAndZipCodes)
+		nearest, err := utils.FindNearestWarehouseByZipCode(u.distanceCalculator, zipCode, idAndZipCodes)
 		if err != nil {
 			return nil, err
 		}
@@ -10,12 +10,14 @@ import (
 )
 
 type WarehouseProductUseCase struct {
-	repoPostgre WarehouseProductPostgreRepo
+	repoPostgre        WarehouseProductPostgreRepo
+	distanceCalculator utils.DistanceCalculator
 }
 
-func NewWarehouseProductUseCase(repoPostgre WarehouseProductPostgreRepo) *WarehouseProductUseCase {
+func NewWarehouseProductUseCase(repoPostgre WarehouseProductPostgreRepo, distanceCalculator utils.DistanceCalculator) *WarehouseProductUseCase {
 	return &WarehouseProductUseCase{
 		repoPostgre,
+		distanceCalculator,
 	}
 }
 
@@ -31,9 +33,18 @@ func (u *WarehouseProductUseCase) UpdateWarehouseProduct(ctx context.Context, wa
 	return u.repoPostgre.Update(ctx, warehouseProduct)
 }
 
-// TODO: need to handle stock movement also if quantity is updated
-func (u *WarehouseProductUseCase) UpdateWarehouseProductQuantity(ctx context.Context, warehouseProduct *entity.WarehouseProduct) error {
-	return u.repoPostgre.UpdateProductQuantity(ctx, warehouseProduct)
+// adjust the product quantity of one warehouse by delta or counted quantity,
+// the adjustment is recorded as stock movement and the new total quantity is published
+func (u *WarehouseProductUseCase) AdjustWarehouseProductQuantity(ctx context.Context, stockAdjustment *entity.StockAdjustment) error {
+	if err := stockAdjustment.Validate(); err != nil {
+		return err
+	}
+
+	if err := u.repoPostgre.Adjust(ctx, stockAdjustment); err != nil {
+		return fmt.Errorf("failed to adjust product quantity: %w", err)
+	}
+
+	return nil
 }
 
 func (u *WarehouseProductUseCase) GetAllWarehouseProducts(ctx context.Context) ([]*entity.WarehouseProduct, error) {
@@ -58,7 +69,7 @@ func (u *WarehouseProductUseCase) GetNearestWarehouseZipCodeByProductID(ctx cont
 		return nil, fmt.Errorf("failed to get warehouse and zipcode data: %w", err)
 	}
 
-	zipCodeRes, err := utils.FindNearestWarehouseByProductID(zipCode, warehouse)
+	zipCodeRes, err := utils.FindNearestWarehouseByProductID(u.distanceCalculator, zipCode, warehouse)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find nearest warehouse: %w", err)
 	}
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/idoyudha/eshop-warehouse/internal/entity"
 	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/idoyudha/eshop-warehouse/internal/utils"
 	"github.com/stretchr/testify/assert"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -57,7 +58,7 @@ func warehouseProduct(t *testing.T) (*usecase.WarehouseProductUseCase, *MockWare
 	defer mockCtl.Finish()
 
 	repo := NewMockWarehouseProductPostgreRepo(mockCtl)
-	warehouseProduct := usecase.NewWarehouseProductUseCase(repo)
+	warehouseProduct := usecase.NewWarehouseProductUseCase(repo, utils.ZipCodeDistanceCalculator{})
 
 	return warehouseProduct, repo
 }
@@ -108,6 +109,91 @@ func TestCreateWarehouseProduct(t *testing.T) {
 	}
 }
 
+func TestAdjustWarehouseProductQuantity(t *testing.T) {
+	// t.Parallell()
+	warehouseProduct, repo := warehouseProduct(t)
+	counted := int64(5)
+
+	tests := []struct {
+		name  string
+		input *entity.StockAdjustment
+		mock  func()
+		err   error
+	}{
+		{
+			name: "success delta",
+			input: &entity.StockAdjustment{
+				ProductID:   uuid.New(),
+				WarehouseID: uuid.New(),
+				Delta:       -2,
+				Reason:      "damaged",
+			},
+			mock: func() {
+				repo.EXPECT().
+					Adjust(context.Background(), gomock.Any()).
+					Return(nil)
+			},
+			err: nil,
+		},
+		{
+			name: "success counted quantity",
+			input: &entity.StockAdjustment{
+				ProductID:       uuid.New(),
+				WarehouseID:     uuid.New(),
+				CountedQuantity: &counted,
+				Reason:          "shelf count",
+			},
+			mock: func() {
+				repo.EXPECT().
+					Adjust(context.Background(), gomock.Any()).
+					Return(nil)
+			},
+			err: nil,
+		},
+		{
+			name: "invalid adjustment",
+			input: &entity.StockAdjustment{
+				ProductID:   uuid.New(),
+				WarehouseID: uuid.New(),
+				Delta:       1,
+			},
+			mock: func() {},
+			err:  entity.ErrInvalidAdjustment,
+		},
+		{
+			name: "error",
+			input: &entity.StockAdjustment{
+				ProductID:   uuid.New(),
+				WarehouseID: uuid.New(),
+				Delta:       1,
+				Reason:      "found",
+			},
+			mock: func() {
+				repo.EXPECT().
+					Adjust(context.Background(), gomock.Any()).
+					Return(errInternalServerError)
+			},
+			err: errInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// t.Parallell()
+
+			tc.mock()
+
+			err := warehouseProduct.AdjustWarehouseProductQuantity(context.Background(), tc.input)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func TestGetAllWarehouseProducts(t *testing.T) {
 	// t.Parallell()
 	warehouseProduct, repo := warehouseProduct(t)
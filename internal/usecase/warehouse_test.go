@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/idoyudha/eshop-warehouse/internal/entity"
 	"github.com/idoyudha/eshop-warehouse/internal/usecase"
+	"github.com/idoyudha/eshop-warehouse/internal/utils"
 	"github.com/stretchr/testify/assert"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -51,7 +52,7 @@ func warehouse(t *testing.T) (*usecase.WarehouseUseCase, *MockWarehousePostgreRe
 	defer mockCtl.Finish()
 
 	repoPostgre := NewMockWarehousePostgreRepo(mockCtl)
-	warehouse := usecase.NewWarehouseUseCase(repoPostgre)
+	warehouse := usecase.NewWarehouseUseCase(repoPostgre, utils.ZipCodeDistanceCalculator{})
 
 	return warehouse, repoPostgre
 }
@@ -0,0 +1,551 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+)
+
+const (
+	AllocationStrategyNearestFirst       = "nearest-first"
+	AllocationStrategyMinimizeWarehouses = "minimize-warehouses"
+	AllocationStrategySingleWarehouse    = "single-warehouse"
+	AllocationStrategyBalanceStock       = "balance-stock"
+	AllocationStrategyMinimizeShipments  = "minimize-shipments"
+)
+
+type AllocationStrategy interface {
+	Name() string
+	// Allocate splits the requested items over the warehouses available quantity
+	Allocate(zipCode string, items []entity.AllocationItem, warehouses []*entity.WarehouseAddressAndProductQty) (*entity.AllocationPlan, error)
+}
+
+func NewAllocationStrategy(name string, calculator DistanceCalculator) (AllocationStrategy, error) {
+	switch name {
+	case AllocationStrategyNearestFirst:
+		return &NearestFirstStrategy{calculator}, nil
+	case AllocationStrategyMinimizeWarehouses:
+		return &MinimizeWarehousesStrategy{calculator}, nil
+	case AllocationStrategySingleWarehouse:
+		return &SingleWarehouseStrategy{calculator}, nil
+	case AllocationStrategyBalanceStock:
+		return &BalanceStockStrategy{calculator}, nil
+	case AllocationStrategyMinimizeShipments:
+		return &MinimizeShipmentsStrategy{calculator}, nil
+	default:
+		return nil, fmt.Errorf("unknown allocation strategy %q", name)
+	}
+}
+
+// NearestFirstStrategy drains the nearest warehouses first
+type NearestFirstStrategy struct {
+	calculator DistanceCalculator
+}
+
+func (s *NearestFirstStrategy) Name() string {
+	return AllocationStrategyNearestFirst
+}
+
+func (s *NearestFirstStrategy) Allocate(zipCode string, items []entity.AllocationItem, warehouses []*entity.WarehouseAddressAndProductQty) (*entity.AllocationPlan, error) {
+	allocation, err := prepareAllocation(s.calculator, zipCode, items, warehouses)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := allocation.plan(s.Name())
+	for _, item := range allocation.items {
+		candidates := allocation.sortedCandidates(item.ProductID, byDistance)
+		plan.Allocations = append(plan.Allocations, drain(item, candidates, func(c *warehouseCandidate) string {
+			return fmt.Sprintf("nearest warehouse with available quantity %d at distance %.1f", c.available, c.distance)
+		})...)
+	}
+
+	return plan.GroupShipments(), nil
+}
+
+// MinimizeWarehousesStrategy takes each item from as few warehouses as possible,
+// the nearest warehouse that has the whole item quantity is preferred
+type MinimizeWarehousesStrategy struct {
+	calculator DistanceCalculator
+}
+
+func (s *MinimizeWarehousesStrategy) Name() string {
+	return AllocationStrategyMinimizeWarehouses
+}
+
+func (s *MinimizeWarehousesStrategy) Allocate(zipCode string, items []entity.AllocationItem, warehouses []*entity.WarehouseAddressAndProductQty) (*entity.AllocationPlan, error) {
+	allocation, err := prepareAllocation(s.calculator, zipCode, items, warehouses)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := allocation.plan(s.Name())
+	for _, item := range allocation.items {
+		plan.Allocations = append(plan.Allocations, allocation.minimizeWarehouses(item, "")...)
+	}
+
+	return plan.GroupShipments(), nil
+}
+
+// SingleWarehouseStrategy prefers the nearest warehouse that can fulfill the whole order,
+// when there is none each item falls back to the minimize warehouses allocation
+type SingleWarehouseStrategy struct {
+	calculator DistanceCalculator
+}
+
+func (s *SingleWarehouseStrategy) Name() string {
+	return AllocationStrategySingleWarehouse
+}
+
+func (s *SingleWarehouseStrategy) Allocate(zipCode string, items []entity.AllocationItem, warehouses []*entity.WarehouseAddressAndProductQty) (*entity.AllocationPlan, error) {
+	allocation, err := prepareAllocation(s.calculator, zipCode, items, warehouses)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := allocation.plan(s.Name())
+	if len(allocation.items) == 0 {
+		return plan.GroupShipments(), nil
+	}
+
+	// count the items each warehouse can fulfill in full
+	fulfilled := make(map[uuid.UUID]int)
+	distances := make(map[uuid.UUID]float64)
+	for _, item := range allocation.items {
+		for _, candidate := range allocation.candidates[item.ProductID] {
+			if candidate.available >= item.Quantity {
+				fulfilled[candidate.warehouseID]++
+				distances[candidate.warehouseID] = candidate.distance
+			}
+		}
+	}
+
+	var selected *uuid.UUID
+	for warehouseID, count := range fulfilled {
+		if count != len(allocation.items) {
+			continue
+		}
+		if selected == nil || distances[warehouseID] < distances[*selected] ||
+			(distances[warehouseID] == distances[*selected] && warehouseID.String() < selected.String()) {
+			id := warehouseID
+			selected = &id
+		}
+	}
+
+	if selected == nil {
+		for _, item := range allocation.items {
+			plan.Allocations = append(plan.Allocations, allocation.minimizeWarehouses(item, "no single warehouse fulfills the whole order, ")...)
+		}
+		return plan.GroupShipments(), nil
+	}
+
+	for _, item := range allocation.items {
+		plan.Allocations = append(plan.Allocations, entity.AllocationLine{
+			ProductID:   item.ProductID,
+			WarehouseID: *selected,
+			Quantity:    item.Quantity,
+			Distance:    distances[*selected],
+			Reason:      fmt.Sprintf("nearest warehouse that fulfills the whole order at distance %.1f", distances[*selected]),
+		})
+	}
+
+	return plan.GroupShipments(), nil
+}
+
+// BalanceStockStrategy takes from the warehouses with the highest available quantity,
+// leveling their remaining quantity so stock stays balanced between warehouses
+type BalanceStockStrategy struct {
+	calculator DistanceCalculator
+}
+
+func (s *BalanceStockStrategy) Name() string {
+	return AllocationStrategyBalanceStock
+}
+
+func (s *BalanceStockStrategy) Allocate(zipCode string, items []entity.AllocationItem, warehouses []*entity.WarehouseAddressAndProductQty) (*entity.AllocationPlan, error) {
+	allocation, err := prepareAllocation(s.calculator, zipCode, items, warehouses)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := allocation.plan(s.Name())
+	for _, item := range allocation.items {
+		candidates := allocation.sortedCandidates(item.ProductID, byAvailable)
+		taken := levelDown(candidates, item.Quantity)
+		for i, candidate := range candidates {
+			if taken[i] == 0 {
+				continue
+			}
+			plan.Allocations = append(plan.Allocations, entity.AllocationLine{
+				ProductID:   item.ProductID,
+				WarehouseID: candidate.warehouseID,
+				Quantity:    taken[i],
+				Distance:    candidate.distance,
+				Reason:      fmt.Sprintf("highest available quantity %d, %d left after allocation", candidate.available, candidate.available-taken[i]),
+			})
+		}
+	}
+
+	return plan.GroupShipments(), nil
+}
+
+// MinimizeShipmentsStrategy allocates the whole order together instead of each item on its own,
+// it picks the fewest warehouses that can fulfill every item, then the nearest of those warehouses
+type MinimizeShipmentsStrategy struct {
+	calculator DistanceCalculator
+}
+
+func (s *MinimizeShipmentsStrategy) Name() string {
+	return AllocationStrategyMinimizeShipments
+}
+
+func (s *MinimizeShipmentsStrategy) Allocate(zipCode string, items []entity.AllocationItem, warehouses []*entity.WarehouseAddressAndProductQty) (*entity.AllocationPlan, error) {
+	allocation, err := prepareAllocation(s.calculator, zipCode, items, warehouses)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := allocation.plan(s.Name())
+	if len(allocation.items) == 0 {
+		return plan.GroupShipments(), nil
+	}
+
+	selected := allocation.fewestWarehouses()
+	for _, item := range allocation.items {
+		var candidates []*warehouseCandidate
+		for _, candidate := range allocation.sortedCandidates(item.ProductID, byDistance) {
+			if selected[candidate.warehouseID] {
+				candidates = append(candidates, candidate)
+			}
+		}
+		plan.Allocations = append(plan.Allocations, drain(item, candidates, func(c *warehouseCandidate) string {
+			return fmt.Sprintf("one of the %d warehouses fulfilling the whole order with the fewest shipments, distance %.1f", len(selected), c.distance)
+		})...)
+	}
+
+	return plan.GroupShipments(), nil
+}
+
+// above this number of warehouses the combinations are not enumerated
+const maxExactShipmentWarehouses = 16
+
+type shipmentWarehouse struct {
+	id        uuid.UUID
+	distance  float64
+	available map[uuid.UUID]int64
+}
+
+// choose the smallest set of warehouses that covers every item quantity,
+// sets of the same size are compared by total distance
+func (a *allocation) fewestWarehouses() map[uuid.UUID]bool {
+	byID := make(map[uuid.UUID]*shipmentWarehouse)
+	var warehouses []*shipmentWarehouse
+	for _, item := range a.items {
+		for _, candidate := range a.candidates[item.ProductID] {
+			warehouse, ok := byID[candidate.warehouseID]
+			if !ok {
+				warehouse = &shipmentWarehouse{
+					id:        candidate.warehouseID,
+					distance:  candidate.distance,
+					available: make(map[uuid.UUID]int64),
+				}
+				byID[candidate.warehouseID] = warehouse
+				warehouses = append(warehouses, warehouse)
+			}
+			warehouse.available[item.ProductID] += candidate.available
+		}
+	}
+	sort.SliceStable(warehouses, func(i, j int) bool {
+		if warehouses[i].distance != warehouses[j].distance {
+			return warehouses[i].distance < warehouses[j].distance
+		}
+		return warehouses[i].id.String() < warehouses[j].id.String()
+	})
+
+	var chosen []*shipmentWarehouse
+	if len(warehouses) <= maxExactShipmentWarehouses {
+		chosen = a.fewestWarehousesExact(warehouses)
+	} else {
+		chosen = a.fewestWarehousesGreedy(warehouses)
+	}
+
+	selected := make(map[uuid.UUID]bool, len(chosen))
+	for _, warehouse := range chosen {
+		selected[warehouse.id] = true
+	}
+	return selected
+}
+
+func (a *allocation) covers(warehouses []*shipmentWarehouse) bool {
+	for _, item := range a.items {
+		var available int64
+		for _, warehouse := range warehouses {
+			available += warehouse.available[item.ProductID]
+		}
+		if available < item.Quantity {
+			return false
+		}
+	}
+	return true
+}
+
+// try every combination from one warehouse up, the first size with a covering combination wins
+func (a *allocation) fewestWarehousesExact(warehouses []*shipmentWarehouse) []*shipmentWarehouse {
+	for size := 1; size <= len(warehouses); size++ {
+		var best []*shipmentWarehouse
+		bestDistance := 0.0
+		combination := make([]*shipmentWarehouse, 0, size)
+
+		var visit func(start int)
+		visit = func(start int) {
+			if len(combination) == size {
+				if !a.covers(combination) {
+					return
+				}
+				var distance float64
+				for _, warehouse := range combination {
+					distance += warehouse.distance
+				}
+				if best == nil || distance < bestDistance {
+					best = append([]*shipmentWarehouse(nil), combination...)
+					bestDistance = distance
+				}
+				return
+			}
+			for i := start; i <= len(warehouses)-(size-len(combination)); i++ {
+				combination = append(combination, warehouses[i])
+				visit(i + 1)
+				combination = combination[:len(combination)-1]
+			}
+		}
+		visit(0)
+
+		if best != nil {
+			return best
+		}
+	}
+
+	return warehouses
+}
+
+// repeatedly take the warehouse covering most of the remaining quantity, the nearest on a tie
+func (a *allocation) fewestWarehousesGreedy(warehouses []*shipmentWarehouse) []*shipmentWarehouse {
+	remaining := make(map[uuid.UUID]int64, len(a.items))
+	for _, item := range a.items {
+		remaining[item.ProductID] = item.Quantity
+	}
+
+	var chosen []*shipmentWarehouse
+	used := make(map[uuid.UUID]bool)
+	for !a.covers(chosen) {
+		var best *shipmentWarehouse
+		var bestCovered int64
+		for _, warehouse := range warehouses {
+			if used[warehouse.id] {
+				continue
+			}
+			var covered int64
+			for productID, quantity := range remaining {
+				covered += min(warehouse.available[productID], quantity)
+			}
+			// warehouses are sorted by distance, so only a larger coverage replaces the best
+			if covered > bestCovered {
+				best, bestCovered = warehouse, covered
+			}
+		}
+		if best == nil {
+			break
+		}
+
+		used[best.id] = true
+		chosen = append(chosen, best)
+		for productID := range remaining {
+			remaining[productID] -= min(best.available[productID], remaining[productID])
+		}
+	}
+
+	return chosen
+}
+
+// take the quantity from candidates sorted by available quantity descending,
+// the highest candidates are reduced to the same level before the lower ones are used
+func levelDown(candidates []*warehouseCandidate, quantity int64) []int64 {
+	taken := make([]int64, len(candidates))
+	if len(candidates) == 0 {
+		return taken
+	}
+
+	remaining := quantity
+	level := candidates[0].available
+	for k := 1; k <= len(candidates) && remaining > 0; k++ {
+		var next int64
+		if k < len(candidates) {
+			next = candidates[k].available
+		}
+
+		capacity := int64(k) * (level - next)
+		if capacity >= remaining {
+			share, extra := remaining/int64(k), remaining%int64(k)
+			for i := 0; i < k; i++ {
+				taken[i] += share
+				if int64(i) < extra {
+					taken[i]++
+				}
+			}
+			break
+		}
+
+		for i := 0; i < k; i++ {
+			taken[i] += level - next
+		}
+		remaining -= capacity
+		level = next
+	}
+
+	return taken
+}
+
+type warehouseCandidate struct {
+	warehouseID uuid.UUID
+	available   int64
+	distance    float64
+}
+
+type allocation struct {
+	items      []entity.AllocationItem
+	shortages  []entity.InsufficientStockItem
+	candidates map[uuid.UUID][]*warehouseCandidate
+}
+
+// merge items of the same product, calculate the distance of every warehouse
+// and separate the items that can not be fulfilled by all warehouses
+func prepareAllocation(calculator DistanceCalculator, zipCode string, items []entity.AllocationItem, warehouses []*entity.WarehouseAddressAndProductQty) (*allocation, error) {
+	locations := make([]Location, 0, len(warehouses))
+	for _, warehouse := range warehouses {
+		locations = append(locations, Location{
+			ZipCode:   warehouse.ZipCode,
+			Latitude:  warehouse.Latitude,
+			Longitude: warehouse.Longitude,
+		})
+	}
+
+	distances, err := calculateDistances(calculator, zipCode, locations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate distance: %w", err)
+	}
+
+	a := &allocation{
+		candidates: make(map[uuid.UUID][]*warehouseCandidate),
+	}
+	available := make(map[uuid.UUID]int64)
+	for i, warehouse := range warehouses {
+		// held quantity can be more than product quantity
+		if warehouse.ProductQuantity <= 0 {
+			continue
+		}
+		a.candidates[warehouse.ProductID] = append(a.candidates[warehouse.ProductID], &warehouseCandidate{
+			warehouseID: warehouse.WarehouseID,
+			available:   warehouse.ProductQuantity,
+			distance:    distances[i],
+		})
+		available[warehouse.ProductID] += warehouse.ProductQuantity
+	}
+
+	index := make(map[uuid.UUID]int)
+	var merged []entity.AllocationItem
+	for _, item := range items {
+		if i, ok := index[item.ProductID]; ok {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+		index[item.ProductID] = len(merged)
+		merged = append(merged, item)
+	}
+
+	for _, item := range merged {
+		if available[item.ProductID] < item.Quantity {
+			a.shortages = append(a.shortages, entity.InsufficientStockItem{
+				ProductID: item.ProductID,
+				Requested: item.Quantity,
+				Available: available[item.ProductID],
+			})
+			continue
+		}
+		a.items = append(a.items, item)
+	}
+
+	return a, nil
+}
+
+func (a *allocation) plan(strategy string) *entity.AllocationPlan {
+	return &entity.AllocationPlan{
+		Strategy:  strategy,
+		Shortages: a.shortages,
+	}
+}
+
+func byDistance(a, b *warehouseCandidate) bool {
+	if a.distance != b.distance {
+		return a.distance < b.distance
+	}
+	return a.available > b.available
+}
+
+func byAvailable(a, b *warehouseCandidate) bool {
+	if a.available != b.available {
+		return a.available > b.available
+	}
+	return a.distance < b.distance
+}
+
+func (a *allocation) sortedCandidates(productID uuid.UUID, less func(a, b *warehouseCandidate) bool) []*warehouseCandidate {
+	candidates := append([]*warehouseCandidate(nil), a.candidates[productID]...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return less(candidates[i], candidates[j])
+	})
+	return candidates
+}
+
+// take the item from the nearest warehouse having the whole quantity,
+// otherwise from the warehouses with the highest available quantity
+func (a *allocation) minimizeWarehouses(item entity.AllocationItem, reasonPrefix string) []entity.AllocationLine {
+	for _, candidate := range a.sortedCandidates(item.ProductID, byDistance) {
+		if candidate.available >= item.Quantity {
+			return []entity.AllocationLine{{
+				ProductID:   item.ProductID,
+				WarehouseID: candidate.warehouseID,
+				Quantity:    item.Quantity,
+				Distance:    candidate.distance,
+				Reason:      fmt.Sprintf("%snearest warehouse that fulfills the whole item at distance %.1f", reasonPrefix, candidate.distance),
+			}}
+		}
+	}
+
+	return drain(item, a.sortedCandidates(item.ProductID, byAvailable), func(c *warehouseCandidate) string {
+		return fmt.Sprintf("%slargest available quantity %d", reasonPrefix, c.available)
+	})
+}
+
+// take the item quantity from the candidates in order until it is fulfilled
+func drain(item entity.AllocationItem, candidates []*warehouseCandidate, reason func(*warehouseCandidate) string) []entity.AllocationLine {
+	var lines []entity.AllocationLine
+	remaining := item.Quantity
+	for _, candidate := range candidates {
+		if remaining == 0 {
+			break
+		}
+		quantity := min(candidate.available, remaining)
+		lines = append(lines, entity.AllocationLine{
+			ProductID:   item.ProductID,
+			WarehouseID: candidate.warehouseID,
+			Quantity:    quantity,
+			Distance:    candidate.distance,
+			Reason:      reason(candidate),
+		})
+		remaining -= quantity
+	}
+
+	return lines
+}
@@ -0,0 +1,283 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocationStrategy(t *testing.T) {
+	// t.Parallell()
+	productA := uuid.New()
+	productB := uuid.New()
+	nearID := uuid.New()
+	middleID := uuid.New()
+	farID := uuid.New()
+
+	// distance from zip code 10000: near 100, middle 2000, far 9000
+	warehouses := []*entity.WarehouseAddressAndProductQty{
+		{WarehouseID: nearID, ProductID: productA, ZipCode: "10100", ProductQuantity: 3},
+		{WarehouseID: middleID, ProductID: productA, ZipCode: "12000", ProductQuantity: 6},
+		{WarehouseID: farID, ProductID: productA, ZipCode: "19000", ProductQuantity: 10},
+		{WarehouseID: nearID, ProductID: productB, ZipCode: "10100", ProductQuantity: 1},
+		{WarehouseID: farID, ProductID: productB, ZipCode: "19000", ProductQuantity: 4},
+	}
+
+	type allocated struct {
+		productID   uuid.UUID
+		warehouseID uuid.UUID
+	}
+
+	tests := []struct {
+		name      string
+		strategy  string
+		items     []entity.AllocationItem
+		expected  map[allocated]int64
+		shortages []entity.InsufficientStockItem
+	}{
+		{
+			name:     "nearest first drains the nearest warehouses",
+			strategy: AllocationStrategyNearestFirst,
+			items:    []entity.AllocationItem{{ProductID: productA, Quantity: 5}},
+			expected: map[allocated]int64{
+				{productA, nearID}:   3,
+				{productA, middleID}: 2,
+			},
+		},
+		{
+			name:     "minimize warehouses prefers the nearest warehouse with the whole item",
+			strategy: AllocationStrategyMinimizeWarehouses,
+			items:    []entity.AllocationItem{{ProductID: productA, Quantity: 5}},
+			expected: map[allocated]int64{
+				{productA, middleID}: 5,
+			},
+		},
+		{
+			name:     "minimize warehouses takes the largest quantity first",
+			strategy: AllocationStrategyMinimizeWarehouses,
+			items:    []entity.AllocationItem{{ProductID: productA, Quantity: 14}},
+			expected: map[allocated]int64{
+				{productA, farID}:    10,
+				{productA, middleID}: 4,
+			},
+		},
+		{
+			name:     "single warehouse fulfills the whole order",
+			strategy: AllocationStrategySingleWarehouse,
+			items: []entity.AllocationItem{
+				{ProductID: productA, Quantity: 2},
+				{ProductID: productB, Quantity: 2},
+			},
+			expected: map[allocated]int64{
+				{productA, farID}: 2,
+				{productB, farID}: 2,
+			},
+		},
+		{
+			name:     "single warehouse falls back to minimize warehouses",
+			strategy: AllocationStrategySingleWarehouse,
+			items: []entity.AllocationItem{
+				{ProductID: productA, Quantity: 11},
+				{ProductID: productB, Quantity: 1},
+			},
+			expected: map[allocated]int64{
+				{productA, farID}:    10,
+				{productA, middleID}: 1,
+				{productB, nearID}:   1,
+			},
+		},
+		{
+			name:     "balance stock levels the highest warehouses",
+			strategy: AllocationStrategyBalanceStock,
+			items:    []entity.AllocationItem{{ProductID: productA, Quantity: 9}},
+			expected: map[allocated]int64{
+				{productA, farID}:    7,
+				{productA, middleID}: 2,
+			},
+		},
+		{
+			name:     "minimize shipments uses one warehouse for the whole basket",
+			strategy: AllocationStrategyMinimizeShipments,
+			items: []entity.AllocationItem{
+				{ProductID: productA, Quantity: 1},
+				{ProductID: productB, Quantity: 1},
+			},
+			expected: map[allocated]int64{
+				{productA, nearID}: 1,
+				{productB, nearID}: 1,
+			},
+		},
+		{
+			name:     "minimize shipments prefers the nearest combination of the same size",
+			strategy: AllocationStrategyMinimizeShipments,
+			items: []entity.AllocationItem{
+				{ProductID: productA, Quantity: 12},
+				{ProductID: productB, Quantity: 1},
+			},
+			expected: map[allocated]int64{
+				{productA, nearID}: 3,
+				{productA, farID}:  9,
+				{productB, nearID}: 1,
+			},
+		},
+		{
+			name:     "minimize shipments beats per item nearest",
+			strategy: AllocationStrategyMinimizeShipments,
+			items: []entity.AllocationItem{
+				{ProductID: productA, Quantity: 4},
+				{ProductID: productB, Quantity: 3},
+			},
+			expected: map[allocated]int64{
+				{productA, farID}: 4,
+				{productB, farID}: 3,
+			},
+		},
+		{
+			name:     "items of the same product are merged",
+			strategy: AllocationStrategyNearestFirst,
+			items: []entity.AllocationItem{
+				{ProductID: productB, Quantity: 1},
+				{ProductID: productB, Quantity: 2},
+			},
+			expected: map[allocated]int64{
+				{productB, nearID}: 1,
+				{productB, farID}:  2,
+			},
+		},
+		{
+			name:     "shortage is not allocated",
+			strategy: AllocationStrategyNearestFirst,
+			items: []entity.AllocationItem{
+				{ProductID: productA, Quantity: 1},
+				{ProductID: productB, Quantity: 6},
+			},
+			expected: map[allocated]int64{
+				{productA, nearID}: 1,
+			},
+			shortages: []entity.InsufficientStockItem{
+				{ProductID: productB, Requested: 6, Available: 5},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+			strategy, err := NewAllocationStrategy(tt.strategy, ZipCodeDistanceCalculator{})
+			assert.NoError(t, err)
+
+			plan, err := strategy.Allocate("10000", tt.items, warehouses)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.strategy, plan.Strategy)
+			assert.Equal(t, tt.shortages, plan.Shortages)
+
+			result := make(map[allocated]int64)
+			for _, line := range plan.Allocations {
+				assert.NotEmpty(t, line.Reason)
+				result[allocated{line.ProductID, line.WarehouseID}] += line.Quantity
+			}
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestNewAllocationStrategyUnknown(t *testing.T) {
+	strategy, err := NewAllocationStrategy("random", ZipCodeDistanceCalculator{})
+
+	assert.Error(t, err)
+	assert.Nil(t, strategy)
+}
+
+func TestLevelDown(t *testing.T) {
+	// t.Parallell()
+	candidates := []*warehouseCandidate{
+		{available: 10},
+		{available: 6},
+		{available: 3},
+	}
+
+	tests := []struct {
+		name     string
+		quantity int64
+		expected []int64
+	}{
+		{name: "only the highest warehouse", quantity: 4, expected: []int64{4, 0, 0}},
+		{name: "two warehouses leveled", quantity: 7, expected: []int64{6, 1, 0}},
+		{name: "all warehouses leveled", quantity: 14, expected: []int64{9, 4, 1}},
+		{name: "all quantity", quantity: 19, expected: []int64{10, 6, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+			assert.Equal(t, tt.expected, levelDown(candidates, tt.quantity))
+		})
+	}
+}
+
+func TestMinimizeShipmentsShipments(t *testing.T) {
+	// t.Parallell()
+	productA := uuid.New()
+	productB := uuid.New()
+	nearID := uuid.New()
+	farID := uuid.New()
+
+	warehouses := []*entity.WarehouseAddressAndProductQty{
+		{WarehouseID: nearID, ProductID: productA, ZipCode: "10100", ProductQuantity: 2},
+		{WarehouseID: farID, ProductID: productA, ZipCode: "19000", ProductQuantity: 5},
+		{WarehouseID: farID, ProductID: productB, ZipCode: "19000", ProductQuantity: 5},
+	}
+
+	strategy, err := NewAllocationStrategy(AllocationStrategyMinimizeShipments, ZipCodeDistanceCalculator{})
+	assert.NoError(t, err)
+
+	plan, err := strategy.Allocate("10000", []entity.AllocationItem{
+		{ProductID: productA, Quantity: 3},
+		{ProductID: productB, Quantity: 2},
+	}, warehouses)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Shipment{
+		{
+			WarehouseID: farID,
+			Distance:    9000,
+			Items: []entity.AllocationItem{
+				{ProductID: productA, Quantity: 3},
+				{ProductID: productB, Quantity: 2},
+			},
+		},
+	}, plan.Shipments)
+}
+
+func TestMinimizeShipmentsGreedy(t *testing.T) {
+	// t.Parallell()
+	productA := uuid.New()
+	productB := uuid.New()
+
+	// more warehouses than the exact search handles, only the last one has both products
+	var warehouses []*entity.WarehouseAddressAndProductQty
+	for i := 0; i <= maxExactShipmentWarehouses; i++ {
+		warehouses = append(warehouses, &entity.WarehouseAddressAndProductQty{
+			WarehouseID: uuid.New(), ProductID: productA, ZipCode: "10100", ProductQuantity: 1,
+		})
+	}
+	bothID := uuid.New()
+	warehouses = append(warehouses,
+		&entity.WarehouseAddressAndProductQty{WarehouseID: bothID, ProductID: productA, ZipCode: "19000", ProductQuantity: 2},
+		&entity.WarehouseAddressAndProductQty{WarehouseID: bothID, ProductID: productB, ZipCode: "19000", ProductQuantity: 2},
+	)
+
+	strategy, err := NewAllocationStrategy(AllocationStrategyMinimizeShipments, ZipCodeDistanceCalculator{})
+	assert.NoError(t, err)
+
+	plan, err := strategy.Allocate("10000", []entity.AllocationItem{
+		{ProductID: productA, Quantity: 2},
+		{ProductID: productB, Quantity: 2},
+	}, warehouses)
+
+	assert.NoError(t, err)
+	assert.Len(t, plan.Shipments, 1)
+	assert.Equal(t, bothID, plan.Shipments[0].WarehouseID)
+}
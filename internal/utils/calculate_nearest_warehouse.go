@@ -2,102 +2,102 @@ package utils
 
 import (
 	"fmt"
+	"slices"
 	"sort"
-	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/idoyudha/eshop-warehouse/internal/entity"
 )
 
-func FindNearestWarehouseByProductID(zipCode string, warehouse []*entity.WarehouseAddressAndProductQty) (string, error) {
-	zipCodeNumber, err := strconv.Atoi(zipCode)
-	if err != nil {
-		return "", fmt.Errorf("invalid zipCodeNumber: %w", err)
-	}
-
-	type warehouseEntry struct {
-		zipCode  string
-		distance int
-	}
-	entries := make([]warehouseEntry, 0, len(warehouse))
+// find nearest warehouse of the product by distance from zipcode
+// returned the zipcode of the nearest warehouse
+func FindNearestWarehouseByProductID(calculator DistanceCalculator, zipCode string, warehouse []*entity.WarehouseAddressAndProductQty) (string, error) {
+	locations := make([]Location, 0, len(warehouse))
 	for _, warehouse := range warehouse {
-		warehouseZipCode, err := strconv.Atoi(warehouse.ZipCode)
-		if err != nil {
-			return "", fmt.Errorf("invalid warehouseZipCode: %w", err)
-		}
-		entries = append(entries, warehouseEntry{
-			zipCode:  warehouse.ZipCode,
-			distance: abs(zipCodeNumber - warehouseZipCode),
+		locations = append(locations, Location{
+			ZipCode:   warehouse.ZipCode,
+			Latitude:  warehouse.Latitude,
+			Longitude: warehouse.Longitude,
 		})
 	}
 
-	// sort by distance value of warehouseDistance
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].distance < entries[j].distance
-	})
+	return findNearestZipCode(calculator, zipCode, locations)
+}
+
+// find nearest warehouse by distance from zipcode
+// returned the zipcode of the nearest warehouse
+func FindNearestWarehouseByZipCode(calculator DistanceCalculator, zipCode string, warehouses []*entity.Warehouse) (string, error) {
+	locations := make([]Location, 0, len(warehouses))
+	for _, warehouse := range warehouses {
+		locations = append(locations, Location{
+			ZipCode:   warehouse.ZipCode,
+			Latitude:  warehouse.Latitude,
+			Longitude: warehouse.Longitude,
+		})
+	}
 
-	return entries[0].zipCode, nil
+	return findNearestZipCode(calculator, zipCode, locations)
 }
 
-func FindNearestWarehouseByZipCode(zipCode string, warehouses []*entity.Warehouse) (string, error) {
-	zipCodeNumber, err := strconv.Atoi(zipCode)
-	if err != nil {
-		return "", fmt.Errorf("invalid zipCodeNumber: %w", err)
+func findNearestZipCode(calculator DistanceCalculator, zipCode string, locations []Location) (string, error) {
+	if len(locations) == 0 {
+		return "", fmt.Errorf("no warehouse found")
 	}
 
-	type warehouseEntry struct {
-		zipCode  string
-		distance int
+	distances, err := calculateDistances(calculator, zipCode, locations)
+	if err != nil {
+		return "", err
 	}
-	entries := make([]warehouseEntry, 0, len(warehouses))
-	for _, warehouse := range warehouses {
-		warehouseZipCode, err := strconv.Atoi(warehouse.ZipCode)
-		if err != nil {
-			return "", fmt.Errorf("invalid warehouseZipCode: %w", err)
+
+	nearest := 0
+	for i := range locations {
+		if distances[i] < distances[nearest] {
+			nearest = i
 		}
-		entries = append(entries, warehouseEntry{
-			zipCode:  warehouse.ZipCode,
-			distance: abs(zipCodeNumber - warehouseZipCode),
-		})
 	}
 
-	// sort by distance value of warehouseDistance
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].distance < entries[j].distance
+	return locations[nearest].ZipCode, nil
+}
+
+// find nearest warehouse by distance from zipcode
+// returned warehouse id with nearest distance ascending
+func FindNearestWarehouseWithQty(calculator DistanceCalculator, zipcode string, warehouses []*entity.WarehouseAddressAndProductQty, productID uuid.UUID, requestQty int64) (map[uuid.UUID]int64, error) {
+	// held quantity can be more than product quantity
+	warehouses = slices.DeleteFunc(slices.Clone(warehouses), func(warehouse *entity.WarehouseAddressAndProductQty) bool {
+		return warehouse.ProductQuantity <= 0
 	})
 
-	return entries[0].zipCode, nil
-}
+	locations := make([]Location, 0, len(warehouses))
+	for _, warehouse := range warehouses {
+		locations = append(locations, Location{
+			ZipCode:   warehouse.ZipCode,
+			Latitude:  warehouse.Latitude,
+			Longitude: warehouse.Longitude,
+		})
+	}
 
-// find nearest warehouse by zipcode
-// returned warehouse id with nearest distance (zipcode difference) ascending
-func FindNearestWarehouseWithQty(zipcode string, warehouses []*entity.WarehouseAddressAndProductQty, requestQty int64) (map[uuid.UUID]int64, error) {
-	zipCodeNumber, err := strconv.Atoi(zipcode)
+	distances, err := calculateDistances(calculator, zipcode, locations)
 	if err != nil {
-		return nil, fmt.Errorf("invalid zipCodeNumber: %w", err)
+		return nil, err
 	}
 
 	type warehouseEntry struct {
 		id       uuid.UUID
-		distance int
+		distance float64
 		quantity int64
 	}
 
 	entries := make([]warehouseEntry, 0, len(warehouses))
-	for _, warehouse := range warehouses {
-		warehouseZipCode, err := strconv.Atoi(warehouse.ZipCode)
-		if err != nil {
-			return nil, fmt.Errorf("invalid warehouseZipCode: %w", err)
-		}
+	for i, warehouse := range warehouses {
 		entries = append(entries, warehouseEntry{
 			id:       warehouse.WarehouseID,
-			distance: abs(zipCodeNumber - warehouseZipCode),
+			distance: distances[i],
 			quantity: warehouse.ProductQuantity,
 		})
 	}
 
 	// sort by distance value of warehouseDistance
-	sort.Slice(entries, func(i, j int) bool {
+	sort.SliceStable(entries, func(i, j int) bool {
 		return entries[i].distance < entries[j].distance
 	})
 
@@ -112,7 +112,11 @@ func FindNearestWarehouseWithQty(zipcode string, warehouses []*entity.WarehouseA
 	}
 
 	if countLeft < requestQty {
-		return nil, fmt.Errorf("not enough product quantity with total quantity %d", requestQty)
+		return nil, &entity.InsufficientStockError{Items: []entity.InsufficientStockItem{{
+			ProductID: productID,
+			Requested: requestQty,
+			Available: countLeft,
+		}}}
 	}
 
 	return result, nil
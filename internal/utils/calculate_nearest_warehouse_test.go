@@ -55,7 +55,7 @@ func TestFindNearestWarehouseByProductID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// t.Parallell()
-			result, err := FindNearestWarehouseByProductID(tt.zipCode, tt.warehouses)
+			result, err := FindNearestWarehouseByProductID(ZipCodeDistanceCalculator{}, tt.zipCode, tt.warehouses)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -73,6 +73,7 @@ func TestFindNearestWarehouseWithQty(t *testing.T) {
 	warehouse1ID := uuid.New()
 	warehouse2ID := uuid.New()
 	warehouse3ID := uuid.New()
+	productID := uuid.New()
 
 	tests := []struct {
 		name        string
@@ -130,12 +131,26 @@ func TestFindNearestWarehouseWithQty(t *testing.T) {
 			expected:    nil,
 			expectError: true,
 		},
+		{
+			name:    "warehouses without available quantity are skipped",
+			zipCode: "10000",
+			warehouses: []*entity.WarehouseAddressAndProductQty{
+				{WarehouseID: warehouse1ID, ZipCode: "10001", ProductQuantity: 0},
+				{WarehouseID: warehouse2ID, ZipCode: "10002", ProductQuantity: -3},
+				{WarehouseID: warehouse3ID, ZipCode: "13000", ProductQuantity: 5},
+			},
+			requestQty: 4,
+			expected: map[uuid.UUID]int64{
+				warehouse3ID: 4,
+			},
+			expectError: false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// t.Parallell()
-			result, err := FindNearestWarehouseWithQty(tt.zipCode, tt.warehouses, tt.requestQty)
+			result, err := FindNearestWarehouseWithQty(ZipCodeDistanceCalculator{}, tt.zipCode, tt.warehouses, productID, tt.requestQty)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -147,3 +162,17 @@ func TestFindNearestWarehouseWithQty(t *testing.T) {
 		})
 	}
 }
+
+func TestFindNearestWarehouseWithQtyInsufficientStock(t *testing.T) {
+	productID := uuid.New()
+	warehouses := []*entity.WarehouseAddressAndProductQty{
+		{WarehouseID: uuid.New(), ZipCode: "11000", ProductQuantity: 5},
+		{WarehouseID: uuid.New(), ZipCode: "12000", ProductQuantity: -2},
+	}
+
+	_, err := FindNearestWarehouseWithQty(ZipCodeDistanceCalculator{}, "10000", warehouses, productID, 10)
+
+	var insufficientStockErr *entity.InsufficientStockError
+	assert.ErrorAs(t, err, &insufficientStockErr)
+	assert.Equal(t, []entity.InsufficientStockItem{{ProductID: productID, Requested: 10, Available: 5}}, insufficientStockErr.Items)
+}
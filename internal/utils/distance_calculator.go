@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ErrLocationNotFound is returned by a DistanceCalculator when a location can not be resolved to coordinates
+var ErrLocationNotFound = errors.New("location not found")
+
+// Location is a warehouse or customer address,
+// latitude and longitude are nil when the coordinates are unknown
+type Location struct {
+	ZipCode   string
+	Latitude  *float64
+	Longitude *float64
+}
+
+type DistanceCalculator interface {
+	// Distance returns the distance from the zip code to the location
+	Distance(zipCode string, location Location) (float64, error)
+}
+
+// ZipCodeDistanceCalculator ranks by the difference of zip codes,
+// it is only used as a fallback when coordinates are not available
+type ZipCodeDistanceCalculator struct{}
+
+func (ZipCodeDistanceCalculator) Distance(zipCode string, location Location) (float64, error) {
+	zipCodeNumber, err := strconv.Atoi(zipCode)
+	if err != nil {
+		return 0, fmt.Errorf("invalid zipCodeNumber: %w", err)
+	}
+
+	locationZipCode, err := strconv.Atoi(location.ZipCode)
+	if err != nil {
+		return 0, fmt.Errorf("invalid warehouseZipCode: %w", err)
+	}
+
+	return float64(abs(zipCodeNumber - locationZipCode)), nil
+}
+
+// HaversineDistanceCalculator returns the great-circle distance in kilometers,
+// the zip code is resolved to coordinates using the postal codes dataset
+type HaversineDistanceCalculator struct {
+	postalCodes PostalCodes
+}
+
+func NewHaversineDistanceCalculator(postalCodes PostalCodes) *HaversineDistanceCalculator {
+	return &HaversineDistanceCalculator{
+		postalCodes,
+	}
+}
+
+func (c *HaversineDistanceCalculator) Distance(zipCode string, location Location) (float64, error) {
+	from, ok := c.postalCodes[zipCode]
+	if !ok {
+		return 0, fmt.Errorf("zip code %s: %w", zipCode, ErrLocationNotFound)
+	}
+
+	to, ok := c.coordinate(location)
+	if !ok {
+		return 0, fmt.Errorf("zip code %s: %w", location.ZipCode, ErrLocationNotFound)
+	}
+
+	return haversine(from, to), nil
+}
+
+// use the location coordinates if set, otherwise look up its zip code
+func (c *HaversineDistanceCalculator) coordinate(location Location) (Coordinate, bool) {
+	if location.Latitude != nil && location.Longitude != nil {
+		return Coordinate{Latitude: *location.Latitude, Longitude: *location.Longitude}, true
+	}
+
+	coordinate, ok := c.postalCodes[location.ZipCode]
+	return coordinate, ok
+}
+
+const earthRadiusKm = 6371.0
+
+func haversine(from, to Coordinate) float64 {
+	lat1 := from.Latitude * math.Pi / 180
+	lat2 := to.Latitude * math.Pi / 180
+	deltaLat := (to.Latitude - from.Latitude) * math.Pi / 180
+	deltaLon := (to.Longitude - from.Longitude) * math.Pi / 180
+
+	a := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(deltaLon/2)*math.Sin(deltaLon/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// calculate the distance from the zip code to every location,
+// if one of them can not be resolved all distances fall back to the zip code difference so they stay comparable
+func calculateDistances(calculator DistanceCalculator, zipCode string, locations []Location) ([]float64, error) {
+	distances := make([]float64, 0, len(locations))
+	for _, location := range locations {
+		distance, err := calculator.Distance(zipCode, location)
+		if errors.Is(err, ErrLocationNotFound) {
+			return calculateDistances(ZipCodeDistanceCalculator{}, zipCode, locations)
+		}
+		if err != nil {
+			return nil, err
+		}
+		distances = append(distances, distance)
+	}
+
+	return distances, nil
+}
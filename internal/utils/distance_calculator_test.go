@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/idoyudha/eshop-warehouse/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+var testPostalCodes = PostalCodes{
+	"10250": {Latitude: -6.2088, Longitude: 106.8176}, // Jakarta
+	"40111": {Latitude: -6.9147, Longitude: 107.6098}, // Bandung
+	"60111": {Latitude: -7.2575, Longitude: 112.7521}, // Surabaya
+	"20111": {Latitude: 3.5952, Longitude: 98.6722},   // Medan
+}
+
+func TestHaversineDistanceCalculator(t *testing.T) {
+	// t.Parallell()
+	calculator := NewHaversineDistanceCalculator(testPostalCodes)
+	latitude, longitude := -7.2575, 112.7521
+
+	tests := []struct {
+		name          string
+		zipCode       string
+		location      Location
+		expectedKm    float64
+		expectedError error
+	}{
+		{
+			name:       "zip code to zip code",
+			zipCode:    "10250",
+			location:   Location{ZipCode: "40111"},
+			expectedKm: 117,
+		},
+		{
+			name:       "location coordinate is used before its zip code",
+			zipCode:    "10250",
+			location:   Location{ZipCode: "99999", Latitude: &latitude, Longitude: &longitude},
+			expectedKm: 664,
+		},
+		{
+			name:       "same location",
+			zipCode:    "60111",
+			location:   Location{ZipCode: "60111"},
+			expectedKm: 0,
+		},
+		{
+			name:          "unknown zip code",
+			zipCode:       "99999",
+			location:      Location{ZipCode: "40111"},
+			expectedError: ErrLocationNotFound,
+		},
+		{
+			name:          "unknown location",
+			zipCode:       "10250",
+			location:      Location{ZipCode: "99999"},
+			expectedError: ErrLocationNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+			distance, err := calculator.Distance(tt.zipCode, tt.location)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedKm, distance, 5)
+		})
+	}
+}
+
+func TestFindNearestWarehouseWithQtyByHaversine(t *testing.T) {
+	// t.Parallell()
+	calculator := NewHaversineDistanceCalculator(testPostalCodes)
+	bandungID := uuid.New()
+	surabayaID := uuid.New()
+	medanID := uuid.New()
+
+	tests := []struct {
+		name       string
+		warehouses []*entity.WarehouseAddressAndProductQty
+		expected   map[uuid.UUID]int64
+	}{
+		{
+			// medan has the closest zip code number but it is the farthest from jakarta
+			name: "ranked by geographic distance",
+			warehouses: []*entity.WarehouseAddressAndProductQty{
+				{WarehouseID: medanID, ZipCode: "20111", ProductQuantity: 10},
+				{WarehouseID: surabayaID, ZipCode: "60111", ProductQuantity: 10},
+				{WarehouseID: bandungID, ZipCode: "40111", ProductQuantity: 3},
+			},
+			expected: map[uuid.UUID]int64{bandungID: 3, surabayaID: 2},
+		},
+		{
+			name: "fallback to zip code difference when a warehouse is unknown",
+			warehouses: []*entity.WarehouseAddressAndProductQty{
+				{WarehouseID: medanID, ZipCode: "20111", ProductQuantity: 10},
+				{WarehouseID: surabayaID, ZipCode: "69999", ProductQuantity: 10},
+			},
+			expected: map[uuid.UUID]int64{medanID: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+			result, err := FindNearestWarehouseWithQty(calculator, "10250", tt.warehouses, uuid.New(), 5)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type Coordinate struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// PostalCodes maps a zip code to its coordinate
+type PostalCodes map[string]Coordinate
+
+// load postal codes from csv with header zip_code,latitude,longitude
+func LoadPostalCodes(r io.Reader) (PostalCodes, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	// skip header
+	if _, err := reader.Read(); err != nil {
+		if errors.Is(err, io.EOF) {
+			return PostalCodes{}, nil
+		}
+		return nil, fmt.Errorf("failed to read postal codes header: %w", err)
+	}
+
+	postalCodes := make(PostalCodes)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read postal codes: %w", err)
+		}
+
+		latitude, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid latitude of zip code %s: %w", record[0], err)
+		}
+		longitude, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid longitude of zip code %s: %w", record[0], err)
+		}
+
+		postalCodes[strings.TrimSpace(record[0])] = Coordinate{
+			Latitude:  latitude,
+			Longitude: longitude,
+		}
+	}
+
+	return postalCodes, nil
+}
+
+func LoadPostalCodesFile(path string) (PostalCodes, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postal codes file: %w", err)
+	}
+	defer file.Close()
+
+	return LoadPostalCodes(file)
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadPostalCodes(t *testing.T) {
+	// t.Parallell()
+
+	tests := []struct {
+		name          string
+		csv           string
+		expected      PostalCodes
+		expectedError bool
+	}{
+		{
+			name: "valid postal codes",
+			csv:  "zip_code,latitude,longitude\n10250,-6.2088,106.8176\n40111, -6.9147, 107.6098\n",
+			expected: PostalCodes{
+				"10250": {Latitude: -6.2088, Longitude: 106.8176},
+				"40111": {Latitude: -6.9147, Longitude: 107.6098},
+			},
+			expectedError: false,
+		},
+		{
+			name:          "empty file",
+			csv:           "",
+			expected:      PostalCodes{},
+			expectedError: false,
+		},
+		{
+			name:          "invalid latitude",
+			csv:           "zip_code,latitude,longitude\n10250,abc,106.8176\n",
+			expectedError: true,
+		},
+		{
+			name:          "missing column",
+			csv:           "zip_code,latitude,longitude\n10250,-6.2088\n",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Parallell()
+			postalCodes, err := LoadPostalCodes(strings.NewReader(tt.csv))
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				assert.Nil(t, postalCodes)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, postalCodes)
+		})
+	}
+}
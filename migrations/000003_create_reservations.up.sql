@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS "reservations" (
+    "id" uuid PRIMARY KEY,
+    "user_id" uuid NOT NULL,
+    "zip_code" varchar NOT NULL,
+    "status" varchar NOT NULL,
+    "expires_at" timestamp NOT NULL,
+    "created_at" timestamp NOT NULL,
+    "updated_at" timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS "reservation_items" (
+    "id" uuid PRIMARY KEY,
+    "reservation_id" uuid NOT NULL,
+    "warehouse_id" uuid NOT NULL,
+    "product_id" uuid NOT NULL,
+    "product_name" varchar NOT NULL,
+    "quantity" integer NOT NULL
+);
+
+CREATE INDEX reservations_status_expires_at_idx ON reservations (status, expires_at);
+
+CREATE INDEX reservation_items_reservation_id_idx ON reservation_items (reservation_id);
+CREATE INDEX reservation_items_warehouse_id_product_id_idx ON reservation_items (warehouse_id, product_id);
+
+ALTER TABLE reservation_items ADD FOREIGN KEY (reservation_id) REFERENCES reservations (id) ON UPDATE CASCADE ON DELETE CASCADE;
+ALTER TABLE reservation_items ADD FOREIGN KEY (warehouse_id) REFERENCES warehouses (id) ON UPDATE CASCADE ON DELETE CASCADE;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS "outbox_events" (
+    "id" uuid PRIMARY KEY,
+    "topic" varchar NOT NULL,
+    "key" varchar NOT NULL,
+    "payload" jsonb NOT NULL,
+    "status" varchar NOT NULL,
+    "attempts" integer NOT NULL DEFAULT 0,
+    "last_error" varchar,
+    "next_attempt_at" timestamp NOT NULL,
+    "created_at" timestamp NOT NULL,
+    "sent_at" timestamp
+);
+
+CREATE INDEX outbox_events_status_next_attempt_at_idx ON outbox_events (status, next_attempt_at);
@@ -0,0 +1,3 @@
+ALTER TABLE stock_movements ADD COLUMN "idempotency_key" varchar(255);
+
+CREATE INDEX stock_movements_idempotency_key_idx ON stock_movements (idempotency_key) WHERE idempotency_key IS NOT NULL;
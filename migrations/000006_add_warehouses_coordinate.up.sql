@@ -0,0 +1,4 @@
+ALTER TABLE warehouses ADD COLUMN "latitude" double precision;
+ALTER TABLE warehouses ADD COLUMN "longitude" double precision;
+
+UPDATE warehouses SET latitude = -6.2088, longitude = 106.8176 WHERE id = '0193d7b4-a3d7-7022-a547-b987b1f9c6a8';
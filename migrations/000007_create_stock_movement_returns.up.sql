@@ -0,0 +1,17 @@
+ALTER TABLE stock_movements ADD COLUMN "movement_type" varchar;
+ALTER TABLE stock_movements ADD COLUMN "from_user_id" uuid;
+ALTER TABLE stock_movements ADD COLUMN "original_movement_id" uuid;
+ALTER TABLE stock_movements ADD COLUMN "disposition" varchar;
+
+UPDATE stock_movements SET movement_type = CASE WHEN to_user_id IS NOT NULL THEN 'sale' ELSE 'transfer' END;
+
+ALTER TABLE stock_movements ALTER COLUMN "movement_type" SET NOT NULL;
+-- returned units come from a user, not from a warehouse
+ALTER TABLE stock_movements ALTER COLUMN "from_warehouse_id" DROP NOT NULL;
+
+ALTER TABLE stock_movements ADD FOREIGN KEY (original_movement_id) REFERENCES stock_movements (id);
+
+CREATE INDEX stock_movements_original_movement_id_idx ON stock_movements (original_movement_id);
+
+-- damaged returned units are kept apart from the sellable product quantity
+ALTER TABLE warehouse_products ADD COLUMN "quarantined_quantity" integer NOT NULL DEFAULT 0;
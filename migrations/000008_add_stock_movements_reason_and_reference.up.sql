@@ -0,0 +1,8 @@
+ALTER TABLE stock_movements ADD COLUMN "reason" varchar;
+ALTER TABLE stock_movements ADD COLUMN "reference_id" varchar;
+
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_movement_type_check
+    CHECK (movement_type IN ('transfer', 'sale', 'return', 'adjustment', 'receipt', 'write-off'));
+
+CREATE INDEX stock_movements_movement_type_idx ON stock_movements (movement_type);
+CREATE INDEX stock_movements_reference_id_idx ON stock_movements (reference_id) WHERE reference_id IS NOT NULL;
@@ -0,0 +1,2 @@
+-- user who recorded a manual movement such as an adjustment
+ALTER TABLE stock_movements ADD COLUMN "created_by" uuid;
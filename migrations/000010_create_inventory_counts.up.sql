@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS "inventory_counts" (
+    "id" uuid PRIMARY KEY,
+    "warehouse_id" uuid NOT NULL,
+    "status" varchar NOT NULL,
+    "created_by" uuid,
+    "posted_by" uuid,
+    "created_at" timestamp NOT NULL,
+    "updated_at" timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS "inventory_count_lines" (
+    "id" uuid PRIMARY KEY,
+    "inventory_count_id" uuid NOT NULL,
+    "product_id" uuid NOT NULL,
+    "product_name" varchar NOT NULL,
+    "expected_quantity" integer NOT NULL,
+    "counted_quantity" integer
+);
+
+-- only one open count per warehouse
+CREATE UNIQUE INDEX inventory_counts_open_warehouse_id_idx ON inventory_counts (warehouse_id) WHERE status = 'open';
+
+CREATE UNIQUE INDEX inventory_count_lines_inventory_count_id_product_id_idx ON inventory_count_lines (inventory_count_id, product_id);
+
+ALTER TABLE inventory_counts ADD FOREIGN KEY (warehouse_id) REFERENCES warehouses (id) ON UPDATE CASCADE ON DELETE CASCADE;
+ALTER TABLE inventory_count_lines ADD FOREIGN KEY (inventory_count_id) REFERENCES inventory_counts (id) ON UPDATE CASCADE ON DELETE CASCADE;
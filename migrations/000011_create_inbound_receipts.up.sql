@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS "inbound_receipts" (
+    "id" uuid PRIMARY KEY,
+    "warehouse_id" uuid NOT NULL,
+    "type" varchar NOT NULL,
+    "reference_number" varchar NOT NULL,
+    "status" varchar NOT NULL,
+    "created_by" uuid,
+    "created_at" timestamp NOT NULL,
+    "updated_at" timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS "inbound_receipt_lines" (
+    "id" uuid PRIMARY KEY,
+    "inbound_receipt_id" uuid NOT NULL,
+    "product_id" uuid NOT NULL,
+    "product_name" varchar NOT NULL,
+    "expected_quantity" integer NOT NULL,
+    "received_quantity" integer NOT NULL DEFAULT 0
+);
+
+CREATE INDEX inbound_receipts_warehouse_id_status_idx ON inbound_receipts (warehouse_id, status);
+CREATE INDEX inbound_receipts_reference_number_idx ON inbound_receipts (reference_number);
+
+CREATE UNIQUE INDEX inbound_receipt_lines_inbound_receipt_id_product_id_idx ON inbound_receipt_lines (inbound_receipt_id, product_id);
+
+ALTER TABLE inbound_receipts ADD FOREIGN KEY (warehouse_id) REFERENCES warehouses (id) ON UPDATE CASCADE ON DELETE CASCADE;
+ALTER TABLE inbound_receipt_lines ADD FOREIGN KEY (inbound_receipt_id) REFERENCES inbound_receipts (id) ON UPDATE CASCADE ON DELETE CASCADE;
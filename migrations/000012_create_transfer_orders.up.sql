@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS "transfer_orders" (
+    "id" uuid PRIMARY KEY,
+    "from_warehouse_id" uuid NOT NULL,
+    "to_warehouse_id" uuid NOT NULL,
+    "status" varchar NOT NULL,
+    "reason" varchar,
+    "created_by" uuid,
+    "updated_by" uuid,
+    "created_at" timestamp NOT NULL,
+    "updated_at" timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS "transfer_order_lines" (
+    "id" uuid PRIMARY KEY,
+    "transfer_order_id" uuid NOT NULL,
+    "product_id" uuid NOT NULL,
+    "product_name" varchar NOT NULL,
+    "quantity" integer NOT NULL,
+    "shipped_quantity" integer NOT NULL DEFAULT 0,
+    "received_quantity" integer NOT NULL DEFAULT 0,
+    "discrepancy_quantity" integer NOT NULL DEFAULT 0
+);
+
+CREATE INDEX transfer_orders_status_idx ON transfer_orders (status);
+CREATE INDEX transfer_order_lines_product_id_idx ON transfer_order_lines (product_id);
+
+CREATE UNIQUE INDEX transfer_order_lines_transfer_order_id_product_id_idx ON transfer_order_lines (transfer_order_id, product_id);
+
+ALTER TABLE transfer_orders ADD FOREIGN KEY (from_warehouse_id) REFERENCES warehouses (id) ON UPDATE CASCADE ON DELETE CASCADE;
+ALTER TABLE transfer_orders ADD FOREIGN KEY (to_warehouse_id) REFERENCES warehouses (id) ON UPDATE CASCADE ON DELETE CASCADE;
+ALTER TABLE transfer_order_lines ADD FOREIGN KEY (transfer_order_id) REFERENCES transfer_orders (id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+-- shipping and receiving of transfer orders are separate movements
+ALTER TABLE stock_movements DROP CONSTRAINT stock_movements_movement_type_check;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_movement_type_check
+    CHECK (movement_type IN ('transfer', 'sale', 'return', 'adjustment', 'receipt', 'write-off', 'transfer-shipment', 'transfer-receipt'));
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS "stock_thresholds" (
+    "id" uuid PRIMARY KEY,
+    "warehouse_id" uuid NOT NULL,
+    "product_id" uuid NOT NULL,
+    "reorder_point" integer NOT NULL,
+    "target_level" integer NOT NULL,
+    "is_low" boolean NOT NULL DEFAULT false,
+    "created_at" timestamp NOT NULL,
+    "updated_at" timestamp NOT NULL
+);
+
+CREATE UNIQUE INDEX stock_thresholds_warehouse_id_product_id_idx ON stock_thresholds (warehouse_id, product_id);
+
+ALTER TABLE stock_thresholds ADD FOREIGN KEY (warehouse_id) REFERENCES warehouses (id) ON UPDATE CASCADE ON DELETE CASCADE;
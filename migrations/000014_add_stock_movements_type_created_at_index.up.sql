@@ -0,0 +1 @@
+CREATE INDEX stock_movements_movement_type_created_at_idx ON stock_movements (movement_type, created_at);
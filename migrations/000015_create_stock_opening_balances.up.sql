@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS "stock_opening_balances" (
+    "warehouse_id" uuid NOT NULL,
+    "product_id" uuid NOT NULL,
+    "quantity" integer NOT NULL,
+    "as_of" timestamp NOT NULL,
+    "created_at" timestamp NOT NULL,
+    PRIMARY KEY ("warehouse_id", "product_id")
+);
+
+ALTER TABLE stock_opening_balances ADD FOREIGN KEY (warehouse_id) REFERENCES warehouses (id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+-- movements before this migration may be incomplete, so the current quantity is the opening balance of existing products
+INSERT INTO stock_opening_balances (warehouse_id, product_id, quantity, as_of, created_at)
+SELECT warehouse_id, product_id, product_quantity, LOCALTIMESTAMP, LOCALTIMESTAMP
+FROM warehouse_products
+WHERE deleted_at IS NULL;
+
+CREATE INDEX stock_movements_product_id_created_at_idx ON stock_movements (product_id, created_at);
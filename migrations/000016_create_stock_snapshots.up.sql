@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS "stock_snapshots" (
+    "snapshot_date" date NOT NULL,
+    "warehouse_id" uuid NOT NULL,
+    "product_id" uuid NOT NULL,
+    "product_name" varchar NOT NULL,
+    "quantity" integer NOT NULL,
+    "product_price" float NOT NULL,
+    "value" float NOT NULL,
+    "created_at" timestamp NOT NULL,
+    PRIMARY KEY ("snapshot_date", "warehouse_id", "product_id")
+);
+
+CREATE INDEX stock_snapshots_warehouse_id_product_id_idx ON stock_snapshots (warehouse_id, product_id, snapshot_date);
+
+ALTER TABLE stock_snapshots ADD FOREIGN KEY (warehouse_id) REFERENCES warehouses (id) ON UPDATE CASCADE ON DELETE CASCADE;
@@ -0,0 +1,40 @@
+ALTER TABLE inbound_receipt_lines ADD COLUMN "unit_cost" float NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS "cost_layers" (
+    "id" uuid PRIMARY KEY,
+    "warehouse_id" uuid NOT NULL,
+    "product_id" uuid NOT NULL,
+    "stock_movement_id" uuid,
+    "quantity" integer NOT NULL,
+    "remaining_quantity" integer NOT NULL,
+    "unit_cost" float NOT NULL,
+    "created_at" timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS "cost_layer_consumptions" (
+    "id" uuid PRIMARY KEY,
+    "cost_layer_id" uuid,
+    "stock_movement_id" uuid NOT NULL,
+    "warehouse_id" uuid NOT NULL,
+    "product_id" uuid NOT NULL,
+    "quantity" integer NOT NULL,
+    "unit_cost" float NOT NULL,
+    "created_at" timestamp NOT NULL
+);
+
+CREATE INDEX cost_layers_warehouse_id_product_id_created_at_idx ON cost_layers (warehouse_id, product_id, created_at);
+
+CREATE INDEX cost_layer_consumptions_stock_movement_id_idx ON cost_layer_consumptions (stock_movement_id);
+
+ALTER TABLE cost_layers ADD FOREIGN KEY (warehouse_id) REFERENCES warehouses (id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE cost_layer_consumptions ADD FOREIGN KEY (cost_layer_id) REFERENCES cost_layers (id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE cost_layer_consumptions ADD FOREIGN KEY (stock_movement_id) REFERENCES stock_movements (id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+-- purchase cost of existing stock is unknown, so the current quantity is valued at the product price
+INSERT INTO cost_layers (id, warehouse_id, product_id, quantity, remaining_quantity, unit_cost, created_at)
+SELECT id, warehouse_id, product_id, product_quantity, product_quantity, product_price, LOCALTIMESTAMP
+FROM warehouse_products
+WHERE deleted_at IS NULL
+AND product_quantity > 0;
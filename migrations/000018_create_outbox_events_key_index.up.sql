@@ -0,0 +1,2 @@
+-- the relay looks up older pending events of the same key to publish each key in order
+CREATE INDEX IF NOT EXISTS outbox_events_pending_topic_key_idx ON outbox_events (topic, key, created_at) WHERE status = 'pending';
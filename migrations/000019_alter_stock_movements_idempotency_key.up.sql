@@ -0,0 +1,2 @@
+-- the stored key is scoped by the operation and the caller, e.g. move-out:<user id>:<key>
+ALTER TABLE stock_movements ALTER COLUMN idempotency_key TYPE varchar(320);
@@ -0,0 +1,3 @@
+ALTER TABLE stock_movements DROP CONSTRAINT stock_movements_movement_type_check;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_movement_type_check
+    CHECK (movement_type IN ('transfer', 'sale', 'return', 'adjustment', 'receipt', 'write-off', 'transfer-shipment', 'transfer-receipt', 'reversal'));
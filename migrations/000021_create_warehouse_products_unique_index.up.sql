@@ -0,0 +1,15 @@
+-- redelivered product events could save the same product twice in a warehouse. Quantity updates match every live row
+-- of the warehouse and product, so the duplicates hold the same quantity and only the oldest row is kept
+UPDATE warehouse_products
+SET deleted_at = LOCALTIMESTAMP
+WHERE deleted_at IS NULL
+AND EXISTS (
+    SELECT 1 FROM warehouse_products older
+    WHERE older.warehouse_id = warehouse_products.warehouse_id
+    AND older.product_id = warehouse_products.product_id
+    AND older.deleted_at IS NULL
+    AND (older.created_at, older.id) < (warehouse_products.created_at, warehouse_products.id)
+);
+
+-- a product is saved once per warehouse, deleted rows do not count
+CREATE UNIQUE INDEX IF NOT EXISTS warehouse_products_warehouse_id_product_id_key ON warehouse_products (warehouse_id, product_id) WHERE deleted_at IS NULL;
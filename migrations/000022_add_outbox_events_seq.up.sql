@@ -0,0 +1,20 @@
+-- created_at is taken from the app clock before the stock rows are locked, so it can be older than an event
+-- of the same key saved before it. The sequence is taken by the insert, after the lock, so it keeps the order of the key
+CREATE SEQUENCE IF NOT EXISTS outbox_events_seq_seq;
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS "seq" bigint;
+ALTER SEQUENCE outbox_events_seq_seq OWNED BY outbox_events.seq;
+
+UPDATE outbox_events
+SET seq = ordered.seq
+FROM (
+    SELECT id, row_number() OVER (ORDER BY created_at, id) AS seq
+    FROM outbox_events
+) ordered
+WHERE outbox_events.id = ordered.id AND outbox_events.seq IS NULL;
+SELECT setval('outbox_events_seq_seq', COALESCE((SELECT MAX(seq) FROM outbox_events), 0) + 1, false);
+
+ALTER TABLE outbox_events ALTER COLUMN "seq" SET DEFAULT nextval('outbox_events_seq_seq');
+ALTER TABLE outbox_events ALTER COLUMN "seq" SET NOT NULL;
+
+DROP INDEX IF EXISTS outbox_events_pending_topic_key_idx;
+CREATE INDEX IF NOT EXISTS outbox_events_pending_topic_key_seq_idx ON outbox_events (topic, key, seq) WHERE status = 'pending';
@@ -0,0 +1,14 @@
+-- a reference is cancelled once its sale movements are compensated, e.g. the order is cancelled,
+-- stock is not moved out for it afterwards even when the cancel arrives before the order
+CREATE TABLE IF NOT EXISTS "cancelled_references" (
+    "reference_id" varchar PRIMARY KEY,
+    "reason" varchar,
+    "cancelled_at" timestamp NOT NULL
+);
+
+INSERT INTO cancelled_references (reference_id, cancelled_at)
+SELECT reference_id, MIN(created_at)
+FROM stock_movements
+WHERE movement_type = 'reversal' AND reference_id IS NOT NULL
+GROUP BY reference_id
+ON CONFLICT (reference_id) DO NOTHING;
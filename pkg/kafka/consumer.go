@@ -13,8 +13,12 @@ const (
 	ProductGroup        = "product-group"
 	ProductCreatedTopic = "product-created"
 	ProductUpdatedTopic = "product-updated"
+	OrderCreatedTopic   = "order-created"
+	OrderCancelledTopic = "order-cancelled"
 	maxRetries          = 5
 	retryDelay          = 2 * time.Second
+	// the consumer leaves the group when it is not polled within the interval, e.g. while draining a revoke
+	MaxPollInterval = 5 * time.Minute
 )
 
 type ConsumerServer struct {
@@ -25,19 +29,19 @@ func NewKafkaConsumer(kafkaCfg config.Kafka) (*ConsumerServer, error) {
 	log.Printf("Creating Kafka consumer with broker URL: %s", kafkaCfg.Broker)
 
 	config := &kafka.ConfigMap{
-		"bootstrap.servers":         kafkaCfg.Broker,
-		"group.id":                  ProductGroup,
-		"auto.offset.reset":         "earliest",
-		"session.timeout.ms":        45000,
-		"heartbeat.interval.ms":     15000,
-		"metadata.max.age.ms":       300000,
-		"enable.auto.commit":        true,
-		"auto.commit.interval.ms":   5000,
-		"enable.partition.eof":      false,
-		"allow.auto.create.topics":  true,
-		"max.poll.interval.ms":      300000,
-		"max.partition.fetch.bytes": 1048576,
-		"fetch.max.bytes":           52428800,
+		"bootstrap.servers":               kafkaCfg.Broker,
+		"group.id":                        ProductGroup,
+		"auto.offset.reset":               "earliest",
+		"session.timeout.ms":              45000,
+		"heartbeat.interval.ms":           15000,
+		"metadata.max.age.ms":             300000,
+		"enable.auto.commit":              false, // offsets are committed once the message is handled
+		"go.application.rebalance.enable": true,  // assignments are polled, so handled offsets are committed before a revoke
+		"enable.partition.eof":            false,
+		"allow.auto.create.topics":        true,
+		"max.poll.interval.ms":            int(MaxPollInterval.Milliseconds()),
+		"max.partition.fetch.bytes":       1048576,
+		"fetch.max.bytes":                 52428800,
 	}
 
 	log.Printf("Kafka configuration: broker=%s, group=%s, auto.offset.reset=earliest",
@@ -48,10 +52,7 @@ func NewKafkaConsumer(kafkaCfg config.Kafka) (*ConsumerServer, error) {
 		return nil, fmt.Errorf("failed to create consumer: %v", err)
 	}
 
-	topics := []string{
-		ProductCreatedTopic,
-		ProductUpdatedTopic,
-	}
+	topics := ConsumedTopics()
 	var subscribeErr error
 	for i := 0; i < maxRetries; i++ {
 		subscribeErr = c.SubscribeTopics(topics, nil)
@@ -0,0 +1,71 @@
+package kafka
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/idoyudha/eshop-warehouse/config"
+)
+
+const (
+	DeadLetterGroup  = "product-dead-letter-group"
+	deadLetterSuffix = ".dlq"
+)
+
+// DeadLetter wraps a message that could not be handled, the payload is the original message value
+type DeadLetter struct {
+	Topic     string    `json:"topic"`
+	Partition int32     `json:"partition"`
+	Offset    int64     `json:"offset"`
+	Key       string    `json:"key"`
+	Payload   string    `json:"payload"`
+	Error     string    `json:"error"`
+	Attempts  int       `json:"attempts"`
+	FailedAt  time.Time `json:"failed_at"`
+}
+
+func DeadLetterTopic(topic string) string {
+	return topic + deadLetterSuffix
+}
+
+// ConsumedTopics are the topics subscribed by the consumer, each of them has its own dead letter topic
+func ConsumedTopics() []string {
+	return []string{
+		ProductCreatedTopic,
+		ProductUpdatedTopic,
+		OrderCreatedTopic,
+		OrderCancelledTopic,
+	}
+}
+
+// NewKafkaDeadLetterConsumer subscribes to the dead letter topics of the given topics.
+// Offsets are not committed automatically, so a dead letter is only consumed once it is replayed.
+func NewKafkaDeadLetterConsumer(kafkaCfg config.Kafka, topics []string) (*ConsumerServer, error) {
+	c, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":        kafkaCfg.Broker,
+		"group.id":                 DeadLetterGroup,
+		"auto.offset.reset":        "earliest",
+		"enable.auto.commit":       false,
+		"enable.partition.eof":     false,
+		"allow.auto.create.topics": true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dead letter consumer: %v", err)
+	}
+
+	deadLetterTopics := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		deadLetterTopics = append(deadLetterTopics, DeadLetterTopic(topic))
+	}
+	if err := c.SubscribeTopics(deadLetterTopics, nil); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to subscribe to dead letter topics: %v", err)
+	}
+	log.Printf("successfully subscribed to dead letter topics")
+
+	return &ConsumerServer{
+		Consumer: c,
+	}, nil
+}
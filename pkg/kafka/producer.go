@@ -8,6 +8,13 @@ import (
 	"github.com/idoyudha/eshop-warehouse/config"
 )
 
+const (
+	ProductQuantityUpdatedTopic = "product-quantity-updated"
+	StockLowTopic               = "stock-low"
+	// result of the stock movement requested by an order event, keyed by order id for the order saga
+	OrderStockReplyTopic = "order-stock-reply"
+)
+
 type ProducerServer struct {
 	Producer *kafka.Producer
 }
@@ -56,3 +63,38 @@ func (s *ProducerServer) Produce(topic string, key []byte, message interface{})
 		Value:          messageBytes,
 	}, nil)
 }
+
+// ProduceSync produces the message and waits for its delivery report,
+// so the caller knows whether the broker has acknowledged the message.
+func (s *ProducerServer) ProduceSync(topic string, key []byte, message interface{}) error {
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal kafka message: %w", err)
+	}
+	return s.ProduceRawSync(topic, key, messageBytes)
+}
+
+// ProduceRawSync is ProduceSync for a value that is already encoded, such as a replayed dead letter payload.
+func (s *ProducerServer) ProduceRawSync(topic string, key []byte, value []byte) error {
+	deliveryChan := make(chan kafka.Event, 1)
+	err := s.Producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Key:            key,
+		Value:          value,
+	}, deliveryChan)
+	if err != nil {
+		return fmt.Errorf("failed to produce kafka message: %w", err)
+	}
+
+	e := <-deliveryChan
+	switch ev := e.(type) {
+	case *kafka.Message:
+		if ev.TopicPartition.Error != nil {
+			return fmt.Errorf("failed to deliver kafka message: %w", ev.TopicPartition.Error)
+		}
+	case kafka.Error:
+		return fmt.Errorf("failed to deliver kafka message: %w", ev)
+	}
+
+	return nil
+}
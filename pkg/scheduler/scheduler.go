@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/idoyudha/eshop-warehouse/pkg/logger"
+)
+
+// Job -.
+type Job func(ctx context.Context) error
+
+type Scheduler struct {
+	l      logger.Interface
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New(l logger.Interface) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Scheduler{
+		l:      l,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Every runs the job on each interval until the scheduler is shut down.
+// Failed run is logged and retried on the next interval.
+func (s *Scheduler) Every(name string, interval time.Duration, job Job) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+				if err := job(s.ctx); err != nil {
+					s.l.Error(err, "scheduler - "+name)
+				}
+			}
+		}
+	}()
+}
+
+// Shutdown stops all jobs and waits for running jobs to finish.
+func (s *Scheduler) Shutdown() {
+	s.cancel()
+	s.wg.Wait()
+}